@@ -2,11 +2,15 @@ package bundle
 
 import (
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/download"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/explain"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/export"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/generate"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/list"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/merge"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/save"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/validate"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/verify"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/verifyall"
 	"github.com/spf13/cobra"
 )
 
@@ -15,15 +19,19 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bundle",
 		Short: "Manage TPM trust bundles",
-		Long:  `Verify, list, and download TPM trust bundles.`,
+		Long:  `Verify, list, download, and export TPM trust bundles.`,
 	}
 
 	cmd.AddCommand(generate.NewCommand())
 	cmd.AddCommand(validate.NewCommand())
 	cmd.AddCommand(verify.NewCommand())
+	cmd.AddCommand(verifyall.NewCommand())
 	cmd.AddCommand(download.NewCommand())
 	cmd.AddCommand(save.NewCommand())
 	cmd.AddCommand(list.NewCommand())
+	cmd.AddCommand(export.NewCommand())
+	cmd.AddCommand(merge.NewCommand())
+	cmd.AddCommand(explain.NewCommand())
 
 	return cmd
 }
@@ -108,9 +108,11 @@ func Run(ctx context.Context, o *Opts) error {
 	}
 
 	cfg := apiv1beta.GetConfig{
-		Date:       o.Date,
-		SkipVerify: o.SkipVerify,
-		CachePath:  o.CacheDir,
+		Date:                  o.Date,
+		SkipVerify:            o.SkipVerify,
+		AcknowledgeSkipVerify: o.SkipVerify, // --skip-verify is an explicit decision
+		CachePath:             o.CacheDir,
+		Logger:                cli.Logger(),
 	}
 
 	trustedBundle, err := apiv1beta.GetTrustedBundle(ctx, cfg)
@@ -0,0 +1,261 @@
+package explain
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/fingerprint"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"github.com/spf13/cobra"
+)
+
+// ErrNotTrusted is returned when the certificate does not chain to a root of the bundle.
+var ErrNotTrusted = errors.New("certificate is not trusted by the bundle")
+
+// Opts holds the configuration for the explain command.
+type Opts struct {
+	CertFile   string
+	BundleFile string
+	Date       string
+	SkipVerify bool
+	CacheDir   string
+}
+
+// NewCommand creates the explain command.
+func NewCommand() *cobra.Command {
+	opts := &Opts{}
+
+	cmd := &cobra.Command{
+		Use:   "explain <certificate-file>",
+		Short: "explain why a certificate is (or is not) trusted by a TPM trust bundle",
+		Long: `Explain which root of a TPM trust bundle anchors a certificate (e.g., a TPM EK certificate).
+
+A chain is built from the certificate to the roots of the bundle, through its intermediate
+certificates. The resulting chain is printed along with the vendor owning the anchoring root.
+When no chain can be built, the reason is printed instead (unknown issuer, expired
+certificate, etc.) and the command exits with a non-zero status.
+
+DER and PEM encodings are supported. Additional certificates of a PEM file are used as
+untrusted intermediates.
+
+The bundle is fetched from GitHub releases and verified, unless a bundle file is provided
+with --bundle (used as-is, use 'tpmtb bundle verify' beforehand). This is a diagnostic
+complement to 'tpmtb bundle verify'.`,
+		Example: `  # Explain which root of the latest bundle anchors an EK certificate
+  tpmtb bundle explain ek.cer
+
+  # Use a specific release
+  tpmtb bundle explain ek.cer --date 2025-12-03
+
+  # Use a local (combined root + intermediate) bundle
+  tpmtb bundle explain ek.cer --bundle tpm-ca-certificates.pem`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.CertFile = args[0]
+			return Run(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Date, "date", "d", "",
+		"Bundle release date (YYYY-MM-DD), default: latest")
+	cmd.Flags().StringVar(&opts.BundleFile, "bundle", "",
+		"Path to a local bundle file to use instead of a release (optional)")
+	cmd.Flags().BoolVar(&opts.SkipVerify, "skip-verify", false,
+		"Skip bundle verification when fetching from GitHub releases")
+	cmd.Flags().StringVar(&opts.CacheDir, "cache-dir", "",
+		"Cache directory path (optional, default: $HOME/.tpmtb)")
+	cmd.MarkFlagsMutuallyExclusive("bundle", "date")
+	cmd.MarkFlagsMutuallyExclusive("bundle", "skip-verify")
+
+	return cmd
+}
+
+// Run executes the explain command.
+func Run(ctx context.Context, out io.Writer, o *Opts) error {
+	if o.BundleFile != "" && o.Date != "" {
+		return fmt.Errorf("--date cannot be used with a bundle file")
+	}
+
+	certs, err := readCertificates(o.CertFile)
+	if err != nil {
+		return err
+	}
+	leaf, extra := certs[0], certs[1:]
+
+	tb, err := loadBundle(ctx, o)
+	if err != nil {
+		return err
+	}
+	defer tb.Stop()
+
+	fmt.Fprintf(out, "Certificate: %s\n", leaf.Subject)
+	fmt.Fprintf(out, "Issuer:      %s\n\n", leaf.Issuer)
+
+	chain, err := buildChain(tb, leaf, extra)
+	if err != nil {
+		fmt.Fprintf(out, "Reason: %s\n", explainError(tb, leaf, err))
+		cli.DisplayErrorTo(out, "❌ Certificate is not trusted by the bundle")
+		return fmt.Errorf("%w: %w", ErrNotTrusted, err)
+	}
+
+	anchor := printChain(out, tb, chain)
+	if anchor != nil {
+		cli.DisplaySuccessTo(out, "✅ Certificate is anchored by the %s root %q", anchor.VendorID, anchor.Subject)
+	}
+	return nil
+}
+
+// readCertificates reads the certificate to explain, followed by any additional certificate of the file.
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := utils.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate file: %w", err)
+	}
+	certs, err := download.ParseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate from %s: %w", path, err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found in %s", path)
+	}
+	return certs, nil
+}
+
+// loadBundle reads the bundle from disk or fetches it from GitHub releases.
+func loadBundle(ctx context.Context, o *Opts) (apiv1beta.TrustedBundle, error) {
+	if o.BundleFile != "" {
+		data, err := utils.ReadFile(o.BundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		tb, err := apiv1beta.NewTrustedBundle(bytes.NewReader(data), apiv1beta.WithSkipVerify())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bundle: %w", err)
+		}
+		return tb, nil
+	}
+
+	tb, err := apiv1beta.GetTrustedBundle(ctx, apiv1beta.GetConfig{
+		Date:                  o.Date,
+		SkipVerify:            o.SkipVerify,
+		AcknowledgeSkipVerify: o.SkipVerify, // --skip-verify is an explicit decision
+		CachePath:             o.CacheDir,
+		Logger:                cli.Logger(),
+		AutoUpdate: apiv1beta.AutoUpdateConfig{
+			DisableAutoUpdate: true,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, apiv1beta.ErrBundleVerificationFailed) {
+			cli.DisplayError("❌ Bundle verification failed")
+		}
+		return nil, err
+	}
+
+	if o.SkipVerify {
+		cli.DisplayStderr("⚠️  Verification skipped (--skip-verify)\n")
+	}
+	return tb, nil
+}
+
+// buildChain builds a chain from leaf to a root of the bundle, through the intermediates
+// of the bundle and the extra certificates.
+func buildChain(tb apiv1beta.TrustedBundle, leaf *x509.Certificate, extra []*x509.Certificate) ([]*x509.Certificate, error) {
+	// TPM EK certificates carry critical extensions unknown to the x509 package
+	leafCopy := *leaf
+	leafCopy.UnhandledCriticalExtensions = nil
+
+	intermediates := tb.GetIntermediateCertPool()
+	for _, cert := range extra {
+		intermediates.AddCert(cert)
+	}
+
+	chains, err := leafCopy.Verify(x509.VerifyOptions{
+		Roots:         tb.GetRootCertPool(),
+		Intermediates: intermediates,
+		// TPM EK certificates don't have standard key usages
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chains[0], nil
+}
+
+// printChain prints each certificate of chain, from the leaf to the root, and returns the
+// bundle entry of the anchoring root.
+func printChain(out io.Writer, tb apiv1beta.TrustedBundle, chain []*x509.Certificate) *apiv1beta.CertificateEntry {
+	fmt.Fprintln(out, "Chain:")
+
+	var anchor *apiv1beta.CertificateEntry
+	for i, cert := range chain {
+		role := "certificate"
+		if entry := lookup(tb, cert); entry != nil {
+			role = "root"
+			if entry.Intermediate {
+				role = "intermediate"
+			}
+			role = fmt.Sprintf("%s, vendor %s", role, entry.VendorID)
+			if i == len(chain)-1 {
+				anchor = entry
+			}
+		} else if i > 0 {
+			role = "provided intermediate"
+		}
+
+		fmt.Fprintf(out, "  [%d] %s (%s)\n", i, cert.Subject, role)
+		fmt.Fprintf(out, "      Not After:  %s\n", cert.NotAfter.UTC().Format(time.RFC3339))
+		fmt.Fprintf(out, "      SHA-256:    %s\n", fingerprint.New(cert.Raw, fingerprint.SHA256))
+	}
+	fmt.Fprintln(out)
+	return anchor
+}
+
+// lookup returns the bundle entry of cert, or nil if cert is not part of the bundle.
+func lookup(tb apiv1beta.TrustedBundle, cert *x509.Certificate) *apiv1beta.CertificateEntry {
+	entry, ok := tb.FindByPublicKey(cert.PublicKey)
+	if !ok || !entry.Certificate.Equal(cert) {
+		return nil
+	}
+	return entry
+}
+
+// explainError describes why no chain could be built to a root of the bundle.
+func explainError(tb apiv1beta.TrustedBundle, leaf *x509.Certificate, err error) string {
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) {
+		cert := invalidErr.Cert
+		switch invalidErr.Reason {
+		case x509.Expired:
+			return fmt.Sprintf("certificate %q is expired or not yet valid (valid from %s to %s)",
+				cert.Subject, cert.NotBefore.UTC().Format(time.RFC3339), cert.NotAfter.UTC().Format(time.RFC3339))
+		case x509.IncompatibleUsage:
+			return fmt.Sprintf("certificate %q has an incompatible key usage", cert.Subject)
+		default:
+			return invalidErr.Error()
+		}
+	}
+
+	var unknownErr x509.UnknownAuthorityError
+	if errors.As(err, &unknownErr) {
+		issuer := tb.FindFunc(func(c *x509.Certificate) bool {
+			return bytes.Equal(c.RawSubject, leaf.RawIssuer)
+		})
+		if issuer != nil {
+			// Same name, different key: the certificate was not signed by the bundle certificate
+			return fmt.Sprintf("unknown issuer: the bundle holds a certificate named %q, but it did not sign this certificate", issuer.Subject)
+		}
+		return fmt.Sprintf("unknown issuer: no certificate of the bundle is named %q (the issuer may be missing from the bundle, or a provided intermediate is required)", leaf.Issuer)
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,169 @@
+package explain
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+)
+
+// testCA is a certificate authority issuing test certificates.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCA creates a self-signed root CA named commonName.
+func newTestCA(t *testing.T, commonName string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+// issue writes a DER leaf certificate signed by the CA, valid until notAfter, and returns its path.
+func (ca *testCA) issue(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Test EK"},
+		NotBefore:    time.Now().AddDate(-2, 0, 0),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageKeyEncipherment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "ek.cer")
+	if err := os.WriteFile(path, der, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeBundle writes a root bundle holding the given CA, owned by IFX, and returns its path.
+func writeBundle(t *testing.T, ca *testCA) string {
+	t.Helper()
+
+	data := bundle.BuildBundleHeader("", "2025-12-03", strings.Repeat("a", 40), bundle.TypeRoot) +
+		bundle.FormatCertificateMetadata(ca.cert, vendors.IFX) + string(bundle.EncodePEM(ca.cert))
+	path := filepath.Join(t.TempDir(), "tpm-ca-certificates.pem")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	root := newTestCA(t, "Test Root CA")
+	bundlePath := writeBundle(t, root)
+
+	t.Run("leaf chaining to a bundle root", func(t *testing.T) {
+		var out bytes.Buffer
+		err := Run(t.Context(), &out, &Opts{
+			CertFile:   root.issue(t, time.Now().AddDate(1, 0, 0)),
+			BundleFile: bundlePath,
+		})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		for _, want := range []string{
+			"[0] CN=Test EK (certificate)",
+			"[1] CN=Test Root CA (root, vendor IFX)",
+			`Certificate is anchored by the IFX root "CN=Test Root CA"`,
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	tests := []struct {
+		name       string
+		certFile   func(t *testing.T) string
+		wantReason string
+	}{
+		{
+			name: "unknown issuer",
+			certFile: func(t *testing.T) string {
+				return newTestCA(t, "Other Root CA").issue(t, time.Now().AddDate(1, 0, 0))
+			},
+			wantReason: `no certificate of the bundle is named "CN=Other Root CA"`,
+		},
+		{
+			name: "issuer with the same name but another key",
+			certFile: func(t *testing.T) string {
+				return newTestCA(t, "Test Root CA").issue(t, time.Now().AddDate(1, 0, 0))
+			},
+			wantReason: "did not sign this certificate",
+		},
+		{
+			name: "expired leaf",
+			certFile: func(t *testing.T) string {
+				return root.issue(t, time.Now().AddDate(-1, 0, 0))
+			},
+			wantReason: `certificate "CN=Test EK" is expired or not yet valid`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Run(t.Context(), &out, &Opts{CertFile: tt.certFile(t), BundleFile: bundlePath})
+			if !errors.Is(err, ErrNotTrusted) {
+				t.Fatalf("expected ErrNotTrusted, got %v", err)
+			}
+			if !strings.Contains(out.String(), tt.wantReason) {
+				t.Errorf("output missing reason %q:\n%s", tt.wantReason, out.String())
+			}
+			if !strings.Contains(out.String(), "Certificate is not trusted by the bundle") {
+				t.Errorf("output missing the verdict:\n%s", out.String())
+			}
+		})
+	}
+
+	t.Run("date with a bundle file", func(t *testing.T) {
+		err := Run(t.Context(), &bytes.Buffer{}, &Opts{CertFile: "ek.cer", BundleFile: bundlePath, Date: "2025-12-03"})
+		if err == nil {
+			t.Fatal("expected an error when --date is used with a bundle file")
+		}
+	})
+}
@@ -0,0 +1,253 @@
+package export
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"github.com/spf13/cobra"
+)
+
+// Opts holds the configuration for the export command.
+type Opts struct {
+	BundleFile string
+	Date       string
+	VendorIDs  []string
+	Format     string
+	Output     string
+	Force      bool
+	SkipVerify bool
+	CacheDir   string
+	NoMetadata bool
+}
+
+// NewCommand creates the export command.
+func NewCommand() *cobra.Command {
+	opts := &Opts{}
+
+	cmd := &cobra.Command{
+		Use:   "export [bundle-file]",
+		Short: "export a TPM trust bundle to another certificate format",
+		Long: `Export the certificates of a TPM trust bundle to a format expected by downstream tooling.
+
+Supported formats:
+  - pem:     TPM trust bundle keeping the metadata comments of each certificate
+             (plain concatenated PEM certificates with --no-metadata)
+  - p7b:     DER-encoded degenerate PKCS#7 containing all certificates
+  - der-dir: one DER file per certificate, named by its SHA-256 fingerprint
+
+When a bundle file is provided, it is exported as-is (use 'tpmtb bundle verify' beforehand).
+Otherwise the bundle is fetched from GitHub releases and verified before export.`,
+		Example: `  # Export the latest bundle as PKCS#7
+  tpmtb bundle export --format p7b --output tpm-ca-certificates.p7b
+
+  # Export a specific release filtered by vendor
+  tpmtb bundle export --date 2025-12-03 --vendor-id IFX,NTC --format pem --output roots.pem
+
+  # Export a local bundle file as one DER file per certificate
+  tpmtb bundle export tpm-ca-certificates.pem --format der-dir --output ./certs
+
+  # Print the PEM certificates to stdout, without metadata comments
+  tpmtb bundle export --format pem --no-metadata`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.BundleFile = args[0]
+			}
+			return Run(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Date, "date", "d", "",
+		"Bundle release date (YYYY-MM-DD), default: latest")
+	cmd.Flags().StringSliceVar(&opts.VendorIDs, "vendor-id", nil,
+		"Comma-separated list of vendor IDs to export (e.g., IFX,NTC,STM,INTC), default: all")
+	cmd.Flags().StringVar(&opts.Format, "format", bundle.FormatPEM.String(),
+		"Output format: pem, p7b or der-dir")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "-",
+		"Output file (use '-' for stdout), or output directory for der-dir")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false,
+		"Overwrite existing files without prompting")
+	cmd.Flags().BoolVar(&opts.SkipVerify, "skip-verify", false,
+		"Skip bundle verification when fetching from GitHub releases")
+	cmd.Flags().BoolVar(&opts.NoMetadata, "no-metadata", false,
+		"Export plain PEM certificates, without metadata comments (pem format only)")
+
+	return cmd
+}
+
+// Run executes the export command.
+func Run(ctx context.Context, o *Opts) error {
+	format := bundle.ExportFormat(o.Format)
+	if err := format.Validate(); err != nil {
+		return err
+	}
+	if format == bundle.FormatDERDir && o.Output == "-" {
+		return fmt.Errorf("format %s requires an output directory (--output)", format)
+	}
+	if o.BundleFile != "" && o.Date != "" {
+		return fmt.Errorf("--date cannot be used with a bundle file")
+	}
+	if o.NoMetadata && format != bundle.FormatPEM {
+		return fmt.Errorf("--no-metadata only applies to the %s format", bundle.FormatPEM)
+	}
+
+	var vendorIDs []vendors.ID
+	for _, vid := range o.VendorIDs {
+		vendorID := vendors.ID(vid)
+		if err := vendorID.Validate(); err != nil {
+			return fmt.Errorf("invalid vendor ID %q: %w", vid, err)
+		}
+		vendorIDs = append(vendorIDs, vendorID)
+	}
+
+	data, err := loadBundle(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	catalog, err := bundle.ParseBundle(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	certs := bundle.SelectCertificates(catalog, vendorIDs)
+	if len(certs) == 0 {
+		return fmt.Errorf("no certificates match the vendor filter")
+	}
+
+	switch format {
+	case bundle.FormatPEM:
+		if o.NoMetadata {
+			return writeOutput(o, bundle.EncodePEMBundle(certs), len(certs))
+		}
+		filtered, err := bundle.FilterBundle(data, vendorIDs)
+		if err != nil {
+			return fmt.Errorf("failed to filter bundle: %w", err)
+		}
+		return writeOutput(o, filtered, len(certs))
+	case bundle.FormatP7B:
+		p7b, err := bundle.EncodePKCS7(certs)
+		if err != nil {
+			return err
+		}
+		return writeOutput(o, p7b, len(certs))
+	case bundle.FormatDERDir:
+		return writeDERDir(o, certs)
+	}
+
+	return nil
+}
+
+// loadBundle reads the bundle from disk or fetches it from GitHub releases.
+func loadBundle(ctx context.Context, o *Opts) ([]byte, error) {
+	if o.BundleFile != "" {
+		data, err := utils.ReadFile(o.BundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		return data, nil
+	}
+
+	tb, err := apiv1beta.GetTrustedBundle(ctx, apiv1beta.GetConfig{
+		Date:                  o.Date,
+		SkipVerify:            o.SkipVerify,
+		AcknowledgeSkipVerify: o.SkipVerify, // --skip-verify is an explicit decision
+		CachePath:             o.CacheDir,
+		Logger:                cli.Logger(),
+		AutoUpdate: apiv1beta.AutoUpdateConfig{
+			DisableAutoUpdate: true,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, apiv1beta.ErrBundleVerificationFailed) {
+			cli.DisplayError("❌ Bundle verification failed")
+		}
+		return nil, err
+	}
+	defer tb.Stop()
+
+	if o.SkipVerify {
+		cli.DisplayStderr("⚠️  Verification skipped (--skip-verify)\n")
+	}
+
+	return tb.GetRawRoot(), nil
+}
+
+// writeOutput writes data to the output file or stdout.
+func writeOutput(o *Opts, data []byte, count int) error {
+	if o.Output == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := confirmOverwrite(o, o.Output); err != nil {
+		return err
+	}
+	if err := os.WriteFile(o.Output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", o.Output, err)
+	}
+
+	cli.DisplaySuccess("✅ Exported %d certificates to %s", count, o.Output)
+	return nil
+}
+
+// writeDERDir writes one DER file per certificate, named by its SHA-256 fingerprint.
+func writeDERDir(o *Opts, certs []*x509.Certificate) error {
+	if err := os.MkdirAll(o.Output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	paths := make([]string, len(certs))
+	for i, cert := range certs {
+		paths[i] = filepath.Join(o.Output, derFilename(cert))
+	}
+
+	// Ask once for the whole directory
+	for _, path := range paths {
+		if utils.FileExists(path) {
+			if err := confirmOverwrite(o, path); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	for i, cert := range certs {
+		if err := os.WriteFile(paths[i], cert.Raw, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", paths[i], err)
+		}
+	}
+
+	cli.DisplaySuccess("✅ Exported %d certificates to %s", len(certs), o.Output)
+	return nil
+}
+
+// derFilename returns the filename used for a certificate in der-dir format.
+func derFilename(cert *x509.Certificate) string {
+	return hex.EncodeToString(digest.Sha256Hash(cert.Raw)) + ".der"
+}
+
+func confirmOverwrite(o *Opts, path string) error {
+	if o.Force || !utils.FileExists(path) {
+		return nil
+	}
+	cli.DisplayWarning("File %s already exists.", path)
+	if !cli.PromptConfirmation("Override?") {
+		fmt.Println()
+		return fmt.Errorf("export cancelled")
+	}
+	fmt.Println()
+	return nil
+}
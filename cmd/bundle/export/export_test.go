@@ -0,0 +1,248 @@
+package export
+
+import (
+	"bytes"
+	"crypto/x509"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/digitorus/pkcs7"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func writeTestBundle(t *testing.T) (string, map[vendors.ID][]*x509.Certificate) {
+	t.Helper()
+
+	data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	catalog, err := bundle.ParseBundle(data)
+	if err != nil {
+		t.Fatalf("failed to parse test bundle: %v", err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "tpm-ca-certificates.pem")
+	if err := os.WriteFile(bundlePath, data, 0644); err != nil {
+		t.Fatalf("failed to write test bundle: %v", err)
+	}
+	return bundlePath, catalog
+}
+
+func rawCertificates(certs []*x509.Certificate) [][]byte {
+	raws := make([][]byte, len(certs))
+	for i, cert := range certs {
+		raws[i] = cert.Raw
+	}
+	slices.SortFunc(raws, bytes.Compare)
+	return raws
+}
+
+func TestRun(t *testing.T) {
+	t.Run("p7b contains every certificate", func(t *testing.T) {
+		bundlePath, catalog := writeTestBundle(t)
+		output := filepath.Join(t.TempDir(), "bundle.p7b")
+
+		if err := Run(t.Context(), &Opts{
+			BundleFile: bundlePath,
+			Format:     bundle.FormatP7B.String(),
+			Output:     output,
+		}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read exported p7b: %v", err)
+		}
+		p7, err := pkcs7.Parse(data)
+		if err != nil {
+			t.Fatalf("failed to parse exported p7b: %v", err)
+		}
+
+		want := rawCertificates(bundle.SelectCertificates(catalog, nil))
+		got := rawCertificates(p7.Certificates)
+		if !slices.EqualFunc(got, want, bytes.Equal) {
+			t.Errorf("exported p7b contains %d certificates, want %d matching the bundle", len(got), len(want))
+		}
+	})
+
+	t.Run("p7b honors vendor filter", func(t *testing.T) {
+		bundlePath, catalog := writeTestBundle(t)
+		output := filepath.Join(t.TempDir(), "bundle.p7b")
+
+		var vendorID vendors.ID
+		for id := range catalog {
+			vendorID = id
+			break
+		}
+
+		if err := Run(t.Context(), &Opts{
+			BundleFile: bundlePath,
+			VendorIDs:  []string{string(vendorID)},
+			Format:     bundle.FormatP7B.String(),
+			Output:     output,
+		}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read exported p7b: %v", err)
+		}
+		p7, err := pkcs7.Parse(data)
+		if err != nil {
+			t.Fatalf("failed to parse exported p7b: %v", err)
+		}
+
+		want := rawCertificates(catalog[vendorID])
+		got := rawCertificates(p7.Certificates)
+		if !slices.EqualFunc(got, want, bytes.Equal) {
+			t.Errorf("exported p7b contains %d certificates, want the %d certificates of %s", len(got), len(want), vendorID)
+		}
+	})
+
+	t.Run("pem round-trips", func(t *testing.T) {
+		bundlePath, catalog := writeTestBundle(t)
+		output := filepath.Join(t.TempDir(), "bundle.pem")
+
+		if err := Run(t.Context(), &Opts{
+			BundleFile: bundlePath,
+			Format:     bundle.FormatPEM.String(),
+			Output:     output,
+		}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read exported pem: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			t.Fatal("exported pem contains no certificate")
+		}
+
+		want := len(bundle.SelectCertificates(catalog, nil))
+		if got := bytes.Count(data, []byte("-----BEGIN CERTIFICATE-----")); got != want {
+			t.Errorf("exported pem contains %d certificates, want %d", got, want)
+		}
+	})
+
+	t.Run("pem keeps metadata of a filtered bundle", func(t *testing.T) {
+		bundlePath, catalog := writeTestBundle(t)
+		output := filepath.Join(t.TempDir(), "bundle.pem")
+
+		if err := Run(t.Context(), &Opts{
+			BundleFile: bundlePath,
+			VendorIDs:  []string{string(vendors.IFX)},
+			Format:     bundle.FormatPEM.String(),
+			Output:     output,
+		}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read exported pem: %v", err)
+		}
+		errs, err := bundle.NewBundleValidator().ValidateBundle(data)
+		if err != nil {
+			t.Fatalf("failed to validate exported pem: %v", err)
+		}
+		for _, e := range errs {
+			t.Errorf("Line %d: %s", e.Line, e.Message)
+		}
+
+		exported, err := bundle.ParseBundle(data)
+		if err != nil {
+			t.Fatalf("failed to parse exported pem: %v", err)
+		}
+		if len(exported) != 1 || !slices.EqualFunc(rawCertificates(exported[vendors.IFX]), rawCertificates(catalog[vendors.IFX]), bytes.Equal) {
+			t.Errorf("exported pem should hold the IFX certificates only, got vendors %v", slices.Collect(maps.Keys(exported)))
+		}
+	})
+
+	t.Run("pem without metadata", func(t *testing.T) {
+		bundlePath, catalog := writeTestBundle(t)
+		output := filepath.Join(t.TempDir(), "bundle.pem")
+
+		if err := Run(t.Context(), &Opts{
+			BundleFile: bundlePath,
+			Format:     bundle.FormatPEM.String(),
+			Output:     output,
+			NoMetadata: true,
+		}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read exported pem: %v", err)
+		}
+		if !bytes.Equal(data, bundle.EncodePEMBundle(bundle.SelectCertificates(catalog, nil))) {
+			t.Error("exported pem should only hold CERTIFICATE blocks")
+		}
+		if bytes.Contains(data, []byte("#")) {
+			t.Error("exported pem should not hold metadata comments")
+		}
+	})
+
+	t.Run("der-dir writes one file per certificate", func(t *testing.T) {
+		bundlePath, catalog := writeTestBundle(t)
+		outputDir := filepath.Join(t.TempDir(), "certs")
+
+		if err := Run(t.Context(), &Opts{
+			BundleFile: bundlePath,
+			Format:     bundle.FormatDERDir.String(),
+			Output:     outputDir,
+		}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		certs := bundle.SelectCertificates(catalog, nil)
+		entries, err := os.ReadDir(outputDir)
+		if err != nil {
+			t.Fatalf("failed to read output directory: %v", err)
+		}
+		if len(entries) != len(certs) {
+			t.Errorf("output directory contains %d files, want %d", len(entries), len(certs))
+		}
+
+		for _, cert := range certs {
+			data, err := os.ReadFile(filepath.Join(outputDir, derFilename(cert)))
+			if err != nil {
+				t.Fatalf("missing DER file for certificate %s: %v", cert.Subject, err)
+			}
+			if !bytes.Equal(data, cert.Raw) {
+				t.Errorf("DER file for certificate %s does not match", cert.Subject)
+			}
+		}
+	})
+
+	t.Run("rejects invalid options", func(t *testing.T) {
+		tests := []struct {
+			name string
+			opts Opts
+		}{
+			{"invalid format", Opts{BundleFile: "bundle.pem", Format: "jks", Output: "-"}},
+			{"der-dir to stdout", Opts{BundleFile: "bundle.pem", Format: "der-dir", Output: "-"}},
+			{"date with bundle file", Opts{BundleFile: "bundle.pem", Date: "2025-12-03", Format: "pem", Output: "-"}},
+			{"invalid vendor ID", Opts{BundleFile: "bundle.pem", VendorIDs: []string{"NOPE"}, Format: "pem", Output: "-"}},
+			{"no-metadata with p7b", Opts{BundleFile: "bundle.pem", Format: "p7b", Output: "-", NoMetadata: true}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := Run(t.Context(), &tt.opts); err == nil {
+					t.Error("Run() error = nil, want error")
+				}
+			})
+		}
+	})
+}
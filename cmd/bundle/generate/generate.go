@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
@@ -79,7 +78,7 @@ func Run(ctx context.Context, o *Opts) error {
 		return fmt.Errorf("concurrency value %d exceeds maximum allowed (%d)", o.Workers, concurrency.MaxWorkers)
 	}
 
-	bundleType, err := resolveBundleType(o.Type, o.ConfigPath)
+	bundleType, err := bundle.ResolveType(o.Type, o.ConfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve bundle type: %w", err)
 	}
@@ -146,29 +145,3 @@ func resolveGitMetadata() (string, string, error) {
 
 	return info.Tag, info.Commit, nil
 }
-
-// resolveBundleType determines the bundle type based on the --type flag or config filename.
-//
-// Priority:
-//  1. If typeFlag is provided (non-empty), use it
-//  2. Otherwise, infer from config filename:
-//     - ".tpm-intermediates.yaml" → intermediate
-//     - anything else → root
-func resolveBundleType(typeFlag, configPath string) (bundle.BundleType, error) {
-	// If type is explicitly provided, use it
-	if typeFlag != "" {
-		bundleType := bundle.BundleType(typeFlag)
-		if err := bundleType.Validate(); err != nil {
-			return "", err
-		}
-		return bundleType, nil
-	}
-
-	// Auto-detect from config filename (check basename only)
-	basename := filepath.Base(configPath)
-	if basename == ".tpm-intermediates.yaml" {
-		return bundle.TypeIntermediate, nil
-	}
-
-	return bundle.TypeRoot, nil
-}
@@ -1,17 +1,45 @@
 package list
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
 
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/spf13/cobra"
 )
 
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
 var (
-	limit     int
-	sortOrder string
+	limit        int
+	sortOrder    string
+	since        string
+	outputFormat string
 )
 
+// newClient returns the GitHub client used to fetch releases (overridden in tests).
+var newClient = func() *github.HTTPClient {
+	return github.NewHTTPClient()
+}
+
+// releaseInfo describes a bundle release and the assets it carries.
+type releaseInfo struct {
+	Date               string    `json:"date"`
+	Commit             string    `json:"commit,omitempty"`
+	PublishedAt        time.Time `json:"publishedAt"`
+	RootBundle         bool      `json:"rootBundle"`
+	IntermediateBundle bool      `json:"intermediateBundle"`
+	Checksums          bool      `json:"checksums"`
+}
+
 // NewCommand creates the list command.
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -21,7 +49,11 @@ func NewCommand() *cobra.Command {
 
 Only releases with date-format tags (YYYY-MM-DD) are displayed, as these
 represent TPM trust bundle releases. Semantic version releases (like v1.0.0)
-are ignored.`,
+are ignored.
+
+For each release, the commit it was generated from, its publication time and
+whether the root bundle, intermediate bundle and checksum assets are attached
+are displayed. This helps picking a version to pin.`,
 		Example: `  # List the last 10 releases (default)
   tpmtb bundle list
 
@@ -32,7 +64,13 @@ are ignored.`,
   tpmtb bundle list --sort asc
 
   # List the last 5 releases in descending order
-  tpmtb bundle list --limit 5 --sort desc`,
+  tpmtb bundle list --limit 5 --sort desc
+
+  # List releases published after a given date
+  tpmtb bundle list --since 2025-12-03
+
+  # List releases as JSON
+  tpmtb bundle list -o json`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE:         run,
@@ -42,6 +80,10 @@ are ignored.`,
 		"Maximum number of releases to display")
 	cmd.Flags().StringVarP(&sortOrder, "sort", "s", "desc",
 		"Sort order for releases: asc (oldest first) or desc (newest first)")
+	cmd.Flags().StringVar(&since, "since", "",
+		"Only list releases with a tag date strictly after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", outputText,
+		"Output format: text or json")
 
 	return cmd
 }
@@ -63,11 +105,22 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("limit must be greater than 0")
 	}
 
-	client := github.NewHTTPClient()
+	if since != "" {
+		if err := bundle.ValidateDate(since); err != nil {
+			return fmt.Errorf("invalid --since date: %w", err)
+		}
+	}
+
+	if outputFormat != outputText && outputFormat != outputJSON {
+		return fmt.Errorf("invalid output format %q, must be 'text' or 'json'", outputFormat)
+	}
+
+	client := newClient()
 
 	opts := github.ReleasesOptions{
 		PageSize:  limit,
 		SortOrder: order,
+		Since:     since,
 	}
 
 	releases, err := client.GetReleases(cmd.Context(), github.SourceRepo, opts)
@@ -75,20 +128,66 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch releases: %w", err)
 	}
 
-	if len(releases) == 0 {
-		fmt.Println("No bundle releases found")
-		return nil
-	}
-
 	// Apply limit if we got more releases than requested
 	if len(releases) > limit {
 		releases = releases[:limit]
 	}
 
-	fmt.Printf("Available TPM trust bundle releases (%d):\n", len(releases))
-	for _, release := range releases {
-		fmt.Printf("  %s\n", release.TagName)
+	infos := make([]releaseInfo, len(releases))
+	for i, release := range releases {
+		infos[i] = releaseInfo{
+			Date:               release.TagName,
+			Commit:             release.Commit(),
+			PublishedAt:        release.PublishedAt,
+			RootBundle:         release.HasAsset(cache.RootBundleFilename),
+			IntermediateBundle: release.HasAsset(cache.IntermediateBundleFilename),
+			Checksums:          release.HasAsset(cache.ChecksumsFilename) && release.HasAsset(cache.ChecksumsSigFilename),
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	if outputFormat == outputJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(infos)
 	}
 
-	return nil
+	return printReleases(out, infos)
+}
+
+// printReleases writes the releases as a human readable table.
+func printReleases(out io.Writer, infos []releaseInfo) error {
+	if len(infos) == 0 {
+		if since != "" {
+			fmt.Fprintf(out, "No bundle releases found since %s\n", since)
+			return nil
+		}
+		fmt.Fprintln(out, "No bundle releases found")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Available TPM trust bundle releases (%d):\n", len(infos))
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  DATE\tCOMMIT\tPUBLISHED\tROOT\tINTERMEDIATE\tCHECKSUMS")
+	for _, info := range infos {
+		commit := "-"
+		if info.Commit != "" {
+			commit = info.Commit[:7]
+		}
+		published := "-"
+		if !info.PublishedAt.IsZero() {
+			published = info.PublishedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Date, commit, published,
+			yesNo(info.RootBundle), yesNo(info.IntermediateBundle), yesNo(info.Checksums))
+	}
+	return w.Flush()
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
 }
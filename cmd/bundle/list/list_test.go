@@ -0,0 +1,146 @@
+package list
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
+	"github.com/spf13/cobra"
+)
+
+const testCommit = "0123456789abcdef0123456789abcdef01234567"
+
+// releasesHTTPClient serves a fixed list of releases.
+type releasesHTTPClient struct {
+	releases []github.Release
+}
+
+func (c *releasesHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := json.Marshal(c.releases)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Header: make(http.Header)}, nil
+}
+
+func testReleases() []github.Release {
+	allAssets := []github.Asset{
+		{Name: cache.RootBundleFilename},
+		{Name: cache.IntermediateBundleFilename},
+		{Name: cache.ChecksumsFilename},
+		{Name: cache.ChecksumsSigFilename},
+	}
+	return []github.Release{
+		{
+			TagName:     "2026-01-15",
+			PublishedAt: time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC),
+			Body:        "This release contains the TPM CA Certificates generated at commit [" + testCommit + "](https://example.com).",
+			Assets:      allAssets,
+		},
+		{
+			TagName:     "2025-12-04",
+			PublishedAt: time.Date(2025, 12, 4, 8, 0, 0, 0, time.UTC),
+			Assets:      []github.Asset{{Name: cache.RootBundleFilename}, {Name: cache.ChecksumsFilename}},
+		},
+		{
+			TagName:     "2025-12-03",
+			PublishedAt: time.Date(2025, 12, 3, 8, 0, 0, 0, time.UTC),
+			Assets:      allAssets,
+		},
+	}
+}
+
+// runList executes the list command with the given flags against the mocked releases.
+func runList(t *testing.T, l int, format string) (string, error) {
+	t.Helper()
+
+	oldClient, oldLimit, oldSort, oldSince, oldFormat := newClient, limit, sortOrder, since, outputFormat
+	t.Cleanup(func() {
+		newClient, limit, sortOrder, since, outputFormat = oldClient, oldLimit, oldSort, oldSince, oldFormat
+	})
+	newClient = func() *github.HTTPClient {
+		return github.NewHTTPClient(&releasesHTTPClient{releases: testReleases()})
+	}
+	limit, sortOrder, since, outputFormat = l, "desc", "", format
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+	cmd.SetOut(&out)
+
+	err := run(cmd, nil)
+	return out.String(), err
+}
+
+func TestRunJSONOutput(t *testing.T) {
+	out, err := runList(t, 10, outputJSON)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	var infos []releaseInfo
+	if err := json.Unmarshal([]byte(out), &infos); err != nil {
+		t.Fatalf("failed to decode JSON output: %v\n%s", err, out)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("expected 3 releases, got %d", len(infos))
+	}
+
+	want := releaseInfo{
+		Date:               "2026-01-15",
+		Commit:             testCommit,
+		PublishedAt:        time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC),
+		RootBundle:         true,
+		IntermediateBundle: true,
+		Checksums:          true,
+	}
+	if infos[0] != want {
+		t.Errorf("infos[0] = %+v, want %+v", infos[0], want)
+	}
+
+	// Missing intermediate bundle and checksum signature
+	if infos[1].Commit != "" || !infos[1].RootBundle || infos[1].IntermediateBundle || infos[1].Checksums {
+		t.Errorf("unexpected asset summary for %s: %+v", infos[1].Date, infos[1])
+	}
+}
+
+func TestRunLimit(t *testing.T) {
+	out, err := runList(t, 2, outputJSON)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	var infos []releaseInfo
+	if err := json.Unmarshal([]byte(out), &infos); err != nil {
+		t.Fatalf("failed to decode JSON output: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(infos))
+	}
+	if infos[0].Date != "2026-01-15" || infos[1].Date != "2025-12-04" {
+		t.Errorf("unexpected releases: %s, %s", infos[0].Date, infos[1].Date)
+	}
+}
+
+func TestRunTextOutput(t *testing.T) {
+	out, err := runList(t, 10, outputText)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !strings.Contains(out, "Available TPM trust bundle releases (3):") {
+		t.Errorf("missing header in output:\n%s", out)
+	}
+	if !strings.Contains(out, testCommit[:7]) {
+		t.Errorf("missing short commit in output:\n%s", out)
+	}
+
+	if _, err := runList(t, 10, "yaml"); err == nil {
+		t.Error("run() expected error for invalid output format")
+	}
+}
@@ -0,0 +1,91 @@
+package merge
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// Opts holds the configuration for the merge command.
+type Opts struct {
+	RootFile         string
+	IntermediateFile string
+	Output           string
+	Force            bool
+}
+
+// NewCommand creates the merge command.
+func NewCommand() *cobra.Command {
+	opts := &Opts{}
+
+	cmd := &cobra.Command{
+		Use:   "merge <root-bundle> <intermediate-bundle>",
+		Short: "merge root and intermediate TPM trust bundles into a single file",
+		Long: `Merge a root and an intermediate TPM trust bundle into a single combined file.
+
+The combined file is the concatenation of both bundles, each keeping its own global
+metadata block. Both bundles must come from the same release (same Date and Commit).
+
+Combined files are accepted by the SDK wherever a bundle is loaded: the root and
+intermediate certificates are both made available.`,
+		Example: `  # Merge the bundles of a release into a single file
+  tpmtb bundle merge tpm-ca-certificates.pem tpm-intermediate-ca-certificates.pem -o combined.pem
+
+  # Print the combined bundle to stdout
+  tpmtb bundle merge tpm-ca-certificates.pem tpm-intermediate-ca-certificates.pem`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.RootFile, opts.IntermediateFile = args[0], args[1]
+			return Run(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", "-",
+		"Output file (use '-' for stdout)")
+	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false,
+		"Overwrite existing file without prompting")
+
+	return cmd
+}
+
+// Run executes the merge command.
+func Run(o *Opts) error {
+	root, err := utils.ReadFile(o.RootFile)
+	if err != nil {
+		return fmt.Errorf("failed to read root bundle: %w", err)
+	}
+	intermediate, err := utils.ReadFile(o.IntermediateFile)
+	if err != nil {
+		return fmt.Errorf("failed to read intermediate bundle: %w", err)
+	}
+
+	combined, err := bundle.Merge(root, intermediate)
+	if err != nil {
+		return err
+	}
+
+	if o.Output == "-" {
+		_, err := os.Stdout.Write(combined)
+		return err
+	}
+
+	if !o.Force && utils.FileExists(o.Output) {
+		cli.DisplayWarning("File %s already exists.", o.Output)
+		if !cli.PromptConfirmation("Override?") {
+			fmt.Println()
+			return fmt.Errorf("merge cancelled")
+		}
+		fmt.Println()
+	}
+	if err := os.WriteFile(o.Output, combined, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", o.Output, err)
+	}
+
+	cli.DisplaySuccess("✅ Combined bundle written to %s", o.Output)
+	return nil
+}
@@ -0,0 +1,59 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestRun(t *testing.T) {
+	root, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	intermediate := strings.Replace(string(root), cache.RootBundleFilename, cache.IntermediateBundleFilename, 1)
+
+	dir := t.TempDir()
+	rootPath := filepath.Join(dir, cache.RootBundleFilename)
+	intermediatePath := filepath.Join(dir, cache.IntermediateBundleFilename)
+	if err := os.WriteFile(rootPath, root, 0644); err != nil {
+		t.Fatalf("failed to write root bundle: %v", err)
+	}
+	if err := os.WriteFile(intermediatePath, []byte(intermediate), 0644); err != nil {
+		t.Fatalf("failed to write intermediate bundle: %v", err)
+	}
+
+	t.Run("writes combined bundle", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "combined.pem")
+		if err := Run(&Opts{RootFile: rootPath, IntermediateFile: intermediatePath, Output: output}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("failed to read combined bundle: %v", err)
+		}
+		docs, err := bundle.SplitDocuments(data)
+		if err != nil {
+			t.Fatalf("SplitDocuments() error = %v", err)
+		}
+		if len(docs) != 2 {
+			t.Errorf("expected 2 documents in combined bundle, got %d", len(docs))
+		}
+	})
+
+	t.Run("rejects swapped arguments", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "combined.pem")
+		if err := Run(&Opts{RootFile: intermediatePath, IntermediateFile: rootPath, Output: output}); err == nil {
+			t.Fatal("Run() expected error when arguments are swapped")
+		}
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Error("expected no output file on error")
+		}
+	})
+}
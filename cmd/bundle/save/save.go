@@ -12,6 +12,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// saveTrustedBundle fetches and verifies the bundle assets.
+var saveTrustedBundle = apiv1beta.SaveTrustedBundle // Allow mocking in tests
+
 // Opts represents the configuration options for the save command.
 type Opts struct {
 	Date       string
@@ -45,7 +48,7 @@ verification without network access.`,
   tpmtb bundle save --local-cache
 
   # Save bundle filtered by specific vendors
-  tpmtb bundle save --vendor-ids IFX,NTC --output-dir /tmp/cache`,
+  tpmtb bundle save --vendor-id IFX,NTC -o /tmp/cache`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,8 +58,11 @@ verification without network access.`,
 
 	cmd.Flags().StringVarP(&o.Date, "date", "d", "",
 		"Bundle release date (YYYY-MM-DD), default: latest")
+	cmd.Flags().StringSliceVar(&o.VendorIDs, "vendor-id", nil,
+		"Comma-separated list of vendor IDs to filter (e.g., IFX,NTC,STM,INTC)")
 	cmd.Flags().StringSliceVar(&o.VendorIDs, "vendor-ids", nil,
 		"Comma-separated list of vendor IDs to filter (e.g., IFX,NTC,STM,INTC)")
+	cmd.Flags().MarkDeprecated("vendor-ids", "use --vendor-id instead")
 	cmd.Flags().StringVarP(&o.OutputDir, "output-dir", "o", ".",
 		"Output directory for saved files")
 	cmd.Flags().BoolVarP(&o.Force, "force", "f", false,
@@ -99,7 +105,7 @@ func Run(ctx context.Context, o *Opts) error {
 		VendorIDs: parsedVendorIDs,
 	}
 
-	resp, err := apiv1beta.SaveTrustedBundle(ctx, cfg)
+	resp, err := saveTrustedBundle(ctx, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to save bundle: %w", err)
 	}
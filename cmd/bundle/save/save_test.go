@@ -0,0 +1,108 @@
+package save
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+func mockSaveTrustedBundle(t *testing.T) *apiv1beta.SaveConfig {
+	t.Helper()
+
+	read := func(filename string) []byte {
+		data, err := testutil.ReadTestFile(filename)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", filename, err)
+		}
+		return data
+	}
+
+	var gotCfg apiv1beta.SaveConfig
+	original := saveTrustedBundle
+	saveTrustedBundle = func(_ context.Context, cfg apiv1beta.SaveConfig) (*apiv1beta.SaveResponse, error) {
+		gotCfg = cfg
+		return &apiv1beta.SaveResponse{
+			RootBundle:         read(testutil.RootBundleFile),
+			IntermediateBundle: read(testutil.RootBundleFile), // only the file presence is checked
+			Checksum:           read(testutil.ChecksumFile),
+			ChecksumSignature:  read(testutil.ChecksumSigstoreFile),
+			Provenance:         read(testutil.ProvenanceFile),
+			TrustedRoot:        read(testutil.TrustedRootFile),
+			CacheConfig:        read(testutil.CacheConfigFile),
+		}, nil
+	}
+	t.Cleanup(func() { saveTrustedBundle = original })
+
+	return &gotCfg
+}
+
+func TestRun(t *testing.T) {
+	t.Run("writes every cache file to the output directory", func(t *testing.T) {
+		gotCfg := mockSaveTrustedBundle(t)
+		outputDir := t.TempDir()
+
+		opts := &Opts{
+			Date:      testutil.BundleVersion,
+			VendorIDs: []string{"IFX", "NTC"},
+			OutputDir: outputDir,
+			Force:     true,
+		}
+		if err := Run(t.Context(), opts); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		for _, filename := range apiv1beta.CacheFilenames {
+			if _, err := os.Stat(filepath.Join(outputDir, filename)); err != nil {
+				t.Errorf("Expected %s to be written: %v", filename, err)
+			}
+		}
+
+		if gotCfg.Date != testutil.BundleVersion {
+			t.Errorf("SaveConfig.Date = %q, want %q", gotCfg.Date, testutil.BundleVersion)
+		}
+		if !slices.Equal(gotCfg.VendorIDs, []apiv1beta.VendorID{apiv1beta.IFX, apiv1beta.NTC}) {
+			t.Errorf("SaveConfig.VendorIDs = %v, want [IFX NTC]", gotCfg.VendorIDs)
+		}
+	})
+
+	t.Run("rejects invalid vendor ID", func(t *testing.T) {
+		mockSaveTrustedBundle(t)
+
+		err := Run(t.Context(), &Opts{VendorIDs: []string{"INVALID"}, OutputDir: t.TempDir(), Force: true})
+		if err == nil || !strings.Contains(err.Error(), "invalid vendor ID") {
+			t.Errorf("Run() error = %v, want invalid vendor ID error", err)
+		}
+	})
+
+	t.Run("rejects missing output directory", func(t *testing.T) {
+		mockSaveTrustedBundle(t)
+
+		err := Run(t.Context(), &Opts{OutputDir: "/nonexistent/directory", Force: true})
+		if err == nil || !strings.Contains(err.Error(), "does not exist") {
+			t.Errorf("Run() error = %v, want missing directory error", err)
+		}
+	})
+}
+
+func TestNewCommandVendorIDFlags(t *testing.T) {
+	for _, flag := range []string{"--vendor-id", "--vendor-ids"} {
+		t.Run(flag, func(t *testing.T) {
+			gotCfg := mockSaveTrustedBundle(t)
+
+			cmd := NewCommand()
+			cmd.SetArgs([]string{flag, "STM", "-o", t.TempDir(), "--force"})
+			if err := cmd.ExecuteContext(t.Context()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !slices.Equal(gotCfg.VendorIDs, []apiv1beta.VendorID{apiv1beta.STM}) {
+				t.Errorf("SaveConfig.VendorIDs = %v, want [STM]", gotCfg.VendorIDs)
+			}
+		})
+	}
+}
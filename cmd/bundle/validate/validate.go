@@ -13,6 +13,7 @@ import (
 var (
 	bundlePath string
 	quiet      bool
+	format     string
 	osExit     = os.Exit // Allow mocking in tests
 )
 
@@ -27,12 +28,21 @@ Validates the bundle structure and contents according
 to internal spec.
 
 Returns exit code 1 if validation errors are found.
-Shows up to 10 validation errors with line numbers.`,
+Shows up to 10 validation errors with line numbers.
+
+Plausibility issues, such as a bundle dated in the future, are
+reported as warnings and don't affect the exit code.
+
+With --format github (the default when GITHUB_ACTIONS=true), the findings
+are printed as GitHub Actions workflow commands so they appear as annotations.`,
 		Example: `  # Validate a bundle file
   tpmtb bundle validate tpm-ca-certificates.pem
 
   # Validate with quiet mode (only exit code)
-  tpmtb bundle validate --quiet tpm-ca-certificates.pem`,
+  tpmtb bundle validate --quiet tpm-ca-certificates.pem
+
+  # Annotate the findings in a GitHub Actions workflow
+  tpmtb bundle validate --format github tpm-ca-certificates.pem`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         run,
@@ -40,6 +50,8 @@ Shows up to 10 validation errors with line numbers.`,
 
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
 		"Suppress output, only return exit code")
+	cmd.Flags().StringVar(&format, "format", "",
+		"Output format: text or github (default: github when GITHUB_ACTIONS=true, text otherwise)")
 
 	return cmd
 }
@@ -47,6 +59,11 @@ Shows up to 10 validation errors with line numbers.`,
 func run(cmd *cobra.Command, args []string) error {
 	bundlePath = args[0]
 
+	outputFormat, err := cli.ResolveFormat(format)
+	if err != nil {
+		return err
+	}
+
 	data, err := utils.ReadFile(bundlePath)
 	if err != nil {
 		return fmt.Errorf("failed to read bundle: %w", err)
@@ -58,6 +75,16 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	if !quiet {
+		for _, warning := range validator.Warnings() {
+			if outputFormat == cli.FormatGitHub {
+				cli.DisplayAnnotation(cli.AnnotationWarning, bundlePath, warning.Line, warning.Message)
+				continue
+			}
+			cli.DisplayWarning("⚠️  Line %d: %s", warning.Line, warning.Message)
+		}
+	}
+
 	if len(errors) == 0 {
 		if !quiet {
 			cli.DisplaySuccess("✅ %s is valid", bundlePath)
@@ -68,6 +95,10 @@ func run(cmd *cobra.Command, args []string) error {
 	if !quiet {
 		cli.DisplayError("❌ %s has validation errors:", bundlePath)
 		for _, verr := range errors {
+			if outputFormat == cli.FormatGitHub {
+				cli.DisplayAnnotation(cli.AnnotationError, bundlePath, verr.Line, verr.Message)
+				continue
+			}
 			cli.DisplayStderr("  Line %d: %s\n", verr.Line, verr.Message)
 		}
 
@@ -169,6 +169,48 @@ func TestValidateCommand_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestValidateCommand_GitHubFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle.pem")
+	bundleData := []byte(`# Certificate: Test
+-----BEGIN CERTIFICATE-----
+INVALID
+-----END CERTIFICATE-----`)
+	if err := os.WriteFile(path, bundleData, 0644); err != nil {
+		t.Fatalf("failed to write test bundle: %v", err)
+	}
+
+	quiet = false
+	format = "github"
+	defer func() { format = "" }()
+
+	exitCode := -1
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = os.Exit }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := run(nil, []string{path})
+
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+
+	want := "::error file=" + path + ",line=1::bundle must start with global metadata block marker '##'\n"
+	if !contains(buf.String(), want) {
+		t.Errorf("expected annotation %q, got: %s", want, buf.String())
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && len(s) >= len(substr) &&
 		(s == substr || bytes.Contains([]byte(s), []byte(substr)))
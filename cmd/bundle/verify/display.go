@@ -52,7 +52,7 @@ func displayPolicyCriteria(cfg policy.Config, commitID string) {
 	fmt.Printf("- Predicate type must match:................ %s\n", cfg.PredicateType)
 	fmt.Printf("- Source Repository Owner URI must match:... %s\n", fmt.Sprintf("https://github.com/%s", cfg.SourceRepo.Owner))
 	fmt.Printf("- Subject Alternative Name must match regex: %s\n", cfg.BuildSANRegex())
-	fmt.Printf("- OIDC Issuer must match:................... %s\n", cfg.OIDCIssuer)
+	fmt.Printf("- OIDC Issuer must match:................... %s\n", strings.Join(cfg.AllowedOIDCIssuers, " or "))
 	fmt.Printf("- Build Workflow must match:................ %s\n", cfg.BuildWorkflowRef())
 	fmt.Printf("- Git commit ID must match:................. %s\n", commitID)
 	fmt.Printf("- Rekor entry date must match:.............. %s\n", cfg.Tag)
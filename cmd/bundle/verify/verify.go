@@ -3,10 +3,10 @@ package verify
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle/verifier"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/cosign"
@@ -18,8 +18,11 @@ import (
 
 // Opts represents the configuration options for the verify command.
 type Opts struct {
+	Intermediate       string
 	ChecksumsFile      string
 	ChecksumsSignature string
+	Provenance         string
+	TrustedRoot        string
 	CacheDir           string
 	Offline            bool
 }
@@ -48,21 +51,44 @@ The verify command performs two types of verification (both required):
    - Verifies SLSA provenance using Sigstore
    - Validates certificate identity (OIDC issuer, source repository)
 
-Both verifications must succeed for the bundle to be considered valid.`,
+Both verifications must succeed for the bundle to be considered valid.
+
+When --trusted-root is provided together with --checksums-file, --checksums-signature
+and --provenance, the verification is fully offline: neither GitHub nor the Sigstore
+TUF repository are contacted. These assets are produced by 'tpmtb bundle save'.
+
+With --intermediate, the intermediate bundle of the same release is verified as well,
+after checking that both bundles come from the same release (same date and commit).
+
+Use '-' as bundle file to read the bundle from stdin (e.g., in a shell pipeline). Its digest
+is computed in memory. As there is no directory to auto-detect the checksum files from,
+they are taken from --checksums-file and --checksums-signature or downloaded from GitHub.`,
 		Example: `  # Verify bundle with default settings
   tpmtb bundle verify tpm-ca-certificates.pem
 
   # Verify with explicit checksum files
   tpmtb bundle verify tpm-ca-certificates.pem --checksums-file checksums.txt --checksums-signature checksums.txt.sigstore.json
 
+  # Verify the root and intermediate bundles of a release
+  tpmtb bundle verify tpm-ca-certificates.pem --intermediate tpm-intermediate-ca-certificates.pem
+
   # Verify bundle from stdin
   cat tpm-ca-certificates.pem | tpmtb bundle verify -
 
+  # Verify bundle streamed from stdin with explicit verification assets
+  curl -sL https://example.com/tpm-ca-certificates.pem | tpmtb bundle verify - \
+    --checksums-file checksums.txt --checksums-signature checksums.txt.sigstore.json --provenance provenance.json
+
   # Verify bundle in offline mode using default cache directory
   tpmtb bundle verify tpm-ca-certificates.pem --offline
 
   # Verify bundle in offline mode with custom cache directory
-  tpmtb bundle verify tpm-ca-certificates.pem --offline --cache-dir /path/to/cache`,
+  tpmtb bundle verify tpm-ca-certificates.pem --offline --cache-dir /path/to/cache
+
+  # Verify bundle offline with explicit assets (e.g., produced by 'tpmtb bundle save')
+  tpmtb bundle verify tpm-ca-certificates.pem --checksums-file checksums.txt \
+    --checksums-signature checksums.txt.sigstore.json --provenance provenance.json \
+    --trusted-root trusted-root.json`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -70,14 +96,22 @@ Both verifications must succeed for the bundle to be considered valid.`,
 		},
 	}
 
+	cmd.Flags().StringVar(&o.Intermediate, "intermediate", "",
+		"Path to the intermediate bundle of the same release to verify as well (optional)")
 	cmd.Flags().StringVar(&o.ChecksumsFile, "checksums-file", "",
 		"Path to checksums.txt file (optional, default: auto-detect or download)")
 	cmd.Flags().StringVar(&o.ChecksumsSignature, "checksums-signature", "",
 		"Path to checksums.txt.sigstore.json file (optional, default: auto-detect or download)")
+	cmd.Flags().StringVar(&o.Provenance, "provenance", "",
+		"Path to provenance.json file (optional, default: fetched from GitHub)")
+	cmd.Flags().StringVar(&o.TrustedRoot, "trusted-root", "",
+		"Path to Sigstore trusted-root.json file (optional, default: fetched from the Sigstore TUF repository)")
 	cmd.Flags().StringVar(&o.CacheDir, "cache-dir", "",
 		"Cache directory path (optional, default: $HOME/.tpmtb)")
 	cmd.Flags().BoolVar(&o.Offline, "offline", false,
 		"Enable offline verification mode using cached assets")
+	cmd.MarkFlagsMutuallyExclusive("offline", "provenance")
+	cmd.MarkFlagsMutuallyExclusive("offline", "trusted-root")
 	return cmd
 }
 
@@ -87,63 +121,119 @@ func run(cmd *cobra.Command, args []string, o *Opts) error {
 	if o.CacheDir != "" && !utils.DirExists(o.CacheDir) {
 		return fmt.Errorf("cache directory does not exist: %s", o.CacheDir)
 	}
+	if bundlePath == "-" && o.Intermediate == "-" {
+		return fmt.Errorf("root and intermediate bundles cannot both be read from stdin")
+	}
 
-	var bundleDir, bundleFilename string
+	bundleDir := filepath.Dir(bundlePath)
 	if bundlePath == "-" {
-		var err error
-		bundleDir, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current working directory: %w", err)
-		}
-		bundleFilename = "stdin"
-	} else {
-		bundleDir = filepath.Dir(bundlePath)
-		bundleFilename = filepath.Base(bundlePath)
+		// There is no directory to look up the checksum files in
+		bundleDir = ""
 	}
 
-	bundleData, err := utils.ReadFile(bundlePath)
+	root, err := readBundleFile(bundlePath)
 	if err != nil {
-		return fmt.Errorf("failed to read bundle file: %w", err)
+		return err
 	}
+	bundles := []*bundleFile{root}
 
-	metadata, err := bundle.ParseMetadata(bundleData)
-	if err != nil {
-		return fmt.Errorf("failed to parse bundle metadata: %w", err)
+	if o.Intermediate != "" {
+		intermediate, err := readBundleFile(o.Intermediate)
+		if err != nil {
+			return fmt.Errorf("intermediate bundle: %w", err)
+		}
+		if err := checkConsistency(root.metadata, intermediate.metadata); err != nil {
+			cli.DisplayError("❌ Root and intermediate bundles are inconsistent")
+			return err
+		}
+		bundles = append(bundles, intermediate)
 	}
 
-	cfg := apiv1beta.VerifyConfig{
-		Bundle:         bundleData,
-		BundleMetadata: metadata,
-	}
+	displayBundleMetadata(root.metadata)
 
-	displayBundleMetadata(metadata)
+	for _, b := range bundles {
+		displayDigest(digest.ComputeSHA256(b.data), b.filename)
+	}
 
-	displayDigest(digest.ComputeSHA256(bundleData), bundleFilename)
+	cfg := apiv1beta.VerifyConfig{
+		Logger: cli.Logger(),
+	}
 
 	// Enrich config with CLI options
 	if err := enrichConfig(&cfg, *o, bundleDir); err != nil {
 		return err
 	}
 
-	fmt.Println()
-	displayTitle("Verification in progress...")
-	fmt.Println()
+	if len(bundles) > 1 {
+		fmt.Println()
+		cli.DisplaySuccess("✅ Root and intermediate bundles come from the same release")
+	}
 
-	result, err := apiv1beta.VerifyTrustedBundle(cmd.Context(), cfg)
-	if err != nil {
-		if errors.Is(err, apiv1beta.ErrBundleVerificationFailed) {
-			cli.DisplayError("❌ Verification failed")
-		} else {
-			cli.DisplayError("An error occurred during verification")
+	for _, b := range bundles {
+		title := "Verification in progress..."
+		if len(bundles) > 1 {
+			title = fmt.Sprintf("Verification of %s in progress...", b.filename)
+		}
+		fmt.Println()
+		displayTitle(title)
+		fmt.Println()
+
+		cfg.Bundle = b.data
+		cfg.BundleMetadata = b.metadata
+		result, err := apiv1beta.VerifyTrustedBundle(cmd.Context(), cfg)
+		if err != nil {
+			if errors.Is(err, apiv1beta.ErrBundleVerificationFailed) {
+				cli.DisplayError("❌ Verification of %s failed", b.filename)
+			} else {
+				cli.DisplayError("An error occurred during verification of %s", b.filename)
+			}
+			return err
 		}
-		return err
-	}
 
-	displaySuccess(result, metadata)
+		displaySuccess(result, b.metadata)
+	}
 
 	return nil
 }
 
+// bundleFile is a bundle read from disk (or stdin) along with its metadata.
+type bundleFile struct {
+	filename string
+	data     []byte
+	metadata *bundle.Metadata
+}
+
+func readBundleFile(path string) (*bundleFile, error) {
+	filename := filepath.Base(path)
+	if path == "-" {
+		filename = "stdin"
+	}
+
+	data, err := utils.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle file: %w", err)
+	}
+
+	metadata, err := bundle.ParseMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bundle metadata: %w", err)
+	}
+
+	return &bundleFile{filename: filename, data: data, metadata: metadata}, nil
+}
+
+// checkConsistency checks that the root and intermediate bundles have the expected types
+// and come from the same release.
+func checkConsistency(root, intermediate *bundle.Metadata) error {
+	if root.Type == bundle.TypeIntermediate {
+		return fmt.Errorf("expected a root bundle, got an intermediate bundle")
+	}
+	if intermediate.Type != bundle.TypeIntermediate {
+		return fmt.Errorf("expected an intermediate bundle, got a root bundle")
+	}
+	return bundle.CheckSameRelease(root, intermediate)
+}
+
 type checksumsData struct {
 	checksumData    []byte
 	checksumSigData []byte
@@ -176,6 +266,7 @@ func enrichConfig(cfg *apiv1beta.VerifyConfig, o Opts, bundleDir string) error {
 		if cacheDir == "" {
 			cacheDir = cache.CacheDir()
 		}
+		cli.DisplayDebug("loading verification assets from %s", cacheDir)
 		if err := cache.ValidateCacheFiles(cacheDir); err != nil {
 			return fmt.Errorf("offline mode requires all cache files to be present: %w", err)
 		}
@@ -204,16 +295,45 @@ func enrichConfig(cfg *apiv1beta.VerifyConfig, o Opts, bundleDir string) error {
 		}
 		cfg.Provenance = provenanceData
 	} else {
+		if o.TrustedRoot != "" {
+			// Verification is only fully offline if no asset has to be fetched
+			if o.ChecksumsFile == "" || o.ChecksumsSignature == "" || o.Provenance == "" {
+				return fmt.Errorf("--trusted-root requires --checksums-file, --checksums-signature and --provenance")
+			}
+			trustedRootData, err := utils.ReadFile(o.TrustedRoot)
+			if err != nil {
+				return fmt.Errorf("failed to read trusted root file: %w", err)
+			}
+			cfg.TrustedRoot = trustedRootData
+		}
+		if o.Provenance != "" {
+			provenanceData, err := utils.ReadFile(o.Provenance)
+			if err != nil {
+				return fmt.Errorf("failed to read provenance file: %w", err)
+			}
+			// Fail fast instead of reporting a verification failure
+			if _, err := verifier.ParseAttestation(provenanceData); err != nil {
+				return fmt.Errorf("invalid provenance file %s: not a Sigstore bundle: %w", o.Provenance, err)
+			}
+			cli.DisplayDebug("using provenance from %s instead of the GitHub API", o.Provenance)
+			cfg.Provenance = provenanceData
+		}
+
 		// Online mode: try to auto-detect or download checksum files
 		skipReadFiles := false
 		if o.ChecksumsFile == "" && o.ChecksumsSignature == "" {
-			fmt.Println("Auto-detecting checksum files...")
-			checksumPath, checksumSigPath, found := cosign.FindChecksumFiles(bundleDir)
-			if !found {
-				fmt.Println("Checksum files not found locally, will be downloaded from GitHub...")
+			if bundleDir == "" {
+				fmt.Println("Checksum files not provided, will be downloaded from GitHub...")
 				skipReadFiles = true
+			} else {
+				fmt.Println("Auto-detecting checksum files...")
+				checksumPath, checksumSigPath, found := cosign.FindChecksumFiles(bundleDir)
+				if !found {
+					fmt.Println("Checksum files not found locally, will be downloaded from GitHub...")
+					skipReadFiles = true
+				}
+				o.ChecksumsFile, o.ChecksumsSignature = checksumPath, checksumSigPath
 			}
-			o.ChecksumsFile, o.ChecksumsSignature = checksumPath, checksumSigPath
 		}
 		if !skipReadFiles {
 			result, err := readChecksumsData(o.ChecksumsFile, o.ChecksumsSignature)
@@ -1,10 +1,16 @@
 package verify
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
 	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
 	"github.com/spf13/cobra"
@@ -122,3 +128,227 @@ func TestRunWithCacheDir(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWithExplicitAssets(t *testing.T) {
+	cacheConfigData, err := json.Marshal(apiv1beta.CacheConfig{
+		Version:       testutil.BundleVersion,
+		LastTimestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal cache config: %v", err)
+	}
+	assetsDir := testutil.CreateCacheDir(t, cacheConfigData)
+	path := func(filename string) string {
+		return filepath.Join(assetsDir, filename)
+	}
+	if err := os.WriteFile(path("invalid-provenance.json"), []byte(`{"mediaType": "unknown"}`), 0644); err != nil {
+		t.Fatalf("Failed to write invalid provenance: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    *Opts
+		wantErr string
+	}{
+		{
+			name: "fully offline with saved provenance and trusted root",
+			opts: &Opts{
+				ChecksumsFile:      path(cache.ChecksumsFilename),
+				ChecksumsSignature: path(cache.ChecksumsSigFilename),
+				Provenance:         path(cache.ProvenanceFilename),
+				TrustedRoot:        path(cache.TrustedRootFilename),
+			},
+		},
+		{
+			name: "trusted root without provenance",
+			opts: &Opts{
+				ChecksumsFile:      path(cache.ChecksumsFilename),
+				ChecksumsSignature: path(cache.ChecksumsSigFilename),
+				TrustedRoot:        path(cache.TrustedRootFilename),
+			},
+			wantErr: "--trusted-root requires",
+		},
+		{
+			name: "provenance is not a Sigstore bundle",
+			opts: &Opts{
+				ChecksumsFile:      path(cache.ChecksumsFilename),
+				ChecksumsSignature: path(cache.ChecksumsSigFilename),
+				Provenance:         path("invalid-provenance.json"),
+			},
+			wantErr: "not a Sigstore bundle",
+		},
+		{
+			name: "non-existent provenance",
+			opts: &Opts{
+				Provenance: path("nonexistent.json"),
+			},
+			wantErr: "failed to read provenance file",
+		},
+		{
+			name: "non-existent trusted root",
+			opts: &Opts{
+				ChecksumsFile:      path(cache.ChecksumsFilename),
+				ChecksumsSignature: path(cache.ChecksumsSigFilename),
+				Provenance:         path(cache.ProvenanceFilename),
+				TrustedRoot:        path("nonexistent.json"),
+			},
+			wantErr: "failed to read trusted root file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.SetContext(t.Context())
+
+			err := run(cmd, []string{path(cache.RootBundleFilename)}, tt.opts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("run() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("run() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunWithIntermediate(t *testing.T) {
+	cacheConfigData, err := json.Marshal(apiv1beta.CacheConfig{
+		Version:       testutil.BundleVersion,
+		LastTimestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal cache config: %v", err)
+	}
+	assetsDir := testutil.CreateCacheDir(t, cacheConfigData)
+	path := func(filename string) string {
+		return filepath.Join(assetsDir, filename)
+	}
+
+	rootData, err := os.ReadFile(path(cache.RootBundleFilename))
+	if err != nil {
+		t.Fatalf("Failed to read root bundle: %v", err)
+	}
+	rootMetadata, err := bundle.ParseMetadata(rootData)
+	if err != nil {
+		t.Fatalf("Failed to parse root bundle metadata: %v", err)
+	}
+
+	writeIntermediate := func(t *testing.T, date, commit string) string {
+		t.Helper()
+		der, _ := testutil.GenerateTestCertWithCN(t, "Test Intermediate CA")
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Failed to parse certificate: %v", err)
+		}
+		data := bundle.BuildBundleHeader(cache.IntermediateBundleFilename, date, commit, bundle.TypeIntermediate) +
+			bundle.BuildCertificateHeader(cert, "Test Intermediate CA", "IFX") + string(bundle.EncodePEM(cert))
+
+		intermediatePath := filepath.Join(t.TempDir(), cache.IntermediateBundleFilename)
+		if err := os.WriteFile(intermediatePath, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write intermediate bundle: %v", err)
+		}
+		return intermediatePath
+	}
+
+	tests := []struct {
+		name         string
+		intermediate func(t *testing.T) string
+		wantErr      string
+	}{
+		{
+			name: "matching metadata",
+			intermediate: func(t *testing.T) string {
+				return writeIntermediate(t, rootMetadata.Date, rootMetadata.Commit)
+			},
+			// The root bundle is verified, then the generated intermediate bundle is not listed in checksums.txt
+			wantErr: cache.IntermediateBundleFilename,
+		},
+		{
+			name: "mismatched commit",
+			intermediate: func(t *testing.T) string {
+				return writeIntermediate(t, rootMetadata.Date, strings.Repeat("a", 40))
+			},
+			wantErr: "different releases",
+		},
+		{
+			name: "mismatched date",
+			intermediate: func(t *testing.T) string {
+				return writeIntermediate(t, "2025-01-01", rootMetadata.Commit)
+			},
+			wantErr: "different releases",
+		},
+		{
+			name: "root bundle given as intermediate",
+			intermediate: func(t *testing.T) string {
+				return path(cache.RootBundleFilename)
+			},
+			wantErr: "expected an intermediate bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.SetContext(t.Context())
+
+			opts := &Opts{
+				Intermediate:       tt.intermediate(t),
+				ChecksumsFile:      path(cache.ChecksumsFilename),
+				ChecksumsSignature: path(cache.ChecksumsSigFilename),
+				Provenance:         path(cache.ProvenanceFilename),
+				TrustedRoot:        path(cache.TrustedRootFilename),
+			}
+			err := run(cmd, []string{path(cache.RootBundleFilename)}, opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("run() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunFromStdin(t *testing.T) {
+	cacheConfigData, err := json.Marshal(apiv1beta.CacheConfig{
+		Version:       testutil.BundleVersion,
+		LastTimestamp: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal cache config: %v", err)
+	}
+	assetsDir := testutil.CreateCacheDir(t, cacheConfigData)
+	path := func(filename string) string {
+		return filepath.Join(assetsDir, filename)
+	}
+	bundleData, err := os.ReadFile(path(cache.RootBundleFilename))
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %v", err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+	os.Stdin = r
+
+	go func() {
+		defer w.Close()
+		w.Write(bundleData)
+	}()
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+	opts := &Opts{
+		ChecksumsFile:      path(cache.ChecksumsFilename),
+		ChecksumsSignature: path(cache.ChecksumsSigFilename),
+		Provenance:         path(cache.ProvenanceFilename),
+		TrustedRoot:        path(cache.TrustedRootFilename),
+	}
+	if err := run(cmd, []string{"-"}, opts); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package verifyall
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"github.com/spf13/cobra"
+)
+
+// maxReleases is the number of most recent releases audited (GitHub API page size limit).
+const maxReleases = 100
+
+// Opts holds the configuration for the verify-all command.
+type Opts struct {
+	Since   string
+	Workers int
+}
+
+var (
+	// newClient returns the GitHub client used to enumerate releases (overridden in tests).
+	newClient = func() *github.HTTPClient {
+		return github.NewHTTPClient()
+	}
+	// verifyAsset downloads and verifies a bundle asset of a release (overridden in tests).
+	verifyAsset = downloadAndVerify
+)
+
+// assetResult is the verification outcome of a single bundle asset.
+type assetResult struct {
+	attached bool
+	err      error
+}
+
+// releaseResult is the verification outcome of a release.
+type releaseResult struct {
+	tag          string
+	root         assetResult
+	intermediate assetResult
+}
+
+func (r releaseResult) failed() bool {
+	return r.root.err != nil || r.intermediate.err != nil
+}
+
+// NewCommand creates the verify-all command.
+func NewCommand() *cobra.Command {
+	opts := &Opts{}
+
+	cmd := &cobra.Command{
+		Use:   "verify-all",
+		Short: "verify every published TPM trust bundle release",
+		Long: `Verify the integrity and provenance of every published TPM trust bundle release.
+
+Releases are enumerated from GitHub (up to the 100 most recent) and the root and
+intermediate bundles attached to each of them are downloaded and verified concurrently.
+
+This is meant as a periodic supply-chain regression test: a release that used to verify
+can start failing after a trusted root rotation or a revoked signing identity.
+
+A pass/fail matrix is printed and the command exits with a non-zero status if any
+release fails verification.`,
+		Example: `  # Verify all published releases
+  tpmtb bundle verify-all
+
+  # Verify releases published after a given date using 4 workers
+  tpmtb bundle verify-all --since 2025-12-03 -j 4`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Since, "since", "",
+		"Only verify releases with a tag date strictly after this date (YYYY-MM-DD)")
+	cmd.Flags().IntVarP(&opts.Workers, "workers", "j", 0,
+		fmt.Sprintf("Number of workers to use (0=auto-detect, max=%d)", concurrency.MaxWorkers))
+
+	return cmd
+}
+
+// Run executes the verify-all command.
+func Run(ctx context.Context, out io.Writer, o *Opts) error {
+	if o.Workers < 0 || o.Workers > concurrency.MaxWorkers {
+		return fmt.Errorf("concurrency value %d must be between 0 and %d", o.Workers, concurrency.MaxWorkers)
+	}
+	if o.Since != "" {
+		if err := bundle.ValidateDate(o.Since); err != nil {
+			return fmt.Errorf("invalid --since date: %w", err)
+		}
+	}
+
+	releases, err := newClient().GetReleases(ctx, github.SourceRepo, github.ReleasesOptions{
+		PageSize:  maxReleases,
+		SortOrder: github.SortOrderDesc,
+		Since:     o.Since,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	if len(releases) == 0 {
+		fmt.Fprintln(out, "No bundle releases found")
+		return nil
+	}
+
+	results := concurrency.Execute(o.Workers, releases, func(_ int, release github.Release) releaseResult {
+		result := releaseResult{tag: release.TagName}
+		if release.HasAsset(cache.RootBundleFilename) {
+			result.root = assetResult{attached: true, err: verifyAsset(ctx, release.TagName, cache.RootBundleFilename)}
+		} else {
+			result.root = assetResult{err: fmt.Errorf("%s is not attached to the release", cache.RootBundleFilename)}
+		}
+		// Older releases predate the intermediate bundle
+		if release.HasAsset(cache.IntermediateBundleFilename) {
+			result.intermediate = assetResult{attached: true, err: verifyAsset(ctx, release.TagName, cache.IntermediateBundleFilename)}
+		}
+		return result
+	})
+
+	if err := printMatrix(out, results); err != nil {
+		return err
+	}
+
+	var failures int
+	var errs []error
+	for _, result := range results {
+		if !result.failed() {
+			continue
+		}
+		failures++
+		if result.root.err != nil {
+			cli.DisplayStderr("%s (%s): %v\n", result.tag, cache.RootBundleFilename, result.root.err)
+			errs = append(errs, result.root.err)
+		}
+		if result.intermediate.err != nil {
+			cli.DisplayStderr("%s (%s): %v\n", result.tag, cache.IntermediateBundleFilename, result.intermediate.err)
+			errs = append(errs, result.intermediate.err)
+		}
+	}
+
+	if failures > 0 {
+		cli.DisplayError("❌ %d of %d releases failed verification", failures, len(results))
+		// The causes are kept so that the exit code reflects the failure class
+		return &failuresError{count: failures, errs: errs}
+	}
+
+	cli.DisplaySuccess("✅ All %d releases verified successfully", len(results))
+	return nil
+}
+
+// failuresError reports the releases which failed verification.
+type failuresError struct {
+	count int
+	errs  []error
+}
+
+func (e *failuresError) Error() string {
+	return fmt.Sprintf("%d releases failed verification", e.count)
+}
+
+func (e *failuresError) Unwrap() []error {
+	return e.errs
+}
+
+// downloadAndVerify downloads a bundle asset from a release and verifies it.
+func downloadAndVerify(ctx context.Context, tag, assetName string) error {
+	data, err := newClient().DownloadReleaseAsset(ctx, github.SourceRepo, tag, assetName)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", assetName, err)
+	}
+
+	// The cache is bypassed so that every asset is fetched and verified from scratch
+	if _, err := apiv1beta.VerifyTrustedBundle(ctx, apiv1beta.VerifyConfig{
+		Bundle:            data,
+		DisableLocalCache: true,
+		Logger:            cli.Logger(),
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// printMatrix writes the pass/fail matrix of the verified releases.
+func printMatrix(out io.Writer, results []releaseResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tROOT\tINTERMEDIATE")
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", result.tag, status(result.root), status(result.intermediate))
+	}
+	return w.Flush()
+}
+
+func status(r assetResult) string {
+	switch {
+	case r.err != nil:
+		return "FAIL"
+	case !r.attached:
+		return "-"
+	default:
+		return "PASS"
+	}
+}
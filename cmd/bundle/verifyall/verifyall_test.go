@@ -0,0 +1,117 @@
+package verifyall
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+// releasesHTTPClient serves a fixed list of releases.
+type releasesHTTPClient struct {
+	releases []github.Release
+}
+
+func (c *releasesHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := json.Marshal(c.releases)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Header: make(http.Header)}, nil
+}
+
+func mockReleases(t *testing.T, failing map[string]bool) *[]string {
+	t.Helper()
+
+	oldClient, oldVerify := newClient, verifyAsset
+	t.Cleanup(func() { newClient, verifyAsset = oldClient, oldVerify })
+
+	root := github.Asset{Name: cache.RootBundleFilename}
+	intermediate := github.Asset{Name: cache.IntermediateBundleFilename}
+	releases := []github.Release{
+		{TagName: "2026-01-15", Assets: []github.Asset{root, intermediate}},
+		{TagName: "2025-12-04", Assets: []github.Asset{root, intermediate}},
+		{TagName: "2025-12-03", Assets: []github.Asset{root}},
+	}
+	newClient = func() *github.HTTPClient {
+		return github.NewHTTPClient(&releasesHTTPClient{releases: releases})
+	}
+
+	var mu sync.Mutex
+	var verified []string
+	verifyAsset = func(_ context.Context, tag, assetName string) error {
+		mu.Lock()
+		verified = append(verified, tag+"/"+assetName)
+		mu.Unlock()
+		if failing[tag+"/"+assetName] {
+			return fmt.Errorf("%w: signing identity mismatch", apiv1beta.ErrBundleVerificationFailed)
+		}
+		return nil
+	}
+	return &verified
+}
+
+func TestRun(t *testing.T) {
+	t.Run("all releases verify", func(t *testing.T) {
+		verified := mockReleases(t, nil)
+
+		var out bytes.Buffer
+		if err := Run(t.Context(), &out, &Opts{Workers: 2}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(*verified) != 5 {
+			t.Errorf("expected 5 verified assets, got %d: %v", len(*verified), *verified)
+		}
+		if !strings.Contains(out.String(), "2025-12-03  PASS  -") {
+			t.Errorf("unexpected matrix:\n%s", out.String())
+		}
+	})
+
+	t.Run("failing release", func(t *testing.T) {
+		mockReleases(t, map[string]bool{"2025-12-04/" + cache.IntermediateBundleFilename: true})
+
+		var out bytes.Buffer
+		err := Run(t.Context(), &out, &Opts{})
+		if err == nil || !strings.Contains(err.Error(), "1 releases failed verification") {
+			t.Fatalf("Run() error = %v, want failure", err)
+		}
+		if !errors.Is(err, apiv1beta.ErrBundleVerificationFailed) {
+			t.Errorf("Run() error = %v, want it to wrap %v", err, apiv1beta.ErrBundleVerificationFailed)
+		}
+		if !strings.Contains(out.String(), "2025-12-04  PASS  FAIL") {
+			t.Errorf("unexpected matrix:\n%s", out.String())
+		}
+	})
+
+	t.Run("since filter", func(t *testing.T) {
+		verified := mockReleases(t, nil)
+
+		var out bytes.Buffer
+		if err := Run(t.Context(), &out, &Opts{Since: "2025-12-04"}); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(*verified) != 2 {
+			t.Errorf("expected 2 verified assets, got %d: %v", len(*verified), *verified)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		if err := Run(t.Context(), io.Discard, &Opts{Workers: concurrency.MaxWorkers + 1}); err == nil {
+			t.Error("Run() expected error for too many workers")
+		}
+		if err := Run(t.Context(), io.Discard, &Opts{Since: "not-a-date"}); err == nil {
+			t.Error("Run() expected error for invalid since date")
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package cache
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the cache command with its subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "inspect and manage the local bundle cache",
+		Long:  `Inspect or clear the local cache ($HOME/.tpmtb by default) used by the SDK and the CLI.`,
+	}
+
+	cmd.AddCommand(newInfoCommand())
+	cmd.AddCommand(newClearCommand())
+
+	return cmd
+}
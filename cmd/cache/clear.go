@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+type clearOptions struct {
+	path  string
+	force bool
+}
+
+func newClearCommand() *cobra.Command {
+	opts := &clearOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "remove the cached bundle and its verification assets",
+		Long: `Remove the cached bundle, its verification assets and the cached attestations.
+
+Only the files managed by tpmtb are removed: the cache directory itself and any
+other file it contains are left untouched. The next SDK or CLI call fetches the
+bundle again.`,
+		Example: `  # Clear the default cache directory ($HOME/.tpmtb)
+  tpmtb cache clear
+
+  # Clear a custom cache directory without prompting
+  tpmtb cache clear --path /path/to/cache --force`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runClear(cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.path, "path", "", "Cache directory path (default: $HOME/.tpmtb)")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Remove files without prompting")
+
+	return cmd
+}
+
+func runClear(out io.Writer, opts *clearOptions) error {
+	cacheDir := opts.path
+	if cacheDir == "" {
+		cacheDir = cache.CacheDir()
+	}
+	cacheDir = filepath.Clean(cacheDir)
+
+	if !opts.force {
+		cli.DisplayWarning("All cached files in %s will be removed.", cacheDir)
+		if !cli.PromptConfirmation("Continue?") {
+			fmt.Fprintln(out, "Operation cancelled")
+			return nil
+		}
+	}
+
+	var removed int
+	for _, filename := range cache.Filenames {
+		err := os.Remove(filepath.Join(cacheDir, filename))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove %s: %w", filename, err)
+		}
+		removed++
+	}
+
+	attestationsDir := filepath.Join(cacheDir, cache.AttestationsDirName)
+	if _, err := os.Stat(attestationsDir); err == nil {
+		if err := os.RemoveAll(attestationsDir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", cache.AttestationsDirName, err)
+		}
+		removed++
+	}
+
+	fmt.Fprintf(out, "Removed %d cache entries from %s\n", removed, cacheDir)
+	return nil
+}
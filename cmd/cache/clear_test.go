@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+func TestRunClear(t *testing.T) {
+	dir := populateCache(t, apiv1beta.CacheConfig{
+		Version:       testutil.BundleVersion,
+		LastTimestamp: time.Now(),
+	})
+	if err := os.MkdirAll(filepath.Join(dir, cache.AttestationsDirName), 0700); err != nil {
+		t.Fatalf("Failed to create attestations directory: %v", err)
+	}
+	unrelated := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(unrelated, []byte("keep me"), 0644); err != nil {
+		t.Fatalf("Failed to write unrelated file: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runClear(&out, &clearOptions{path: dir, force: true}); err != nil {
+		t.Fatalf("runClear() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Removed 3 cache entries") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+
+	for _, name := range []string{cache.RootBundleFilename, cache.ConfigFilename, cache.AttestationsDirName} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", name)
+		}
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("unrelated file should be kept: %v", err)
+	}
+
+	// Clearing an empty cache is a no-op
+	out.Reset()
+	if err := runClear(&out, &clearOptions{path: dir, force: true}); err != nil {
+		t.Fatalf("runClear() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Removed 0 cache entries") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
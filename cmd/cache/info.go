@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"github.com/spf13/cobra"
+)
+
+type infoOptions struct {
+	path string
+}
+
+func newInfoCommand() *cobra.Command {
+	opts := &infoOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "display the cache directory and its contents",
+		Long: `Display the resolved cache directory, the files it contains and the cached
+bundle version.
+
+Missing required files are flagged: the verification assets are only required
+when the cached bundle was not persisted with verification skipped. The
+intermediate bundle and the trusted root (offline mode) are optional.`,
+		Example: `  # Inspect the default cache directory ($HOME/.tpmtb)
+  tpmtb cache info
+
+  # Inspect a custom cache directory
+  tpmtb cache info --path /path/to/cache`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInfo(cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.path, "path", "", "Cache directory path (default: $HOME/.tpmtb)")
+
+	return cmd
+}
+
+func runInfo(out io.Writer, opts *infoOptions) error {
+	cacheDir := opts.path
+	if cacheDir == "" {
+		cacheDir = cache.CacheDir()
+	}
+	cacheDir = filepath.Clean(cacheDir)
+
+	fmt.Fprintf(out, "Cache directory: %s\n", cacheDir)
+	if !utils.DirExists(cacheDir) {
+		fmt.Fprintln(out, "The cache directory does not exist")
+		return nil
+	}
+
+	var cacheCfg *apiv1beta.CacheConfig
+	if data, err := cache.LoadFile(cacheDir, cache.ConfigFilename); err == nil {
+		cacheCfg = &apiv1beta.CacheConfig{}
+		if err := json.Unmarshal(data, cacheCfg); err != nil {
+			cli.DisplayWarning("⚠️  Failed to parse %s: %v", cache.ConfigFilename, err)
+			cacheCfg = nil
+		}
+	}
+
+	if cacheCfg != nil {
+		fmt.Fprintf(out, "Version:         %s\n", cacheCfg.Version)
+		if !cacheCfg.LastTimestamp.IsZero() {
+			fmt.Fprintf(out, "Last update:     %s (%s ago)\n",
+				cacheCfg.LastTimestamp.UTC().Format(time.RFC3339),
+				time.Since(cacheCfg.LastTimestamp).Truncate(time.Second))
+		}
+		fmt.Fprintf(out, "Verification:    %s\n", verificationStatus(cacheCfg.SkipVerify))
+	}
+	if data, err := cache.LoadFile(cacheDir, cache.RootBundleFilename); err == nil {
+		if metadata, err := bundle.ParseMetadata(data); err == nil {
+			fmt.Fprintf(out, "Commit:          %s\n", metadata.Commit)
+		}
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  FILE\tSTATUS\tBYTES")
+	var missing []string
+	for _, filename := range cache.Filenames {
+		info, err := os.Stat(filepath.Join(cacheDir, filename))
+		if err == nil {
+			fmt.Fprintf(w, "  %s\tpresent\t%d\n", filename, info.Size())
+			continue
+		}
+		if isRequired(filename, cacheCfg) {
+			missing = append(missing, filename)
+			fmt.Fprintf(w, "  %s\tmissing (required)\t-\n", filename)
+		} else {
+			fmt.Fprintf(w, "  %s\tmissing\t-\n", filename)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		fmt.Fprintln(out)
+		cli.DisplayWarning("⚠️  %d required file(s) missing: %v", len(missing), missing)
+	}
+	return nil
+}
+
+// isRequired reports whether a cache file is needed to load the cached bundle.
+func isRequired(filename string, cacheCfg *apiv1beta.CacheConfig) bool {
+	switch filename {
+	case cache.RootBundleFilename, cache.ConfigFilename:
+		return true
+	case cache.ChecksumsFilename, cache.ChecksumsSigFilename, cache.ProvenanceFilename:
+		return cacheCfg == nil || !cacheCfg.SkipVerify
+	default:
+		// The intermediate bundle is absent from older releases and
+		// the trusted root is only needed in offline mode
+		return false
+	}
+}
+
+func verificationStatus(skipVerify bool) string {
+	if skipVerify {
+		return "skipped"
+	}
+	return "enabled"
+}
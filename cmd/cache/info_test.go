@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+// populateCache writes a root bundle and its config to a temporary cache directory.
+func populateCache(t *testing.T, cfg apiv1beta.CacheConfig) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	rootBundle, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	configData, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	for filename, data := range map[string][]byte{
+		cache.RootBundleFilename: rootBundle,
+		cache.ConfigFilename:     configData,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, filename), data, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", filename, err)
+		}
+	}
+	return dir
+}
+
+func TestRunInfo(t *testing.T) {
+	t.Run("populated cache", func(t *testing.T) {
+		dir := populateCache(t, apiv1beta.CacheConfig{
+			Version:       testutil.BundleVersion,
+			LastTimestamp: time.Now().Add(-time.Hour),
+		})
+
+		var out bytes.Buffer
+		if err := runInfo(&out, &infoOptions{path: dir}); err != nil {
+			t.Fatalf("runInfo() error = %v", err)
+		}
+
+		for _, want := range []string{
+			"Cache directory: " + dir,
+			"Version:         " + testutil.BundleVersion,
+			"Commit:",
+			"Verification:    enabled",
+		} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+		assertFileStatus(t, out.String(), cache.RootBundleFilename, "present")
+		assertFileStatus(t, out.String(), cache.ChecksumsFilename, "missing (required)")
+		assertFileStatus(t, out.String(), cache.IntermediateBundleFilename, "missing")
+	})
+
+	t.Run("verification skipped", func(t *testing.T) {
+		dir := populateCache(t, apiv1beta.CacheConfig{
+			Version:    testutil.BundleVersion,
+			SkipVerify: true,
+		})
+
+		var out bytes.Buffer
+		if err := runInfo(&out, &infoOptions{path: dir}); err != nil {
+			t.Fatalf("runInfo() error = %v", err)
+		}
+		assertFileStatus(t, out.String(), cache.ChecksumsFilename, "missing")
+	})
+
+	t.Run("empty cache", func(t *testing.T) {
+		dir := t.TempDir()
+
+		var out bytes.Buffer
+		if err := runInfo(&out, &infoOptions{path: dir}); err != nil {
+			t.Fatalf("runInfo() error = %v", err)
+		}
+		if strings.Contains(out.String(), "Version:") {
+			t.Errorf("unexpected version in output:\n%s", out.String())
+		}
+		for _, filename := range []string{cache.RootBundleFilename, cache.ConfigFilename} {
+			assertFileStatus(t, out.String(), filename, "missing (required)")
+		}
+	})
+
+	t.Run("nonexistent cache", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := runInfo(&out, &infoOptions{path: filepath.Join(t.TempDir(), "missing")}); err != nil {
+			t.Fatalf("runInfo() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "does not exist") {
+			t.Errorf("expected nonexistent directory message:\n%s", out.String())
+		}
+	})
+}
+
+// assertFileStatus checks the status column displayed for a cache file.
+func assertFileStatus(t *testing.T, out, filename, status string) {
+	t.Helper()
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != filename {
+			continue
+		}
+		if got := strings.Join(fields[1:len(fields)-1], " "); got != status {
+			t.Errorf("%s status = %q, want %q", filename, got, status)
+		}
+		return
+	}
+	t.Errorf("%s not listed in output:\n%s", filename, out)
+}
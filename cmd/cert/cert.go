@@ -0,0 +1,18 @@
+package cert
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCommand creates the cert command with its subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cert",
+		Short: "inspect individual certificates",
+		Long:  `Inspect certificates before adding them to the configuration files.`,
+	}
+
+	cmd.AddCommand(newFingerprintCommand())
+
+	return cmd
+}
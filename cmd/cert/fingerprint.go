@@ -0,0 +1,153 @@
+package cert
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/fingerprint"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// stdinPath is the file name (or argument) reading the certificate from stdin.
+const stdinPath = "-"
+
+var supportedAlgorithms = []string{fingerprint.SHA1, fingerprint.SHA256, fingerprint.SHA384, fingerprint.SHA512}
+
+// downloadClient downloads the certificate served by --url.
+var downloadClient = download.NewClient() // Allow mocking in tests
+
+type fingerprintOptions struct {
+	url        string
+	file       string
+	algorithms []string
+}
+
+func newFingerprintCommand() *cobra.Command {
+	opts := &fingerprintOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "fingerprint (-u URL | -f FILE | -)",
+		Short: "compute the fingerprints of a certificate",
+		Long: `Compute the fingerprints of a certificate downloaded from an HTTPS URL, read
+from a file or from stdin ("-"). DER and PEM encodings are supported; every
+certificate of a PEM file is printed.
+
+Fingerprints are printed in the format expected by the configuration files
+(uppercase hexadecimal separated by colons), alongside the subject, issuer and
+validity period of the certificate for sanity checks.`,
+		Example: `  # Compute the SHA256 fingerprint of a certificate served by a vendor
+  tpmtb cert fingerprint -u "https://example.com/cert.crt"
+
+  # Compute the SHA1 and SHA256 fingerprints of a local certificate
+  tpmtb cert fingerprint -f cert.pem -a sha1,sha256
+
+  # Read the certificate from stdin
+  cat cert.pem | tpmtb cert fingerprint -`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if args[0] != stdinPath || opts.file != "" {
+					return fmt.Errorf("unexpected argument %q (use --file to read a certificate file)", args[0])
+				}
+				opts.file = stdinPath
+			}
+			return runFingerprint(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "HTTPS URL of the certificate to download")
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", `Path of the certificate file ("-" for stdin)`)
+	cmd.Flags().StringSliceVarP(&opts.algorithms, "hash-algorithm", "a", []string{fingerprint.SHA256},
+		fmt.Sprintf("Hash algorithm(s) to use, comma-separated (%s)", strings.Join(supportedAlgorithms, ", ")))
+	cmd.MarkFlagsMutuallyExclusive("url", "file")
+
+	return cmd
+}
+
+func runFingerprint(ctx context.Context, in io.Reader, out io.Writer, opts *fingerprintOptions) error {
+	algorithms, err := parseAlgorithms(opts.algorithms)
+	if err != nil {
+		return err
+	}
+
+	certs, err := loadCertificates(ctx, in, opts)
+	if err != nil {
+		return err
+	}
+
+	for i, cert := range certs {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Subject:     %s\n", cert.Subject)
+		fmt.Fprintf(out, "Issuer:      %s\n", cert.Issuer)
+		fmt.Fprintf(out, "Not Before:  %s\n", cert.NotBefore.UTC().Format(time.RFC3339))
+		fmt.Fprintf(out, "Not After:   %s\n", cert.NotAfter.UTC().Format(time.RFC3339))
+		fmt.Fprintln(out, "fingerprint:")
+		for _, algorithm := range algorithms {
+			fmt.Fprintf(out, "    %s: %q\n", algorithm, fingerprint.New(cert.Raw, algorithm))
+		}
+	}
+	return nil
+}
+
+// parseAlgorithms validates the requested hash algorithms, dropping duplicates.
+func parseAlgorithms(values []string) ([]string, error) {
+	var algorithms []string
+	for _, value := range values {
+		algorithm := strings.ToLower(strings.TrimSpace(value))
+		if !slices.Contains(supportedAlgorithms, algorithm) {
+			return nil, fmt.Errorf("invalid hash algorithm '%s', must be one of: %s", value, strings.Join(supportedAlgorithms, ", "))
+		}
+		if !slices.Contains(algorithms, algorithm) {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	if len(algorithms) == 0 {
+		return nil, fmt.Errorf("at least one hash algorithm must be provided")
+	}
+	return algorithms, nil
+}
+
+// loadCertificates returns the certificates served by the URL, or read from the file or stdin.
+func loadCertificates(ctx context.Context, in io.Reader, opts *fingerprintOptions) ([]*x509.Certificate, error) {
+	switch {
+	case opts.url != "" && opts.file != "":
+		return nil, fmt.Errorf("--url and --file are mutually exclusive")
+	case opts.url != "":
+		if !strings.HasPrefix(strings.ToLower(opts.url), "https://") {
+			return nil, fmt.Errorf("invalid URL scheme: %s (must use HTTPS)", opts.url)
+		}
+		return downloadClient.DownloadCertificates(ctx, opts.url)
+	case opts.file == stdinPath:
+		data, err := io.ReadAll(io.LimitReader(in, utils.DefaultMaxFileSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate from stdin: %w", err)
+		}
+		return parseCertificates(data, "stdin")
+	case opts.file != "":
+		data, err := utils.ReadFile(opts.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate file: %w", err)
+		}
+		return parseCertificates(data, opts.file)
+	default:
+		return nil, fmt.Errorf("a certificate source is required: --url, --file or - (stdin)")
+	}
+}
+
+func parseCertificates(data []byte, source string) ([]*x509.Certificate, error) {
+	certs, err := download.ParseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate from %s: %w", source, err)
+	}
+	return certs, nil
+}
@@ -0,0 +1,149 @@
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/fingerprint"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestRunFingerprint(t *testing.T) {
+	der, _ := testutil.GenerateTestCertWithCN(t, "Test Root CA")
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	assertOutput := func(t *testing.T, output string, algorithms ...string) {
+		t.Helper()
+		for _, want := range []string{
+			"Subject:     " + cert.Subject.String(),
+			"Issuer:      " + cert.Issuer.String(),
+			"fingerprint:",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Output missing %q:\n%s", want, output)
+			}
+		}
+		for _, algorithm := range algorithms {
+			fp := fingerprint.New(der, algorithm)
+			if !fingerprint.IsValid(fp) {
+				t.Fatalf("Fingerprint %q is not in the canonical format", fp)
+			}
+			if want := fmt.Sprintf("    %s: %q\n", algorithm, fp); !strings.Contains(output, want) {
+				t.Errorf("Output missing %q:\n%s", want, output)
+			}
+		}
+	}
+
+	t.Run("reads DER and PEM files", func(t *testing.T) {
+		dir := t.TempDir()
+		for name, data := range map[string][]byte{"cert.der": der, "cert.pem": pemData} {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", name, err)
+			}
+
+			var out bytes.Buffer
+			opts := &fingerprintOptions{file: path, algorithms: []string{"sha256"}}
+			if err := runFingerprint(context.Background(), nil, &out, opts); err != nil {
+				t.Fatalf("runFingerprint(%s) error = %v", name, err)
+			}
+			assertOutput(t, out.String(), fingerprint.SHA256)
+		}
+	})
+
+	t.Run("reads stdin with multiple algorithms", func(t *testing.T) {
+		var out bytes.Buffer
+		opts := &fingerprintOptions{file: stdinPath, algorithms: []string{"sha1", "SHA256", "sha1"}}
+		if err := runFingerprint(context.Background(), bytes.NewReader(pemData), &out, opts); err != nil {
+			t.Fatalf("runFingerprint() error = %v", err)
+		}
+		assertOutput(t, out.String(), fingerprint.SHA1, fingerprint.SHA256)
+		if n := strings.Count(out.String(), "sha1:"); n != 1 {
+			t.Errorf("Expected sha1 fingerprint once, got %d", n)
+		}
+	})
+
+	t.Run("prints every certificate of a PEM file", func(t *testing.T) {
+		other, _ := testutil.GenerateTestCertWithCN(t, "Other Root CA")
+		chain := append(bytes.Clone(pemData), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other})...)
+
+		var out bytes.Buffer
+		opts := &fingerprintOptions{file: stdinPath, algorithms: []string{"sha256"}}
+		if err := runFingerprint(context.Background(), bytes.NewReader(chain), &out, opts); err != nil {
+			t.Fatalf("runFingerprint() error = %v", err)
+		}
+		if !strings.Contains(out.String(), fingerprint.New(other, fingerprint.SHA256)) {
+			t.Errorf("Output missing the second certificate:\n%s", out.String())
+		}
+	})
+
+	t.Run("downloads certificate from URL", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(der)
+		}))
+		defer server.Close()
+
+		originalClient := downloadClient
+		downloadClient = download.NewClient(server.Client())
+		defer func() { downloadClient = originalClient }()
+
+		var out bytes.Buffer
+		opts := &fingerprintOptions{url: server.URL + "/cert.crt", algorithms: []string{"sha384"}}
+		if err := runFingerprint(context.Background(), nil, &out, opts); err != nil {
+			t.Fatalf("runFingerprint() error = %v", err)
+		}
+		assertOutput(t, out.String(), fingerprint.SHA384)
+	})
+
+	errorTests := []struct {
+		name    string
+		opts    *fingerprintOptions
+		input   []byte
+		wantErr string
+	}{
+		{
+			name:    "rejects missing source",
+			opts:    &fingerprintOptions{algorithms: []string{"sha256"}},
+			wantErr: "a certificate source is required",
+		},
+		{
+			name:    "rejects HTTP URL",
+			opts:    &fingerprintOptions{url: "http://example.com/cert.crt", algorithms: []string{"sha256"}},
+			wantErr: "must use HTTPS",
+		},
+		{
+			name:    "rejects invalid algorithm",
+			opts:    &fingerprintOptions{file: stdinPath, algorithms: []string{"sha256", "md5"}},
+			input:   der,
+			wantErr: "invalid hash algorithm 'md5'",
+		},
+		{
+			name:    "rejects invalid certificate",
+			opts:    &fingerprintOptions{file: stdinPath, algorithms: []string{"sha256"}},
+			input:   []byte("not a certificate"),
+			wantErr: "failed to parse certificate from stdin",
+		},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runFingerprint(context.Background(), bytes.NewReader(tt.input), &bytes.Buffer{}, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("runFingerprint() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package certificates
 import (
 	"context"
 	"crypto/x509"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"slices"
 	"strings"
 
@@ -25,15 +29,28 @@ const (
 	sha512 = "sha512"
 )
 
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
 // AddOptions holds options for the add command.
 type AddOptions struct {
-	ConfigPath    string
-	VendorID      string
-	Name          string
-	URL           string
-	Fingerprint   string
-	HashAlgorithm string
-	Concurrency   int
+	ConfigPath        string
+	VendorID          string
+	Name              string
+	URL               string
+	Fingerprint       string
+	HashAlgorithm     string
+	Concurrency       int
+	Output            string
+	CreateVendor      bool
+	VendorName        string
+	IncludeChain      bool
+	ClientCert        string
+	ClientKey         string
+	CAFile            string
+	NoFollowRedirects bool
 }
 
 func newAddCommand() *cobra.Command {
@@ -55,7 +72,25 @@ Multiple URLs can be provided by separating them with commas. When multiple URLs
 
 If no fingerprint is provided, it will be calculated automatically using the specified
 hash algorithm (default: SHA256). Use -a to specify a different algorithm (sha1, sha256, sha384, sha512).
-When a fingerprint is provided, the hash algorithm is automatically inferred from it.`,
+When a fingerprint is provided, the hash algorithm is automatically inferred from it.
+
+If the vendor is not present in the configuration file yet, use --create-vendor together
+with --vendor-name to create it (inserted in alphabetical order by ID) before adding the
+certificates. The vendor ID must be registered in the TCG TPM Vendor ID Registry.
+
+Some vendor endpoints serve a PEM file containing a whole chain (e.g., a root followed by
+its intermediates). By default only the first certificate is added; use --include-chain to
+add every certificate of the file. The additional certificates are named after their CN,
+get a SHA fingerprint computed with the selected hash algorithm, and are stored with the
+URL suffixed by their position in the file (e.g., "#2"); the fragment is never sent to the
+server. Certificates already present in the configuration are skipped.
+
+Vendor portals requiring mutual TLS are supported: use --client-cert and --client-key
+to present a client certificate when downloading. Use --ca-file to only trust servers
+whose certificate is issued by the given CA(s) instead of the system trust store.
+
+Redirects (e.g., to a vendor CDN) are followed as long as they stay on HTTPS, up to
+5 hops. Use --no-follow-redirects to fail on any redirect instead.`,
 		Example: `  # Add a single certificate with automatic SHA256 fingerprint
   tpmtb config certificates add -i STM -u "https://example.com/cert.crt" -n "My Certificate"
 
@@ -72,7 +107,22 @@ When a fingerprint is provided, the hash algorithm is automatically inferred fro
   tpmtb config certificates add -i STM -u "https://example.com/cert1.crt,https://example.com/cert2.crt" -a sha384
 
   # Add multiple certificates with specific SHA256 fingerprints
-  tpmtb config certificates add -i STM -u "https://example.com/cert1.crt,https://example.com/cert2.crt" -f "SHA256:AB:CD:...,SHA256:12:34:..."`,
+  tpmtb config certificates add -i STM -u "https://example.com/cert1.crt,https://example.com/cert2.crt" -f "SHA256:AB:CD:...,SHA256:12:34:..."
+
+  # Add a certificate for a vendor not yet present in the configuration file
+  tpmtb config certificates add -i NTC -u "https://example.com/cert.crt" --create-vendor --vendor-name "Nuvoton Technology"
+
+  # Add every certificate served by a PEM file containing a chain
+  tpmtb config certificates add -i STM -u "https://example.com/chain.pem" --include-chain
+
+  # Download from a portal requiring a client certificate (mTLS)
+  tpmtb config certificates add -i STM -u "https://portal.internal/cert.crt" --client-cert client.pem --client-key client-key.pem
+
+  # Pin the CA expected to have issued the vendor server certificate
+  tpmtb config certificates add -i STM -u "https://example.com/cert.crt" --ca-file vendor-ca.pem
+
+  # Print a machine-readable summary of added and failed certificates
+  tpmtb config certificates add -i STM -u "https://example.com/cert1.crt,https://example.com/cert2.crt" -o json`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return Run(cmd.Context(), opts)
@@ -87,6 +137,14 @@ When a fingerprint is provided, the hash algorithm is automatically inferred fro
 	cmd.Flags().StringVarP(&opts.HashAlgorithm, "hash-algorithm", "a", "sha256", "Hash algorithm to use for fingerprint calculation (sha1, sha256, sha384, sha512)")
 	cmd.Flags().IntVarP(&opts.Concurrency, "workers", "j", 0,
 		fmt.Sprintf("Number of workers to use for parallel downloads (0=auto-detect, max=%d)", concurrency.MaxWorkers))
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", outputText, "Output format: text or json")
+	cmd.Flags().BoolVar(&opts.CreateVendor, "create-vendor", false, "Create the vendor if it is not present in the configuration file")
+	cmd.Flags().StringVar(&opts.VendorName, "vendor-name", "", "Name of the vendor to create (required with --create-vendor)")
+	cmd.Flags().BoolVar(&opts.IncludeChain, "include-chain", false, "Add every certificate served by each URL instead of only the first one")
+	cmd.Flags().StringVar(&opts.ClientCert, "client-cert", "", "Path to a PEM client certificate presented to servers requiring mutual TLS")
+	cmd.Flags().StringVar(&opts.ClientKey, "client-key", "", "Path to the PEM private key of the client certificate")
+	cmd.Flags().BoolVar(&opts.NoFollowRedirects, "no-follow-redirects", false, "Fail instead of following HTTP redirects")
+	cmd.Flags().StringVar(&opts.CAFile, "ca-file", "", "Path to a PEM file with the CA(s) trusted for the server certificates (default: system roots)")
 
 	cmd.MarkFlagRequired("vendor-id")
 	cmd.MarkFlagRequired("url")
@@ -94,11 +152,21 @@ When a fingerprint is provided, the hash algorithm is automatically inferred fro
 	return cmd
 }
 
+// outputWriter is where the JSON summary is written.
+var outputWriter io.Writer = os.Stdout // Allow mocking in tests
+
+// downloadClientGetter builds the download client shared by all download workers.
+var downloadClientGetter = func(workers int, opts ...download.HTTPClientOption) *download.Client { // Allow mocking in tests
+	return download.NewClient(download.NewPooledHTTPClient(workers, opts...))
+}
+
 type certDownloadResult struct {
 	url         string
 	cert        *x509.Certificate
 	fingerprint string
-	err         error
+	// chain holds the certificates served after cert (only with --include-chain).
+	chain []*x509.Certificate
+	err   error
 }
 
 // Run executes the add command with the given options.
@@ -109,6 +177,23 @@ func Run(ctx context.Context, opts *AddOptions) error {
 	}
 
 	cfg, vendorIdx, err := loadConfigAndFindVendor(opts.ConfigPath, opts.VendorID)
+	if err != nil {
+		if !errors.Is(err, errVendorNotFound) || !opts.CreateVendor {
+			return err
+		}
+		// The vendor is only persisted if at least one certificate is added
+		cfg.Vendors, vendorIdx = InsertVendorSorted(cfg.Vendors, config.Vendor{
+			ID:           opts.VendorID,
+			Name:         opts.VendorName,
+			Certificates: []config.Certificate{},
+		})
+	}
+
+	tlsConfig, err := download.TLSOptions{
+		ClientCertFile: opts.ClientCert,
+		ClientKeyFile:  opts.ClientKey,
+		CAFile:         opts.CAFile,
+	}.TLSConfig()
 	if err != nil {
 		return err
 	}
@@ -117,7 +202,12 @@ func Run(ctx context.Context, opts *AddOptions) error {
 	if workers == 0 {
 		workers = concurrency.DetectCPUCount()
 	}
-	results := downloadCertificatesParallel(ctx, urls, fingerprints, hashAlgo, workers)
+	clientOpts := []download.HTTPClientOption{download.WithTLSConfig(tlsConfig)}
+	if opts.NoFollowRedirects {
+		clientOpts = append(clientOpts, download.WithoutRedirects())
+	}
+	client := downloadClientGetter(workers, clientOpts...)
+	results := downloadCertificatesParallel(ctx, client, urls, fingerprints, hashAlgo, workers, opts.IncludeChain)
 
 	successfulCerts, failures := processDownloadResults(results, cfg.Vendors[vendorIdx].Certificates, opts.Name, hashAlgo, len(urls))
 
@@ -134,6 +224,9 @@ func Run(ctx context.Context, opts *AddOptions) error {
 		}
 	}
 
+	if opts.Output == outputJSON {
+		return writeJSONResults(outputWriter, successfulCerts, failures)
+	}
 	return displayResults(successfulCerts, failures, len(urls), opts.VendorID)
 }
 
@@ -225,6 +318,17 @@ func validateAndPrepareInputs(opts *AddOptions) (hashAlgo string, urls, fingerpr
 		return "", nil, nil, fmt.Errorf("concurrency value %d exceeds maximum allowed (%d)", opts.Concurrency, concurrency.MaxWorkers)
 	}
 
+	if opts.CreateVendor && strings.TrimSpace(opts.VendorName) == "" {
+		return "", nil, nil, fmt.Errorf("--vendor-name is required with --create-vendor")
+	}
+	if !opts.CreateVendor && opts.VendorName != "" {
+		return "", nil, nil, fmt.Errorf("--vendor-name can only be used with --create-vendor")
+	}
+
+	if opts.Output != "" && opts.Output != outputText && opts.Output != outputJSON {
+		return "", nil, nil, fmt.Errorf("invalid output format '%s', must be one of: %s, %s", opts.Output, outputText, outputJSON)
+	}
+
 	// Parse and validate fingerprints
 	fingerprints, inferredAlgo, err := parseAndValidateFingerprints(opts.Fingerprint)
 	if err != nil {
@@ -255,7 +359,12 @@ func validateAndPrepareInputs(opts *AddOptions) (hashAlgo string, urls, fingerpr
 	return hashAlgo, urls, fingerprints, nil
 }
 
+// errVendorNotFound is returned by [loadConfigAndFindVendor] when the vendor is absent from the configuration.
+var errVendorNotFound = errors.New("vendor not found")
+
 // loadConfigAndFindVendor loads the configuration and finds the vendor index.
+//
+// If the vendor is absent, the loaded configuration is returned along with an error wrapping [errVendorNotFound].
 func loadConfigAndFindVendor(configPath, vendorID string) (*config.TPMRootsConfig, int, error) {
 	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
@@ -270,7 +379,7 @@ func loadConfigAndFindVendor(configPath, vendorID string) (*config.TPMRootsConfi
 		}
 	}
 	if vendorIdx == -1 {
-		return nil, -1, fmt.Errorf("vendor with ID '%s' not found", vendorID)
+		return cfg, -1, fmt.Errorf("%w: no vendor with ID '%s' in configuration (use --create-vendor to create it)", errVendorNotFound, vendorID)
 	}
 
 	return cfg, vendorIdx, nil
@@ -320,11 +429,43 @@ func processDownloadResults(results []certDownloadResult, existingCerts []config
 		}
 
 		successfulCerts = append(successfulCerts, newCert)
+
+		for i, chainCert := range result.chain {
+			chainEntry, err := newChainCertificate(result.url, i+2, chainCert, hashAlgo)
+			if err != nil {
+				failures = append(failures, downloadFailure{chainEntry.URL, err})
+				continue
+			}
+			// Chains frequently share their root: skip what is already known
+			if err := validate.CheckCertificate(slices.Concat(existingCerts, successfulCerts), chainEntry.URL, chainCert); err != nil {
+				cli.DisplayWarning("⚠️  Skipping chain certificate '%s': %v", chainEntry.Name, err)
+				continue
+			}
+			successfulCerts = append(successfulCerts, chainEntry)
+		}
 	}
 
 	return successfulCerts, failures
 }
 
+// newChainCertificate builds the entry of the certificate found at position in the file served at url.
+//
+// The position is appended to the URL as a fragment so that each entry keeps a unique URL; it is
+// never sent to the server and the certificate is selected by its fingerprint when generating bundles.
+func newChainCertificate(url string, position int, cert *x509.Certificate, hashAlgo string) (config.Certificate, error) {
+	entry := config.Certificate{
+		Name: extractCertificateName(cert),
+		URL:  fmt.Sprintf("%s#%d", url, position),
+		Validation: config.Validation{
+			Fingerprint: *config.NewFingerprint(hashAlgo, fingerprint.New(cert.Raw, hashAlgo)),
+		},
+	}
+	if entry.Name == "" {
+		return entry, fmt.Errorf("chain certificate CN is empty")
+	}
+	return entry, nil
+}
+
 // saveAndFormatConfig saves and formats the configuration file.
 func saveAndFormatConfig(configPath string, cfg *config.TPMRootsConfig) error {
 	if err := config.SaveConfig(configPath, cfg); err != nil {
@@ -380,8 +521,61 @@ func displayResults(successfulCerts []config.Certificate, failures []downloadFai
 	return nil
 }
 
+// addSummary is the machine-readable summary of the add operation.
+type addSummary struct {
+	Added  []addedCertificate `json:"added"`
+	Failed []failedURL        `json:"failed"`
+}
+
+type addedCertificate struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+type failedURL struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// writeJSONResults writes the results of the add operation as JSON.
+//
+// Like [displayResults], an error is returned when no certificate was added.
+func writeJSONResults(w io.Writer, successfulCerts []config.Certificate, failures []downloadFailure) error {
+	summary := addSummary{
+		Added:  make([]addedCertificate, 0, len(successfulCerts)),
+		Failed: make([]failedURL, 0, len(failures)),
+	}
+	for _, cert := range successfulCerts {
+		value, alg := cert.Validation.Fingerprint.GetFingerprintValue()
+		summary.Added = append(summary.Added, addedCertificate{
+			Name:        cert.Name,
+			URL:         cert.URL,
+			Fingerprint: strings.ToUpper(alg) + ":" + value,
+		})
+	}
+	for _, f := range failures {
+		summary.Failed = append(summary.Failed, failedURL{URL: f.url, Error: f.err.Error()})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(summary); err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	if len(successfulCerts) == 0 {
+		return fmt.Errorf("no certificates were added")
+	}
+	return nil
+}
+
 // downloadCertificatesParallel downloads multiple certificates in parallel with a goroutine limit.
-func downloadCertificatesParallel(ctx context.Context, urls []string, fingerprints []string, hashAlgo string, maxWorkers int) []certDownloadResult {
+//
+// The client is shared by all workers so connections to the same host are reused.
+//
+// When includeChain is true, the certificates served after the first one are returned in the chain field.
+func downloadCertificatesParallel(ctx context.Context, client *download.Client, urls []string, fingerprints []string, hashAlgo string, maxWorkers int, includeChain bool) []certDownloadResult {
 	type downloadInput struct {
 		url         string
 		fingerprint string
@@ -399,14 +593,15 @@ func downloadCertificatesParallel(ctx context.Context, urls []string, fingerprin
 		result := certDownloadResult{url: input.url}
 
 		// Download certificate
-		client := download.NewClient()
-		cert, err := client.DownloadCertificate(ctx, input.url)
+		certs, err := downloadCertificates(ctx, client, input.url, includeChain)
 		if err != nil {
 			result.err = err
 			return result
 		}
 
+		cert := certs[0]
 		result.cert = cert
+		result.chain = certs[1:]
 
 		// Handle fingerprint
 		var fpValidation string
@@ -439,6 +634,18 @@ func downloadCertificatesParallel(ctx context.Context, urls []string, fingerprin
 	})
 }
 
+// downloadCertificates downloads the certificate served at url, or all of them when includeChain is true.
+func downloadCertificates(ctx context.Context, client *download.Client, url string, includeChain bool) ([]*x509.Certificate, error) {
+	if includeChain {
+		return client.DownloadCertificates(ctx, url)
+	}
+	cert, err := client.DownloadCertificate(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return []*x509.Certificate{cert}, nil
+}
+
 // extractCertificateName extracts the certificate name from its CN (Common Name).
 func extractCertificateName(cert *x509.Certificate) string {
 	return strings.TrimSpace(cert.Subject.CommonName)
@@ -465,6 +672,18 @@ func ParseFingerprint(fp string) (string, string, error) {
 	return alg, hash, nil
 }
 
+// InsertVendorSorted inserts a vendor in sorted order by ID and returns the updated list with the vendor index.
+func InsertVendorSorted(vendorList []config.Vendor, newVendor config.Vendor) ([]config.Vendor, int) {
+	insertIdx := len(vendorList)
+	for i, v := range vendorList {
+		if newVendor.ID < v.ID {
+			insertIdx = i
+			break
+		}
+	}
+	return slices.Insert(vendorList, insertIdx, newVendor), insertIdx
+}
+
 // InsertCertificateAlphabetically inserts a certificate in alphabetical order by name.
 func InsertCertificateAlphabetically(certs []config.Certificate, newCert config.Certificate) []config.Certificate {
 	insertIdx := len(certs)
@@ -1,8 +1,20 @@
 package certificates
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
 )
 
 func TestValidateAndPrepareInputs(t *testing.T) {
@@ -300,3 +312,266 @@ func TestParseFingerprint(t *testing.T) {
 		}
 	})
 }
+
+func TestRun_SharesDownloadClient(t *testing.T) {
+	certDER, _ := testutil.GenerateTestCertDER(t)
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+		w.Write(certDER)
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+	initialConfig := `version: "alpha"
+vendors:
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates: []
+`
+	if err := os.WriteFile(configPath, []byte(initialConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	var constructions atomic.Int32
+	originalGetter := downloadClientGetter
+	defer func() { downloadClientGetter = originalGetter }()
+	downloadClientGetter = func(workers int, _ ...download.HTTPClientOption) *download.Client {
+		constructions.Add(1)
+		return download.NewClient(server.Client())
+	}
+
+	opts := &AddOptions{
+		ConfigPath:    configPath,
+		VendorID:      "STM",
+		URL:           server.URL + "/a.crt," + server.URL + "/b.crt," + server.URL + "/c.crt",
+		HashAlgorithm: "sha256",
+		Concurrency:   2,
+	}
+
+	// Test certificates have no CN, so no certificate is added; only the download path matters here.
+	_ = Run(t.Context(), opts)
+
+	if got := constructions.Load(); got != 1 {
+		t.Errorf("download client constructed %d times, want 1", got)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestRun_JSONOutput(t *testing.T) {
+	certDER, _ := testutil.GenerateTestCertWithCN(t, "Test Root CA")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/root.crt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(certDER)
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+	initialConfig := `version: "alpha"
+vendors:
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates: []
+`
+	if err := os.WriteFile(configPath, []byte(initialConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	originalGetter, originalWriter := downloadClientGetter, outputWriter
+	defer func() { downloadClientGetter, outputWriter = originalGetter, originalWriter }()
+	downloadClientGetter = func(workers int, _ ...download.HTTPClientOption) *download.Client {
+		return download.NewClient(server.Client())
+	}
+
+	run := func(t *testing.T, urls ...string) (addSummary, error) {
+		t.Helper()
+		var out bytes.Buffer
+		outputWriter = &out
+		err := Run(t.Context(), &AddOptions{
+			ConfigPath:    configPath,
+			VendorID:      "STM",
+			URL:           strings.Join(urls, ","),
+			HashAlgorithm: "sha256",
+			Output:        outputJSON,
+		})
+		var summary addSummary
+		if jsonErr := json.Unmarshal(out.Bytes(), &summary); jsonErr != nil {
+			t.Fatalf("failed to unmarshal JSON output: %v\n%s", jsonErr, out.String())
+		}
+		return summary, err
+	}
+
+	t.Run("mixed success and failure", func(t *testing.T) {
+		summary, err := run(t, server.URL+"/root.crt", server.URL+"/missing.crt")
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if len(summary.Added) != 1 || len(summary.Failed) != 1 {
+			t.Fatalf("expected 1 added and 1 failed, got %+v", summary)
+		}
+		added := summary.Added[0]
+		if added.Name != "Test Root CA" || added.URL != server.URL+"/root.crt" || !strings.HasPrefix(added.Fingerprint, "SHA256:") {
+			t.Errorf("unexpected added certificate: %+v", added)
+		}
+		if summary.Failed[0].URL != server.URL+"/missing.crt" || summary.Failed[0].Error == "" {
+			t.Errorf("unexpected failure: %+v", summary.Failed[0])
+		}
+	})
+
+	t.Run("nothing added returns an error", func(t *testing.T) {
+		// root.crt is now a duplicate
+		summary, err := run(t, server.URL+"/root.crt")
+		if err == nil {
+			t.Fatal("Run() expected error when no certificate is added")
+		}
+		if len(summary.Added) != 0 || len(summary.Failed) != 1 {
+			t.Errorf("expected 0 added and 1 failed, got %+v", summary)
+		}
+	})
+}
+
+func TestRun_CreateVendor(t *testing.T) {
+	certDER, _ := testutil.GenerateTestCertWithCN(t, "Nuvoton Root CA")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(certDER)
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+	initialConfig := `version: "alpha"
+vendors:
+  - id: "IFX"
+    name: "Infineon"
+    certificates: []
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates: []
+`
+	if err := os.WriteFile(configPath, []byte(initialConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	originalGetter := downloadClientGetter
+	defer func() { downloadClientGetter = originalGetter }()
+	downloadClientGetter = func(workers int, _ ...download.HTTPClientOption) *download.Client {
+		return download.NewClient(server.Client())
+	}
+
+	opts := &AddOptions{
+		ConfigPath:    configPath,
+		VendorID:      "NTC",
+		URL:           server.URL + "/ntc.crt",
+		HashAlgorithm: "sha256",
+	}
+
+	if err := Run(t.Context(), opts); err == nil || !strings.Contains(err.Error(), "--create-vendor") {
+		t.Fatalf("Run() error = %v, want vendor not found error", err)
+	}
+
+	opts.CreateVendor = true
+	if err := Run(t.Context(), opts); err == nil || !strings.Contains(err.Error(), "--vendor-name") {
+		t.Fatalf("Run() error = %v, want missing vendor name error", err)
+	}
+
+	opts.VendorName = "Nuvoton Technology"
+	if err := Run(t.Context(), opts); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	var ids []string
+	for _, v := range cfg.Vendors {
+		ids = append(ids, v.ID)
+	}
+	if strings.Join(ids, ",") != "IFX,NTC,STM" {
+		t.Fatalf("vendors = %v, want [IFX NTC STM]", ids)
+	}
+	ntc := cfg.Vendors[1]
+	if ntc.Name != "Nuvoton Technology" || len(ntc.Certificates) != 1 || ntc.Certificates[0].Name != "Nuvoton Root CA" {
+		t.Errorf("unexpected vendor: %+v", ntc)
+	}
+}
+
+func TestRun_IncludeChain(t *testing.T) {
+	rootDER, _ := testutil.GenerateTestCertWithCN(t, "Test Root CA")
+	intermediateDER, _ := testutil.GenerateTestCertWithCN(t, "Test Intermediate CA")
+	var chain []byte
+	for _, der := range [][]byte{rootDER, intermediateDER} {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(chain)
+	}))
+	defer server.Close()
+
+	configPath := filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+	initialConfig := `version: "alpha"
+vendors:
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates: []
+`
+	if err := os.WriteFile(configPath, []byte(initialConfig), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	originalGetter := downloadClientGetter
+	defer func() { downloadClientGetter = originalGetter }()
+	downloadClientGetter = func(workers int, _ ...download.HTTPClientOption) *download.Client {
+		return download.NewClient(server.Client())
+	}
+
+	if err := Run(t.Context(), &AddOptions{
+		ConfigPath:    configPath,
+		VendorID:      "STM",
+		URL:           server.URL + "/chain.pem",
+		HashAlgorithm: "sha256",
+		IncludeChain:  true,
+	}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	certs := cfg.Vendors[0].Certificates
+	if len(certs) != 2 {
+		t.Fatalf("expected 2 certificates, got %d", len(certs))
+	}
+	wantURLs := map[string]string{
+		"Test Root CA":         server.URL + "/chain.pem",
+		"Test Intermediate CA": server.URL + "/chain.pem#2",
+	}
+	for _, cert := range certs {
+		if want, ok := wantURLs[cert.Name]; !ok || cert.URL != want {
+			t.Errorf("unexpected certificate %s with URL %s", cert.Name, cert.URL)
+		}
+	}
+}
+
+func TestInsertVendorSorted(t *testing.T) {
+	vendorList := []config.Vendor{{ID: "IFX"}, {ID: "STM"}}
+
+	got, idx := InsertVendorSorted(vendorList, config.Vendor{ID: "AMD"})
+	if idx != 0 || got[0].ID != "AMD" {
+		t.Errorf("InsertVendorSorted() idx = %d, vendors = %v", idx, got)
+	}
+
+	got, idx = InsertVendorSorted([]config.Vendor{{ID: "IFX"}, {ID: "STM"}}, config.Vendor{ID: "TXN"})
+	if idx != 2 || got[2].ID != "TXN" {
+		t.Errorf("InsertVendorSorted() idx = %d, vendors = %v", idx, got)
+	}
+}
@@ -0,0 +1,156 @@
+package check
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/format"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/sanity"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/validate"
+	"github.com/spf13/cobra"
+)
+
+const defaultThreshold = 365 // days
+
+const (
+	checkValidate = "validate"
+	checkFormat   = "format"
+	checkSanity   = "sanity"
+)
+
+var (
+	configPath    string
+	quiet         bool
+	withSanity    bool
+	workers       int
+	threshold     int
+	osExit        = os.Exit // Allow mocking in tests
+	checkerGetter = sanity.NewChecker
+)
+
+// finding is a single issue reported by one of the checks.
+type finding struct {
+	check   string
+	message string
+}
+
+// NewCommand creates the check command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "run all configuration file checks at once",
+		Long: `Run every check on a TPM roots YAML configuration file in a single pass.
+
+The following checks are performed:
+  - validate: structural and content validation (same as 'tpmtb config validate')
+  - format:   formatting check (same as 'tpmtb config format --dry-run')
+  - sanity:   certificate download and expiration checks (same as 'tpmtb config sanity'),
+              only when --sanity is set as it requires network access
+
+All findings are aggregated into one report. Returns exit code 1 if any check reports an issue.`,
+		Example: `  # Validate and check formatting of the default config file
+  tpmtb config check
+
+  # Also run sanity checks
+  tpmtb config check --sanity
+
+  # Quiet mode (only return exit code)
+  tpmtb config check --quiet --config custom-roots.yaml`,
+		SilenceUsage: true,
+		RunE:         run,
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", ".tpm-roots.yaml",
+		"Path to TPM roots configuration file")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
+		"Suppress output, only return exit code")
+	cmd.Flags().BoolVar(&withSanity, "sanity", false,
+		"Also run sanity checks (downloads every certificate)")
+	cmd.Flags().IntVarP(&workers, "workers", "j", 0,
+		fmt.Sprintf("Number of workers to use for sanity checks (0=auto-detect, max=%d)", concurrency.MaxWorkers))
+	cmd.Flags().IntVarP(&threshold, "threshold", "t", defaultThreshold,
+		"Days threshold for expiration warnings in sanity checks")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	if workers > concurrency.MaxWorkers {
+		return fmt.Errorf("concurrency value %d exceeds maximum allowed (%d)", workers, concurrency.MaxWorkers)
+	}
+
+	var findings []finding
+
+	validator := validate.NewYAMLValidator()
+	validationErrors, err := validator.ValidateFile(configPath)
+	if err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	for _, verr := range validationErrors {
+		findings = append(findings, finding{checkValidate, fmt.Sprintf("Line %d: %s", verr.Line, verr.Message)})
+	}
+
+	needsFormatting, err := format.NewFormatter().NeedsFormatting(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to check formatting: %w", err)
+	}
+	if needsFormatting {
+		findings = append(findings, finding{checkFormat, "file is not properly formatted (run 'tpmtb config format')"})
+	}
+
+	if withSanity {
+		findings = append(findings, runSanity()...)
+	}
+
+	if len(findings) == 0 {
+		if !quiet {
+			cli.DisplaySuccess("✅ %s passed all checks", configPath)
+		}
+		return nil
+	}
+
+	if !quiet {
+		cli.DisplayError("❌ %s has %d issues:", configPath, len(findings))
+		for _, f := range findings {
+			cli.DisplayStderr("  [%s] %s\n", f.check, f.message)
+		}
+	}
+
+	osExit(1)
+	return nil
+}
+
+// runSanity runs the sanity checks and converts their results into findings.
+func runSanity() []finding {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return []finding{{checkSanity, fmt.Sprintf("skipped: failed to load configuration: %v", err)}}
+	}
+
+	result, err := checkerGetter().Check(cfg, workers, threshold)
+	if err != nil {
+		return []finding{{checkSanity, fmt.Sprintf("sanity check failed: %v", err)}}
+	}
+
+	var findings []finding
+	for _, verr := range result.ValidationErrors {
+		findings = append(findings, finding{checkSanity,
+			fmt.Sprintf("%s / %s: %v", verr.VendorID, verr.CertName, verr.Error)})
+	}
+	for _, warning := range result.ExpirationWarnings {
+		status := fmt.Sprintf("expires in %d days (%s)", warning.DaysLeft, warning.ExpiryDate.Format("2006-01-02"))
+		if warning.IsExpired {
+			status = fmt.Sprintf("expired on %s", warning.ExpiryDate.Format("2006-01-02"))
+		}
+		findings = append(findings, finding{checkSanity,
+			fmt.Sprintf("%s / %s: %s", warning.VendorID, warning.CertName, status)})
+	}
+	for _, warning := range result.PolicyWarnings {
+		findings = append(findings, finding{checkSanity,
+			fmt.Sprintf("%s / %s: %s", warning.VendorID, warning.CertName, warning.Reason)})
+	}
+	return findings
+}
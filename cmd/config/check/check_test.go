@@ -0,0 +1,96 @@
+package check
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCheck runs the check command on the given config content and returns its output
+// and whether os.Exit was called.
+func runCheck(t *testing.T, content string) (string, bool) {
+	t.Helper()
+
+	configPath = filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create test config: %v", err)
+	}
+	quiet, withSanity = false, false
+
+	exitCalls := 0
+	osExit = func(code int) {
+		exitCalls++
+		if code != 1 {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+	}
+	defer func() { osExit = os.Exit }()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, w
+
+	err := run(nil, nil)
+
+	w.Close()
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if exitCalls > 1 {
+		t.Errorf("os.Exit called %d times, want at most 1", exitCalls)
+	}
+	return buf.String(), exitCalls == 1
+}
+
+func TestCheckCommand(t *testing.T) {
+	t.Run("valid and formatted", func(t *testing.T) {
+		output, exited := runCheck(t, `---
+version: "alpha"
+vendors:
+    - id: "STM"
+      name: "STMicroelectronics"
+      certificates:
+        - name: "Test Certificate"
+          url: "https://example.com/cert.crt"
+          validation:
+            fingerprint:
+                sha256: "AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99"
+`)
+		if exited {
+			t.Errorf("expected no exit, got output:\n%s", output)
+		}
+		if !strings.Contains(output, "passed all checks") {
+			t.Errorf("unexpected output:\n%s", output)
+		}
+	})
+
+	t.Run("unformatted with invalid vendor ID", func(t *testing.T) {
+		output, exited := runCheck(t, `---
+version: "alpha"
+vendors:
+  - id: "INVALID"
+    name: STMicroelectronics
+    certificates:
+      - name: "Test Certificate"
+        url: "https://example.com/cert.crt"
+        validation:
+          fingerprint:
+            sha256: "aabbccddeeff00112233445566778899aabbccddeeff00112233445566778899"
+`)
+		if !exited {
+			t.Fatal("expected os.Exit to be called")
+		}
+		for _, want := range []string{"[validate]", "INVALID", "[format]"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+}
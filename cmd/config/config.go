@@ -2,6 +2,9 @@ package config
 
 import (
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/certificates"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/check"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/diff"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/fixurls"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/format"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/sanity"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/config/validate"
@@ -18,8 +21,11 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(format.NewCommand())
+	cmd.AddCommand(fixurls.NewCommand())
 	cmd.AddCommand(validate.NewCommand())
 	cmd.AddCommand(sanity.NewCommand())
+	cmd.AddCommand(check.NewCommand())
+	cmd.AddCommand(diff.NewCommand())
 	cmd.AddCommand(certificates.NewCommand())
 	cmd.AddCommand(vendors.NewCommand())
 
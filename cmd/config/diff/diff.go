@@ -0,0 +1,218 @@
+package diff
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/fingerprint"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"github.com/spf13/cobra"
+)
+
+const latest = "latest"
+
+var (
+	configPath       string
+	date             string
+	bundleTypeFlag   string
+	skipVerify       bool
+	osExit           = os.Exit                    // Allow mocking in tests
+	getTrustedBundle = apiv1beta.GetTrustedBundle // Allow mocking in tests
+)
+
+// localCert is a certificate declared in the configuration file.
+type localCert struct {
+	vendorID    string
+	name        string
+	fingerprint string
+	hashAlg     string
+}
+
+// upstreamCert is a certificate published in the release bundle.
+type upstreamCert struct {
+	vendorID string
+	cert     *x509.Certificate
+}
+
+// result holds the certificates found on one side only.
+type result struct {
+	missingLocally  []upstreamCert
+	missingUpstream []localCert
+}
+
+// NewCommand creates the diff command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "compare the configuration file with a published bundle",
+		Long: `Compare the certificates of a TPM roots YAML configuration file with the ones
+of a published release bundle.
+
+Certificates are matched by vendor ID and fingerprint (using the algorithm declared
+in the configuration file). The command reports:
+  - certificates present upstream but missing from the configuration file
+  - certificates declared in the configuration file but missing upstream
+
+Unlike bundle-to-bundle comparisons, this helps finding out whether a local
+configuration is in sync with the latest release (eg. a vendor added upstream).
+
+Returns exit code 1 if the configuration file and the bundle differ.`,
+		Example: `  # Compare the default config file with the latest release
+  tpmtb config diff
+
+  # Compare with a specific release
+  tpmtb config diff --date 2025-12-05
+
+  # Compare the intermediate config file with the intermediate bundle
+  tpmtb config diff --config .tpm-intermediates.yaml`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         run,
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", ".tpm-roots.yaml",
+		"Path to TPM roots configuration file")
+	cmd.Flags().StringVarP(&date, "date", "d", latest,
+		"Bundle release date (YYYY-MM-DD) or 'latest'")
+	cmd.Flags().StringVarP(&bundleTypeFlag, "type", "t", "",
+		"Bundle type: root or intermediate (default: auto-detect from config filename)")
+	cmd.Flags().BoolVar(&skipVerify, "skip-verify", false,
+		"Skip bundle verification after download")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	bundleType, err := bundle.ResolveType(bundleTypeFlag, configPath)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	releaseDate := date
+	if releaseDate == latest {
+		releaseDate = ""
+	}
+
+	tb, err := getTrustedBundle(cmd.Context(), apiv1beta.GetConfig{
+		Date:                  releaseDate,
+		SkipVerify:            skipVerify,
+		AcknowledgeSkipVerify: skipVerify, // --skip-verify is an explicit decision
+		Logger:                cli.Logger(),
+		AutoUpdate: apiv1beta.AutoUpdateConfig{
+			DisableAutoUpdate: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get release bundle: %w", err)
+	}
+	defer tb.Stop()
+
+	rawBundle := tb.GetRawRoot()
+	if bundleType == bundle.TypeIntermediate {
+		rawBundle = tb.GetRawIntermediate()
+		if len(rawBundle) == 0 {
+			return fmt.Errorf("intermediate bundle not available for release %s", tb.GetRootMetadata().Date)
+		}
+	}
+
+	catalog, err := bundle.ParseBundle(rawBundle)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s bundle: %w", bundleType, err)
+	}
+
+	res := diff(cfg, catalog)
+	printResult(cmd.OutOrStdout(), res, tb.GetRootMetadata().Date, bundleType)
+
+	if len(res.missingLocally) > 0 || len(res.missingUpstream) > 0 {
+		osExit(1)
+	}
+	return nil
+}
+
+// diff compares the certificates of cfg with the ones of catalog.
+func diff(cfg *config.TPMRootsConfig, catalog map[vendors.ID][]*x509.Certificate) result {
+	var locals []localCert
+	for _, vendor := range cfg.Vendors {
+		for _, cert := range vendor.Certificates {
+			fp, hashAlg := cert.Validation.Fingerprint.GetFingerprintValue()
+			locals = append(locals, localCert{
+				vendorID:    vendor.ID,
+				name:        cert.Name,
+				fingerprint: fingerprint.FormatFingerprint(fp),
+				hashAlg:     hashAlg,
+			})
+		}
+	}
+
+	var upstreams []upstreamCert
+	for vendorID, certs := range catalog {
+		for _, cert := range certs {
+			upstreams = append(upstreams, upstreamCert{vendorID: string(vendorID), cert: cert})
+		}
+	}
+
+	matches := func(l localCert, u upstreamCert) bool {
+		return l.vendorID == u.vendorID && fingerprint.New(u.cert.Raw, l.hashAlg) == l.fingerprint
+	}
+
+	var res result
+	for _, u := range upstreams {
+		if !slices.ContainsFunc(locals, func(l localCert) bool { return matches(l, u) }) {
+			res.missingLocally = append(res.missingLocally, u)
+		}
+	}
+	for _, l := range locals {
+		if !slices.ContainsFunc(upstreams, func(u upstreamCert) bool { return matches(l, u) }) {
+			res.missingUpstream = append(res.missingUpstream, l)
+		}
+	}
+
+	slices.SortFunc(res.missingLocally, func(a, b upstreamCert) int {
+		if c := strings.Compare(a.vendorID, b.vendorID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.cert.Subject.String(), b.cert.Subject.String())
+	})
+	slices.SortFunc(res.missingUpstream, func(a, b localCert) int {
+		if c := strings.Compare(a.vendorID, b.vendorID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	return res
+}
+
+// printResult writes the differences as a human readable report.
+func printResult(out io.Writer, res result, releaseDate string, bundleType bundle.BundleType) {
+	if len(res.missingLocally) == 0 && len(res.missingUpstream) == 0 {
+		cli.DisplaySuccess("✅ %s is in sync with the %s bundle of release %s", configPath, bundleType, releaseDate)
+		return
+	}
+
+	fmt.Fprintf(out, "Comparing %s with the %s bundle of release %s\n", configPath, bundleType, releaseDate)
+	if len(res.missingLocally) > 0 {
+		fmt.Fprintf(out, "\nPresent upstream but missing locally (%d):\n", len(res.missingLocally))
+		for _, u := range res.missingLocally {
+			fmt.Fprintf(out, "  + %s / %s (sha256: %s)\n", u.vendorID, u.cert.Subject.String(), fingerprint.New(u.cert.Raw, fingerprint.SHA256))
+		}
+	}
+	if len(res.missingUpstream) > 0 {
+		fmt.Fprintf(out, "\nPresent locally but missing upstream (%d):\n", len(res.missingUpstream))
+		for _, l := range res.missingUpstream {
+			fmt.Fprintf(out, "  - %s / %s (%s: %s)\n", l.vendorID, l.name, l.hashAlg, l.fingerprint)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/fingerprint"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"go.yaml.in/yaml/v4"
+)
+
+// configFromBundle builds a configuration declaring every certificate of the test bundle.
+func configFromBundle(t *testing.T, rawBundle []byte) *config.TPMRootsConfig {
+	t.Helper()
+
+	catalog, err := bundle.ParseBundle(rawBundle)
+	if err != nil {
+		t.Fatalf("failed to parse test bundle: %v", err)
+	}
+
+	cfg := &config.TPMRootsConfig{Version: "alpha"}
+	for vendorID, certs := range catalog {
+		vendor := config.Vendor{ID: string(vendorID), Name: string(vendorID)}
+		for _, cert := range certs {
+			vendor.Certificates = append(vendor.Certificates, config.Certificate{
+				Name: cert.Subject.String(),
+				URL:  "https://example.com/" + cert.SerialNumber.String() + ".crt",
+				Validation: config.Validation{
+					Fingerprint: config.Fingerprint{SHA256: fingerprint.New(cert.Raw, fingerprint.SHA256)},
+				},
+			})
+		}
+		cfg.Vendors = append(cfg.Vendors, vendor)
+	}
+	slices.SortFunc(cfg.Vendors, func(a, b config.Vendor) int { return strings.Compare(a.ID, b.ID) })
+	return cfg
+}
+
+// runDiff writes cfg to disk, runs the diff command against rawBundle and returns its output
+// and whether os.Exit was called.
+func runDiff(t *testing.T, cfg *config.TPMRootsConfig, rawBundle []byte) (string, bool) {
+	t.Helper()
+
+	// Create the command first as binding its flags resets their values
+	cmd := NewCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(context.Background())
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("failed to marshal config: %v", err)
+	}
+	configPath = filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	getTrustedBundle = func(ctx context.Context, cfg apiv1beta.GetConfig) (apiv1beta.TrustedBundle, error) {
+		if cfg.Date != "" {
+			t.Errorf("expected latest release to be requested, got %q", cfg.Date)
+		}
+		return apiv1beta.NewTrustedBundle(bytes.NewReader(rawBundle), apiv1beta.WithSkipVerify())
+	}
+	exitCalls := 0
+	osExit = func(code int) {
+		exitCalls++
+		if code != 1 {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+	}
+	t.Cleanup(func() {
+		getTrustedBundle = apiv1beta.GetTrustedBundle
+		osExit = os.Exit
+	})
+
+	if err := run(cmd, nil); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	return out.String(), exitCalls == 1
+}
+
+func TestDiffCommand(t *testing.T) {
+	rawBundle, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+
+	t.Run("in sync", func(t *testing.T) {
+		output, exited := runDiff(t, configFromBundle(t, rawBundle), rawBundle)
+		if exited {
+			t.Errorf("expected no exit, got output:\n%s", output)
+		}
+	})
+
+	t.Run("missing upstream certificate", func(t *testing.T) {
+		cfg := configFromBundle(t, rawBundle)
+		// Drop the last certificate of the first vendor and declare a certificate unknown upstream
+		vendor := &cfg.Vendors[0]
+		dropped := vendor.Certificates[len(vendor.Certificates)-1]
+		vendor.Certificates = vendor.Certificates[:len(vendor.Certificates)-1]
+		vendor.Certificates = append(vendor.Certificates, config.Certificate{
+			Name: "Unknown Certificate",
+			URL:  "https://example.com/unknown.crt",
+			Validation: config.Validation{
+				Fingerprint: config.Fingerprint{SHA256: strings.Repeat("AA:", 31) + "AA"},
+			},
+		})
+
+		output, exited := runDiff(t, cfg, rawBundle)
+		if !exited {
+			t.Fatalf("expected os.Exit to be called, got output:\n%s", output)
+		}
+
+		for _, want := range []string{
+			"Present upstream but missing locally (1):",
+			"+ " + vendor.ID + " / " + dropped.Name,
+			dropped.Validation.Fingerprint.SHA256,
+			"Present locally but missing upstream (1):",
+			"- " + vendor.ID + " / Unknown Certificate",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestDiffMatchesConfiguredAlgorithm(t *testing.T) {
+	rawBundle, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	catalog, err := bundle.ParseBundle(rawBundle)
+	if err != nil {
+		t.Fatalf("failed to parse test bundle: %v", err)
+	}
+
+	// Declare every certificate with a lowercase SHA-1 fingerprint without colons
+	cfg := configFromBundle(t, rawBundle)
+	for i := range cfg.Vendors {
+		for j := range cfg.Vendors[i].Certificates {
+			fp := &cfg.Vendors[i].Certificates[j].Validation.Fingerprint
+			for _, cert := range catalog[vendors.ID(cfg.Vendors[i].ID)] {
+				if fingerprint.New(cert.Raw, fingerprint.SHA256) == fp.SHA256 {
+					fp.SHA1 = strings.ToLower(strings.ReplaceAll(fingerprint.New(cert.Raw, fingerprint.SHA1), ":", ""))
+				}
+			}
+			fp.SHA256 = ""
+		}
+	}
+
+	res := diff(cfg, catalog)
+	if len(res.missingLocally) != 0 || len(res.missingUpstream) != 0 {
+		t.Errorf("expected no difference, got %d missing locally and %d missing upstream",
+			len(res.missingLocally), len(res.missingUpstream))
+	}
+}
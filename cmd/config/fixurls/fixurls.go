@@ -0,0 +1,81 @@
+package fixurls
+
+import (
+	"fmt"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/format"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath string
+	dryRun     bool
+)
+
+// NewCommand creates the fix-urls command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fix-urls",
+		Short: "re-encode certificate URLs in the configuration file",
+		Long: `Re-encode every certificate URL of a TPM roots YAML configuration file.
+
+Each URL is normalized the way the validator expects it (spaces, non-ASCII and
+other reserved characters are percent-encoded), and every change is reported
+with its line number. Unlike format, only URLs are rewritten: vendor and
+certificate order and comments are kept.
+
+The file is fixed in-place unless --dry-run is specified.
+
+With --dry-run, the command reports the URLs that would change and exits with:
+  - Exit code 0: All URLs are properly encoded
+  - Exit code 1: Some URLs need to be re-encoded`,
+		Example: `  # Fix URLs in the default config file
+  tpmtb config fix-urls
+
+  # Fix URLs in a specific config file
+  tpmtb config fix-urls --config .tpm-intermediates.yaml
+
+  # List URLs that need to be re-encoded (dry-run mode)
+  tpmtb config fix-urls --dry-run`,
+		SilenceUsage: true,
+		RunE:         run,
+	}
+
+	cmd.Flags().StringVarP(&configPath, "config", "c", ".tpm-roots.yaml",
+		"Path to TPM roots configuration file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Report URLs to re-encode without modifying the file")
+
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	formatter := format.NewFormatter()
+
+	outputPath := configPath
+	if dryRun {
+		outputPath = ""
+	}
+
+	changes, err := formatter.FixURLs(configPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to fix URLs: %w", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("All URLs are properly encoded: %s\n", configPath)
+		return nil
+	}
+
+	for _, c := range changes {
+		fmt.Printf("  Line %d: %s/%q\n    - %s\n    + %s\n", c.Line, c.VendorID, c.Certificate, c.Old, c.New)
+	}
+
+	if dryRun {
+		fmt.Printf("%d URL(s) need to be re-encoded: %s\n", len(changes), configPath)
+		return fmt.Errorf("file has improperly encoded URLs")
+	}
+
+	fmt.Printf("Fixed %d URL(s): %s\n", len(changes), configPath)
+	return nil
+}
@@ -0,0 +1,95 @@
+package fixurls
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+)
+
+func TestFixURLsCommand(t *testing.T) {
+	const malformed = `---
+version: "alpha"
+vendors:
+    - id: "TV"
+      name: "Test Vendor"
+      certificates:
+        - name: "Test Cert"
+          url: "https://example.com/test cert é.cer"
+          validation:
+            fingerprint:
+                sha1: "AA:BB:CC:DD"
+`
+	const fixedURL = "https://example.com/test%20cert%20%C3%A9.cer"
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		expectError bool
+		expectURL   string
+	}{
+		{
+			name:        "fix in place",
+			expectError: false,
+			expectURL:   fixedURL,
+		},
+		{
+			name:        "dry-run reports malformed URLs",
+			dryRun:      true,
+			expectError: true,
+			expectURL:   "https://example.com/test cert é.cer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+			if err := os.WriteFile(configPath, []byte(malformed), 0644); err != nil {
+				t.Fatalf("failed to create test config: %v", err)
+			}
+
+			cmd := NewCommand()
+			args := []string{"--config", configPath}
+			if tt.dryRun {
+				args = append(args, "--dry-run")
+			}
+			cmd.SetArgs(args)
+
+			err := cmd.Execute()
+			if tt.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cfg, err := config.LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			if got := cfg.Vendors[0].Certificates[0].URL; got != tt.expectURL {
+				t.Errorf("expected URL %q, got %q", tt.expectURL, got)
+			}
+		})
+	}
+
+	t.Run("dry-run on fixed file succeeds", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), ".tpm-roots.yaml")
+		if err := os.WriteFile(configPath, []byte(malformed), 0644); err != nil {
+			t.Fatalf("failed to create test config: %v", err)
+		}
+
+		cmd := NewCommand()
+		cmd.SetArgs([]string{"--config", configPath})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cmd = NewCommand()
+		cmd.SetArgs([]string{"--config", configPath, "--dry-run"})
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("expected no URL left to fix, got: %v", err)
+		}
+	})
+}
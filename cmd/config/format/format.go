@@ -22,7 +22,7 @@ func NewCommand() *cobra.Command {
 The formatter applies the following rules:
   - Sort vendors alphabetically by ID
   - Sort certificates within each vendor alphabetically by name
-  - URL-encode certificate URLs (if needed)
+  - Re-encode every certificate URL (see also fix-urls)
   - Format fingerprints to uppercase with colon separators (AA:BB:CC:DD)
   - Add double quotes to all string values
 
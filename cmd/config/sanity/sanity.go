@@ -1,12 +1,16 @@
 package sanity
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli/exitcode"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/sanity"
 	"github.com/spf13/cobra"
 )
@@ -21,10 +25,25 @@ var (
 	quiet         bool
 	workers       int
 	threshold     int
+	timeout       time.Duration
+	minRSABits    int
+	allowWeakSigs bool
+	strict        bool
+	caFile        string
 	osExit        = os.Exit // Allow mocking in tests
-	checkerGetter = sanity.NewChecker
+	checkerGetter = newChecker
 )
 
+// newChecker returns a checker downloading certificates with tlsConfig.
+//
+// The default client is kept when tlsConfig is nil.
+func newChecker(tlsConfig *tls.Config) *sanity.Checker {
+	if tlsConfig == nil {
+		return sanity.NewChecker()
+	}
+	return sanity.NewCheckerWithClient(download.NewPooledHTTPClient(concurrency.MaxWorkers, download.WithTLSConfig(tlsConfig)))
+}
+
 // NewCommand creates the sanity command.
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -36,15 +55,30 @@ The sanity checker:
   - Downloads each certificate from its URL
   - Validates the certificate fingerprint matches the configuration
   - Checks if certificates are expired or expiring soon (within threshold days)
+  - Flags certificates with small RSA keys or weak signature algorithms (e.g. SHA-1)
 
-Returns exit code 1 if any issues are found.
-Shows up to 10 validation errors and 10 expiration warnings.`,
+Returns exit code 4 if validation errors are found (exit code 5 if the downloads
+failed because of the network), and exit code 1 if only expiration warnings are
+found. Policy warnings are only reported, unless --strict is set.
+Shows up to 10 validation errors, 10 expiration warnings and 10 policy warnings.`,
 		Example: `  # Check all certificates with default settings (180 days threshold)
   tpmtb config sanity
 
   # Check with custom threshold
   tpmtb config sanity --threshold 30
 
+  # Abort each certificate download after 10 seconds
+  tpmtb config sanity --timeout 10s
+
+  # Only flag RSA keys smaller than 1024 bits and ignore weak signature algorithms
+  tpmtb config sanity --min-rsa-bits 1024 --allow-weak-signatures
+
+  # Fail on policy warnings too (e.g. in CI)
+  tpmtb config sanity --strict
+
+  # Only trust vendor servers whose certificate is issued by the given CA(s)
+  tpmtb config sanity --ca-file vendor-ca.pem
+
   # Check with specific config file
   tpmtb config sanity --config custom-roots.yaml
 
@@ -62,6 +96,16 @@ Shows up to 10 validation errors and 10 expiration warnings.`,
 		fmt.Sprintf("Number of workers to use (0=auto-detect, max=%d)", concurrency.MaxWorkers))
 	cmd.Flags().IntVarP(&threshold, "threshold", "t", defaultThreshold,
 		"Days threshold for expiration warnings (default: 365 days)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0,
+		"Maximum duration for each certificate download (0=no limit)")
+	cmd.Flags().IntVar(&minRSABits, "min-rsa-bits", sanity.DefaultMinRSAKeySize,
+		"Minimum RSA key size in bits before emitting a policy warning (0=disabled)")
+	cmd.Flags().BoolVar(&allowWeakSigs, "allow-weak-signatures", false,
+		"Do not emit policy warnings for weak signature algorithms (e.g. SHA-1, MD5)")
+	cmd.Flags().BoolVar(&strict, "strict", false,
+		"Report policy warnings as issues")
+	cmd.Flags().StringVar(&caFile, "ca-file", "",
+		"Path to a PEM file with the CA(s) trusted for the server certificates (default: system roots)")
 
 	return cmd
 }
@@ -76,14 +120,25 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("concurrency value %d exceeds maximum allowed (%d)", workers, concurrency.MaxWorkers)
 	}
 
-	checker := checkerGetter()
+	tlsConfig, err := download.TLSOptions{CAFile: caFile}.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	checker := checkerGetter(tlsConfig)
+	checker.PerCertTimeout = timeout
+	checker.MinRSAKeySize = minRSABits
+	if allowWeakSigs {
+		checker.WeakSignatureAlgorithms = nil
+	}
 	result, err := checker.Check(cfg, workers, threshold)
 	if err != nil {
 		return fmt.Errorf("sanity check failed: %w", err)
 	}
 
-	if !result.HasIssues() {
+	if !hasIssues(result) {
 		if !quiet {
+			displayResults(result)
 			cli.DisplaySuccess("✅ All certificates passed sanity checks.")
 		}
 		return nil
@@ -93,10 +148,32 @@ func run(cmd *cobra.Command, args []string) error {
 		displayResults(result)
 	}
 
-	osExit(1)
+	osExit(exitCode(result))
 	return nil
 }
 
+// hasIssues reports whether result fails the check: policy warnings only do with --strict.
+func hasIssues(result *sanity.Result) bool {
+	return result.HasIssues() || (strict && len(result.PolicyWarnings) > 0)
+}
+
+// exitCode returns the exit code reporting the issues of result.
+//
+// Validation errors are reported as an invalid configuration, unless they are all
+// download failures caused by the network, in which case the check may be retried.
+// Warnings alone don't make the configuration invalid and return a generic error.
+func exitCode(result *sanity.Result) int {
+	if len(result.ValidationErrors) == 0 {
+		return exitcode.Error
+	}
+	for _, verr := range result.ValidationErrors {
+		if exitcode.FromError(verr.Error) != exitcode.NetworkError {
+			return exitcode.ConfigInvalid
+		}
+	}
+	return exitcode.NetworkError
+}
+
 func displayResults(result *sanity.Result) {
 	if len(result.ValidationErrors) > 0 {
 		cli.DisplayError("❌ Certificate validation errors:")
@@ -120,4 +197,16 @@ func displayResults(result *sanity.Result) {
 			cli.DisplayStderr("(showing first %d warnings)\n", maxErrors)
 		}
 	}
+
+	// Display policy warnings
+	if len(result.PolicyWarnings) > 0 {
+		cli.DisplayWarning("⚠️  Certificate policy warnings:")
+		displayCount := min(len(result.PolicyWarnings), maxErrors)
+		for i := range displayCount {
+			cli.DisplayStderr("%s\n", result.PolicyWarnings[i].String())
+		}
+		if len(result.PolicyWarnings) > maxErrors {
+			cli.DisplayStderr("(showing first %d warnings)\n", maxErrors)
+		}
+	}
 }
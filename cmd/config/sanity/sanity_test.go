@@ -2,9 +2,12 @@ package sanity
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -12,6 +15,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli/exitcode"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/sanity"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
 )
@@ -22,6 +26,7 @@ func TestSanityCommand(t *testing.T) {
 		quiet          bool
 		threshold      int
 		expectExit     bool
+		exitCode       int
 		expectOutput   bool
 		outputContains []string
 	}{
@@ -44,6 +49,7 @@ func TestSanityCommand(t *testing.T) {
 			quiet:        false,
 			threshold:    90,
 			expectExit:   true,
+			exitCode:     exitcode.ConfigInvalid,
 			expectOutput: true,
 			outputContains: []string{
 				"validation errors",
@@ -55,6 +61,7 @@ func TestSanityCommand(t *testing.T) {
 			quiet:        true,
 			threshold:    90,
 			expectExit:   true,
+			exitCode:     exitcode.ConfigInvalid,
 			expectOutput: false,
 		},
 		{
@@ -62,6 +69,7 @@ func TestSanityCommand(t *testing.T) {
 			quiet:        false,
 			threshold:    90,
 			expectExit:   true,
+			exitCode:     exitcode.Error,
 			expectOutput: true,
 			outputContains: []string{
 				"expiration warnings",
@@ -73,6 +81,7 @@ func TestSanityCommand(t *testing.T) {
 			quiet:        false,
 			threshold:    90,
 			expectExit:   true,
+			exitCode:     exitcode.Error,
 			expectOutput: true,
 			outputContains: []string{
 				"expiration warnings",
@@ -132,7 +141,7 @@ vendors:
 			workers = 1
 
 			// Mock checker with server's HTTP client
-			checkerGetter = func() *sanity.Checker {
+			checkerGetter = func(*tls.Config) *sanity.Checker {
 				return sanity.NewCheckerWithClient(server.Client())
 			}
 
@@ -148,8 +157,8 @@ vendors:
 			exitCalled := false
 			osExit = func(code int) {
 				exitCalled = true
-				if code != 1 {
-					t.Errorf("expected exit code 1, got %d", code)
+				if code != tt.exitCode {
+					t.Errorf("expected exit code %d, got %d", tt.exitCode, code)
 				}
 			}
 
@@ -199,7 +208,7 @@ vendors:
 
 			// Reset osExit and checkerGetter
 			osExit = os.Exit
-			checkerGetter = sanity.NewChecker
+			checkerGetter = newChecker
 		})
 	}
 }
@@ -417,3 +426,71 @@ func formatFingerprintWithColons(fp string) string {
 	}
 	return result.String()
 }
+
+func TestHasIssues(t *testing.T) {
+	policyWarnings := &sanity.Result{PolicyWarnings: []sanity.PolicyWarning{{Reason: "weak signature algorithm SHA1-RSA"}}}
+	defer func() { strict = false }()
+
+	strict = false
+	if hasIssues(policyWarnings) {
+		t.Error("expected policy warnings not to fail the check")
+	}
+
+	strict = true
+	if !hasIssues(policyWarnings) {
+		t.Error("expected policy warnings to fail the check with --strict")
+	}
+	if got := exitCode(policyWarnings); got != exitcode.Error {
+		t.Errorf("exitCode() = %d, want %d", got, exitcode.Error)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	networkErr := fmt.Errorf("failed to download certificate: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+
+	tests := []struct {
+		name   string
+		result *sanity.Result
+		want   int
+	}{
+		{
+			name:   "fingerprint mismatch",
+			result: &sanity.Result{ValidationErrors: []sanity.ValidationError{{Error: errors.New("fingerprint mismatch")}}},
+			want:   exitcode.ConfigInvalid,
+		},
+		{
+			name:   "expiration warning",
+			result: &sanity.Result{ExpirationWarnings: []sanity.ExpirationWarning{{}}},
+			want:   exitcode.Error,
+		},
+		{
+			name: "fingerprint mismatch and expiration warning",
+			result: &sanity.Result{
+				ValidationErrors:   []sanity.ValidationError{{Error: errors.New("fingerprint mismatch")}},
+				ExpirationWarnings: []sanity.ExpirationWarning{{}},
+			},
+			want: exitcode.ConfigInvalid,
+		},
+		{
+			name:   "network failures only",
+			result: &sanity.Result{ValidationErrors: []sanity.ValidationError{{Error: networkErr}}},
+			want:   exitcode.NetworkError,
+		},
+		{
+			name: "network failure and fingerprint mismatch",
+			result: &sanity.Result{ValidationErrors: []sanity.ValidationError{
+				{Error: networkErr},
+				{Error: errors.New("fingerprint mismatch")},
+			}},
+			want: exitcode.ConfigInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.result); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli/exitcode"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/validate"
 	"github.com/spf13/cobra"
 )
@@ -12,6 +13,8 @@ import (
 var (
 	configPath string
 	quiet      bool
+	strict     bool
+	format     string
 	osExit     = os.Exit // Allow mocking in tests
 )
 
@@ -33,13 +36,26 @@ The validator checks:
   - Fingerprints are formatted in uppercase with colon separators (AA:BB:CC:DD)
   - String values are double-quoted
 
-Returns exit code 1 if validation errors are found.
-Shows up to 10 validation errors with line numbers.`,
+It also warns about vendors without certificates and certificates pinned
+under several vendors (by URL or fingerprint); use --strict to report such
+warnings as errors.
+
+Returns exit code 4 if validation errors are found.
+Shows up to 10 validation errors with line numbers.
+
+With --format github (the default when GITHUB_ACTIONS=true), the findings
+are printed as GitHub Actions workflow commands so they appear as annotations.`,
 		Example: `  # Validate the default config file
   tpmtb config validate
 
   # Validate a specific config file
-  tpmtb config validate --config custom-roots.yaml`,
+  tpmtb config validate --config custom-roots.yaml
+
+  # Fail on warnings too (e.g. in CI)
+  tpmtb config validate --strict
+
+  # Annotate the findings in a GitHub Actions workflow
+  tpmtb config validate --format github`,
 		SilenceUsage: true,
 		RunE:         run,
 	}
@@ -48,17 +64,37 @@ Shows up to 10 validation errors with line numbers.`,
 		"Path to TPM roots configuration file")
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
 		"Suppress output, only return exit code")
+	cmd.Flags().BoolVar(&strict, "strict", false,
+		"Report warnings as validation errors")
+	cmd.Flags().StringVar(&format, "format", "",
+		"Output format: text or github (default: github when GITHUB_ACTIONS=true, text otherwise)")
 
 	return cmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	validator := validate.NewYAMLValidator()
+	outputFormat, err := cli.ResolveFormat(format)
+	if err != nil {
+		return err
+	}
+
+	validator := validate.NewYAMLValidator(validate.WithStrict(strict))
 	errors, err := validator.ValidateFile(configPath)
 	if err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	if warnings := validator.Warnings(); len(warnings) > 0 && !quiet {
+		cli.DisplayWarning("⚠️  %s has warnings:", configPath)
+		for _, warning := range warnings {
+			if outputFormat == cli.FormatGitHub {
+				cli.DisplayAnnotation(cli.AnnotationWarning, configPath, warning.Line, warning.Message)
+				continue
+			}
+			cli.DisplayStderr("  Line %d: %s\n", warning.Line, warning.Message)
+		}
+	}
+
 	if len(errors) == 0 {
 		if !quiet {
 			cli.DisplaySuccess("✅ %s is valid", configPath)
@@ -69,6 +105,10 @@ func run(cmd *cobra.Command, args []string) error {
 	if !quiet {
 		cli.DisplayError("❌ %s has validation errors:", configPath)
 		for _, verr := range errors {
+			if outputFormat == cli.FormatGitHub {
+				cli.DisplayAnnotation(cli.AnnotationError, configPath, verr.Line, verr.Message)
+				continue
+			}
 			cli.DisplayStderr("  Line %d: %s\n", verr.Line, verr.Message)
 		}
 
@@ -77,6 +117,6 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	osExit(1)
+	osExit(exitcode.ConfigInvalid)
 	return nil
 }
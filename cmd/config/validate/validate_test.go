@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli/exitcode"
 )
 
 func TestValidateCommand(t *testing.T) {
@@ -13,6 +15,7 @@ func TestValidateCommand(t *testing.T) {
 		name           string
 		configContent  string
 		quiet          bool
+		strict         bool
 		expectExit     bool
 		expectOutput   bool
 		outputContains string
@@ -91,6 +94,33 @@ vendors:
 			expectExit:   true,
 			expectOutput: false,
 		},
+		{
+			name: "vendor without certificates",
+			configContent: `---
+version: "alpha"
+vendors:
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates: []
+`,
+			expectExit:     false,
+			expectOutput:   true,
+			outputContains: `vendor "STM" has no certificates`,
+		},
+		{
+			name: "vendor without certificates in strict mode",
+			configContent: `---
+version: "alpha"
+vendors:
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates: []
+`,
+			strict:         true,
+			expectExit:     true,
+			expectOutput:   true,
+			outputContains: "validation errors",
+		},
 	}
 
 	for _, tt := range tests {
@@ -103,8 +133,9 @@ vendors:
 				t.Fatalf("failed to create test config: %v", err)
 			}
 
-			// Set quiet flag
+			// Set flags
 			quiet = tt.quiet
+			strict = tt.strict
 
 			// Capture stdout and stderr
 			oldStdout := os.Stdout
@@ -118,8 +149,8 @@ vendors:
 			exitCalled := false
 			osExit = func(code int) {
 				exitCalled = true
-				if code != 1 {
-					t.Errorf("expected exit code 1, got %d", code)
+				if code != exitcode.ConfigInvalid {
+					t.Errorf("expected exit code %d, got %d", exitcode.ConfigInvalid, code)
 				}
 			}
 
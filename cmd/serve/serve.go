@@ -0,0 +1,308 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultListenAddr is the default TCP address of the API, only reachable locally.
+	defaultListenAddr = "127.0.0.1:8787"
+
+	// defaultPollInterval is the default interval between two checks of the cache.
+	defaultPollInterval = 5 * time.Second
+
+	// shutdownTimeout bounds the time given to in-flight requests on shutdown.
+	shutdownTimeout = 5 * time.Second
+)
+
+// Opts represents the configuration options for the serve command.
+type Opts struct {
+	CachePath    string
+	ListenAddr   string
+	SocketPath   string
+	WatchCache   bool
+	PollInterval time.Duration
+	OfflineMode  bool
+}
+
+// NewCommand creates the serve command.
+func NewCommand() *cobra.Command {
+	o := &Opts{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "serve the cached trust bundle over a local HTTP API",
+		Long: `Load the trust bundle from the local cache and serve it over a local HTTP API,
+turning tpmtb into a local trust-store service.
+
+Endpoints:
+  GET /v1/roots     root bundle (PEM)
+  GET /v1/metadata  metadata of the served bundles (JSON)
+  GET /metrics      Prometheus metrics (only with PROMETHEUS_ENABLED=true)
+
+With --watch-cache, the cache is checked every --poll-interval and the bundle
+is reloaded when config.json changes (e.g. after a sidecar persisted a new
+release). If the new bundle cannot be loaded, the previous one keeps being served.`,
+		Example: `  # Serve the default cache directory ($HOME/.tpmtb) on 127.0.0.1:8787
+  tpmtb serve
+
+  # Reload the bundle when a sidecar updates the cache
+  tpmtb serve --cache-path /var/lib/tpmtb --watch-cache
+
+  # Serve over a unix socket
+  tpmtb serve --socket /run/tpmtb.sock --watch-cache`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Run(cmd.Context(), o)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.CachePath, "cache-path", "", "Cache directory path (default: $HOME/.tpmtb)")
+	cmd.Flags().StringVar(&o.ListenAddr, "listen", defaultListenAddr, "TCP address to listen on")
+	cmd.Flags().StringVar(&o.SocketPath, "socket", "", "Unix socket path to listen on, instead of a TCP address")
+	cmd.Flags().BoolVar(&o.WatchCache, "watch-cache", false, "Reload the bundle when the cache changes")
+	cmd.Flags().DurationVar(&o.PollInterval, "poll-interval", defaultPollInterval, "Interval between two checks of the cache (with --watch-cache)")
+	cmd.Flags().BoolVar(&o.OfflineMode, "offline", false, "Verify the bundle with the trusted root stored in the cache")
+	cmd.MarkFlagsMutuallyExclusive("listen", "socket")
+
+	return cmd
+}
+
+// Run executes the serve command with the given options until ctx is done or
+// an interrupt signal is received.
+func Run(ctx context.Context, o *Opts) error {
+	if o.WatchCache && o.PollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cachePath := o.CachePath
+	if cachePath == "" {
+		cachePath = cache.CacheDir()
+	}
+	s := newServer(cachePath, func(ctx context.Context) (apiv1beta.TrustedBundle, error) {
+		return apiv1beta.LoadTrustedBundle(ctx, apiv1beta.LoadConfig{
+			CachePath:   cachePath,
+			OfflineMode: o.OfflineMode,
+			Logger:      cli.Logger(),
+		})
+	})
+	if err := s.reload(ctx); err != nil {
+		return fmt.Errorf("failed to load bundle: %w", err)
+	}
+	defer s.stop()
+
+	listener, err := listen(o)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if o.WatchCache {
+		go s.watch(ctx, o.PollInterval)
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- srv.Serve(listener)
+	}()
+	cli.DisplaySuccess("✅ Serving bundle %s on %s", s.current().GetRootMetadata().Date, listener.Addr())
+
+	select {
+	case err := <-errC:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
+
+// listen opens the unix socket or TCP listener of the API.
+func listen(o *Opts) (net.Listener, error) {
+	if o.SocketPath == "" {
+		listener, err := net.Listen("tcp", o.ListenAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", o.ListenAddr, err)
+		}
+		return listener, nil
+	}
+
+	// A socket left by a previous run would make the listener fail
+	if err := os.Remove(o.SocketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", o.SocketPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", o.SocketPath, err)
+	}
+	return listener, nil
+}
+
+// server serves the bundle loaded from a cache directory, and swaps it for a new one on reload.
+type server struct {
+	cachePath string
+	load      func(ctx context.Context) (apiv1beta.TrustedBundle, error)
+
+	mu       sync.RWMutex
+	tb       apiv1beta.TrustedBundle
+	loadedAt time.Time
+	// cacheConfig is the content of config.json when the served bundle was loaded.
+	cacheConfig []byte
+}
+
+func newServer(cachePath string, load func(ctx context.Context) (apiv1beta.TrustedBundle, error)) *server {
+	return &server{cachePath: cachePath, load: load}
+}
+
+// current returns the served bundle.
+func (s *server) current() apiv1beta.TrustedBundle {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tb
+}
+
+// reload loads the bundle from the cache and serves it in place of the previous one,
+// which keeps being served if the load fails.
+func (s *server) reload(ctx context.Context) error {
+	// Read before loading: a change made during the load triggers another reload
+	cacheConfig, err := cache.LoadFile(s.cachePath, cache.ConfigFilename)
+	if err != nil {
+		return err
+	}
+	tb, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	previous := s.tb
+	s.tb = tb
+	s.loadedAt = time.Now()
+	s.cacheConfig = cacheConfig
+	s.mu.Unlock()
+
+	if previous != nil {
+		if err := previous.Stop(); err != nil {
+			cli.DisplayWarning("⚠️  Failed to stop the previous bundle: %v", err)
+		}
+	}
+	return nil
+}
+
+// changed reports whether config.json differs from the one of the served bundle.
+func (s *server) changed() bool {
+	cacheConfig, err := cache.LoadFile(s.cachePath, cache.ConfigFilename)
+	if err != nil {
+		// Likely being rewritten: checked again on the next tick
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !bytes.Equal(cacheConfig, s.cacheConfig)
+}
+
+// watch reloads the bundle every time config.json changes, until ctx is done.
+func (s *server) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.changed() {
+				continue
+			}
+			if err := s.reload(ctx); err != nil {
+				cli.DisplayWarning("⚠️  Failed to reload bundle, still serving the previous one: %v", err)
+				continue
+			}
+			cli.Display("Reloaded bundle %s", s.current().GetRootMetadata().Date)
+		}
+	}
+}
+
+// stop stops the served bundle.
+func (s *server) stop() {
+	if tb := s.current(); tb != nil {
+		_ = tb.Stop()
+	}
+}
+
+// metadataResponse is the body of the /v1/metadata endpoint.
+type metadataResponse struct {
+	Root         *bundleMetadata `json:"root"`
+	Intermediate *bundleMetadata `json:"intermediate,omitempty"`
+	LoadedAt     time.Time       `json:"loadedAt"`
+}
+
+// bundleMetadata is the global metadata of a served bundle.
+type bundleMetadata struct {
+	Date   string `json:"date"`
+	Commit string `json:"commit"`
+	Type   string `json:"type"`
+	Format int    `json:"format"`
+}
+
+func newBundleMetadata(metadata *bundle.Metadata) *bundleMetadata {
+	if metadata == nil {
+		return nil
+	}
+	return &bundleMetadata{
+		Date:   metadata.Date,
+		Commit: metadata.Commit,
+		Type:   metadata.Type.String(),
+		Format: metadata.Format,
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/roots", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-pem-file")
+		_, _ = w.Write(s.current().GetRawRoot())
+	})
+	mux.HandleFunc("GET /v1/metadata", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		resp := metadataResponse{
+			Root:         newBundleMetadata(s.tb.GetRootMetadata()),
+			Intermediate: newBundleMetadata(s.tb.GetIntermediateMetadata()),
+			LoadedAt:     s.loadedAt,
+		}
+		s.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	// Responds with 404 unless the Prometheus exporter is enabled
+	mux.Handle("GET /metrics", apiv1beta.MetricsHandler())
+	return mux
+}
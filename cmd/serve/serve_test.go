@@ -0,0 +1,149 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+func TestServeWatchCache(t *testing.T) {
+	const newDate = "2026-01-15"
+
+	configData, _ := json.Marshal(apiv1beta.CacheConfig{Version: testutil.BundleVersion, SkipVerify: true, LastTimestamp: time.Now()})
+	cachePath := testutil.CreateCacheDir(t, configData)
+
+	s := newServer(cachePath, func(ctx context.Context) (apiv1beta.TrustedBundle, error) {
+		return apiv1beta.LoadTrustedBundle(ctx, apiv1beta.LoadConfig{CachePath: cachePath})
+	})
+	if err := s.reload(t.Context()); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	defer s.stop()
+
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	getMetadata := func(t *testing.T) metadataResponse {
+		t.Helper()
+		resp, err := http.Get(ts.URL + "/v1/metadata")
+		if err != nil {
+			t.Fatalf("GET /v1/metadata error = %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /v1/metadata status = %d", resp.StatusCode)
+		}
+		var metadata metadataResponse
+		if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+			t.Fatalf("failed to decode metadata: %v", err)
+		}
+		return metadata
+	}
+
+	if got := getMetadata(t).Root.Date; got != testutil.BundleVersion {
+		t.Fatalf("served date = %s, want %s", got, testutil.BundleVersion)
+	}
+
+	resp, err := http.Get(ts.URL + "/v1/roots")
+	if err != nil {
+		t.Fatalf("GET /v1/roots error = %v", err)
+	}
+	var roots bytes.Buffer
+	_, _ = roots.ReadFrom(resp.Body)
+	resp.Body.Close()
+	if !bytes.Equal(roots.Bytes(), s.current().GetRawRoot()) {
+		t.Error("expected /v1/roots to serve the root bundle")
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go s.watch(ctx, 10*time.Millisecond)
+
+	// A sidecar persists a new release: bundle first, then config.json
+	rootData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	rootData = bytes.Replace(rootData, []byte("## Date: "+testutil.BundleVersion), []byte("## Date: "+newDate), 1)
+	if err := cache.SaveFile(cachePath, cache.RootBundleFilename, rootData); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	configData, _ = json.Marshal(apiv1beta.CacheConfig{Version: newDate, SkipVerify: true, LastTimestamp: time.Now()})
+	if err := cache.SaveFile(cachePath, cache.ConfigFilename, configData); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for getMetadata(t).Root.Date != newDate {
+		if time.Now().After(deadline) {
+			t.Fatalf("served metadata was not updated to %s", newDate)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServeReloadFailureKeepsBundle(t *testing.T) {
+	configData, _ := json.Marshal(apiv1beta.CacheConfig{Version: testutil.BundleVersion, SkipVerify: true, LastTimestamp: time.Now()})
+	cachePath := testutil.CreateCacheDir(t, configData)
+
+	s := newServer(cachePath, func(ctx context.Context) (apiv1beta.TrustedBundle, error) {
+		return apiv1beta.LoadTrustedBundle(ctx, apiv1beta.LoadConfig{CachePath: cachePath})
+	})
+	if err := s.reload(t.Context()); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	defer s.stop()
+	served := s.current()
+
+	if err := cache.SaveFile(cachePath, cache.ConfigFilename, []byte("{")); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if !s.changed() {
+		t.Fatal("expected the cache change to be detected")
+	}
+	if err := s.reload(t.Context()); err == nil {
+		t.Fatal("expected reload() to fail on a malformed config")
+	}
+	if s.current() != served {
+		t.Error("expected the previous bundle to keep being served")
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	configData, _ := json.Marshal(apiv1beta.CacheConfig{Version: testutil.BundleVersion, SkipVerify: true, LastTimestamp: time.Now()})
+	cachePath := testutil.CreateCacheDir(t, configData)
+
+	s := newServer(cachePath, func(ctx context.Context) (apiv1beta.TrustedBundle, error) {
+		return apiv1beta.LoadTrustedBundle(ctx, apiv1beta.LoadConfig{CachePath: cachePath})
+	})
+	if err := s.reload(t.Context()); err != nil {
+		t.Fatalf("reload() error = %v", err)
+	}
+	defer s.stop()
+
+	ts := httptest.NewServer(s.handler())
+	defer ts.Close()
+
+	shutdown, err := apiv1beta.EnablePrometheusMetrics(t.Context())
+	if err != nil {
+		t.Fatalf("EnablePrometheusMetrics() error = %v", err)
+	}
+	defer shutdown(context.Background())
+
+	resp, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /metrics status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
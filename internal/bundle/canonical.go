@@ -0,0 +1,107 @@
+package bundle
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+)
+
+// Canonicalize re-emits a TPM trust bundle in its canonical form.
+//
+// Certificates are sorted by vendor ID, then by SHA-256 fingerprint, and every header
+// (global and certificate metadata) is regenerated with the format of [BuildBundleHeader]
+// and [BuildCertificateHeader]. Equivalent bundles (same metadata and certificates, whatever
+// their order or whitespace) thus produce byte-identical output, which makes bundle
+// generation reproducible and bundles easy to diff.
+//
+// Each document of a combined bundle (see [SplitDocuments]) is canonicalized on its own.
+//
+// Example:
+//
+//	canonical, err := bundle.Canonicalize(bundleData)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if !bytes.Equal(canonical, bundleData) {
+//	    fmt.Println("bundle is not in canonical form")
+//	}
+func Canonicalize(data []byte) ([]byte, error) {
+	docs, err := SplitDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	canonicalDocs := make([][]byte, 0, len(docs))
+	for _, doc := range docs {
+		canonical, err := canonicalizeDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		canonicalDocs = append(canonicalDocs, canonical)
+	}
+	return bytes.Join(canonicalDocs, []byte(DocumentSeparator)), nil
+}
+
+// canonicalEntry is a certificate entry of a bundle being canonicalized.
+type canonicalEntry struct {
+	owner       vendors.ID
+	fingerprint [sha256.Size]byte
+	block       string
+}
+
+// canonicalizeDocument re-emits a single bundle document in its canonical form.
+func canonicalizeDocument(doc []byte) ([]byte, error) {
+	metadata, err := ParseMetadata(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ParseEntries(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s bundle", metadata.Type)
+	}
+
+	canonicalEntries := make([]canonicalEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Err != nil {
+			return nil, fmt.Errorf("invalid certificate entry at line %d: %w", entry.Line, entry.Err)
+		}
+		owner := vendors.ID(entry.Headers[CertMetadataKeyOwner.Key()])
+		if err := owner.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid certificate entry at line %d: %w", entry.Line, err)
+		}
+
+		cert := entry.Certificate
+		name := entry.Headers[CertMetadataKeyCertificate.Key()]
+		canonicalEntries = append(canonicalEntries, canonicalEntry{
+			owner:       owner,
+			fingerprint: sha256.Sum256(cert.Raw),
+			block:       BuildCertificateHeader(cert, name, string(owner)) + string(EncodePEM(cert)),
+		})
+	}
+
+	slices.SortStableFunc(canonicalEntries, func(a, b canonicalEntry) int {
+		return cmp.Or(
+			cmp.Compare(a.owner, b.owner),
+			bytes.Compare(a.fingerprint[:], b.fingerprint[:]),
+		)
+	})
+
+	blocks := make([]string, 0, len(canonicalEntries))
+	for _, entry := range canonicalEntries {
+		blocks = append(blocks, entry.block)
+	}
+
+	var canonical strings.Builder
+	canonical.WriteString(BuildBundleHeader("", metadata.Date, metadata.Commit, metadata.Type))
+	canonical.WriteString(strings.Join(blocks, "\n"))
+	return []byte(canonical.String()), nil
+}
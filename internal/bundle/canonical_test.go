@@ -0,0 +1,112 @@
+package bundle_test
+
+import (
+	"bytes"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestCanonicalize(t *testing.T) {
+	type entry struct {
+		cert  *x509.Certificate
+		owner vendors.ID
+	}
+	newEntry := func(cn string, owner vendors.ID) entry {
+		der, _ := testutil.GenerateTestCertWithCN(t, cn)
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %v", err)
+		}
+		return entry{cert: cert, owner: owner}
+	}
+	stmA, stmB, ifx := newEntry("STM Root A", vendors.STM), newEntry("STM Root B", vendors.STM), newEntry("IFX Root", vendors.IFX)
+
+	header := bundlepkg.BuildBundleHeader("", "2025-12-03", "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0", bundlepkg.TypeRoot)
+	build := func(separator string, entries ...entry) []byte {
+		blocks := make([]string, 0, len(entries))
+		for _, e := range entries {
+			blocks = append(blocks, bundlepkg.FormatCertificateMetadata(e.cert, e.owner)+string(bundlepkg.EncodePEM(e.cert)))
+		}
+		return []byte(header + strings.Join(blocks, separator))
+	}
+
+	first := build("\n", stmA, ifx, stmB)
+	// Same certificates in another order, with extra blank lines and trailing whitespace
+	second := bytes.ReplaceAll(build("\n\n\n", ifx, stmB, stmA), []byte("# Owner: STM\n"), []byte("# Owner: STM   \n"))
+
+	canonicalFirst, err := bundlepkg.Canonicalize(first)
+	if err != nil {
+		t.Fatalf("Canonicalize() error = %v", err)
+	}
+	canonicalSecond, err := bundlepkg.Canonicalize(second)
+	if err != nil {
+		t.Fatalf("Canonicalize() error = %v", err)
+	}
+	if !bytes.Equal(canonicalFirst, canonicalSecond) {
+		t.Errorf("expected identical canonical output, got:\n%s\n---\n%s", canonicalFirst, canonicalSecond)
+	}
+
+	t.Run("idempotent", func(t *testing.T) {
+		again, err := bundlepkg.Canonicalize(canonicalFirst)
+		if err != nil {
+			t.Fatalf("Canonicalize() error = %v", err)
+		}
+		if !bytes.Equal(again, canonicalFirst) {
+			t.Errorf("canonical output changed when canonicalized again")
+		}
+	})
+
+	t.Run("vendors sorted", func(t *testing.T) {
+		catalog, err := bundlepkg.ParseBundle(canonicalFirst)
+		if err != nil {
+			t.Fatalf("ParseBundle() error = %v", err)
+		}
+		if len(catalog[vendors.IFX]) != 1 || len(catalog[vendors.STM]) != 2 {
+			t.Fatalf("unexpected catalog: %v", catalog)
+		}
+		if strings.Index(string(canonicalFirst), "# Owner: IFX") > strings.Index(string(canonicalFirst), "# Owner: STM") {
+			t.Errorf("expected IFX certificates before STM ones")
+		}
+	})
+
+	t.Run("valid bundle", func(t *testing.T) {
+		errors, err := bundlepkg.NewBundleValidator().ValidateBundle(canonicalFirst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, e := range errors {
+			t.Errorf("Line %d: %s", e.Line, e.Message)
+		}
+	})
+
+	t.Run("merged bundles are canonical", func(t *testing.T) {
+		intermediateHeader := bundlepkg.BuildBundleHeader("", "2025-12-03", "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0", bundlepkg.TypeIntermediate)
+		intermediate, err := bundlepkg.Canonicalize(bytes.Replace(first, []byte(header), []byte(intermediateHeader), 1))
+		if err != nil {
+			t.Fatalf("Canonicalize() error = %v", err)
+		}
+		combined, err := bundlepkg.Merge(canonicalFirst, intermediate)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+		canonical, err := bundlepkg.Canonicalize(combined)
+		if err != nil {
+			t.Fatalf("Canonicalize() error = %v", err)
+		}
+		if !bytes.Equal(canonical, combined) {
+			t.Errorf("expected the merge of canonical bundles to be canonical")
+		}
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		invalid := bytes.Replace(first, []byte("# Owner: IFX\n"), nil, 1)
+		if _, err := bundlepkg.Canonicalize(invalid); err == nil {
+			t.Fatal("expected an error for an entry without owner")
+		}
+	})
+}
@@ -1,7 +1,12 @@
 package bundle
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 )
@@ -48,6 +53,30 @@ func (t BundleType) DefaultFilename() string {
 	}
 }
 
+// ResolveType determines the bundle type based on the --type flag or config filename.
+//
+// Priority:
+//  1. If typeFlag is provided (non-empty), use it
+//  2. Otherwise, infer from config filename:
+//     - ".tpm-intermediates.yaml" → intermediate
+//     - anything else → root
+func ResolveType(typeFlag, configPath string) (BundleType, error) {
+	// If type is explicitly provided, use it
+	if typeFlag != "" {
+		bundleType := BundleType(typeFlag)
+		if err := bundleType.Validate(); err != nil {
+			return "", err
+		}
+		return bundleType, nil
+	}
+
+	// Auto-detect from config filename (check basename only)
+	if filepath.Base(configPath) == ".tpm-intermediates.yaml" {
+		return TypeIntermediate, nil
+	}
+	return TypeRoot, nil
+}
+
 // Description returns the human-readable description for the bundle type
 // used in the bundle header.
 func (t BundleType) Description() string {
@@ -74,6 +103,10 @@ const (
 
 	// PEMEndMarker is the PEM certificate end marker.
 	PEMEndMarker = "-----END CERTIFICATE-----"
+
+	// DocumentSeparator separates the documents of a combined bundle (see [SplitDocuments]):
+	// a blank line between the last certificate of the root bundle and the intermediate one.
+	DocumentSeparator = "\n"
 )
 
 // MetadataKey represents a metadata key with its prefix.
@@ -101,6 +134,54 @@ func (m MetadataKey) Prefix() string {
 	return m.prefix
 }
 
+// Markers are the prefixes of the metadata lines of a bundle.
+//
+// The bundles generated by this repository use [DefaultMarkers]; other markers
+// allow to parse compatible formats.
+type Markers struct {
+	// Global is the prefix of the global metadata lines.
+	//
+	// Optional. Default: [GlobalMetadataPrefix].
+	Global string
+
+	// Cert is the prefix of the certificate metadata lines.
+	//
+	// Optional. Default: [CertMetadataPrefix].
+	Cert string
+}
+
+// DefaultMarkers returns the markers of the bundles generated by this repository.
+func DefaultMarkers() Markers {
+	return Markers{Global: GlobalMetadataPrefix, Cert: CertMetadataPrefix}
+}
+
+// CheckAndSetDefaults validates the markers and sets the default values.
+func (m *Markers) CheckAndSetDefaults() error {
+	if m.Global == "" {
+		m.Global = GlobalMetadataPrefix
+	}
+	if m.Cert == "" {
+		m.Cert = CertMetadataPrefix
+	}
+	if strings.ContainsFunc(m.Global+m.Cert, unicode.IsSpace) {
+		return fmt.Errorf("metadata markers cannot contain whitespace")
+	}
+	// Global metadata is detected first: a certificate line must not look like a global one
+	if strings.HasPrefix(m.Cert, m.Global) {
+		return fmt.Errorf("certificate metadata marker %q cannot start with global metadata marker %q", m.Cert, m.Global)
+	}
+	return nil
+}
+
+// key returns the formatted metadata key using these markers, see [MetadataKey.String].
+func (m Markers) key(k MetadataKey) string {
+	prefix := m.Cert
+	if k.prefix == GlobalMetadataPrefix {
+		prefix = m.Global
+	}
+	return prefix + " " + k.key + ": "
+}
+
 // Global metadata keys used in the bundle header.
 var (
 	// MetadataKeyDate is the key for the bundle generation date (YYYY-MM-DD format).
@@ -108,8 +189,32 @@ var (
 
 	// MetadataKeyCommit is the key for the Git commit hash (40-character hex string).
 	MetadataKeyCommit = MetadataKey{prefix: GlobalMetadataPrefix, key: "Commit"}
+
+	// MetadataKeyFormat is the key for the bundle format version (see [FormatVersion]).
+	MetadataKeyFormat = MetadataKey{prefix: GlobalMetadataPrefix, key: "Format"}
 )
 
+// FormatVersion is the latest version of the bundle format, written by [BuildBundleHeader].
+//
+// Bundles released before the format was versioned have no Format metadata: they
+// use version 1.
+const FormatVersion = 1
+
+// ErrUnsupportedFormatVersion is returned when a bundle declares a format version
+// this release does not know, e.g. a bundle generated by a newer release.
+var ErrUnsupportedFormatVersion = errors.New("unsupported bundle format version")
+
+// ParseFormatVersion parses the value of the Format metadata.
+//
+// It returns [ErrUnsupportedFormatVersion] for any version other than 1 to [FormatVersion].
+func ParseFormatVersion(value string) (int, error) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 || version > FormatVersion {
+		return 0, fmt.Errorf("%w %q: supported versions are 1 to %d", ErrUnsupportedFormatVersion, value, FormatVersion)
+	}
+	return version, nil
+}
+
 // Certificate metadata keys used in certificate blocks.
 var (
 	// CertMetadataKeyCertificate is the key for the certificate name/identifier.
@@ -0,0 +1,34 @@
+package bundle_test
+
+import (
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+)
+
+func TestResolveType(t *testing.T) {
+	tests := []struct {
+		name       string
+		typeFlag   string
+		configPath string
+		want       bundle.BundleType
+		wantErr    bool
+	}{
+		{name: "explicit type wins", typeFlag: "root", configPath: ".tpm-intermediates.yaml", want: bundle.TypeRoot},
+		{name: "intermediate config", configPath: "configs/.tpm-intermediates.yaml", want: bundle.TypeIntermediate},
+		{name: "root config", configPath: ".tpm-roots.yaml", want: bundle.TypeRoot},
+		{name: "invalid type", typeFlag: "leaf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bundle.ResolveType(tt.typeFlag, tt.configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/digitorus/pkcs7"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+)
+
+// ExportFormat represents the output format of an exported bundle.
+type ExportFormat string
+
+const (
+	// FormatPEM exports certificates as concatenated PEM blocks.
+	FormatPEM ExportFormat = "pem"
+
+	// FormatP7B exports certificates as a DER-encoded degenerate PKCS#7 (certificates only, no signers).
+	FormatP7B ExportFormat = "p7b"
+
+	// FormatDERDir exports each certificate as a DER file named by its SHA-256 fingerprint.
+	FormatDERDir ExportFormat = "der-dir"
+)
+
+// String returns the string representation of the export format.
+func (f ExportFormat) String() string {
+	return string(f)
+}
+
+// Validate checks if the export format is valid.
+func (f ExportFormat) Validate() error {
+	switch f {
+	case FormatPEM, FormatP7B, FormatDERDir:
+		return nil
+	default:
+		return fmt.Errorf("invalid export format %q: must be one of [pem, p7b, der-dir]", f)
+	}
+}
+
+// SelectCertificates returns the certificates of the catalog matching the vendor filter.
+//
+// Certificates are ordered by vendor ID, then by their position in the bundle.
+// If vendorIDs is empty, all vendors are included.
+func SelectCertificates(catalog map[vendors.ID][]*x509.Certificate, vendorIDs []vendors.ID) []*x509.Certificate {
+	ids := make([]vendors.ID, 0, len(catalog))
+	for id := range catalog {
+		if len(vendorIDs) == 0 || slices.Contains(vendorIDs, id) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var certs []*x509.Certificate
+	for _, id := range ids {
+		certs = append(certs, catalog[id]...)
+	}
+	return certs
+}
+
+// EncodePEMBundle encodes certificates as concatenated PEM blocks.
+func EncodePEMBundle(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		buf.Write(EncodePEM(cert))
+	}
+	return buf.Bytes()
+}
+
+// FilterBundle re-emits a TPM trust bundle keeping only the certificates of the given vendors.
+//
+// The global and certificate metadata blocks are regenerated with [BuildBundleHeader] and
+// [BuildCertificateHeader], keeping the certificate names, so that the output is
+// self-describing and passes [BundleValidator]. Certificates are ordered like
+// [SelectCertificates]. If vendorIDs is empty, all vendors are kept.
+//
+// Each document of a combined bundle (see [SplitDocuments]) is filtered on its own, and
+// documents left without certificates are dropped.
+func FilterBundle(data []byte, vendorIDs []vendors.ID) ([]byte, error) {
+	docs, err := SplitDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var filteredDocs []string
+	for _, doc := range docs {
+		metadata, err := ParseMetadata(doc)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := ParseEntries(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		blocks := make(map[vendors.ID][]string)
+		for _, entry := range entries {
+			if entry.Err != nil {
+				return nil, fmt.Errorf("invalid certificate entry at line %d: %w", entry.Line, entry.Err)
+			}
+			owner := vendors.ID(entry.Headers[CertMetadataKeyOwner.Key()])
+			if err := owner.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid certificate entry at line %d: %w", entry.Line, err)
+			}
+			if len(vendorIDs) > 0 && !slices.Contains(vendorIDs, owner) {
+				continue
+			}
+			name := entry.Headers[CertMetadataKeyCertificate.Key()]
+			blocks[owner] = append(blocks[owner], BuildCertificateHeader(entry.Certificate, name, string(owner))+string(EncodePEM(entry.Certificate)))
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+
+		owners := make([]vendors.ID, 0, len(blocks))
+		for owner := range blocks {
+			owners = append(owners, owner)
+		}
+		slices.Sort(owners)
+
+		var filtered strings.Builder
+		filtered.WriteString(BuildBundleHeader("", metadata.Date, metadata.Commit, metadata.Type))
+		for i, owner := range owners {
+			if i > 0 {
+				filtered.WriteString("\n")
+			}
+			filtered.WriteString(strings.Join(blocks[owner], "\n"))
+		}
+		filteredDocs = append(filteredDocs, filtered.String())
+	}
+
+	if len(filteredDocs) == 0 {
+		return nil, fmt.Errorf("no certificates match the vendor filter")
+	}
+	return []byte(strings.Join(filteredDocs, DocumentSeparator)), nil
+}
+
+// EncodePKCS7 encodes certificates as a DER-encoded degenerate PKCS#7 SignedData
+// structure (also known as a .p7b file).
+func EncodePKCS7(certs []*x509.Certificate) ([]byte, error) {
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates to encode")
+	}
+
+	var raw []byte
+	for _, cert := range certs {
+		raw = append(raw, cert.Raw...)
+	}
+
+	data, err := pkcs7.DegenerateCertificate(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PKCS#7: %w", err)
+	}
+	return data, nil
+}
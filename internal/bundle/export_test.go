@@ -0,0 +1,106 @@
+package bundle_test
+
+import (
+	"bytes"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestFilterBundle(t *testing.T) {
+	newCert := func(cn string) *x509.Certificate {
+		der, _ := testutil.GenerateTestCertWithCN(t, cn)
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %v", err)
+		}
+		return cert
+	}
+	stm, ifx, ntc := newCert("STM Root"), newCert("IFX Root"), newCert("NTC Intermediate")
+
+	const commit = "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0"
+	root := bundlepkg.BuildBundleHeader("", "2025-12-03", commit, bundlepkg.TypeRoot) +
+		bundlepkg.BuildCertificateHeader(stm, "STMicroelectronics Root", string(vendors.STM)) + string(bundlepkg.EncodePEM(stm)) + "\n" +
+		bundlepkg.FormatCertificateMetadata(ifx, vendors.IFX) + string(bundlepkg.EncodePEM(ifx))
+	intermediate := bundlepkg.BuildBundleHeader("", "2025-12-03", commit, bundlepkg.TypeIntermediate) +
+		bundlepkg.FormatCertificateMetadata(ntc, vendors.NTC) + string(bundlepkg.EncodePEM(ntc))
+
+	assertValid := func(t *testing.T, data []byte) {
+		t.Helper()
+		errs, err := bundlepkg.NewBundleValidator().ValidateBundle(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, e := range errs {
+			t.Errorf("Line %d: %s", e.Line, e.Message)
+		}
+	}
+
+	t.Run("keeps the metadata of the selected vendors", func(t *testing.T) {
+		filtered, err := bundlepkg.FilterBundle([]byte(root), []vendors.ID{vendors.STM})
+		if err != nil {
+			t.Fatalf("FilterBundle() error = %v", err)
+		}
+		assertValid(t, filtered)
+
+		entries, err := bundlepkg.ParseEntries(filtered)
+		if err != nil {
+			t.Fatalf("ParseEntries() error = %v", err)
+		}
+		if len(entries) != 1 || !entries[0].Certificate.Equal(stm) {
+			t.Fatalf("expected the STM certificate only, got %d entries", len(entries))
+		}
+		// The certificate name is preserved rather than derived from the subject
+		if got := entries[0].Headers[bundlepkg.CertMetadataKeyCertificate.Key()]; got != "STMicroelectronics Root" {
+			t.Errorf("certificate name = %q, want %q", got, "STMicroelectronics Root")
+		}
+		metadata, err := bundlepkg.ParseMetadata(filtered)
+		if err != nil {
+			t.Fatalf("ParseMetadata() error = %v", err)
+		}
+		if metadata.Date != "2025-12-03" || metadata.Commit != commit {
+			t.Errorf("unexpected global metadata: %+v", metadata)
+		}
+	})
+
+	t.Run("orders certificates by vendor", func(t *testing.T) {
+		filtered, err := bundlepkg.FilterBundle([]byte(root), nil)
+		if err != nil {
+			t.Fatalf("FilterBundle() error = %v", err)
+		}
+		assertValid(t, filtered)
+		if bytes.Index(filtered, bundlepkg.EncodePEM(ifx)) > bytes.Index(filtered, bundlepkg.EncodePEM(stm)) {
+			t.Error("expected the IFX certificate before the STM certificate")
+		}
+	})
+
+	t.Run("drops emptied documents of a combined bundle", func(t *testing.T) {
+		combined, err := bundlepkg.Merge([]byte(root), []byte(intermediate))
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		filtered, err := bundlepkg.FilterBundle(combined, []vendors.ID{vendors.NTC})
+		if err != nil {
+			t.Fatalf("FilterBundle() error = %v", err)
+		}
+		assertValid(t, filtered)
+		docs, err := bundlepkg.SplitDocuments(filtered)
+		if err != nil {
+			t.Fatalf("SplitDocuments() error = %v", err)
+		}
+		if len(docs) != 1 || !strings.Contains(string(docs[0]), "NTC Intermediate") {
+			t.Errorf("expected the intermediate document only, got %d documents", len(docs))
+		}
+	})
+
+	t.Run("no matching vendor", func(t *testing.T) {
+		if _, err := bundlepkg.FilterBundle([]byte(root), []vendors.ID{vendors.INTC}); err == nil {
+			t.Fatal("expected an error when no certificate matches the vendor filter")
+		}
+	})
+}
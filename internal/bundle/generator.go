@@ -7,15 +7,16 @@ import (
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
-	"sync"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/validate"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 )
 
@@ -36,7 +37,8 @@ func NewGenerator(optionalClient ...utils.HTTPClient) *Generator {
 // It downloads all certificates, validates their fingerprints, converts them to PEM format,
 // and concatenates them into a single bundle.
 //
-// The process fails immediately on the first error (fail-fast approach).
+// A failed certificate does not stop the others from being processed: no bundle is
+// returned if any certificate fails, and the error reports every failed certificate.
 //
 // Example:
 //
@@ -50,10 +52,10 @@ func (g *Generator) Generate(cfg *config.TPMRootsConfig) (string, error) {
 	return g.GenerateWithConcurrency(cfg, 1)
 }
 
-// GenerateWithConcurrency creates a PEM-encoded trust bundle with concurrent vendor processing.
+// GenerateWithConcurrency creates a PEM-encoded trust bundle with concurrent certificate processing.
 //
-// It processes vendors in parallel (up to 'workers' vendors at once) while maintaining
-// the certificate order from the configuration file.
+// It processes certificates in parallel (up to 'workers' certificates at once, whatever
+// their vendor) while maintaining the certificate order from the configuration file.
 // If workers is 0, it auto-detects the optimal count.
 // The number of workers is capped at [concurrency.MaxWorkers].
 //
@@ -71,10 +73,10 @@ func (g *Generator) GenerateWithConcurrency(cfg *config.TPMRootsConfig, workers
 	return g.GenerateWithConcurrencyAndOutput(cfg, workers, "")
 }
 
-// GenerateWithConcurrencyAndOutput creates a PEM-encoded trust bundle with concurrent vendor processing.
+// GenerateWithConcurrencyAndOutput creates a PEM-encoded trust bundle with concurrent certificate processing.
 //
-// It processes vendors in parallel (up to 'workers' vendors at once) while maintaining
-// the certificate order from the configuration file.
+// It processes certificates in parallel (up to 'workers' certificates at once, whatever
+// their vendor) while maintaining the certificate order from the configuration file.
 // If workers is 0, it auto-detects the optimal count.
 // The number of workers is capped at [concurrency.MaxWorkers].
 //
@@ -93,10 +95,10 @@ func (g *Generator) GenerateWithConcurrencyAndOutput(cfg *config.TPMRootsConfig,
 	return g.GenerateWithMetadata(cfg, workers, outputPath, "", "", TypeRoot)
 }
 
-// GenerateWithMetadata creates a PEM-encoded trust bundle with concurrent vendor processing and git metadata.
+// GenerateWithMetadata creates a PEM-encoded trust bundle with concurrent certificate processing and git metadata.
 //
-// It processes vendors in parallel (up to 'workers' vendors at once) while maintaining
-// the certificate order from the configuration file.
+// It processes certificates in parallel (up to 'workers' certificates at once, whatever
+// their vendor) while maintaining the certificate order from the configuration file.
 // If workers is 0, it auto-detects the optimal count.
 // The number of workers is capped at [concurrency.MaxWorkers].
 //
@@ -114,81 +116,48 @@ func (g *Generator) GenerateWithConcurrencyAndOutput(cfg *config.TPMRootsConfig,
 //	}
 //	fmt.Println(pemBundle)
 func (g *Generator) GenerateWithMetadata(cfg *config.TPMRootsConfig, workers int, outputPath, date, commit string, bundleType BundleType) (string, error) {
-	if workers == 0 {
-		workers = concurrency.DetectCPUCount()
-	}
-	if workers > concurrency.MaxWorkers {
-		workers = concurrency.MaxWorkers
-	}
-	if workers < 1 {
-		workers = 1
+	type certInput struct {
+		vendor config.Vendor
+		cert   config.Certificate
 	}
 
-	// Prepare result storage that maintains order
 	type certResult struct {
-		certIdx  int
 		pemBlock string
+		err      error
 	}
 
-	type vendorResult struct {
-		vendorIdx int
-		certs     []certResult
-		err       error
-	}
-
-	// Create a channel to limit concurrent vendor processing
-	vendorChan := make(chan int, workers)
-
-	results := make([]vendorResult, len(cfg.Vendors))
-	var wg sync.WaitGroup
-
-	// Process each vendor concurrently
-	for vendorIdx, vendor := range cfg.Vendors {
-		wg.Add(1)
-		go func(vIdx int, v config.Vendor) {
-			defer wg.Done()
-
-			// Acquire worker slot
-			vendorChan <- 1
-			defer func() { <-vendorChan }()
-
-			certs := make([]certResult, len(v.Certificates))
-
-			// Process certificates for this vendor sequentially to maintain order
-			for certIdx, cert := range v.Certificates {
-				pemBlock, err := g.processCertificate(cert, v.ID)
-				if err != nil {
-					results[vIdx] = vendorResult{
-						vendorIdx: vIdx,
-						err: fmt.Errorf("failed to process certificate %q from vendor %q: %w",
-							cert.Name, v.Name, err),
-					}
-					return
-				}
-				certs[certIdx] = certResult{
-					certIdx:  certIdx,
-					pemBlock: pemBlock,
-				}
-			}
-
-			results[vIdx] = vendorResult{
-				vendorIdx: vIdx,
-				certs:     certs,
-			}
-		}(vendorIdx, vendor)
+	// Certificates of all vendors share the worker pool, so that a vendor with
+	// many certificates (or a slow server) does not hold back the others
+	var inputs []certInput
+	for _, vendor := range cfg.Vendors {
+		for _, cert := range vendor.Certificates {
+			inputs = append(inputs, certInput{vendor: vendor, cert: cert})
+		}
 	}
 
-	wg.Wait()
-
-	// Check for errors and build final output in order
-	var pemBlocks []string
+	results := concurrency.Execute(workers, inputs, func(_ int, input certInput) certResult {
+		pemBlock, err := g.processCertificate(input.cert, input.vendor.ID)
+		if err != nil {
+			return certResult{err: fmt.Errorf("failed to process certificate %q from vendor %q: %w",
+				input.cert.Name, input.vendor.Name, err)}
+		}
+		return certResult{pemBlock: pemBlock}
+	})
+
+	// Build final output in configuration order, reporting every failed certificate
+	var (
+		pemBlocks []string
+		errs      []error
+	)
 	for _, result := range results {
 		if result.err != nil {
-			return "", result.err
-		}
-		for _, cert := range result.certs {
-			pemBlocks = append(pemBlocks, cert.pemBlock)
+			errs = append(errs, result.err)
+			continue
 		}
+		pemBlocks = append(pemBlocks, result.pemBlock)
+	}
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
 	}
 
 	// Build final bundle with header
@@ -201,12 +170,14 @@ func (g *Generator) GenerateWithMetadata(cfg *config.TPMRootsConfig, workers int
 
 // processCertificate downloads, validates, and converts a certificate to PEM with a comment header.
 func (g *Generator) processCertificate(cert config.Certificate, vendorID string) (string, error) {
-	x509Cert, err := g.downloader.DownloadCertificate(context.Background(), cert.URL)
+	// The URL may serve a whole chain: the certificate is selected by its fingerprint
+	x509Certs, err := g.downloader.DownloadCertificates(context.Background(), cert.URL)
 	if err != nil {
 		return "", err
 	}
 
-	if err := validate.ValidateFingerprint(x509Cert, cert.Validation.Fingerprint); err != nil {
+	x509Cert, err := validate.SelectCertificate(x509Certs, cert.Validation.Fingerprint)
+	if err != nil {
 		return "", fmt.Errorf("fingerprint validation failed: %w", err)
 	}
 
@@ -248,6 +219,19 @@ func BuildCertificateHeader(cert *x509.Certificate, name string, vendorID string
 	return header.String()
 }
 
+// FormatCertificateMetadata returns the metadata block of a certificate owned by owner,
+// as expected by [BundleValidator].
+//
+// The certificate name is its subject common name, or the full subject when it has none.
+// Appending the PEM encoding of the certificate (see [EncodePEM]) yields a complete bundle entry.
+func FormatCertificateMetadata(cert *x509.Certificate, owner vendors.ID) string {
+	name := cert.Subject.CommonName
+	if name == "" {
+		name = cert.Subject.String()
+	}
+	return BuildCertificateHeader(cert, name, string(owner))
+}
+
 // formatFingerprint formats a hash as a colon-separated uppercase hex string.
 func formatFingerprint(hash []byte) string {
 	hexStr := strings.ToUpper(hex.EncodeToString(hash))
@@ -285,6 +269,7 @@ func BuildBundleHeader(outputPath, date, commit string, bundleType BundleType) s
 	if commit != "" {
 		fmt.Fprintf(&header, "## Commit: %s\n", commit)
 	}
+	fmt.Fprintf(&header, "%s%d\n", MetadataKeyFormat, FormatVersion)
 
 	header.WriteString("##\n")
 	header.WriteString("## This file has been auto-generated by tpmtb (TPM Trust Bundle)\n")
@@ -3,13 +3,18 @@ package bundle_test
 import (
 	"crypto/x509"
 	"encoding/pem"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
 )
 
@@ -100,3 +105,162 @@ func formatFingerprintWithColons(hexStr string) string {
 	}
 	return strings.ToUpper(result)
 }
+
+func TestFormatCertificateMetadata(t *testing.T) {
+	certDER, _ := testutil.GenerateTestCertWithCN(t, "Test Root CA")
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	metadata := bundlepkg.FormatCertificateMetadata(cert, vendors.STM)
+	if !strings.Contains(metadata, "# Certificate: Test Root CA\n") {
+		t.Errorf("expected the common name as certificate name, got:\n%s", metadata)
+	}
+
+	data := bundlepkg.BuildBundleHeader("", "2025-12-03", "a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0", bundlepkg.TypeRoot) +
+		metadata + string(bundlepkg.EncodePEM(cert))
+
+	errors, err := bundlepkg.NewBundleValidator().ValidateBundle([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range errors {
+		t.Errorf("Line %d: %s", e.Line, e.Message)
+	}
+}
+
+// vendorServer is a mock vendor server serving certificates by path, after delay.
+type vendorServer struct {
+	*httptest.Server
+	certs map[string][]byte
+}
+
+func newVendorServer(t testing.TB, delay time.Duration, inFlight, maxInFlight *atomic.Int32) *vendorServer {
+	vs := &vendorServer{certs: make(map[string][]byte)}
+	vs.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+
+		der, ok := vs.certs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(der)
+	}))
+	t.Cleanup(vs.Close)
+	return vs
+}
+
+// newMultiVendorConfig returns a configuration of vendorCount vendors with certsPerVendor
+// certificates each, every vendor being served by its own mock server.
+func newMultiVendorConfig(t testing.TB, vendorCount, certsPerVendor int, delay time.Duration) (*config.TPMRootsConfig, []*vendorServer, *atomic.Int32) {
+	var inFlight, maxInFlight atomic.Int32
+	cfg := &config.TPMRootsConfig{Version: "alpha"}
+	servers := make([]*vendorServer, vendorCount)
+	ids := []vendors.ID{vendors.IFX, vendors.NTC, vendors.STM, vendors.INTC}
+	for v := range vendorCount {
+		servers[v] = newVendorServer(t, delay, &inFlight, &maxInFlight)
+		vendor := config.Vendor{ID: string(ids[v]), Name: fmt.Sprintf("Vendor %d", v)}
+		for c := range certsPerVendor {
+			name := fmt.Sprintf("Vendor %d Cert %d", v, c)
+			der, fingerprint := testutil.GenerateTestCertWithCN(t, name)
+			path := fmt.Sprintf("/cert-%d.cer", c)
+			servers[v].certs[path] = der
+			vendor.Certificates = append(vendor.Certificates, config.Certificate{
+				Name: name,
+				URL:  servers[v].URL + path,
+				Validation: config.Validation{
+					Fingerprint: config.Fingerprint{SHA1: formatFingerprintWithColons(fingerprint)},
+				},
+			})
+		}
+		cfg.Vendors = append(cfg.Vendors, vendor)
+	}
+	return cfg, servers, &maxInFlight
+}
+
+func TestGenerateMultipleVendors(t *testing.T) {
+	const vendorCount, certsPerVendor = 3, 4
+
+	t.Run("parallel across vendors and certificates", func(t *testing.T) {
+		cfg, servers, maxInFlight := newMultiVendorConfig(t, vendorCount, certsPerVendor, 20*time.Millisecond)
+		gen := bundlepkg.NewGenerator(servers[0].Client())
+
+		pemBundle, err := gen.GenerateWithConcurrency(cfg, 6)
+		if err != nil {
+			t.Fatalf("GenerateWithConcurrency() error = %v", err)
+		}
+
+		// Certificates keep the configuration order
+		entries, err := bundlepkg.ParseEntries([]byte(pemBundle))
+		if err != nil {
+			t.Fatalf("ParseEntries() error = %v", err)
+		}
+		var got, want []string
+		for _, entry := range entries {
+			got = append(got, entry.Headers[bundlepkg.CertMetadataKeyCertificate.Key()])
+		}
+		for _, vendor := range cfg.Vendors {
+			for _, cert := range vendor.Certificates {
+				want = append(want, cert.Name)
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("certificates = %v, want %v", got, want)
+		}
+
+		// More downloads ran at once than there are vendors
+		if n := maxInFlight.Load(); n <= vendorCount || n > 6 {
+			t.Errorf("expected between %d and 6 concurrent downloads, got %d", vendorCount+1, n)
+		}
+	})
+
+	t.Run("failures reported per certificate", func(t *testing.T) {
+		cfg, servers, _ := newMultiVendorConfig(t, vendorCount, certsPerVendor, 0)
+		// Vendor 0 is unreachable for one certificate, vendor 2 serves a wrong certificate
+		delete(servers[0].certs, "/cert-1.cer")
+		servers[2].certs["/cert-3.cer"] = servers[2].certs["/cert-0.cer"]
+		gen := bundlepkg.NewGenerator(servers[0].Client())
+
+		_, err := gen.GenerateWithConcurrency(cfg, 4)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		msg := err.Error()
+		for _, name := range []string{"Vendor 0 Cert 1", "Vendor 2 Cert 3"} {
+			if !strings.Contains(msg, name) {
+				t.Errorf("expected failure of %q to be reported, got: %v", name, err)
+			}
+		}
+		if !strings.Contains(msg, "fingerprint validation failed") {
+			t.Errorf("expected a fingerprint failure, got: %v", err)
+		}
+		if strings.Count(msg, "failed to process certificate") != 2 {
+			t.Errorf("expected exactly 2 failed certificates, got: %v", err)
+		}
+	})
+}
+
+func BenchmarkGenerateMultipleVendors(b *testing.B) {
+	cfg, servers, _ := newMultiVendorConfig(b, 4, 5, 5*time.Millisecond)
+	gen := bundlepkg.NewGenerator(servers[0].Client())
+
+	for _, workers := range []int{1, 4, 10} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := gen.GenerateWithConcurrency(cfg, workers); err != nil {
+					b.Fatalf("GenerateWithConcurrency() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
@@ -23,6 +25,9 @@ type Metadata struct {
 	Date   string
 	Commit string
 	Type   BundleType
+	// Format is the bundle format version, 1 for bundles without Format metadata.
+	// Zero means unspecified and is accepted by [Metadata.Check].
+	Format int
 }
 
 func (m *Metadata) Check() error {
@@ -41,16 +46,167 @@ func (m *Metadata) Check() error {
 	if err := m.Type.Validate(); err != nil {
 		return err
 	}
+	if m.Format != 0 {
+		if _, err := ParseFormatVersion(strconv.Itoa(m.Format)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// maxDocuments is the maximum number of documents in a combined bundle (root + intermediate).
+const maxDocuments = 2
+
+// SplitDocuments splits a combined TPM trust bundle into its documents.
+//
+// A combined bundle is the concatenation of a root and an intermediate bundle, each
+// starting with its own global metadata block. A regular bundle yields a single document.
+// The [DocumentSeparator] ending a document followed by another one isn't part of it.
+//
+// Example:
+//
+//	docs, err := bundle.SplitDocuments(combinedData)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, doc := range docs {
+//	    metadata, _ := bundle.ParseMetadata(doc)
+//	    fmt.Println(metadata.Type)
+//	}
+func SplitDocuments(data []byte) ([][]byte, error) {
+	var (
+		docs           [][]byte
+		start, offset  int
+		previousGlobal bool
+	)
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		global := bytes.HasPrefix(line, []byte(GlobalMetadataPrefix))
+		// A global metadata block following any other content starts a new document
+		if global && !previousGlobal && offset > start {
+			docs = append(docs, trimDocumentSeparator(data[start:offset]))
+			start = offset
+		}
+		previousGlobal = global
+		offset += len(line)
+	}
+	if start < len(data) {
+		docs = append(docs, data[start:])
+	}
+
+	if len(docs) > maxDocuments {
+		return nil, fmt.Errorf("bundle contains %d global metadata blocks, at most %d (root + intermediate) are supported", len(docs), maxDocuments)
+	}
+	return docs, nil
+}
+
+// trimDocumentSeparator removes the [DocumentSeparator] ending doc, if any.
+func trimDocumentSeparator(doc []byte) []byte {
+	if bytes.HasSuffix(doc, []byte("\n"+DocumentSeparator)) {
+		return doc[:len(doc)-len(DocumentSeparator)]
+	}
+	return doc
+}
+
+// Merge concatenates a root and an intermediate bundle into a single combined bundle,
+// separated by [DocumentSeparator].
+//
+// Both bundles must come from the same release (same Date and Commit). They are copied
+// verbatim, so that [SplitDocuments] returns them byte for byte: the root bundle must
+// therefore end with a newline.
+func Merge(root, intermediate []byte) ([]byte, error) {
+	rootMetadata, err := parseSingleDocumentMetadata(root, TypeRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root bundle: %w", err)
+	}
+	intermediateMetadata, err := parseSingleDocumentMetadata(intermediate, TypeIntermediate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid intermediate bundle: %w", err)
+	}
+	if err := CheckSameRelease(rootMetadata, intermediateMetadata); err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasSuffix(root, []byte("\n")) {
+		return nil, fmt.Errorf("invalid root bundle: missing trailing newline")
+	}
+
+	combined := make([]byte, 0, len(root)+len(DocumentSeparator)+len(intermediate))
+	combined = append(combined, root...)
+	combined = append(combined, DocumentSeparator...)
+	return append(combined, intermediate...), nil
+}
+
+// CheckSameRelease returns an error if the root and intermediate bundles do not come from
+// the same release (same Date and Commit).
+func CheckSameRelease(root, intermediate *Metadata) error {
+	if root.Date != intermediate.Date || root.Commit != intermediate.Commit {
+		return fmt.Errorf("bundles come from different releases: root is %s (%s), intermediate is %s (%s)",
+			root.Date, root.Commit, intermediate.Date, intermediate.Commit)
+	}
+	return nil
+}
+
+// parseSingleDocumentMetadata parses the metadata of a bundle made of a single document of the expected type.
+func parseSingleDocumentMetadata(data []byte, expected BundleType) (*Metadata, error) {
+	docs, err := SplitDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) != 1 {
+		return nil, fmt.Errorf("expected a single bundle, got %d", len(docs))
+	}
+	metadata, err := ParseMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Type != expected {
+		return nil, fmt.Errorf("expected a %s bundle, got %s", expected, metadata.Type)
+	}
+	return metadata, nil
+}
+
 // ParseMetadata parses a TPM trust bundle from bytes and extracts the global metadata.
+//
+// For a combined bundle (see [SplitDocuments]), the metadata of the first document is returned.
 func ParseMetadata(data []byte) (*Metadata, error) {
 	return ParseMetadataFromReader(bytes.NewReader(data))
 }
 
 // ParseMetadataFromReader reads a TPM trust bundle from an [io.Reader] and extracts the global metadata.
 func ParseMetadataFromReader(reader io.Reader) (*Metadata, error) {
+	return ParseMetadataWithOptions(reader)
+}
+
+// ParseOption configures [ParseMetadataWithOptions], [ParseBundleWithOptions],
+// [ParseBundleWithDiagnostics] and [ParseEntriesWithOptions].
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	markers Markers
+}
+
+// WithMarkers sets the prefixes of the metadata lines, to parse a compatible format.
+//
+// Default: [DefaultMarkers].
+func WithMarkers(markers Markers) ParseOption {
+	return func(o *parseOptions) {
+		o.markers = markers
+	}
+}
+
+// ParseMetadataWithOptions reads a TPM trust bundle from an [io.Reader] and extracts
+// the global metadata, like [ParseMetadataFromReader] but configurable.
+//
+// Example:
+//
+//	// Bundle whose global metadata lines start with "%%"
+//	metadata, err := bundle.ParseMetadataWithOptions(reader, bundle.WithMarkers(bundle.Markers{Global: "%%", Cert: "%"}))
+func ParseMetadataWithOptions(reader io.Reader, opts ...ParseOption) (*Metadata, error) {
+	markers, err := parseMarkers(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	var metadata Metadata
 	metadata.Type = TypeUnspecified
 	scanner := bufio.NewScanner(reader)
@@ -60,20 +216,29 @@ func ParseMetadataFromReader(reader io.Reader) (*Metadata, error) {
 		line := scanner.Text()
 
 		// Stop when we reach the end of the global metadata section
-		if !strings.HasPrefix(line, GlobalMetadataPrefix) {
+		if !strings.HasPrefix(line, markers.Global) {
 			break
 		}
 
 		// Look for "## Date: YYYY-MM-DD"
-		if after, ok := strings.CutPrefix(line, MetadataKeyDate.String()); ok {
+		if after, ok := strings.CutPrefix(line, markers.key(MetadataKeyDate)); ok {
 			metadata.Date = strings.TrimSpace(after)
 		}
 
 		// Look for "## Commit: <hash>"
-		if after, ok := strings.CutPrefix(line, MetadataKeyCommit.String()); ok {
+		if after, ok := strings.CutPrefix(line, markers.key(MetadataKeyCommit)); ok {
 			metadata.Commit = strings.TrimSpace(after)
 		}
 
+		// Look for "## Format: <version>"
+		if after, ok := strings.CutPrefix(line, markers.key(MetadataKeyFormat)); ok {
+			format, err := ParseFormatVersion(strings.TrimSpace(after))
+			if err != nil {
+				return nil, err
+			}
+			metadata.Format = format
+		}
+
 		// Detect bundle type from filename in header
 		if strings.Contains(line, cache.IntermediateBundleFilename) {
 			metadata.Type = TypeIntermediate
@@ -100,9 +265,26 @@ func ParseMetadataFromReader(reader io.Reader) (*Metadata, error) {
 		metadata.Type = TypeRoot
 	}
 
+	// Bundles released before the format was versioned use version 1
+	if metadata.Format == 0 {
+		metadata.Format = 1
+	}
+
 	return &metadata, nil
 }
 
+// parseMarkers returns the markers set by opts, with defaults applied.
+func parseMarkers(opts []ParseOption) (Markers, error) {
+	o := parseOptions{markers: DefaultMarkers()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.markers.CheckAndSetDefaults(); err != nil {
+		return Markers{}, fmt.Errorf("invalid markers: %w", err)
+	}
+	return o.markers, nil
+}
+
 // ParseBundle parses a PEM-encoded TPM trust bundle and extracts certificates organized by vendor.
 //
 // The function reads the bundle format as specified in docs/specifications/04-tpm-trust-bundle-format.md
@@ -123,40 +305,129 @@ func ParseBundle(data []byte) (map[vendors.ID][]*x509.Certificate, error) {
 
 // ParseBundleFromReader reads a PEM-encoded TPM trust bundle from an [io.Reader]
 // and extracts certificates organized by vendor.
+//
+// The parsing is strict: the first certificate that cannot be parsed aborts it.
+// Use [ParseBundleWithDiagnostics] to skip such certificates instead.
 func ParseBundleFromReader(reader io.Reader) (map[vendors.ID][]*x509.Certificate, error) {
-	catalog := make(map[vendors.ID][]*x509.Certificate)
+	return ParseBundleWithOptions(reader)
+}
+
+// ParseBundleWithOptions reads a PEM-encoded TPM trust bundle from an [io.Reader] and
+// extracts certificates organized by vendor, like [ParseBundleFromReader] but configurable.
+//
+// Example:
+//
+//	// Bundle whose metadata lines start with "%%" and "%"
+//	catalog, err := bundle.ParseBundleWithOptions(reader, bundle.WithMarkers(bundle.Markers{Global: "%%", Cert: "%"}))
+func ParseBundleWithOptions(reader io.Reader, opts ...ParseOption) (map[vendors.ID][]*x509.Certificate, error) {
+	markers, err := parseMarkers(opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseBundle(reader, markers)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Skipped) > 0 {
+		return nil, result.Skipped[0].Reason
+	}
+	if len(result.Catalog) == 0 {
+		return nil, fmt.Errorf("no certificates found in bundle")
+	}
+	return result.Catalog, nil
+}
+
+// SkippedBlock describes a PEM block of a bundle that could not be parsed.
+type SkippedBlock struct {
+	// Line is the line number (1-based) of the block BEGIN marker.
+	Line int
+	// Reason explains why the block was skipped.
+	Reason error
+}
+
+func (b SkippedBlock) String() string {
+	return fmt.Sprintf("line %d: %v", b.Line, b.Reason)
+}
+
+// ParseResult is the outcome of [ParseBundleWithDiagnostics].
+type ParseResult struct {
+	// Catalog holds the successfully parsed certificates organized by vendor.
+	Catalog map[vendors.ID][]*x509.Certificate
+	// Skipped lists the PEM blocks that could not be parsed, in bundle order.
+	Skipped []SkippedBlock
+}
+
+// ParseBundleWithDiagnostics is like [ParseBundle] but skips the certificates that
+// cannot be parsed (undecodable PEM, invalid DER, missing or invalid owner) instead
+// of aborting, and reports them in [ParseResult.Skipped].
+//
+// An error is returned if the bundle cannot be read or no certificate could be parsed;
+// the result is still returned in the latter case so that skipped blocks can be inspected.
+//
+// Example:
+//
+//	result, err := bundle.ParseBundleWithDiagnostics(bundleData)
+//	for _, skipped := range result.Skipped {
+//	    log.Printf("skipped certificate at %s", skipped)
+//	}
+func ParseBundleWithDiagnostics(data []byte, opts ...ParseOption) (*ParseResult, error) {
+	markers, err := parseMarkers(opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseBundle(bytes.NewReader(data), markers)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Catalog) == 0 {
+		return result, fmt.Errorf("no certificates found in bundle")
+	}
+	return result, nil
+}
+
+// parseBundle extracts the certificates of a bundle whose metadata lines use markers,
+// recording the blocks that cannot be parsed.
+func parseBundle(reader io.Reader, markers Markers) (*ParseResult, error) {
+	result := &ParseResult{Catalog: make(map[vendors.ID][]*x509.Certificate)}
 	scanner := bufio.NewScanner(reader)
 
-	var currentOwner vendors.ID
-	var pemBlock strings.Builder
-	inPEMBlock := false
+	var (
+		currentOwner vendors.ID
+		ownerErr     error
+		pemBlock     strings.Builder
+		inPEMBlock   bool
+		lineNum      int
+		blockLine    int
+	)
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		lineNum++
 
 		// Skip global metadata (lines starting with ##)
-		if strings.HasPrefix(line, GlobalMetadataPrefix) {
+		if strings.HasPrefix(line, markers.Global) {
 			continue
 		}
 
 		// Parse certificate metadata (lines starting with #)
-		if after, ok := strings.CutPrefix(line, CertMetadataKeyOwner.String()); ok {
-			ownerStr := strings.TrimSpace(after)
-			currentOwner = vendors.ID(ownerStr)
+		if after, ok := strings.CutPrefix(line, markers.key(CertMetadataKeyOwner)); ok {
+			currentOwner = vendors.ID(strings.TrimSpace(after))
+			ownerErr = nil
 			if err := currentOwner.Validate(); err != nil {
-				return nil, fmt.Errorf("invalid vendor ID in certificate metadata: %w", err)
+				ownerErr = fmt.Errorf("invalid vendor ID in certificate metadata: %w", err)
 			}
 			continue
 		}
 
 		// Skip other metadata lines
-		if strings.HasPrefix(line, CertMetadataPrefix) {
+		if strings.HasPrefix(line, markers.Cert) {
 			continue
 		}
 
 		// Handle PEM blocks
 		if strings.HasPrefix(line, PEMBeginMarker) {
 			inPEMBlock = true
+			blockLine = lineNum
 			pemBlock.Reset()
 			pemBlock.WriteString(line)
 			pemBlock.WriteString("\n")
@@ -170,22 +441,164 @@ func ParseBundleFromReader(reader io.Reader) (map[vendors.ID][]*x509.Certificate
 			if strings.HasPrefix(line, PEMEndMarker) {
 				inPEMBlock = false
 
-				block, _ := pem.Decode([]byte(pemBlock.String()))
-				if block == nil {
-					return nil, fmt.Errorf("failed to decode PEM block")
-				}
-
-				cert, err := x509.ParseCertificate(block.Bytes)
+				cert, err := parseCertificateBlock(pemBlock.String(), currentOwner, ownerErr)
 				if err != nil {
-					return nil, fmt.Errorf("failed to parse certificate: %w", err)
+					result.Skipped = append(result.Skipped, SkippedBlock{Line: blockLine, Reason: err})
+					continue
 				}
+				result.Catalog[currentOwner] = append(result.Catalog[currentOwner], cert)
+			}
+		}
+	}
 
-				if currentOwner == "" {
-					return nil, fmt.Errorf("certificate found without owner metadata")
-				}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	return result, nil
+}
+
+// parseCertificateBlock parses a PEM certificate block owned by owner.
+func parseCertificateBlock(pemData string, owner vendors.ID, ownerErr error) (*x509.Certificate, error) {
+	if ownerErr != nil {
+		return nil, ownerErr
+	}
+
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
 
-				catalog[currentOwner] = append(catalog[currentOwner], cert)
+	if owner == "" {
+		return nil, fmt.Errorf("certificate found without owner metadata")
+	}
+	return cert, nil
+}
+
+// requiredCertMetadataKeys are the metadata headers every certificate of a bundle must carry.
+var requiredCertMetadataKeys = []MetadataKey{
+	CertMetadataKeyCertificate,
+	CertMetadataKeyOwner,
+	CertMetadataKeyIssuer,
+	CertMetadataKeySerialNumber,
+	CertMetadataKeySubject,
+	CertMetadataKeyNotValidBefore,
+	CertMetadataKeyNotValidAfter,
+	CertMetadataKeyFingerprintSHA256,
+	CertMetadataKeyFingerprintSHA1,
+}
+
+// Entry is a certificate of a TPM trust bundle paired with its metadata headers.
+type Entry struct {
+	// Line is the 1-based line where the entry starts.
+	Line int
+	// Headers maps each metadata key (e.g., "Serial Number") to its value.
+	Headers map[string]string
+	// Certificate is nil if the PEM block could not be parsed.
+	Certificate *x509.Certificate
+	// Err is set when the entry is partial (malformed or missing headers, invalid certificate).
+	Err error
+}
+
+// ParseEntries parses a TPM trust bundle and returns each certificate with its metadata headers.
+//
+// Unlike [ParseBundle], malformed entries don't abort the parsing: they are returned
+// with [Entry.Err] set, along with whatever could be parsed.
+func ParseEntries(data []byte) ([]Entry, error) {
+	return ParseEntriesWithOptions(data)
+}
+
+// ParseEntriesWithOptions is like [ParseEntries] but configurable, e.g. to parse a
+// compatible format with [WithMarkers].
+func ParseEntriesWithOptions(data []byte, opts ...ParseOption) ([]Entry, error) {
+	markers, err := parseMarkers(opts)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var (
+		entries  []Entry
+		current  *Entry
+		errs     []error
+		pemBlock strings.Builder
+		inPEM    bool
+		lineNum  int
+	)
+
+	// flush completes the current entry and resets the parsing state
+	flush := func() {
+		if current == nil {
+			return
+		}
+		for _, key := range requiredCertMetadataKeys {
+			if _, ok := current.Headers[key.Key()]; !ok {
+				errs = append(errs, fmt.Errorf("missing required %q header", key.Key()))
+			}
+		}
+		current.Err = errors.Join(errs...)
+		entries = append(entries, *current)
+		current, errs = nil, nil
+	}
+	start := func() {
+		current = &Entry{Line: lineNum, Headers: make(map[string]string)}
+	}
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if inPEM {
+			pemBlock.WriteString(line)
+			pemBlock.WriteString("\n")
+			if !strings.HasPrefix(line, PEMEndMarker) {
+				continue
+			}
+			inPEM = false
+
+			block, _ := pem.Decode([]byte(pemBlock.String()))
+			if block == nil {
+				errs = append(errs, fmt.Errorf("failed to decode PEM block"))
+			} else if cert, err := x509.ParseCertificate(block.Bytes); err != nil {
+				errs = append(errs, fmt.Errorf("failed to parse certificate: %w", err))
+			} else {
+				current.Certificate = cert
+			}
+			flush()
+			continue
+		}
+
+		switch {
+		case line == "" || strings.HasPrefix(line, markers.Global):
+			continue
+		case strings.HasPrefix(line, PEMBeginMarker):
+			if current == nil {
+				start()
+				errs = append(errs, fmt.Errorf("certificate found without metadata block"))
+			}
+			inPEM = true
+			pemBlock.Reset()
+			pemBlock.WriteString(line)
+			pemBlock.WriteString("\n")
+		case strings.HasPrefix(line, markers.Cert):
+			if current == nil {
+				start()
 			}
+			field := strings.TrimSpace(strings.TrimPrefix(line, markers.Cert))
+			if field == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(field, ":")
+			if key = strings.TrimSpace(key); !ok || key == "" {
+				errs = append(errs, fmt.Errorf("line %d: malformed metadata header %q", lineNum, field))
+				continue
+			}
+			current.Headers[key] = strings.TrimSpace(value)
 		}
 	}
 
@@ -193,9 +606,14 @@ func ParseBundleFromReader(reader io.Reader) (map[vendors.ID][]*x509.Certificate
 		return nil, fmt.Errorf("failed to read bundle: %w", err)
 	}
 
-	if len(catalog) == 0 {
-		return nil, fmt.Errorf("no certificates found in bundle")
+	if current != nil {
+		if inPEM {
+			errs = append(errs, fmt.Errorf("unterminated PEM block"))
+		} else {
+			errs = append(errs, fmt.Errorf("metadata block without certificate"))
+		}
+		flush()
 	}
 
-	return catalog, nil
+	return entries, nil
 }
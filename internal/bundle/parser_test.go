@@ -1,10 +1,13 @@
 package bundle_test
 
 import (
+	"bytes"
+	"maps"
 	"strings"
 	"testing"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
 )
@@ -65,12 +68,43 @@ MIICaTCCAcugAwIBAgIBAjAKBggqhkjOPQQDBDBWMR4wHAYDVQQDExVOUENUeHh4
 	})
 }
 
+func TestParseBundleWithDiagnostics(t *testing.T) {
+	// The first certificate of the test bundle is the only IFX one
+	data, line := testutil.CorruptRootBundleCertificate(t, 0)
+
+	result, err := bundle.ParseBundleWithDiagnostics(data)
+	if err != nil {
+		t.Fatalf("ParseBundleWithDiagnostics() error = %v", err)
+	}
+	if len(result.Skipped) != 1 {
+		t.Fatalf("expected 1 skipped block, got %d: %v", len(result.Skipped), result.Skipped)
+	}
+	if result.Skipped[0].Line != line {
+		t.Errorf("skipped block line = %d, want %d", result.Skipped[0].Line, line)
+	}
+	if !strings.Contains(result.Skipped[0].Reason.Error(), "failed to parse certificate") {
+		t.Errorf("unexpected skip reason: %v", result.Skipped[0].Reason)
+	}
+	if len(result.Catalog[vendors.IFX]) != 0 {
+		t.Errorf("expected the corrupt IFX certificate to be skipped, got %d", len(result.Catalog[vendors.IFX]))
+	}
+	if len(result.Catalog[vendors.NTC]) != 8 {
+		t.Errorf("expected 8 certificates for NTC vendor, got %d", len(result.Catalog[vendors.NTC]))
+	}
+
+	// The strict parser still aborts on the corrupt block
+	if _, err := bundle.ParseBundle(data); err == nil {
+		t.Error("ParseBundle() expected error for corrupt certificate")
+	}
+}
+
 func TestParseMetadata(t *testing.T) {
 	tests := []struct {
 		name       string
 		bundleData string
 		wantDate   string
 		wantCommit string
+		wantFormat int
 		wantErrMsg string
 	}{
 		{
@@ -95,6 +129,7 @@ MIIBdjCCARygAwIBAgIRAKjIOzWTCC66SJLRB5eewJMwCgYIKoZIzj0EAwIwGTEX
 `,
 			wantDate:   "2025-12-04",
 			wantCommit: "63e6a017e9c15428b2959cb2760d21f05dea42f4",
+			wantFormat: 1,
 		},
 		{
 			name: "valid bundle with different date format",
@@ -107,6 +142,45 @@ MIIBdjCCARygAwIBAgIRAKjIOzWTCC66SJLRB5eewJMwCgYIKoZIzj0EAwIwGTEX
 `,
 			wantDate:   "2025-01-03",
 			wantCommit: "a703c9c414fcad56351b5b6326a7d0cbaf2f0b9c",
+			wantFormat: 1,
+		},
+		{
+			name: "versioned bundle",
+			bundleData: `##
+## tpm-ca-certificates.pem
+##
+## Date: 2025-12-04
+## Commit: 63e6a017e9c15428b2959cb2760d21f05dea42f4
+## Format: 1
+##
+`,
+			wantDate:   "2025-12-04",
+			wantCommit: "63e6a017e9c15428b2959cb2760d21f05dea42f4",
+			wantFormat: 1,
+		},
+		{
+			name: "unsupported format version",
+			bundleData: `##
+## tpm-ca-certificates.pem
+##
+## Date: 2025-12-04
+## Commit: 63e6a017e9c15428b2959cb2760d21f05dea42f4
+## Format: 2
+##
+`,
+			wantErrMsg: "unsupported bundle format version \"2\"",
+		},
+		{
+			name: "invalid format version",
+			bundleData: `##
+## tpm-ca-certificates.pem
+##
+## Date: 2025-12-04
+## Commit: 63e6a017e9c15428b2959cb2760d21f05dea42f4
+## Format: v1
+##
+`,
+			wantErrMsg: "unsupported bundle format version \"v1\"",
 		},
 		{
 			name: "bundle missing date",
@@ -162,6 +236,7 @@ MIIBdjCCARygAwIBAgIRAKjIOzWTCC66SJLRB5eewJMwCgYIKoZIzj0EAwIwGTEX
 `,
 			wantDate:   "2025-12-04",
 			wantCommit: "63e6a017e9c15428b2959cb2760d21f05dea42f4",
+			wantFormat: 1,
 		},
 	}
 
@@ -193,6 +268,10 @@ MIIBdjCCARygAwIBAgIRAKjIOzWTCC66SJLRB5eewJMwCgYIKoZIzj0EAwIwGTEX
 			if metadata.Commit != tt.wantCommit {
 				t.Errorf("Commit mismatch: got %q, want %q", metadata.Commit, tt.wantCommit)
 			}
+
+			if metadata.Format != tt.wantFormat {
+				t.Errorf("Format mismatch: got %d, want %d", metadata.Format, tt.wantFormat)
+			}
 		})
 	}
 }
@@ -218,10 +297,127 @@ func TestParseMetadata_RealBundle(t *testing.T) {
 		t.Error("Expected non-empty Commit from test bundle")
 	}
 
+	// The test bundle was released before the format was versioned
+	if metadata.Format != 1 {
+		t.Errorf("Expected legacy test bundle to use format 1, got %d", metadata.Format)
+	}
+
 	// Log the values for visibility
 	t.Logf("Parsed test bundle metadata - Date: %s, Commit: %s", metadata.Date, metadata.Commit)
 }
 
+func TestParseMetadata_CustomMarkers(t *testing.T) {
+	data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	markers := bundle.Markers{Global: "%%", Cert: "%"}
+	custom := withMarkers(data, markers)
+
+	want, err := bundle.ParseMetadata(data)
+	if err != nil {
+		t.Fatalf("Failed to parse test bundle: %v", err)
+	}
+	got, err := bundle.ParseMetadataWithOptions(strings.NewReader(custom), bundle.WithMarkers(markers))
+	if err != nil {
+		t.Fatalf("Failed to parse bundle with custom markers: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("Expected metadata %+v, got %+v", want, got)
+	}
+
+	// The default markers don't match the custom ones
+	if _, err := bundle.ParseMetadataFromReader(strings.NewReader(custom)); err == nil {
+		t.Error("Expected an error parsing custom markers with the default ones")
+	}
+
+	// A certificate line must not be mistaken for a global one
+	_, err = bundle.ParseMetadataWithOptions(strings.NewReader(custom), bundle.WithMarkers(bundle.Markers{Global: "%", Cert: "%%"}))
+	if err == nil || !strings.Contains(err.Error(), "invalid markers") {
+		t.Errorf("Expected an invalid markers error, got %v", err)
+	}
+}
+
+func TestParseBundle_CustomMarkers(t *testing.T) {
+	data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	markers := bundle.Markers{Global: "%%", Cert: "%"}
+	custom := withMarkers(data, markers)
+
+	want, err := bundle.ParseBundle(data)
+	if err != nil {
+		t.Fatalf("Failed to parse test bundle: %v", err)
+	}
+	got, err := bundle.ParseBundleWithOptions(strings.NewReader(custom), bundle.WithMarkers(markers))
+	if err != nil {
+		t.Fatalf("Failed to parse bundle with custom markers: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d vendors, got %d", len(want), len(got))
+	}
+	for vendorID, certs := range want {
+		if len(got[vendorID]) != len(certs) {
+			t.Errorf("Expected %d certificates for vendor %s, got %d", len(certs), vendorID, len(got[vendorID]))
+		}
+	}
+
+	result, err := bundle.ParseBundleWithDiagnostics([]byte(custom), bundle.WithMarkers(markers))
+	if err != nil {
+		t.Fatalf("Failed to parse bundle with diagnostics: %v", err)
+	}
+	if len(result.Skipped) != 0 {
+		t.Errorf("Expected no skipped certificate, got %v", result.Skipped)
+	}
+
+	// Without the custom markers, no certificate has an owner
+	if _, err := bundle.ParseBundleFromReader(strings.NewReader(custom)); err == nil {
+		t.Error("Expected an error parsing custom markers with the default ones")
+	}
+}
+
+func TestParseEntries_CustomMarkers(t *testing.T) {
+	data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	markers := bundle.Markers{Global: "%%", Cert: "%"}
+
+	want, err := bundle.ParseEntries(data)
+	if err != nil {
+		t.Fatalf("Failed to parse test bundle: %v", err)
+	}
+	got, err := bundle.ParseEntriesWithOptions([]byte(withMarkers(data, markers)), bundle.WithMarkers(markers))
+	if err != nil {
+		t.Fatalf("Failed to parse entries with custom markers: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(got))
+	}
+	for i := range got {
+		if got[i].Err != nil {
+			t.Errorf("Entry %d: unexpected error: %v", i, got[i].Err)
+		}
+		if !maps.Equal(got[i].Headers, want[i].Headers) {
+			t.Errorf("Entry %d: expected headers %v, got %v", i, want[i].Headers, got[i].Headers)
+		}
+	}
+}
+
+// withMarkers rewrites the metadata lines of a bundle generated with the default markers.
+func withMarkers(data []byte, markers bundle.Markers) string {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if after, ok := strings.CutPrefix(line, bundle.GlobalMetadataPrefix); ok {
+			lines[i] = markers.Global + after
+		} else if after, ok := strings.CutPrefix(line, bundle.CertMetadataPrefix); ok {
+			lines[i] = markers.Cert + after
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // containsString checks if a string contains a substring.
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(substr) == 0 ||
@@ -237,3 +433,107 @@ func indexString(s, substr string) int {
 	}
 	return -1
 }
+
+// testBundles returns the root test bundle and an intermediate bundle from the same release.
+func testBundles(t *testing.T) (root, intermediate []byte) {
+	t.Helper()
+	root, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	intermediate = []byte(strings.Replace(string(root), cache.RootBundleFilename, cache.IntermediateBundleFilename, 1))
+	return root, intermediate
+}
+
+func TestSplitDocuments(t *testing.T) {
+	root, intermediate := testBundles(t)
+
+	t.Run("single bundle", func(t *testing.T) {
+		docs, err := bundle.SplitDocuments(root)
+		if err != nil {
+			t.Fatalf("SplitDocuments() error = %v", err)
+		}
+		if len(docs) != 1 || string(docs[0]) != string(root) {
+			t.Errorf("expected the bundle as a single document, got %d documents", len(docs))
+		}
+	})
+
+	t.Run("combined bundle", func(t *testing.T) {
+		combined, err := bundle.Merge(root, intermediate)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		docs, err := bundle.SplitDocuments(combined)
+		if err != nil {
+			t.Fatalf("SplitDocuments() error = %v", err)
+		}
+		if len(docs) != 2 {
+			t.Fatalf("expected 2 documents, got %d", len(docs))
+		}
+		if !bytes.Equal(docs[0], root) || !bytes.Equal(docs[1], intermediate) {
+			t.Error("expected the documents to be the merged bundles, byte for byte")
+		}
+		for i, want := range []bundle.BundleType{bundle.TypeRoot, bundle.TypeIntermediate} {
+			metadata, err := bundle.ParseMetadata(docs[i])
+			if err != nil {
+				t.Fatalf("ParseMetadata() error = %v", err)
+			}
+			if metadata.Type != want {
+				t.Errorf("document %d: expected type %s, got %s", i, want, metadata.Type)
+			}
+		}
+	})
+
+	t.Run("three blocks are rejected", func(t *testing.T) {
+		data := append(append(append([]byte{}, root...), intermediate...), root...)
+		if _, err := bundle.SplitDocuments(data); err == nil {
+			t.Error("SplitDocuments() expected error for three global metadata blocks")
+		}
+	})
+}
+
+func TestMerge(t *testing.T) {
+	root, intermediate := testBundles(t)
+
+	tests := []struct {
+		name         string
+		root         []byte
+		intermediate []byte
+		wantErr      string
+	}{
+		{
+			name:         "swapped bundles",
+			root:         intermediate,
+			intermediate: root,
+			wantErr:      "invalid root bundle",
+		},
+		{
+			name:         "different releases",
+			root:         root,
+			intermediate: []byte(strings.Replace(string(intermediate), "Date: 2025-12-05", "Date: 2025-12-06", 1)),
+			wantErr:      "different releases",
+		},
+		{
+			name:         "root without trailing newline",
+			root:         bytes.TrimRight(root, "\n"),
+			intermediate: intermediate,
+			wantErr:      "missing trailing newline",
+		},
+		{
+			name:         "already combined",
+			root:         append(append([]byte{}, root...), intermediate...),
+			intermediate: intermediate,
+			wantErr:      "expected a single bundle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bundle.Merge(tt.root, tt.intermediate)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Merge() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
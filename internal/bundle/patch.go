@@ -0,0 +1,192 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Patch describes the certificates added and removed between two versions of a TPM trust bundle.
+//
+// It lets a client holding the base version rebuild the target version without
+// downloading it (see [ApplyPatch]). Patches are not authenticated: the patched bundle
+// must be checked against the checksum of the target version before being trusted.
+type Patch struct {
+	// From is the version (release date) of the bundle the patch applies to.
+	From string `json:"from"`
+	// To is the version (release date) of the bundle produced by the patch.
+	To string `json:"to"`
+	// Header is the global metadata block of the target bundle.
+	Header string `json:"header"`
+	// Removed lists the SHA-256 fingerprints of the certificates removed from the base bundle.
+	Removed []string `json:"removed,omitempty"`
+	// Added lists the certificate entries added to the base bundle, by ascending index.
+	Added []PatchEntry `json:"added,omitempty"`
+}
+
+// PatchEntry is a certificate entry added by a [Patch].
+type PatchEntry struct {
+	// Index is the position of the entry in the target bundle.
+	Index int `json:"index"`
+	// Block is the entry content: the certificate metadata block followed by the PEM certificate.
+	Block string `json:"block"`
+}
+
+// PatchFilename returns the name of the release asset holding the patch of the
+// bundle named bundleFilename from version from.
+//
+// Example:
+//
+//	fmt.Println(bundle.PatchFilename("tpm-ca-certificates.pem", "2025-12-03"))
+//	// Output: tpm-ca-certificates.pem.2025-12-03.patch
+func PatchFilename(bundleFilename, from string) string {
+	return fmt.Sprintf("%s.%s.patch", bundleFilename, from)
+}
+
+// patchBlock is a certificate entry of a bundle, as written in the bundle.
+type patchBlock struct {
+	fingerprint string
+	text        string
+}
+
+// DiffBundles computes the [Patch] turning the base bundle into the target bundle.
+//
+// An error is returned if a bundle holds the same certificate twice, or if the
+// certificates kept from the base bundle are reordered in the target bundle.
+func DiffBundles(base, target []byte) (*Patch, error) {
+	baseMetadata, err := ParseMetadata(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base bundle: %w", err)
+	}
+	targetMetadata, err := ParseMetadata(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target bundle: %w", err)
+	}
+	_, baseBlocks, err := splitBlocks(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base bundle: %w", err)
+	}
+	header, targetBlocks, err := splitBlocks(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target bundle: %w", err)
+	}
+
+	// An entry is kept only if it is unchanged, metadata included
+	targetTexts := make(map[string]string, len(targetBlocks))
+	for _, block := range targetBlocks {
+		targetTexts[block.fingerprint] = block.text
+	}
+	patch := &Patch{From: baseMetadata.Date, To: targetMetadata.Date, Header: header}
+	kept := make(map[string]bool, len(baseBlocks))
+	var keptOrder []string
+	for _, block := range baseBlocks {
+		if text, ok := targetTexts[block.fingerprint]; ok && text == block.text {
+			kept[block.fingerprint] = true
+			keptOrder = append(keptOrder, block.fingerprint)
+			continue
+		}
+		patch.Removed = append(patch.Removed, block.fingerprint)
+	}
+
+	var targetKeptOrder []string
+	for i, block := range targetBlocks {
+		if kept[block.fingerprint] {
+			targetKeptOrder = append(targetKeptOrder, block.fingerprint)
+			continue
+		}
+		patch.Added = append(patch.Added, PatchEntry{Index: i, Block: block.text})
+	}
+	if !slices.Equal(keptOrder, targetKeptOrder) {
+		return nil, fmt.Errorf("certificates are reordered between %s and %s", patch.From, patch.To)
+	}
+
+	return patch, nil
+}
+
+// ApplyPatch applies patch to the base bundle and returns the target bundle.
+//
+// The base bundle must be the version the patch was computed from.
+func ApplyPatch(base []byte, patch *Patch) ([]byte, error) {
+	metadata, err := ParseMetadata(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base bundle: %w", err)
+	}
+	if metadata.Date != patch.From {
+		return nil, fmt.Errorf("patch applies to bundle %s, got %s", patch.From, metadata.Date)
+	}
+	_, baseBlocks, err := splitBlocks(base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base bundle: %w", err)
+	}
+
+	blocks := make([]string, 0, len(baseBlocks)+len(patch.Added))
+	removed := make(map[string]bool, len(patch.Removed))
+	for _, block := range baseBlocks {
+		if slices.Contains(patch.Removed, block.fingerprint) {
+			removed[block.fingerprint] = true
+			continue
+		}
+		blocks = append(blocks, block.text)
+	}
+	for _, fingerprint := range patch.Removed {
+		if !removed[fingerprint] {
+			return nil, fmt.Errorf("removed certificate %s not found in bundle %s", fingerprint, patch.From)
+		}
+	}
+
+	previous := -1
+	for _, entry := range patch.Added {
+		if entry.Index <= previous || entry.Index > len(blocks) {
+			return nil, fmt.Errorf("invalid index %d for added certificate", entry.Index)
+		}
+		previous = entry.Index
+		blocks = slices.Insert(blocks, entry.Index, normalizeBlock(entry.Block))
+	}
+
+	var patched strings.Builder
+	patched.WriteString(patch.Header)
+	patched.WriteString(strings.Join(blocks, "\n"))
+	return []byte(patched.String()), nil
+}
+
+// splitBlocks splits a bundle into its global metadata block and its certificate entries.
+func splitBlocks(data []byte) (string, []patchBlock, error) {
+	entries, err := ParseEntries(data)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(entries) == 0 {
+		return "", nil, fmt.Errorf("no certificates found in bundle")
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	header := strings.Join(lines[:entries[0].Line-1], "")
+
+	blocks := make([]patchBlock, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if entry.Err != nil {
+			return "", nil, fmt.Errorf("invalid certificate entry at line %d: %w", entry.Line, entry.Err)
+		}
+		hash := sha256.Sum256(entry.Certificate.Raw)
+		fingerprint := formatFingerprint(hash[:])
+		if seen[fingerprint] {
+			return "", nil, fmt.Errorf("duplicate certificate %s at line %d", fingerprint, entry.Line)
+		}
+		seen[fingerprint] = true
+
+		end := len(lines)
+		if i+1 < len(entries) {
+			end = entries[i+1].Line - 1
+		}
+		text := strings.Join(lines[entry.Line-1:end], "")
+		blocks = append(blocks, patchBlock{fingerprint: fingerprint, text: normalizeBlock(text)})
+	}
+	return header, blocks, nil
+}
+
+// normalizeBlock trims the blank lines separating a certificate entry from the next one.
+func normalizeBlock(block string) string {
+	return strings.TrimRight(block, "\n") + "\n"
+}
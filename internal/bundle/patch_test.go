@@ -0,0 +1,105 @@
+package bundle_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+// previousTestBundle derives an older version of the test bundle: released on date,
+// without its first certificate entry.
+func previousTestBundle(t *testing.T, target []byte, date string) []byte {
+	t.Helper()
+
+	metadata, err := bundlepkg.ParseMetadata(target)
+	if err != nil {
+		t.Fatalf("failed to parse bundle metadata: %v", err)
+	}
+	entries, err := bundlepkg.ParseEntries(target)
+	if err != nil || len(entries) < 2 {
+		t.Fatalf("failed to parse bundle entries: %v", err)
+	}
+
+	lines := strings.SplitAfter(string(target), "\n")
+	header := strings.Join(lines[:entries[0].Line-1], "")
+	header = strings.Replace(header, bundlepkg.MetadataKeyDate.String()+metadata.Date, bundlepkg.MetadataKeyDate.String()+date, 1)
+	return []byte(header + strings.Join(lines[entries[1].Line-1:], ""))
+}
+
+func TestDiffAndApplyPatch(t *testing.T) {
+	target, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	base := previousTestBundle(t, target, "2025-12-01")
+
+	t.Run("round trip", func(t *testing.T) {
+		patch, err := bundlepkg.DiffBundles(base, target)
+		if err != nil {
+			t.Fatalf("DiffBundles() error = %v", err)
+		}
+		if patch.From != "2025-12-01" || patch.To != testutil.BundleVersion {
+			t.Errorf("unexpected patch versions: %s -> %s", patch.From, patch.To)
+		}
+		if len(patch.Added) != 1 || patch.Added[0].Index != 0 || len(patch.Removed) != 0 {
+			t.Fatalf("expected a single certificate added first, got %d added, %d removed", len(patch.Added), len(patch.Removed))
+		}
+
+		// The patch is published as JSON
+		data, err := json.Marshal(patch)
+		if err != nil {
+			t.Fatalf("failed to marshal patch: %v", err)
+		}
+		var decoded bundlepkg.Patch
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("failed to unmarshal patch: %v", err)
+		}
+
+		patched, err := bundlepkg.ApplyPatch(base, &decoded)
+		if err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		if !bytes.Equal(patched, target) {
+			t.Errorf("patched bundle differs from the target bundle")
+		}
+	})
+
+	t.Run("removed certificates", func(t *testing.T) {
+		reversed := previousTestBundle(t, target, "2026-01-01")
+		patch, err := bundlepkg.DiffBundles(target, reversed)
+		if err != nil {
+			t.Fatalf("DiffBundles() error = %v", err)
+		}
+		if len(patch.Removed) != 1 || len(patch.Added) != 0 {
+			t.Fatalf("expected a single certificate removed, got %d added, %d removed", len(patch.Added), len(patch.Removed))
+		}
+		patched, err := bundlepkg.ApplyPatch(target, patch)
+		if err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		if !bytes.Equal(patched, reversed) {
+			t.Errorf("patched bundle differs from the target bundle")
+		}
+	})
+
+	t.Run("wrong base version", func(t *testing.T) {
+		patch, err := bundlepkg.DiffBundles(base, target)
+		if err != nil {
+			t.Fatalf("DiffBundles() error = %v", err)
+		}
+		if _, err := bundlepkg.ApplyPatch(target, patch); err == nil {
+			t.Fatal("expected an error when applying the patch to another version")
+		}
+	})
+
+	t.Run("unknown removed certificate", func(t *testing.T) {
+		patch := &bundlepkg.Patch{From: "2025-12-01", Removed: []string{"AA:BB"}}
+		if _, err := bundlepkg.ApplyPatch(base, patch); err == nil {
+			t.Fatal("expected an error for a certificate missing from the base bundle")
+		}
+	})
+}
@@ -23,20 +23,47 @@ type ValidationError struct {
 	Message string
 }
 
+// maxDateSkew is the clock skew tolerated when checking that the global Date is not in the future.
+//
+// Dates have a one day granularity and are generated in UTC, hence a full day of allowance.
+const maxDateSkew = 24 * time.Hour
+
 // BundleValidator handles bundle validation operations.
 type BundleValidator struct {
+	// Now returns the current time used by plausibility checks.
+	//
+	// Optional. Default: [time.Now].
+	Now func() time.Time
+
+	// Markers are the prefixes of the metadata lines, to validate a compatible format.
+	//
+	// Optional. Default: [DefaultMarkers].
+	Markers Markers
+
 	errors    []ValidationError
+	warnings  []ValidationError
 	maxErrors int
 }
 
 // NewBundleValidator creates a new bundle validator.
 func NewBundleValidator() *BundleValidator {
 	return &BundleValidator{
+		Now:       time.Now,
+		Markers:   DefaultMarkers(),
 		errors:    make([]ValidationError, 0),
+		warnings:  make([]ValidationError, 0),
 		maxErrors: 10,
 	}
 }
 
+// Warnings returns the non-fatal findings of the last validation (max 10).
+//
+// Warnings flag plausibility issues, such as a global Date in the future,
+// that don't make the bundle invalid.
+func (v *BundleValidator) Warnings() []ValidationError {
+	return v.warnings
+}
+
 // ValidateBundle validates a TPM trust bundle from bytes.
 //
 // It performs comprehensive validation including:
@@ -46,7 +73,8 @@ func NewBundleValidator() *BundleValidator {
 //   - Metadata consistency with certificate content
 //   - Vendor ID validity
 //
-// Returns the list of validation errors (max 10).
+// Returns the list of validation errors (max 10). Non-fatal findings are
+// available through [BundleValidator.Warnings].
 //
 // Example:
 //
@@ -66,6 +94,11 @@ func (v *BundleValidator) ValidateBundle(data []byte) ([]ValidationError, error)
 
 // ValidateBundleFromReader validates a TPM trust bundle from an [io.Reader].
 func (v *BundleValidator) ValidateBundleFromReader(reader io.Reader) ([]ValidationError, error) {
+	markers := v.Markers
+	if err := markers.CheckAndSetDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid markers: %w", err)
+	}
+
 	scanner := bufio.NewScanner(reader)
 	lineNum := 0
 
@@ -88,8 +121,8 @@ func (v *BundleValidator) ValidateBundleFromReader(reader io.Reader) ([]Validati
 
 		// Validate global metadata block
 		if lineNum == 1 {
-			if line != GlobalMetadataPrefix {
-				v.addError(lineNum, "bundle must start with global metadata block marker '"+GlobalMetadataPrefix+"'")
+			if line != markers.Global {
+				v.addError(lineNum, "bundle must start with global metadata block marker '"+markers.Global+"'")
 			}
 			inGlobalMetadata = true
 			continue
@@ -97,7 +130,7 @@ func (v *BundleValidator) ValidateBundleFromReader(reader io.Reader) ([]Validati
 
 		// Process global metadata
 		if inGlobalMetadata {
-			if !strings.HasPrefix(line, GlobalMetadataPrefix) {
+			if !strings.HasPrefix(line, markers.Global) {
 				// End of global metadata
 				inGlobalMetadata = false
 				if !foundDate {
@@ -108,20 +141,28 @@ func (v *BundleValidator) ValidateBundleFromReader(reader io.Reader) ([]Validati
 				}
 			} else {
 				// Parse global metadata fields
-				if strings.HasPrefix(line, MetadataKeyDate.String()) {
+				if strings.HasPrefix(line, markers.key(MetadataKeyDate)) {
 					foundDate = true
-					dateValue := strings.TrimSpace(strings.TrimPrefix(line, MetadataKeyDate.String()))
+					dateValue := strings.TrimSpace(strings.TrimPrefix(line, markers.key(MetadataKeyDate)))
 					if err := ValidateDate(dateValue); err != nil {
 						v.addError(lineNum, fmt.Sprintf("invalid date format: %v", err))
+					} else {
+						v.checkDatePlausibility(dateValue, lineNum)
 					}
 				}
-				if strings.HasPrefix(line, MetadataKeyCommit.String()) {
+				if strings.HasPrefix(line, markers.key(MetadataKeyCommit)) {
 					foundCommit = true
-					commitValue := strings.TrimSpace(strings.TrimPrefix(line, MetadataKeyCommit.String()))
+					commitValue := strings.TrimSpace(strings.TrimPrefix(line, markers.key(MetadataKeyCommit)))
 					if err := ValidateCommit(commitValue); err != nil {
 						v.addError(lineNum, fmt.Sprintf("invalid commit hash: %v", err))
 					}
 				}
+				if strings.HasPrefix(line, markers.key(MetadataKeyFormat)) {
+					formatValue := strings.TrimSpace(strings.TrimPrefix(line, markers.key(MetadataKeyFormat)))
+					if _, err := ParseFormatVersion(formatValue); err != nil {
+						v.addError(lineNum, err.Error())
+					}
+				}
 			}
 			continue
 		}
@@ -131,7 +172,7 @@ func (v *BundleValidator) ValidateBundleFromReader(reader io.Reader) ([]Validati
 			continue
 		}
 
-		if line == CertMetadataPrefix && !inCertMetadata && !inPEMBlock {
+		if line == markers.Cert && !inCertMetadata && !inPEMBlock {
 			inCertMetadata = true
 			certMetadata = &certificateMetadata{
 				startLine: lineNum,
@@ -141,13 +182,13 @@ func (v *BundleValidator) ValidateBundleFromReader(reader io.Reader) ([]Validati
 		}
 
 		// Process certificate metadata
-		if inCertMetadata && strings.HasPrefix(line, CertMetadataPrefix) {
-			if line == CertMetadataPrefix {
+		if inCertMetadata && strings.HasPrefix(line, markers.Cert) {
+			if line == markers.Cert {
 				// Empty metadata line (separator before Owner)
 				continue
 			}
 
-			field := strings.TrimPrefix(line, CertMetadataPrefix+" ")
+			field := strings.TrimPrefix(line, markers.Cert+" ")
 
 			// Handle special case for "Not Valid After :" with space before colon
 			// This matches the format in the spec where "Not Valid After :" has alignment
@@ -295,6 +336,34 @@ func (v *BundleValidator) addError(line int, message string) {
 	})
 }
 
+// addWarning adds a validation warning if the limit hasn't been reached.
+func (v *BundleValidator) addWarning(line int, message string) {
+	if len(v.warnings) >= v.maxErrors {
+		return
+	}
+
+	v.warnings = append(v.warnings, ValidationError{
+		Line:    line,
+		Message: message,
+	})
+}
+
+// checkDatePlausibility warns when the global Date is after now, which hints at a clock skew in the release pipeline.
+func (v *BundleValidator) checkDatePlausibility(date string, line int) {
+	parsed, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return
+	}
+
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	if current := now().UTC(); parsed.After(current.Add(maxDateSkew)) {
+		v.addWarning(line, fmt.Sprintf("date %s is in the future (current date is %s)", date, current.Format(time.DateOnly)))
+	}
+}
+
 // validateVendorID validates that a vendor ID is valid according to TCG registry.
 func (v *BundleValidator) validateVendorID(id string) error {
 	vendorID := vendors.ID(id)
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
@@ -31,6 +32,34 @@ func TestValidateBundle_ValidBundle(t *testing.T) {
 	}
 }
 
+func TestValidateBundle_CustomMarkers(t *testing.T) {
+	data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	markers := bundlepkg.Markers{Global: "%%", Cert: "%"}
+	custom := withMarkers(data, markers)
+
+	validator := bundlepkg.NewBundleValidator()
+	validator.Markers = markers
+	errors, err := validator.ValidateBundle([]byte(custom))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range errors {
+		t.Errorf("unexpected validation error at line %d: %s", e.Line, e.Message)
+	}
+
+	// The default markers reject the custom ones
+	errors, err = bundlepkg.NewBundleValidator().ValidateBundle([]byte(custom))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(errors) == 0 {
+		t.Error("expected validation errors with the default markers")
+	}
+}
+
 func TestValidateBundle_MissingGlobalMetadata(t *testing.T) {
 	bundle := `# Certificate: Test
 # Owner: STM
@@ -142,6 +171,45 @@ func TestValidateBundle_InvalidCommitHash(t *testing.T) {
 	}
 }
 
+func TestValidateBundle_FormatVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+	}{
+		{"legacy bundle", "", false},
+		{"current version", "## Format: 1\n", false},
+		{"future version", "## Format: 2\n", true},
+		{"invalid version", "## Format: latest\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := "##\n## tpm-ca-certificates.pem\n##\n## Date: 2024-12-08\n## Commit: 1234567890abcdef1234567890abcdef12345678\n" +
+				tt.format + "##\n"
+
+			validator := bundlepkg.NewBundleValidator()
+			errors, err := validator.ValidateBundle([]byte(bundle))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			found := false
+			for _, e := range errors {
+				if strings.Contains(e.Message, "unsupported bundle format version") {
+					found = true
+					if e.Line != 6 {
+						t.Errorf("expected error on line 6, got line %d", e.Line)
+					}
+				}
+			}
+			if found != tt.wantErr {
+				t.Errorf("unsupported format version reported = %t, want %t (errors: %v)", found, tt.wantErr, errors)
+			}
+		})
+	}
+}
+
 func TestValidateBundle_MissingRequiredFields(t *testing.T) {
 	bundle := `##
 ## tpm-ca-certificates.pem
@@ -543,3 +611,60 @@ func TestValidateCommit(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBundle_FutureDate(t *testing.T) {
+	bundle := `##
+## tpm-ca-certificates.pem
+##
+## Date: 2025-06-15
+## Commit: 1234567890abcdef1234567890abcdef12345678
+##
+## This file has been auto-generated by tpmtb (TPM Trust Bundle)
+## and contains a list of verified TPM Root Endorsement Certificates.
+##
+`
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		wantWarning bool
+	}{
+		{
+			name: "date in the past",
+			now:  time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "date within skew allowance",
+			now:  time.Date(2025, 6, 14, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "date in the future",
+			now:         time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC),
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := bundlepkg.NewBundleValidator()
+			validator.Now = func() time.Time { return tt.now }
+
+			errors, err := validator.ValidateBundle([]byte(bundle))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(errors) > 0 {
+				t.Errorf("expected no validation errors, got %v", errors)
+			}
+
+			warnings := validator.Warnings()
+			if tt.wantWarning {
+				if len(warnings) != 1 || warnings[0].Line != 4 || !strings.Contains(warnings[0].Message, "in the future") {
+					t.Errorf("expected a future date warning on line 4, got %v", warnings)
+				}
+			} else if len(warnings) > 0 {
+				t.Errorf("expected no warnings, got %v", warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package verifier
+
+import (
+	"fmt"
+
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/cosign"
+)
+
+var (
+	// ErrChecksumEntryMissing is returned when the checksums file has no entry for the
+	// filename of the verified bundle, e.g. the entry was stripped from the file.
+	ErrChecksumEntryMissing = cosign.ErrChecksumEntryMissing
+
+	// ErrChecksumMismatch is returned when the checksums entry of the bundle filename
+	// doesn't match the bundle digest.
+	ErrChecksumMismatch = cosign.ErrChecksumMismatch
+
+	// ErrMalformedChecksums is returned when the checksums file cannot be parsed
+	// (see [cosign.ParseChecksums]), e.g. the bundle filename has several entries.
+	ErrMalformedChecksums = cosign.ErrMalformedChecksums
+)
+
+// verifyChecksumEntry checks that the checksums file has exactly one entry for the
+// expected filename of the bundle (see [bundlepkg.FilenamebyBundleType]), matching its digest.
+//
+// The signature of the checksums file only proves who produced it: this check binds
+// the signed file to the bundle being verified.
+func verifyChecksumEntry(checksumsData, bundleData []byte) error {
+	metadata, err := bundlepkg.ParseMetadata(bundleData)
+	if err != nil {
+		return fmt.Errorf("failed to parse bundle metadata: %w", err)
+	}
+	return cosign.ValidateChecksum(checksumsData, bundleData, bundlepkg.FilenamebyBundleType[metadata.Type])
+}
@@ -0,0 +1,176 @@
+package verifier
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+)
+
+// tlogTimestampType is the timestamp type reported for transparency log integrated times.
+const tlogTimestampType = "Tlog"
+
+// pinnedKey is a public key (optionally bound to a certificate) trusted as the only signer.
+type pinnedKey struct {
+	publicKey crypto.PublicKey
+	der       []byte
+}
+
+// LoadPinnedKey parses a PEM-encoded public key or X.509 certificate.
+//
+// When a certificate is provided, only its public key is pinned.
+func LoadPinnedKey(data []byte) (crypto.PublicKey, error) {
+	key, err := parsePinnedKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return key.publicKey, nil
+}
+
+func parsePinnedKey(data []byte) (*pinnedKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM pinned key")
+	}
+
+	var publicKey crypto.PublicKey
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pinned certificate: %w", err)
+		}
+		publicKey = cert.PublicKey
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pinned public key: %w", err)
+		}
+		publicKey = key
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q (expected CERTIFICATE or PUBLIC KEY)", block.Type)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported pinned key: %w", err)
+	}
+	return &pinnedKey{publicKey: publicKey, der: der}, nil
+}
+
+// matches reports whether the given public key is the pinned key.
+func (k *pinnedKey) matches(publicKey crypto.PublicKey) bool {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(der, k.der)
+}
+
+// trustedMaterial exposes the pinned key to sigstore-go for bundles carrying a public key hint.
+func (k *pinnedKey) trustedMaterial(isDSSE bool) (root.TrustedMaterial, error) {
+	// Message signatures over blobs use ED25519ph, DSSE envelopes use pure ED25519
+	var opts []signature.LoadOption
+	if !isDSSE {
+		opts = append(opts, options.WithED25519ph())
+	}
+	sv, err := signature.LoadDefaultVerifier(k.publicKey, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pinned key verifier: %w", err)
+	}
+	expiringKey := root.NewExpiringKey(sv, time.Time{}, time.Time{})
+	return root.NewTrustedPublicKeyMaterial(func(string) (root.TimeConstrainedVerifier, error) {
+		return expiringKey, nil
+	}), nil
+}
+
+// verifyPinned verifies a Sigstore bundle signed by the pinned key.
+//
+// Message signatures are checked against artifact, DSSE envelopes against artifactDigest
+// (hex-encoded SHA-256). Transparency log entries are not verified against Rekor: their
+// integrated time is reported as-is so that the caller can still enforce the release date.
+func (k *pinnedKey) verifyPinned(b *bundle.Bundle, artifact []byte, artifactDigest string) (*verify.VerificationResult, error) {
+	verificationContent, err := b.VerificationContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verification material: %w", err)
+	}
+	sigContent, err := b.SignatureContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature content: %w", err)
+	}
+	isDSSE := sigContent.EnvelopeContent() != nil
+
+	result := verify.NewVerificationResult()
+
+	// A certificate embedded in the bundle is only accepted if it certifies the pinned key
+	if cert := verificationContent.Certificate(); cert != nil {
+		if !k.matches(cert.PublicKey) {
+			return nil, fmt.Errorf("signing certificate does not match the pinned key")
+		}
+		summary, err := certificate.SummarizeCertificate(cert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize signing certificate: %w", err)
+		}
+		result.Signature = &verify.SignatureVerificationResult{Certificate: &summary}
+	} else {
+		keyID := []byte(verificationContent.PublicKey().Hint())
+		result.Signature = &verify.SignatureVerificationResult{PublicKeyID: &keyID}
+	}
+
+	trustedMaterial, err := k.trustedMaterial(isDSSE)
+	if err != nil {
+		return nil, err
+	}
+
+	if isDSSE {
+		digestBytes, err := hex.DecodeString(artifactDigest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact digest: %w", err)
+		}
+		digests := []verify.ArtifactDigest{{Algorithm: "sha256", Digest: digestBytes}}
+		if err := verify.VerifySignatureWithArtifactDigests(sigContent, verificationContent, trustedMaterial, digests); err != nil {
+			return nil, fmt.Errorf("signature verification with pinned key failed: %w", err)
+		}
+		envelope, err := b.Envelope()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read envelope: %w", err)
+		}
+		statement, err := envelope.Statement()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statement: %w", err)
+		}
+		result.Statement = statement
+	} else {
+		artifacts := []io.Reader{bytes.NewReader(artifact)}
+		if err := verify.VerifySignatureWithArtifacts(sigContent, verificationContent, trustedMaterial, artifacts); err != nil {
+			return nil, fmt.Errorf("signature verification with pinned key failed: %w", err)
+		}
+	}
+
+	entries, err := b.TlogEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transparency log entries: %w", err)
+	}
+	for _, entry := range entries {
+		if integratedTime := entry.IntegratedTime(); !integratedTime.IsZero() {
+			result.VerifiedTimestamps = append(result.VerifiedTimestamps, verify.TimestampVerificationResult{
+				Type:      tlogTimestampType,
+				URI:       entry.LogKeyID(),
+				Timestamp: integratedTime,
+			})
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,187 @@
+package verifier
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+// signChecksums signs checksums with key and returns a Sigstore bundle (v0.1) whose
+// transparency log entry was integrated at signedAt.
+func signChecksums(t *testing.T, key *ecdsa.PrivateKey, checksums []byte, signedAt time.Time) []byte {
+	t.Helper()
+
+	digest := sha256.Sum256(checksums)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign checksums: %v", err)
+	}
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	body, _ := json.Marshal(map[string]any{
+		"apiVersion": "0.0.1",
+		"kind":       "hashedrekord",
+		"spec": map[string]any{
+			"data": map[string]any{
+				"hash": map[string]any{"algorithm": "sha256", "value": hex.EncodeToString(digest[:])},
+			},
+			"signature": map[string]any{
+				"content":   base64.StdEncoding.EncodeToString(sig),
+				"publicKey": map[string]any{"content": base64.StdEncoding.EncodeToString(pubPEM)},
+			},
+		},
+	})
+
+	b64 := base64.StdEncoding.EncodeToString
+	sigBundle, _ := json.Marshal(map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle+json;version=0.1",
+		"verificationMaterial": map[string]any{
+			"publicKey": map[string]any{"hint": "pinned"},
+			"tlogEntries": []any{map[string]any{
+				"logIndex":          "1",
+				"logId":             map[string]any{"keyId": b64([]byte("test-log"))},
+				"kindVersion":       map[string]any{"kind": "hashedrekord", "version": "0.0.1"},
+				"integratedTime":    fmt.Sprintf("%d", signedAt.Unix()),
+				"inclusionPromise":  map[string]any{"signedEntryTimestamp": b64([]byte("unverified"))},
+				"canonicalizedBody": b64(body),
+			}},
+		},
+		"messageSignature": map[string]any{
+			"messageDigest": map[string]any{"algorithm": "SHA2_256", "digest": b64(digest[:])},
+			"signature":     b64(sig),
+		},
+	})
+	return sigBundle
+}
+
+func encodePublicKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal public key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestVerifyCosignWithPinnedKey(t *testing.T) {
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	metadata, err := bundlepkg.ParseMetadata(bundleData)
+	if err != nil {
+		t.Fatalf("Failed to parse bundle metadata: %v", err)
+	}
+	releaseDate, err := time.Parse("2006-01-02", metadata.Date)
+	if err != nil {
+		t.Fatalf("Failed to parse bundle date: %v", err)
+	}
+
+	bundleDigest := sha256.Sum256(bundleData)
+	checksums := fmt.Appendf(nil, "%s  %s\n", hex.EncodeToString(bundleDigest[:]), bundlepkg.FilenamebyBundleType[metadata.Type])
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		pinnedKey []byte
+		checksums []byte
+		signedAt  time.Time
+		wantErr   string
+	}{
+		{
+			name:      "valid signature",
+			pinnedKey: encodePublicKey(t, signingKey),
+			checksums: checksums,
+			signedAt:  releaseDate.Add(12 * time.Hour),
+		},
+		{
+			name:      "signature from another key",
+			pinnedKey: encodePublicKey(t, otherKey),
+			checksums: checksums,
+			signedAt:  releaseDate.Add(12 * time.Hour),
+			wantErr:   "signature verification with pinned key failed",
+		},
+		{
+			name:      "signed on another day",
+			pinnedKey: encodePublicKey(t, signingKey),
+			checksums: checksums,
+			signedAt:  releaseDate.Add(-12 * time.Hour),
+			wantErr:   "date mismatch",
+		},
+		{
+			name:      "checksum mismatch",
+			pinnedKey: encodePublicKey(t, signingKey),
+			checksums: fmt.Appendf(nil, "%s  %s\n", strings.Repeat("0", 64), bundlepkg.FilenamebyBundleType[metadata.Type]),
+			signedAt:  releaseDate.Add(12 * time.Hour),
+			wantErr:   ErrChecksumMismatch.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := New(Config{
+				Date:      metadata.Date,
+				Commit:    metadata.Commit,
+				PinnedKey: tt.pinnedKey,
+			})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			sigBundle := signChecksums(t, signingKey, tt.checksums, tt.signedAt)
+			_, _, err = v.verifyCosign(t.Context(), bundleData, tt.checksums, sigBundle)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("verifyCosign() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("verifyCosign() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPinnedKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	if _, err := LoadPinnedKey(encodePublicKey(t, key)); err != nil {
+		t.Errorf("LoadPinnedKey() with public key error = %v", err)
+	}
+	if _, err := LoadPinnedKey([]byte("not a pem")); err == nil {
+		t.Error("LoadPinnedKey() expected error for invalid PEM")
+	}
+	if _, err := New(Config{Date: "2025-12-03", Commit: "abc", PinnedKey: []byte("not a pem")}); err == nil {
+		t.Error("New() expected error for invalid pinned key")
+	}
+}
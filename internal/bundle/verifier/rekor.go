@@ -0,0 +1,99 @@
+package verifier
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/tlog"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+// rekorV1CheckpointOrigin matches the origin of Rekor v1 checkpoints ("<hostname> - <tree ID>").
+var rekorV1CheckpointOrigin = regexp.MustCompile(`^.* - [0-9]+$`)
+
+// RekorEntry describes a Rekor transparency log entry of the Cosign signature.
+type RekorEntry struct {
+	// LogIndex is the index of the entry in the Rekor log.
+	LogIndex int64
+
+	// IntegratedTime is the time the entry was added to the log.
+	IntegratedTime time.Time
+
+	// InclusionProofVerified is true when the entry holds an inclusion proof which verifies
+	// against a checkpoint signed by a Rekor log of the Sigstore trusted root.
+	//
+	// It is always false with [Config.PinnedKey], since no trusted root is used.
+	InclusionProofVerified bool
+}
+
+// rekorEntries describes the Rekor entries of b, verifying their inclusion proof against trustedMaterial.
+//
+// The returned error joins the reasons why inclusion proofs did not verify; it is only
+// relevant when at least one verified inclusion proof is required.
+func rekorEntries(b *bundle.Bundle, trustedMaterial root.TrustedMaterial) ([]RekorEntry, error) {
+	entries, err := b.TlogEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transparency log entries: %w", err)
+	}
+
+	result := make([]RekorEntry, 0, len(entries))
+	var errs []error
+	for _, entry := range entries {
+		e := RekorEntry{LogIndex: entry.LogIndex(), IntegratedTime: entry.IntegratedTime()}
+		switch {
+		case trustedMaterial == nil:
+			errs = append(errs, fmt.Errorf("entry %d: no trusted root to verify the inclusion proof", e.LogIndex))
+		case !entry.HasInclusionProof():
+			errs = append(errs, fmt.Errorf("entry %d: no inclusion proof", e.LogIndex))
+		default:
+			if err := verifyInclusionProof(entry, trustedMaterial); err != nil {
+				errs = append(errs, fmt.Errorf("entry %d: %w", e.LogIndex, err))
+			} else {
+				e.InclusionProofVerified = true
+			}
+		}
+		result = append(result, e)
+	}
+	if len(entries) == 0 {
+		errs = append(errs, errors.New("no transparency log entry"))
+	}
+	return result, errors.Join(errs...)
+}
+
+// verifyInclusionProof verifies the inclusion proof of entry and the signature of its checkpoint.
+func verifyInclusionProof(entry *tlog.Entry, trustedMaterial root.TrustedMaterial) error {
+	tlogVerifier, ok := trustedMaterial.RekorLogs()[hex.EncodeToString([]byte(entry.LogKeyID()))]
+	if !ok {
+		return fmt.Errorf("log not found in trusted root")
+	}
+	verifier, err := signature.LoadVerifier(tlogVerifier.PublicKey, tlogVerifier.SignatureHashFunc)
+	if err != nil {
+		return fmt.Errorf("failed to load log verifier: %w", err)
+	}
+
+	checkpoint := entry.TransparencyLogEntry().GetInclusionProof().GetCheckpoint().GetEnvelope()
+	origin, _, _ := strings.Cut(checkpoint, "\n")
+	if rekorV1CheckpointOrigin.MatchString(origin) {
+		if err := tlog.VerifyInclusion(entry, verifier); err != nil {
+			return fmt.Errorf("invalid inclusion proof: %w", err)
+		}
+		return nil
+	}
+
+	// Rekor v2 checkpoints are identified by the log hostname
+	u, err := url.Parse(tlogVerifier.BaseURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid log base URL %q", tlogVerifier.BaseURL)
+	}
+	if err := tlog.VerifyCheckpointAndInclusion(entry, verifier, u.Hostname()); err != nil {
+		return fmt.Errorf("invalid inclusion proof: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+)
+
+// testRekorLogIndex is the Rekor log index of the signature of the test bundle.
+const testRekorLogIndex = 743790112
+
+func TestRekorEntries(t *testing.T) {
+	sigData, err := testutil.ReadTestFile(testutil.ChecksumSigstoreFile)
+	if err != nil {
+		t.Fatalf("failed to read sigstore bundle: %v", err)
+	}
+	trustedRootData, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+	if err != nil {
+		t.Fatalf("failed to read trusted root: %v", err)
+	}
+	trustedRoot, err := LoadTrustedRoot(trustedRootData)
+	if err != nil {
+		t.Fatalf("failed to load trusted root: %v", err)
+	}
+
+	parse := func(t *testing.T, data []byte) *bundle.Bundle {
+		t.Helper()
+		var b bundle.Bundle
+		if err := b.UnmarshalJSON(data); err != nil {
+			t.Fatalf("failed to parse sigstore bundle: %v", err)
+		}
+		return &b
+	}
+
+	tests := []struct {
+		name         string
+		data         []byte
+		trustedRoot  root.TrustedMaterial
+		wantVerified bool
+	}{
+		{
+			name:         "valid inclusion proof",
+			data:         sigData,
+			trustedRoot:  trustedRoot,
+			wantVerified: true,
+		},
+		{
+			name: "no trusted root",
+			data: sigData,
+		},
+		{
+			name: "tampered root hash",
+			data: bytes.Replace(sigData,
+				[]byte("keP7eE4y4kNJC3ZBDqCHMf13GdZ6f4O5O8euCgmYMuE="),
+				[]byte("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="), 1),
+			trustedRoot: trustedRoot,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := rekorEntries(parse(t, tt.data), tt.trustedRoot)
+			if tt.wantVerified && err != nil {
+				t.Fatalf("rekorEntries() error = %v", err)
+			}
+			if !tt.wantVerified && err == nil {
+				t.Fatal("expected an error for an unverified inclusion proof")
+			}
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 Rekor entry, got %d", len(entries))
+			}
+			if entries[0].LogIndex != testRekorLogIndex {
+				t.Errorf("LogIndex = %d, want %d", entries[0].LogIndex, testRekorLogIndex)
+			}
+			if entries[0].IntegratedTime.IsZero() {
+				t.Error("expected a non-zero IntegratedTime")
+			}
+			if entries[0].InclusionProofVerified != tt.wantVerified {
+				t.Errorf("InclusionProofVerified = %v, want %v", entries[0].InclusionProofVerified, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestVerifyRequireRekorInclusion(t *testing.T) {
+	verifyCfg := newTestVerifyConfig(t)
+
+	t.Run("verified inclusion proof", func(t *testing.T) {
+		cfg := newTestConfig(t)
+		cfg.RequireRekorInclusion = true
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		result, err := v.Verify(context.Background(), verifyCfg)
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if len(result.RekorEntries) != 1 {
+			t.Fatalf("expected 1 Rekor entry, got %d", len(result.RekorEntries))
+		}
+		if entry := result.RekorEntries[0]; entry.LogIndex != testRekorLogIndex || !entry.InclusionProofVerified {
+			t.Errorf("unexpected Rekor entry: %+v", entry)
+		}
+	})
+
+	t.Run("not required", func(t *testing.T) {
+		v, err := New(newTestConfig(t))
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		result, err := v.Verify(context.Background(), verifyCfg)
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if result.RekorEntries != nil {
+			t.Errorf("expected no Rekor entry when the inclusion proof is not required, got %+v", result.RekorEntries)
+		}
+	})
+
+	t.Run("pinned key", func(t *testing.T) {
+		cfg := newTestConfig(t)
+		cfg.TrustedRoot = nil
+		cfg.PinnedKey = []byte("-----BEGIN PUBLIC KEY-----")
+		cfg.RequireRekorInclusion = true
+		_, err := New(cfg)
+		if err == nil || !strings.Contains(err.Error(), "rekor inclusion") {
+			t.Fatalf("expected an error when requiring Rekor inclusion with a pinned key, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package verifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Verification phases reported in telemetry.
+const (
+	phaseCosign      = "cosign"
+	phaseRekor       = "rekor"
+	phaseAttestation = "attestation"
+)
+
+// Verification outcomes reported in telemetry.
+const (
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+)
+
+// Telemetry attribute keys and metric names.
+const (
+	attrPhase      = "tpmtb.verification.phase"
+	attrOutcome    = "tpmtb.verification.outcome"
+	attrDurationMs = "tpmtb.verification.duration_ms"
+
+	metricVerifications = "tpmtb.verification.count"
+	metricDuration      = "tpmtb.verification.duration"
+)
+
+var (
+	// phaseTracer and phaseMeter return the tracer and meter recording the verification
+	// phases. They are variables so that tests can record them in memory.
+	phaseTracer = observability.Tracer
+	phaseMeter  = observability.Meter
+
+	// instrumentsMu guards instruments.
+	instrumentsMu sync.Mutex
+
+	// instruments are the verification instruments of the current meter.
+	instruments *phaseInstruments
+)
+
+// phaseInstruments are the metric instruments recording the verification phases.
+type phaseInstruments struct {
+	meter     metric.Meter
+	counter   metric.Int64Counter
+	histogram metric.Float64Histogram
+}
+
+// currentInstruments returns the instruments of the current meter, creating them on first
+// use and whenever the meter provider changes.
+func currentInstruments() *phaseInstruments {
+	meter := phaseMeter()
+
+	instrumentsMu.Lock()
+	defer instrumentsMu.Unlock()
+	if instruments != nil && instruments.meter == meter {
+		return instruments
+	}
+
+	// Instrument creation only fails on invalid names (constants here), and a usable
+	// no-op instrument is returned anyway
+	counter, _ := meter.Int64Counter(metricVerifications,
+		metric.WithDescription("Number of bundle verification phases by outcome"))
+	histogram, _ := meter.Float64Histogram(metricDuration,
+		metric.WithDescription("Duration of bundle verification phases"),
+		metric.WithUnit("ms"))
+	instruments = &phaseInstruments{meter: meter, counter: counter, histogram: histogram}
+	return instruments
+}
+
+// startPhase starts the span of a verification phase.
+//
+// The returned function ends it, recording the phase outcome and duration on
+// the span and in the verification metrics. Both are no-ops when observability is disabled.
+func startPhase(ctx context.Context, phase string) (context.Context, func(err error)) {
+	ctx, span := phaseTracer().Start(ctx, "tpmtb.verify."+phase)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		defer span.End()
+
+		duration := time.Since(start)
+		outcome := outcomeSuccess
+		if err != nil {
+			outcome = outcomeFailure
+			observability.RecordError(span, err)
+		}
+
+		attrs := []attribute.KeyValue{
+			attribute.String(attrPhase, phase),
+			attribute.String(attrOutcome, outcome),
+		}
+		span.SetAttributes(append(attrs, attribute.Int64(attrDurationMs, duration.Milliseconds()))...)
+		recordPhaseMetrics(ctx, duration, attrs)
+	}
+}
+
+// recordPhaseMetrics records a verification phase in the verification counter and duration histogram.
+func recordPhaseMetrics(ctx context.Context, duration time.Duration, attrs []attribute.KeyValue) {
+	instruments := currentInstruments()
+	opt := metric.WithAttributes(attrs...)
+	instruments.counter.Add(ctx, 1, opt)
+	instruments.histogram.Record(ctx, float64(duration.Microseconds())/1000, opt)
+}
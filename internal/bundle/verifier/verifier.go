@@ -3,11 +3,14 @@ package verifier
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
-	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/concurrency"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/cosign"
 	transparencyGithub "github.com/loicsikidi/tpm-ca-certificates/internal/transparency/github"
@@ -20,6 +23,14 @@ import (
 	"github.com/sigstore/sigstore-go/pkg/verify"
 )
 
+// ErrSubjectDigestMismatch is returned when no subject of an attestation has the digest of the verified bundle,
+// i.e. the attestation was issued for another artifact.
+var ErrSubjectDigestMismatch = errors.New("attestation subject digest does not match the bundle digest")
+
+// ErrTrustedRootUnavailable is returned when the Sigstore trusted root cannot be fetched from its TUF repository,
+// i.e. the bundle could not be verified rather than failing its verification.
+var ErrTrustedRootUnavailable = errors.New("sigstore trusted root unavailable")
+
 // Config contains configuration for bundle verification.
 type Config struct {
 	// Date is the bundle generation date (YYYY-MM-DD format)
@@ -42,6 +53,22 @@ type Config struct {
 	// Optional, default: [github.ReleaseBundleWorkflowPath]
 	WorkflowFilename string
 
+	// WorkflowFilenames lists additional GitHub Actions workflow file names accepted
+	// as signer, such as the former path of a renamed release workflow.
+	//
+	// Verification passes if the bundle was signed by [Config.WorkflowFilename] or by
+	// any of them, so that releases built before the rename can still be verified. The
+	// checksums signature and the provenance attestation must come from the same workflow.
+	//
+	// Optional.
+	WorkflowFilenames []string
+
+	// AllowedOIDCIssuers are the OIDC issuers accepted for the signing certificates,
+	// e.g. both issuers during an identity provider migration.
+	//
+	// Optional, default: [policy.DefaultOIDCIssuer]
+	AllowedOIDCIssuers []string
+
 	// HTTPClient is the HTTP client to use for requests.
 	//
 	// Optional. If nil, it stays nil and default HTTP client will be used.
@@ -57,6 +84,35 @@ type Config struct {
 	//
 	// Optional. If provided, this will be used instead of fetching from TUF.
 	TrustedRoot []byte
+
+	// TUFMirror is the TUF repository the Sigstore trusted root is fetched from,
+	// in place of Sigstore's public-good repository.
+	//
+	// Optional. Cannot be combined with TrustedRoot.
+	TUFMirror *verifier.TUFMirror
+
+	// PinnedKey is a PEM-encoded public key or certificate expected to have signed
+	// both the checksums file and the provenance attestation.
+	//
+	// When set, keyless Sigstore verification (Fulcio identity, Rekor inclusion) is
+	// replaced by a signature check against this key and no TUF request is made.
+	// Commit and release date checks are still enforced, but the date is read from the
+	// transparency log entries embedded in the bundles without authenticating them.
+	//
+	// Optional.
+	PinnedKey []byte
+
+	// RequireRekorInclusion fails the verification unless an inclusion proof of the Cosign
+	// signature in Rekor verifies against the Sigstore trusted root.
+	//
+	// Optional. Cannot be combined with PinnedKey.
+	RequireRekorInclusion bool
+
+	// CollectAllFailures runs every verification phase and check even after a failure,
+	// and returns all failures joined in a single error rather than the first one.
+	//
+	// Optional. Default is false (verification stops at the first failure).
+	CollectAllFailures bool
 }
 
 // CheckAndSetDefaults validates and sets default values.
@@ -79,13 +135,36 @@ func (c *Config) CheckAndSetDefaults() error {
 	if c.WorkflowFilename == "" {
 		c.WorkflowFilename = github.ReleaseBundleWorkflowPath
 	}
+	if slices.Contains(c.WorkflowFilenames, "") {
+		return fmt.Errorf("workflow filenames cannot contain an empty value")
+	}
+	if slices.Contains(c.AllowedOIDCIssuers, "") {
+		return fmt.Errorf("allowed OIDC issuers cannot contain an empty value")
+	}
+	if c.RequireRekorInclusion && len(c.PinnedKey) > 0 {
+		return fmt.Errorf("rekor inclusion cannot be required with a pinned key")
+	}
+	if c.TUFMirror != nil {
+		if len(c.TrustedRoot) > 0 {
+			return fmt.Errorf("trusted root and TUF mirror are mutually exclusive")
+		}
+		if err := c.TUFMirror.CheckAndSetDefaults(); err != nil {
+			return fmt.Errorf("invalid TUF mirror: %w", err)
+		}
+	}
 
 	return nil
 }
 
 // Verifier handles bundle verification.
 type Verifier struct {
-	config Config
+	config    Config
+	pinnedKey *pinnedKey
+
+	// sigstoreConfig caches the Sigstore verifier config, so that the trusted root is only
+	// fetched once for all verification phases
+	sigstoreMu     sync.Mutex
+	sigstoreConfig *verifier.Config
 }
 
 // New creates a new Verifier instance.
@@ -93,7 +172,15 @@ func New(cfg Config) (*Verifier, error) {
 	if err := cfg.CheckAndSetDefaults(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
-	return &Verifier{config: cfg}, nil
+	v := &Verifier{config: cfg}
+	if len(cfg.PinnedKey) > 0 {
+		key, err := parsePinnedKey(cfg.PinnedKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pinned key: %w", err)
+		}
+		v.pinnedKey = key
+	}
+	return v, nil
 }
 
 // VerifyConfig contains configuration for bundle verification.
@@ -115,8 +202,15 @@ type VerifyConfig struct {
 
 	// ProvenanceData is the provenance attestation bundle
 	//
-	// Required.
+	// Required unless Attestations is set.
 	ProvenanceData []byte
+
+	// Attestations holds every attestation bundle found for the bundle digest, when there
+	// are several. They are verified concurrently and the first one (in order) matching the
+	// policy is used, see [VerifyResult.Provenance].
+	//
+	// Optional. Ignored if ProvenanceData is set.
+	Attestations [][]byte
 }
 
 // CheckAndSetDefaults validates the configuration.
@@ -130,7 +224,7 @@ func (c *VerifyConfig) CheckAndSetDefaults() error {
 	if len(c.ChecksumsSigData) == 0 {
 		return fmt.Errorf("checksums signature data cannot be empty")
 	}
-	if len(c.ProvenanceData) == 0 {
+	if len(c.ProvenanceData) == 0 && len(c.Attestations) == 0 {
 		return fmt.Errorf("provenance data cannot be empty")
 	}
 	return nil
@@ -146,6 +240,18 @@ type VerifyResult struct {
 
 	// GithubAttestationResults contains all verified attestations
 	GithubAttestationResults []*verify.VerificationResult
+
+	// Provenance is the attestation bundle matching the policy: ProvenanceData, or one of
+	// Attestations.
+	Provenance []byte
+
+	// RekorEntries describes the Rekor entries of the Cosign signature, only when
+	// [Config.RequireRekorInclusion] is set
+	RekorEntries []RekorEntry
+
+	// CachedAt is the time of the original verification when the result is served
+	// from a verification cache. CosignResult, GithubAttestationResults and RekorEntries are then empty.
+	CachedAt time.Time
 }
 
 // Verify performs full bundle verification (Cosign + GitHub Attestations).
@@ -156,33 +262,157 @@ func (v *Verifier) Verify(ctx context.Context, cfg VerifyConfig) (*VerifyResult,
 
 	result := &VerifyResult{Policy: v.GetPolicyConfig()}
 
+	failures := v.newFailures()
+
 	// Phase 1: Cosign verification
-	cosignResult, err := v.verifyCosign(ctx, cfg.BundleData, cfg.ChecksumsData, cfg.ChecksumsSigData)
-	if err != nil {
-		return nil, fmt.Errorf("cosign verification failed: %w", err)
+	cosignCtx, endCosign := startPhase(ctx, phaseCosign)
+	cosignResult, policyCfg, err := v.verifyCosign(cosignCtx, cfg.BundleData, cfg.ChecksumsData, cfg.ChecksumsSigData)
+	endCosign(err)
+	if err != nil && failures.add(fmt.Errorf("cosign verification failed: %w", err)) {
+		return nil, failures.err()
 	}
 	result.CosignResult = cosignResult
+	result.Policy = policyCfg
+
+	// The attestation must come from the workflow which signed the checksums file
+	attestationPolicies := v.policyConfigs()
+	if err == nil {
+		attestationPolicies = []policy.Config{policyCfg}
+	}
+
+	if v.config.RequireRekorInclusion {
+		_, endRekor := startPhase(ctx, phaseRekor)
+		rekorEntries, err := v.verifyRekorInclusion(cfg.ChecksumsSigData)
+		endRekor(err)
+		if err != nil && failures.add(fmt.Errorf("rekor inclusion verification failed: %w", err)) {
+			return nil, failures.err()
+		}
+		result.RekorEntries = rekorEntries
+	}
 
 	// Phase 2: GitHub Attestation verification
 	bundleDigest := digest.ComputeSHA256(cfg.BundleData)
-	attestationResults, err := v.verifyGitHubAttestations(ctx, cfg.ProvenanceData, bundleDigest)
-	if err != nil {
-		return nil, fmt.Errorf("github attestation verification failed: %w", err)
+	attestationCtx, endAttestation := startPhase(ctx, phaseAttestation)
+	attestations := cfg.Attestations
+	if len(cfg.ProvenanceData) > 0 {
+		attestations = [][]byte{cfg.ProvenanceData}
+	}
+	attestationResults, provenance, err := v.verifyGitHubAttestations(attestationCtx, attestations, bundleDigest, attestationPolicies)
+	endAttestation(err)
+	if err != nil && failures.add(fmt.Errorf("github attestation verification failed: %w", err)) {
+		return nil, failures.err()
 	}
 	result.GithubAttestationResults = attestationResults
+	result.Provenance = provenance
 
+	if err := failures.err(); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
+// failures records the failed checks of a verification.
+type failures struct {
+	collectAll bool
+	errs       []error
+}
+
+// newFailures returns an empty record of failures honoring [Config.CollectAllFailures].
+func (v *Verifier) newFailures() *failures {
+	return &failures{collectAll: v.config.CollectAllFailures}
+}
+
+// add records a failure and reports whether verification must stop, which is the
+// case unless all failures are collected.
+func (f *failures) add(err error) bool {
+	f.errs = append(f.errs, err)
+	return !f.collectAll
+}
+
+// err returns the recorded failures, or nil if there is none.
+func (f *failures) err() error {
+	if len(f.errs) == 1 {
+		return f.errs[0]
+	}
+	return errors.Join(f.errs...)
+}
+
+// GetPolicyConfig returns the policy enforced by the verifier, with defaults applied.
+//
+// When several workflows are accepted (see [Config.WorkflowFilenames]), the policy
+// of [Config.WorkflowFilename] is returned.
 func (v *Verifier) GetPolicyConfig() policy.Config {
-	return policy.Config{
-		SourceRepo:    v.config.SourceRepo,
-		BuildWorkflow: v.config.WorkflowFilename,
-		Tag:           v.config.Date,
+	return v.policyConfig(v.config.WorkflowFilename)
+}
+
+// policyConfig returns the policy accepting workflow as signer, with defaults applied.
+func (v *Verifier) policyConfig(workflow string) policy.Config {
+	cfg := policy.Config{
+		SourceRepo:         v.config.SourceRepo,
+		AllowedOIDCIssuers: v.config.AllowedOIDCIssuers,
+		BuildWorkflow:      workflow,
+		Tag:                v.config.Date,
+	}
+	_ = cfg.CheckAndSetDefaults() // inputs are validated by Config.CheckAndSetDefaults
+	return cfg
+}
+
+// policyConfigs returns the policies of all accepted workflows, [Config.WorkflowFilename] first.
+func (v *Verifier) policyConfigs() []policy.Config {
+	workflows := []string{v.config.WorkflowFilename}
+	for _, workflow := range v.config.WorkflowFilenames {
+		if !slices.Contains(workflows, workflow) {
+			workflows = append(workflows, workflow)
+		}
+	}
+
+	policies := make([]policy.Config, 0, len(workflows))
+	for _, workflow := range workflows {
+		policies = append(policies, v.policyConfig(workflow))
 	}
+	return policies
 }
 
+// verifyAnyPolicy calls verifyOne with each policy and returns the result of the first
+// one passing verification, along with that policy.
+func verifyAnyPolicy(policies []policy.Config, verifyOne func(policy.Config) (*verify.VerificationResult, error)) (*verify.VerificationResult, policy.Config, error) {
+	if len(policies) == 1 {
+		result, err := verifyOne(policies[0])
+		return result, policies[0], err
+	}
+
+	failures := make([]error, 0, len(policies))
+	for _, policyCfg := range policies {
+		result, err := verifyOne(policyCfg)
+		if err == nil {
+			return result, policyCfg, nil
+		}
+		failures = append(failures, fmt.Errorf("workflow %s: %w", policyCfg.BuildWorkflow, err))
+	}
+	return nil, policy.Config{}, fmt.Errorf("no accepted workflow matched: %w", errors.Join(failures...))
+}
+
+// GetSigstoreVerifierConfig returns the Sigstore verifier config of the trusted root.
+//
+// The trusted root is only fetched by the first successful call: later calls, such as
+// the ones of the verification phases following the Cosign one, reuse it.
 func (v *Verifier) GetSigstoreVerifierConfig() (verifier.Config, error) {
+	v.sigstoreMu.Lock()
+	defer v.sigstoreMu.Unlock()
+
+	if v.sigstoreConfig != nil {
+		return *v.sigstoreConfig, nil
+	}
+	cfg, err := v.newSigstoreVerifierConfig()
+	if err != nil {
+		return cfg, err
+	}
+	v.sigstoreConfig = &cfg
+	return cfg, nil
+}
+
+// newSigstoreVerifierConfig loads or fetches the trusted root.
+func (v *Verifier) newSigstoreVerifierConfig() (verifier.Config, error) {
 	cfg := verifier.Config{}
 
 	// Priority 1: Use custom trusted root if provided (offline mode)
@@ -195,93 +425,330 @@ func (v *Verifier) GetSigstoreVerifierConfig() (verifier.Config, error) {
 		return cfg, nil
 	}
 
-	// Priority 2: Fetch from TUF with local cache disabled
+	// Priority 2: Fetch from the custom TUF repository
+	if v.config.TUFMirror != nil {
+		trustedRoot, err := verifier.NewMirrorRoot(*v.config.TUFMirror, v.config.DisableLocalCache, v.config.HTTPClient)
+		if err != nil {
+			return cfg, fmt.Errorf("%w: %w", ErrTrustedRootUnavailable, err)
+		}
+		cfg.Root = trustedRoot
+		return cfg, nil
+	}
+
+	// Priority 3: Fetch from TUF with local cache disabled
 	if v.config.DisableLocalCache {
 		opts := verifier.GetDefaultTUFOptions(v.config.HTTPClient)
 		opts.DisableLocalCache = true
 		trustedRoot, err := root.FetchTrustedRootWithOptions(opts)
 		if err != nil {
-			return cfg, err
+			return cfg, fmt.Errorf("%w: %w", ErrTrustedRootUnavailable, err)
 		}
 		cfg.Root = trustedRoot
+		return cfg, nil
 	}
 
-	// Priority 3: Use default (fetch from TUF with local cache enabled)
+	// Priority 4: Use default (fetch from TUF with local cache enabled)
 	trustedRoot, err := verifier.NewDefaultRoot(v.config.HTTPClient)
 	if err != nil {
-		return cfg, err
+		return cfg, fmt.Errorf("%w: %w", ErrTrustedRootUnavailable, err)
 	}
 	cfg.Root = trustedRoot
 	return cfg, nil
 }
 
 // verifyCosign performs Cosign signature verification.
-func (v *Verifier) verifyCosign(ctx context.Context, bundleData, checksumsData, checksumsSigData []byte) (*verify.VerificationResult, error) {
+//
+// It returns the policy of the accepted workflow which signed the checksums file.
+func (v *Verifier) verifyCosign(ctx context.Context, bundleData, checksumsData, checksumsSigData []byte) (*verify.VerificationResult, policy.Config, error) {
+	// The checksums file must cover the bundle before its signature is worth checking
+	if err := verifyChecksumEntry(checksumsData, bundleData); err != nil {
+		return nil, policy.Config{}, err
+	}
+
+	if v.pinnedKey != nil {
+		result, err := v.verifyCosignWithPinnedKey(checksumsData, checksumsSigData)
+		return result, v.GetPolicyConfig(), err
+	}
+
 	verifierCfg, err := v.GetSigstoreVerifierConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to produce sigstore verifier config: %w", err)
+		return nil, policy.Config{}, fmt.Errorf("failed to produce sigstore verifier config: %w", err)
 	}
-	metadata, err := bundlepkg.ParseMetadata(bundleData)
+	result, policyCfg, err := verifyAnyPolicy(v.policyConfigs(), func(policyCfg policy.Config) (*verify.VerificationResult, error) {
+		return cosign.VerifyChecksumSignature(ctx, policyCfg, verifierCfg, checksumsData, checksumsSigData)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse bundle metadata: %w", err)
+		return nil, policy.Config{}, err
 	}
-	bundleFilename := bundlepkg.FilenamebyBundleType[metadata.Type]
-	result, err := cosign.VerifyChecksum(ctx, v.GetPolicyConfig(), verifierCfg, checksumsData, checksumsSigData, bundleData, bundleFilename)
+
+	if err := v.checkCosignResult(result, true); err != nil {
+		return nil, policy.Config{}, err
+	}
+
+	return result, policyCfg, nil
+}
+
+// checkCosignResult checks that a verified Cosign signature was produced from [Config.Commit]
+// on [Config.Date]. The commit is only checked when requireCommit is set or the signature
+// holds a certificate.
+func (v *Verifier) checkCosignResult(result *verify.VerificationResult, requireCommit bool) error {
+	failures := v.newFailures()
+	if requireCommit || result.Signature.Certificate != nil {
+		if err := verifyCosignCommit(result, v.config.Commit); err != nil && failures.add(fmt.Errorf("commit verification failed: %w", err)) {
+			return failures.err()
+		}
+	}
+	if err := verifyRekorTimestampDate(result, v.config.Date); err != nil {
+		failures.add(err)
+	}
+	return failures.err()
+}
+
+// checkAttestationResult checks that a verified attestation was produced from [Config.Commit]
+// on [Config.Date].
+func (v *Verifier) checkAttestationResult(result *verify.VerificationResult) error {
+	failures := v.newFailures()
+	if err := verifyRekorTimestampDate(result, v.config.Date); err != nil && failures.add(fmt.Errorf("timestamp validation failed: %w", err)) {
+		return failures.err()
+	}
+	if err := verifyAttestationCommit(result, v.config.Commit); err != nil {
+		failures.add(fmt.Errorf("commit validation failed: %w", err))
+	}
+	return failures.err()
+}
+
+// verifyRekorInclusion describes the Rekor entries of the Cosign signature bundle, and returns
+// an error unless the inclusion proof of one of them verifies (see [Config.RequireRekorInclusion]).
+//
+// The trusted root fetched by the Cosign phase is reused.
+func (v *Verifier) verifyRekorInclusion(checksumsSigData []byte) ([]RekorEntry, error) {
+	var b bundle.Bundle
+	if err := b.UnmarshalJSON(checksumsSigData); err != nil {
+		return nil, fmt.Errorf("failed to load signature bundle: %w", err)
+	}
+
+	verifierCfg, err := v.GetSigstoreVerifierConfig()
 	if err != nil {
+		return nil, fmt.Errorf("failed to produce sigstore verifier config: %w", err)
+	}
+
+	entries, err := rekorEntries(&b, verifierCfg.Root)
+	if entries == nil {
 		return nil, err
 	}
+	if !slices.ContainsFunc(entries, func(e RekorEntry) bool { return e.InclusionProofVerified }) {
+		return nil, fmt.Errorf("no verified inclusion proof: %w", err)
+	}
+	return entries, nil
+}
 
-	if err := verifyCosignCommit(result, v.config.Commit); err != nil {
-		return nil, fmt.Errorf("commit verification failed: %w", err)
+// verifyCosignWithPinnedKey performs Cosign signature verification against [Config.PinnedKey].
+//
+// The checksums entry of the bundle is checked beforehand, see [verifyChecksumEntry].
+func (v *Verifier) verifyCosignWithPinnedKey(checksumsData, checksumsSigData []byte) (*verify.VerificationResult, error) {
+	var b bundle.Bundle
+	if err := b.UnmarshalJSON(checksumsSigData); err != nil {
+		return nil, fmt.Errorf("failed to load signature bundle: %w", err)
 	}
 
-	if err := verifyRekorTimestampDate(result, v.config.Date); err != nil {
+	result, err := v.pinnedKey.verifyPinned(&b, checksumsData, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// A key-only signature carries no commit, which is then enforced by the attestation
+	if err := v.checkCosignResult(result, false); err != nil {
 		return nil, err
 	}
 
 	return result, nil
 }
 
+// ParseAttestation parses a provenance attestation bundle.
+func ParseAttestation(provenanceData []byte) (*bundle.Bundle, error) {
+	var b bundle.Bundle
+	if err := json.Unmarshal(provenanceData, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
 // verifyGitHubAttestations performs GitHub Attestation verification.
-func (v *Verifier) verifyGitHubAttestations(_ context.Context, provenanceData []byte, digest string) ([]*verify.VerificationResult, error) {
-	// Unmarshal the provenance data (attestation)
-	var bundle bundle.Bundle
-	if err := json.Unmarshal(provenanceData, &bundle); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal provenance: %w", err)
+//
+// When several attestations are given, they are verified concurrently (up to
+// [concurrency.MaxWorkers] at once) and the first one matching the policy, in order,
+// is returned along with its verification result.
+func (v *Verifier) verifyGitHubAttestations(ctx context.Context, attestations [][]byte, digest string, policies []policy.Config) ([]*verify.VerificationResult, []byte, error) {
+	if len(attestations) == 0 {
+		return nil, nil, fmt.Errorf("no attestation found")
+	}
+
+	verifyAttestation := func(b *bundle.Bundle) (*verify.VerificationResult, error) {
+		return v.verifyAttestationWithPinnedKey(b, digest)
+	}
+	if v.pinnedKey == nil {
+		var err error
+		if verifyAttestation, err = v.newAttestationVerifier(digest, policies); err != nil {
+			return nil, nil, err
+		}
 	}
 
+	i, result, err := verifyFirstMatch(ctx, attestations, func(ctx context.Context, data []byte) (*verify.VerificationResult, error) {
+		b, err := ParseAttestation(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal provenance: %w", err)
+		}
+		if err := verifySubjectDigest(b, digest); err != nil {
+			return nil, err
+		}
+		// Skip the signature verification once another attestation matched
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return verifyAttestation(b)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return []*verify.VerificationResult{result}, attestations[i], nil
+}
+
+// verifySubjectDigest checks that a subject of the attestation has the given SHA-256 digest,
+// so that an attestation issued for another artifact is rejected with [ErrSubjectDigestMismatch].
+//
+// The statement is read before its signature is verified: as the signature covers the whole
+// statement, a signed statement cannot have other subjects.
+func verifySubjectDigest(b *bundle.Bundle, digest string) error {
+	sigContent, err := b.SignatureContent()
+	if err != nil {
+		return fmt.Errorf("failed to read attestation content: %w", err)
+	}
+	envelope := sigContent.EnvelopeContent()
+	if envelope == nil {
+		return fmt.Errorf("attestation is not a DSSE envelope")
+	}
+	statement, err := envelope.Statement()
+	if err != nil {
+		return fmt.Errorf("failed to read attestation statement: %w", err)
+	}
+
+	expected := strings.TrimPrefix(digest, "sha256:")
+	for _, subject := range statement.GetSubject() {
+		if strings.EqualFold(subject.GetDigest()["sha256"], expected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: expected sha256:%s", ErrSubjectDigestMismatch, expected)
+}
+
+// newAttestationVerifier returns a function verifying a single attestation against the Sigstore trusted root.
+//
+// The attestation passes if it matches any of policies.
+func (v *Verifier) newAttestationVerifier(digest string, policies []policy.Config) (func(*bundle.Bundle) (*verify.VerificationResult, error), error) {
 	verifierCfg, err := v.GetSigstoreVerifierConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to produce sigstore verifier config: %w", err)
 	}
-	cfg := transparencyGithub.Config{
-		Digest:   digest,
-		Policy:   v.GetPolicyConfig(),
-		Verifier: verifierCfg,
+
+	verifiers := make(map[string]*transparencyGithub.Verifier, len(policies))
+	for _, policyCfg := range policies {
+		cfg := transparencyGithub.Config{
+			Digest:   digest,
+			Policy:   policyCfg,
+			Verifier: verifierCfg,
+		}
+		verifier, err := transparencyGithub.NewVerifier(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create github verifier: %w", err)
+		}
+		verifiers[policyCfg.BuildWorkflow] = verifier
+	}
+
+	return func(b *bundle.Bundle) (*verify.VerificationResult, error) {
+		// Verify the attestation
+		result, _, err := verifyAnyPolicy(policies, func(policyCfg policy.Config) (*verify.VerificationResult, error) {
+			return verifiers[policyCfg.BuildWorkflow].Verify(b)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("attestation verification failed: %w", err)
+		}
+
+		// Verify Rekor timestamp and commit match the bundle date and commit
+		if err := v.checkAttestationResult(result); err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}, nil
+}
+
+// verifyFirstMatch verifies attestations with a bounded worker pool and returns the index and
+// result of the first attestation (in input order) passing verification.
+//
+// The context given to verifyOne is canceled as soon as one succeeds, and pending
+// verifications are skipped. If none succeeds, the failures are reported in input order.
+func verifyFirstMatch(ctx context.Context, attestations [][]byte, verifyOne func(context.Context, []byte) (*verify.VerificationResult, error)) (int, *verify.VerificationResult, error) {
+	if len(attestations) == 1 {
+		result, err := verifyOne(ctx, attestations[0])
+		return 0, result, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*verify.VerificationResult, len(attestations))
+	errs := make([]error, len(attestations))
+	semaphore := make(chan struct{}, min(len(attestations), concurrency.MaxWorkers))
+	var wg sync.WaitGroup
+	for i, attestation := range attestations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+			results[i], errs[i] = verifyOne(ctx, attestation)
+			if errs[i] == nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if errs[i] == nil {
+			return i, result, nil
+		}
 	}
 
-	verifier, err := transparencyGithub.NewVerifier(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create github verifier: %w", err)
+	failures := make([]error, len(errs))
+	for i, err := range errs {
+		failures[i] = fmt.Errorf("attestation %d: %w", i, err)
 	}
+	return 0, nil, fmt.Errorf("no attestation matched the policy: %w", errors.Join(failures...))
+}
 
-	// Verify the attestation
-	result, err := verifier.Verify(&bundle)
+// verifyAttestationWithPinnedKey performs GitHub Attestation verification against [Config.PinnedKey].
+func (v *Verifier) verifyAttestationWithPinnedKey(b *bundle.Bundle, digest string) (*verify.VerificationResult, error) {
+	result, err := v.pinnedKey.verifyPinned(b, nil, strings.TrimPrefix(digest, "sha256:"))
 	if err != nil {
 		return nil, fmt.Errorf("attestation verification failed: %w", err)
 	}
 
-	// Verify Rekor timestamp matches the bundle date
-	if err := verifyRekorTimestampDate(result, v.config.Date); err != nil {
-		return nil, fmt.Errorf("timestamp validation failed: %w", err)
+	policyCfg := v.GetPolicyConfig()
+	if result.Statement.GetPredicateType() != policyCfg.PredicateType {
+		return nil, fmt.Errorf("attestation verification failed: unexpected predicate type %q", result.Statement.GetPredicateType())
 	}
 
-	// Verify commit matches the expected commit
-	if err := verifyAttestationCommit(result, v.config.Commit); err != nil {
-		return nil, fmt.Errorf("commit validation failed: %w", err)
+	if err := v.checkAttestationResult(result); err != nil {
+		return nil, err
 	}
 
-	return []*verify.VerificationResult{result}, nil
+	return result, nil
 }
 
 // verifyRekorTimestampDate validates that the Rekor timestamp date matches the expected tag date.
@@ -0,0 +1,602 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/policy"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestConfig returns the configuration of an offline verifier for the test bundle.
+func newTestConfig(t *testing.T) Config {
+	t.Helper()
+
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	metadata, err := bundlepkg.ParseMetadata(bundleData)
+	if err != nil {
+		t.Fatalf("failed to parse bundle metadata: %v", err)
+	}
+	trustedRoot, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+	if err != nil {
+		t.Fatalf("failed to read trusted root: %v", err)
+	}
+
+	return Config{
+		Date:        metadata.Date,
+		Commit:      metadata.Commit,
+		TrustedRoot: trustedRoot,
+	}
+}
+
+// newTestVerifier returns an offline verifier for the test bundle and its digest.
+func newTestVerifier(t *testing.T) (*Verifier, string) {
+	t.Helper()
+
+	v, err := New(newTestConfig(t))
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	return v, digest.ComputeSHA256(bundleData)
+}
+
+// newTestVerifyConfig returns the release assets of the test bundle.
+func newTestVerifyConfig(t *testing.T) VerifyConfig {
+	t.Helper()
+
+	verifyCfg := VerifyConfig{}
+	for _, asset := range []struct {
+		name string
+		data *[]byte
+	}{
+		{testutil.RootBundleFile, &verifyCfg.BundleData},
+		{testutil.ChecksumFile, &verifyCfg.ChecksumsData},
+		{testutil.ChecksumSigstoreFile, &verifyCfg.ChecksumsSigData},
+		{testutil.ProvenanceFile, &verifyCfg.ProvenanceData},
+	} {
+		data, err := testutil.ReadTestFile(asset.name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", asset.name, err)
+		}
+		*asset.data = data
+	}
+	return verifyCfg
+}
+
+func TestVerifyGitHubAttestationsMultiple(t *testing.T) {
+	v, bundleDigest := newTestVerifier(t)
+
+	provenance, err := testutil.ReadTestFile(testutil.ProvenanceFile)
+	if err != nil {
+		t.Fatalf("failed to read provenance: %v", err)
+	}
+	// A valid Sigstore bundle which is not an attestation of the bundle
+	unrelated, err := testutil.ReadTestFile(testutil.ChecksumSigstoreFile)
+	if err != nil {
+		t.Fatalf("failed to read checksum signature: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		attestations [][]byte
+	}{
+		{"single", [][]byte{provenance}},
+		{"matching first", [][]byte{provenance, unrelated, unrelated}},
+		{"matching last", [][]byte{unrelated, unrelated, provenance}},
+		{"matching in the middle", [][]byte{unrelated, provenance, unrelated}},
+		{"malformed candidate", [][]byte{[]byte("{"), provenance}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, matched, err := v.verifyGitHubAttestations(context.Background(), tt.attestations, bundleDigest, v.policyConfigs())
+			if err != nil {
+				t.Fatalf("verifyGitHubAttestations() error = %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			if err := verifyAttestationCommit(results[0], v.config.Commit); err != nil {
+				t.Errorf("unexpected attestation returned: %v", err)
+			}
+			if !bytes.Equal(matched, provenance) {
+				t.Error("expected the matching attestation to be returned")
+			}
+		})
+	}
+
+	t.Run("none matching", func(t *testing.T) {
+		_, _, err := v.verifyGitHubAttestations(context.Background(), [][]byte{unrelated, unrelated}, bundleDigest, v.policyConfigs())
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		msg := err.Error()
+		first, second := strings.Index(msg, "attestation 0:"), strings.Index(msg, "attestation 1:")
+		if first == -1 || second == -1 || first > second {
+			t.Errorf("expected failures reported in order, got: %v", err)
+		}
+	})
+
+	t.Run("no attestation", func(t *testing.T) {
+		if _, _, err := v.verifyGitHubAttestations(context.Background(), nil, bundleDigest, v.policyConfigs()); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestVerifySubjectDigest(t *testing.T) {
+	v, bundleDigest := newTestVerifier(t)
+
+	provenance, err := testutil.ReadTestFile(testutil.ProvenanceFile)
+	if err != nil {
+		t.Fatalf("failed to read provenance: %v", err)
+	}
+
+	// The same attestation, issued for another artifact
+	var attestation map[string]any
+	if err := json.Unmarshal(provenance, &attestation); err != nil {
+		t.Fatalf("failed to parse provenance: %v", err)
+	}
+	envelope := attestation["dsseEnvelope"].(map[string]any)
+	payload, err := base64.StdEncoding.DecodeString(envelope["payload"].(string))
+	if err != nil {
+		t.Fatalf("failed to decode attestation payload: %v", err)
+	}
+	bundleHex := []byte(strings.TrimPrefix(bundleDigest, "sha256:"))
+	if !bytes.Contains(payload, bundleHex) {
+		t.Fatal("expected the attestation subject to be the test bundle")
+	}
+	payload = bytes.ReplaceAll(payload, bundleHex, bytes.Repeat([]byte("0"), len(bundleHex)))
+	envelope["payload"] = base64.StdEncoding.EncodeToString(payload)
+	mismatched, err := json.Marshal(attestation)
+	if err != nil {
+		t.Fatalf("failed to encode provenance: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		provenance []byte
+		digest     string
+	}{
+		{"mismatched subject", mismatched, bundleDigest},
+		{"another bundle", provenance, digest.ComputeSHA256([]byte("another bundle"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := v.verifyGitHubAttestations(context.Background(), [][]byte{tt.provenance}, tt.digest, v.policyConfigs())
+			if !errors.Is(err, ErrSubjectDigestMismatch) {
+				t.Fatalf("verifyGitHubAttestations() error = %v, want %v", err, ErrSubjectDigestMismatch)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksumEntry(t *testing.T) {
+	v, bundleDigest := newTestVerifier(t)
+	verifyCfg := newTestVerifyConfig(t)
+	bundleHex := strings.TrimPrefix(bundleDigest, "sha256:")
+	otherHex := strings.Repeat("0", len(bundleHex))
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   error
+	}{
+		{"valid", string(verifyCfg.ChecksumsData), nil},
+		{"missing bundle entry", otherHex + "  malicious.pem\n", ErrChecksumEntryMissing},
+		{"wrong digest", otherHex + "  tpm-ca-certificates.pem\n", ErrChecksumMismatch},
+		{"duplicated entry", string(verifyCfg.ChecksumsData) + otherHex + "  tpm-ca-certificates.pem\n", ErrMalformedChecksums},
+		{"malformed line", string(verifyCfg.ChecksumsData) + "not-a-checksum\n", ErrMalformedChecksums},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyChecksumEntry([]byte(tt.checksums), verifyCfg.BundleData)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("verifyChecksumEntry() unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verifyChecksumEntry() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Verify fails on the checksums file before checking its signature
+	verifyCfg.ChecksumsData = []byte(otherHex + "  malicious.pem\n")
+	if _, err := v.Verify(context.Background(), verifyCfg); !errors.Is(err, ErrChecksumEntryMissing) {
+		t.Fatalf("Verify() error = %v, want %v", err, ErrChecksumEntryMissing)
+	}
+}
+
+func TestVerifyAllowedOIDCIssuers(t *testing.T) {
+	// The test bundle was signed by GitHub Actions on github.com
+	const alternateIssuer = "https://token.actions.example.com"
+	verifyCfg := newTestVerifyConfig(t)
+
+	tests := []struct {
+		name    string
+		issuers []string
+		wantErr bool
+	}{
+		{"default issuer", nil, false},
+		{"alternate issuer only", []string{alternateIssuer}, true},
+		{"alternate and default issuers", []string{alternateIssuer, policy.DefaultOIDCIssuer}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			cfg.AllowedOIDCIssuers = tt.issuers
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			_, err = v.Verify(context.Background(), verifyCfg)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected Verify() to reject the github.com issuer")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyWorkflowFilenames(t *testing.T) {
+	// The test bundle was released by github.ReleaseBundleWorkflowPath: the verifier expects
+	// another workflow, and only accepts the signing one through WorkflowFilenames
+	const otherWorkflow = ".github/workflows/release-v2.yaml"
+	verifyCfg := newTestVerifyConfig(t)
+
+	tests := []struct {
+		name         string
+		workflows    []string
+		wantErr      bool
+		wantWorkflow string
+	}{
+		{
+			name:         "signing workflow accepted",
+			workflows:    []string{github.ReleaseBundleWorkflowPath},
+			wantWorkflow: github.ReleaseBundleWorkflowPath,
+		},
+		{
+			name:         "duplicates ignored",
+			workflows:    []string{otherWorkflow, github.ReleaseBundleWorkflowPath, github.ReleaseBundleWorkflowPath},
+			wantWorkflow: github.ReleaseBundleWorkflowPath,
+		},
+		{
+			name:    "signing workflow not accepted",
+			wantErr: true,
+		},
+		{
+			name:      "unrelated workflows only",
+			workflows: []string{".github/workflows/ci.yaml"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestConfig(t)
+			cfg.WorkflowFilename = otherWorkflow
+			cfg.WorkflowFilenames = tt.workflows
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("failed to create verifier: %v", err)
+			}
+
+			result, err := v.Verify(context.Background(), verifyCfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected Verify() to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if result.Policy.BuildWorkflow != tt.wantWorkflow {
+				t.Errorf("Policy.BuildWorkflow = %s, want %s", result.Policy.BuildWorkflow, tt.wantWorkflow)
+			}
+		})
+	}
+
+	t.Run("attestation from another workflow than the checksums signature", func(t *testing.T) {
+		cfg := newTestConfig(t)
+		cfg.WorkflowFilenames = []string{otherWorkflow}
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+
+		// Both workflows are accepted, but Cosign matched otherWorkflow
+		policies := []policy.Config{v.policyConfig(otherWorkflow)}
+		bundleDigest := digest.ComputeSHA256(verifyCfg.BundleData)
+		if _, _, err := v.verifyGitHubAttestations(context.Background(), [][]byte{verifyCfg.ProvenanceData}, bundleDigest, policies); err == nil {
+			t.Fatal("expected the attestation of another workflow to be rejected")
+		}
+	})
+
+	t.Run("empty workflow", func(t *testing.T) {
+		cfg := newTestConfig(t)
+		cfg.WorkflowFilenames = []string{""}
+		if _, err := New(cfg); err == nil {
+			t.Fatal("expected an error for an empty workflow filename")
+		}
+	})
+}
+
+func TestGetSigstoreVerifierConfigReused(t *testing.T) {
+	v, _ := newTestVerifier(t)
+
+	first, err := v.GetSigstoreVerifierConfig()
+	if err != nil {
+		t.Fatalf("GetSigstoreVerifierConfig() error = %v", err)
+	}
+	second, err := v.GetSigstoreVerifierConfig()
+	if err != nil {
+		t.Fatalf("GetSigstoreVerifierConfig() error = %v", err)
+	}
+	if first.Root != second.Root {
+		t.Error("expected the trusted root to be loaded once per verifier")
+	}
+}
+
+func TestVerifyTelemetry(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previousTracer, previousMeter := phaseTracer, phaseMeter
+	t.Cleanup(func() { phaseTracer, phaseMeter = previousTracer, previousMeter })
+	phaseTracer = func() trace.Tracer { return tp.Tracer("test") }
+	phaseMeter = func() metric.Meter { return mp.Meter("test") }
+
+	v, _ := newTestVerifier(t)
+	verifyCfg := newTestVerifyConfig(t)
+
+	// spanAttributes returns the attributes of the exported span with the given name.
+	spanAttributes := func(t *testing.T, name string) map[attribute.Key]attribute.Value {
+		t.Helper()
+		for _, span := range exporter.GetSpans() {
+			if span.Name == name {
+				attrs := make(map[attribute.Key]attribute.Value)
+				for _, kv := range span.Attributes {
+					attrs[kv.Key] = kv.Value
+				}
+				return attrs
+			}
+		}
+		t.Fatalf("span %s not found", name)
+		return nil
+	}
+	assertPhase := func(t *testing.T, phase, outcome string) {
+		t.Helper()
+		attrs := spanAttributes(t, "tpmtb.verify."+phase)
+		if got := attrs[attrPhase].AsString(); got != phase {
+			t.Errorf("%s = %q, want %q", attrPhase, got, phase)
+		}
+		if got := attrs[attrOutcome].AsString(); got != outcome {
+			t.Errorf("%s = %q, want %q", attrOutcome, got, outcome)
+		}
+		if duration, ok := attrs[attrDurationMs]; !ok || duration.AsInt64() < 0 {
+			t.Errorf("%s = %v, want a non-negative duration", attrDurationMs, duration.Emit())
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		exporter.Reset()
+		if _, err := v.Verify(context.Background(), verifyCfg); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		assertPhase(t, phaseCosign, outcomeSuccess)
+		assertPhase(t, phaseAttestation, outcomeSuccess)
+	})
+
+	t.Run("rekor inclusion required", func(t *testing.T) {
+		exporter.Reset()
+		cfg := newTestConfig(t)
+		cfg.RequireRekorInclusion = true
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+		if _, err := v.Verify(context.Background(), verifyCfg); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		assertPhase(t, phaseRekor, outcomeSuccess)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		exporter.Reset()
+		cfg := verifyCfg
+		cfg.ChecksumsData = []byte("tampered checksums")
+		if _, err := v.Verify(context.Background(), cfg); err == nil {
+			t.Fatal("expected Verify() to fail")
+		}
+		assertPhase(t, phaseCosign, outcomeFailure)
+		if n := len(exporter.GetSpans()); n != 1 {
+			t.Errorf("expected the attestation phase to be skipped, got %d spans", n)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatalf("failed to collect metrics: %v", err)
+		}
+
+		counts := make(map[string]int64)
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != metricVerifications {
+					continue
+				}
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					phase, _ := dp.Attributes.Value(attrPhase)
+					outcome, _ := dp.Attributes.Value(attrOutcome)
+					counts[phase.AsString()+"/"+outcome.AsString()] += dp.Value
+				}
+			}
+		}
+
+		want := map[string]int64{
+			phaseCosign + "/" + outcomeSuccess:      2,
+			phaseRekor + "/" + outcomeSuccess:       1,
+			phaseAttestation + "/" + outcomeSuccess: 2,
+			phaseCosign + "/" + outcomeFailure:      1,
+		}
+		if !maps.Equal(counts, want) {
+			t.Errorf("verification counts = %v, want %v", counts, want)
+		}
+	})
+}
+
+func TestCurrentInstruments(t *testing.T) {
+	previousMeter := phaseMeter
+	t.Cleanup(func() { phaseMeter = previousMeter })
+
+	mp := sdkmetric.NewMeterProvider()
+	phaseMeter = func() metric.Meter { return mp.Meter("test") }
+	first := currentInstruments()
+	if currentInstruments() != first {
+		t.Error("expected the instruments to be created once per meter provider")
+	}
+
+	other := sdkmetric.NewMeterProvider()
+	phaseMeter = func() metric.Meter { return other.Meter("test") }
+	if currentInstruments() == first {
+		t.Error("expected new instruments for another meter provider")
+	}
+}
+
+func TestVerifyCollectAllFailures(t *testing.T) {
+	v, bundleDigest := newTestVerifier(t)
+	verifyCfg := newTestVerifyConfig(t)
+
+	// Results produced from the genuine release, checked against another commit and date
+	cosignResult, _, err := v.verifyCosign(context.Background(), verifyCfg.BundleData, verifyCfg.ChecksumsData, verifyCfg.ChecksumsSigData)
+	if err != nil {
+		t.Fatalf("verifyCosign() error = %v", err)
+	}
+	attestationResults, _, err := v.verifyGitHubAttestations(context.Background(), [][]byte{verifyCfg.ProvenanceData}, bundleDigest, v.policyConfigs())
+	if err != nil {
+		t.Fatalf("verifyGitHubAttestations() error = %v", err)
+	}
+
+	newVerifier := func(t *testing.T, collectAll bool) *Verifier {
+		t.Helper()
+		cfg := newTestConfig(t)
+		cfg.Commit = strings.Repeat("0", 40)
+		cfg.Date = "2020-01-01"
+		cfg.CollectAllFailures = collectAll
+		v, err := New(cfg)
+		if err != nil {
+			t.Fatalf("failed to create verifier: %v", err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name       string
+		collectAll bool
+		check      func(v *Verifier) error
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "cosign collects all failures",
+			collectAll: true,
+			check:      func(v *Verifier) error { return v.checkCosignResult(cosignResult, true) },
+			want:       []string{"commit mismatch", "date mismatch"},
+		},
+		{
+			name:    "cosign fails fast",
+			check:   func(v *Verifier) error { return v.checkCosignResult(cosignResult, true) },
+			want:    []string{"commit mismatch"},
+			notWant: []string{"date mismatch"},
+		},
+		{
+			name:       "attestation collects all failures",
+			collectAll: true,
+			check:      func(v *Verifier) error { return v.checkAttestationResult(attestationResults[0]) },
+			want:       []string{"timestamp validation failed", "commit validation failed"},
+		},
+		{
+			name:    "attestation fails fast",
+			check:   func(v *Verifier) error { return v.checkAttestationResult(attestationResults[0]) },
+			want:    []string{"timestamp validation failed"},
+			notWant: []string{"commit validation failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(newVerifier(t, tt.collectAll))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q to be reported, got: %v", want, err)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(err.Error(), notWant) {
+					t.Errorf("expected %q not to be reported, got: %v", notWant, err)
+				}
+			}
+		})
+	}
+
+	t.Run("all phases run", func(t *testing.T) {
+		for _, collectAll := range []bool{true, false} {
+			cfg := newTestConfig(t)
+			cfg.Commit = strings.Repeat("0", 40)
+			cfg.CollectAllFailures = collectAll
+			v, err := New(cfg)
+			if err != nil {
+				t.Fatalf("failed to create verifier: %v", err)
+			}
+
+			_, err = v.Verify(context.Background(), verifyCfg)
+			if err == nil {
+				t.Fatal("expected Verify() to fail")
+			}
+			if !strings.Contains(err.Error(), "cosign verification failed") {
+				t.Errorf("expected the Cosign failure to be reported, got: %v", err)
+			}
+			if got := strings.Contains(err.Error(), "github attestation verification failed"); got != collectAll {
+				t.Errorf("CollectAllFailures = %t: attestation failure reported = %t, got: %v", collectAll, got, err)
+			}
+		}
+	})
+}
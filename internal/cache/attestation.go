@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+)
+
+const (
+	// AttestationsDirName is the folder (relative to the cache directory) holding
+	// provenance attestations keyed by artifact digest.
+	AttestationsDirName = "attestations"
+
+	// DefaultAttestationTTL is the default lifetime of a cached attestation.
+	DefaultAttestationTTL = 7 * 24 * time.Hour
+
+	// attestationEntryVersion is bumped whenever the on-disk entry format changes.
+	attestationEntryVersion = 1
+)
+
+// ErrAttestationCacheMiss is returned when no usable attestation is cached for a digest.
+var ErrAttestationCacheMiss = errors.New("attestation not found in cache")
+
+// attestationEntry is the on-disk representation of a cached attestation.
+type attestationEntry struct {
+	Version    int             `json:"version"`
+	Digest     string          `json:"digest"`
+	FetchedAt  time.Time       `json:"fetchedAt"`
+	Provenance json.RawMessage `json:"provenance"`
+}
+
+// LoadAttestation returns the provenance cached for the given artifact digest.
+//
+// Entries written by another format version, recorded for another digest or
+// older than ttl are treated as a miss. A ttl of zero disables the age check.
+// [ErrAttestationCacheMiss] is returned on miss.
+func LoadAttestation(cacheDir, digest string, ttl time.Duration) ([]byte, error) {
+	filePath := attestationPath(cacheDir, digest)
+	if !utils.FileExists(filePath) {
+		return nil, ErrAttestationCacheMiss
+	}
+
+	data, err := utils.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached attestation: %w", err)
+	}
+
+	var entry attestationEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, ErrAttestationCacheMiss
+	}
+	if entry.Version != attestationEntryVersion || entry.Digest != digest || len(entry.Provenance) == 0 {
+		return nil, ErrAttestationCacheMiss
+	}
+	if ttl > 0 && time.Since(entry.FetchedAt) > ttl {
+		return nil, ErrAttestationCacheMiss
+	}
+
+	return entry.Provenance, nil
+}
+
+// SaveAttestation stores the provenance for the given artifact digest in the cache directory.
+func SaveAttestation(cacheDir, digest string, provenance []byte) error {
+	if len(provenance) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(attestationEntry{
+		Version:    attestationEntryVersion,
+		Digest:     digest,
+		FetchedAt:  time.Now(),
+		Provenance: provenance,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal attestation: %w", err)
+	}
+
+	dir := filepath.Join(cacheDir, AttestationsDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create attestations cache directory: %w", err)
+	}
+	if err := os.WriteFile(attestationPath(cacheDir, digest), data, 0644); err != nil {
+		return fmt.Errorf("failed to write attestation to cache: %w", err)
+	}
+	return nil
+}
+
+// attestationPath returns the cache file path for a digest such as "sha256:abc...".
+func attestationPath(cacheDir, digest string) string {
+	filename := strings.ReplaceAll(digest, ":", "-") + ".json"
+	return filepath.Join(cacheDir, AttestationsDirName, filename)
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAttestationCache(t *testing.T) {
+	const digest = "sha256:0123456789abcdef"
+	provenance := []byte(`{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json"}`)
+
+	t.Run("round trip", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if err := SaveAttestation(tmpDir, digest, provenance); err != nil {
+			t.Fatalf("SaveAttestation() error = %v", err)
+		}
+
+		got, err := LoadAttestation(tmpDir, digest, DefaultAttestationTTL)
+		if err != nil {
+			t.Fatalf("LoadAttestation() error = %v", err)
+		}
+		if string(got) != string(provenance) {
+			t.Errorf("LoadAttestation() = %s, want %s", got, provenance)
+		}
+	})
+
+	t.Run("miss for unknown digest", func(t *testing.T) {
+		_, err := LoadAttestation(t.TempDir(), digest, DefaultAttestationTTL)
+		if !errors.Is(err, ErrAttestationCacheMiss) {
+			t.Errorf("LoadAttestation() error = %v, want %v", err, ErrAttestationCacheMiss)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		entry attestationEntry
+		ttl   time.Duration
+	}{
+		{
+			name:  "miss for expired entry",
+			entry: attestationEntry{Version: attestationEntryVersion, Digest: digest, FetchedAt: time.Now().Add(-2 * time.Hour)},
+			ttl:   time.Hour,
+		},
+		{
+			name:  "miss for other format version",
+			entry: attestationEntry{Version: attestationEntryVersion + 1, Digest: digest, FetchedAt: time.Now()},
+			ttl:   time.Hour,
+		},
+		{
+			name:  "miss for mismatched digest",
+			entry: attestationEntry{Version: attestationEntryVersion, Digest: "sha256:other", FetchedAt: time.Now()},
+			ttl:   time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := SaveAttestation(tmpDir, digest, provenance); err != nil {
+				t.Fatalf("SaveAttestation() error = %v", err)
+			}
+
+			tt.entry.Provenance = provenance
+			data, _ := json.Marshal(tt.entry)
+			if err := os.WriteFile(attestationPath(tmpDir, digest), data, 0644); err != nil {
+				t.Fatalf("Failed to overwrite entry: %v", err)
+			}
+
+			_, err := LoadAttestation(tmpDir, digest, tt.ttl)
+			if !errors.Is(err, ErrAttestationCacheMiss) {
+				t.Errorf("LoadAttestation() error = %v, want %v", err, ErrAttestationCacheMiss)
+			}
+		})
+	}
+}
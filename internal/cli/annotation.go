@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Output formats of the validation findings.
+const (
+	// FormatText prints the findings as "Line N: message".
+	FormatText = "text"
+
+	// FormatGitHub prints the findings as GitHub Actions workflow commands,
+	// so they appear as annotations in the GitHub UI.
+	FormatGitHub = "github"
+)
+
+// Annotation levels of the GitHub Actions workflow commands.
+const (
+	AnnotationError   = "error"
+	AnnotationWarning = "warning"
+)
+
+// ResolveFormat returns the output format of the validation findings.
+//
+// An empty format defaults to [FormatGitHub] when running in GitHub Actions
+// (GITHUB_ACTIONS=true), [FormatText] otherwise.
+func ResolveFormat(format string) (string, error) {
+	switch format {
+	case "":
+		if os.Getenv("GITHUB_ACTIONS") == "true" {
+			return FormatGitHub, nil
+		}
+		return FormatText, nil
+	case FormatText, FormatGitHub:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q, must be '%s' or '%s'", format, FormatText, FormatGitHub)
+	}
+}
+
+// Annotation returns the GitHub Actions workflow command annotating a line of a file.
+//
+// Example:
+//
+//	cli.Annotation(cli.AnnotationError, "bundle.pem", 1, "invalid date") // "::error file=bundle.pem,line=1::invalid date"
+func Annotation(level, file string, line int, message string) string {
+	return fmt.Sprintf("::%s file=%s,line=%d::%s", level, escapeProperty(file), line, escapeData(message))
+}
+
+// DisplayAnnotation writes a GitHub Actions workflow command to stdout, where the runner reads them.
+func DisplayAnnotation(level, file string, line int, message string) {
+	fmt.Println(Annotation(level, file, line, message))
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import "testing"
+
+func TestAnnotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		file     string
+		line     int
+		message  string
+		expected string
+	}{
+		{
+			name:     "error",
+			level:    AnnotationError,
+			file:     "tpm-ca-certificates.pem",
+			line:     3,
+			message:  "invalid date format",
+			expected: "::error file=tpm-ca-certificates.pem,line=3::invalid date format",
+		},
+		{
+			name:     "warning",
+			level:    AnnotationWarning,
+			file:     ".tpm-roots.yaml",
+			line:     12,
+			message:  "vendor has no certificates",
+			expected: "::warning file=.tpm-roots.yaml,line=12::vendor has no certificates",
+		},
+		{
+			name:     "escaped message",
+			level:    AnnotationError,
+			file:     "bundle.pem",
+			line:     1,
+			message:  "100% wrong\nsecond line",
+			expected: "::error file=bundle.pem,line=1::100%25 wrong%0Asecond line",
+		},
+		{
+			name:     "escaped file",
+			level:    AnnotationError,
+			file:     "C:\\a,b.pem",
+			line:     1,
+			message:  "invalid",
+			expected: "::error file=C%3A\\a%2Cb.pem,line=1::invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Annotation(tt.level, tt.file, tt.line, tt.message); got != tt.expected {
+				t.Errorf("Annotation() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveFormat(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	if got, err := ResolveFormat(""); err != nil || got != FormatText {
+		t.Errorf("ResolveFormat(\"\") = %q, %v, want %q", got, err, FormatText)
+	}
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	if got, err := ResolveFormat(""); err != nil || got != FormatGitHub {
+		t.Errorf("ResolveFormat(\"\") in GitHub Actions = %q, %v, want %q", got, err, FormatGitHub)
+	}
+	if got, err := ResolveFormat(FormatText); err != nil || got != FormatText {
+		t.Errorf("ResolveFormat(%q) = %q, %v, want %q", FormatText, got, err, FormatText)
+	}
+
+	if _, err := ResolveFormat("json"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 )
 
@@ -19,24 +21,43 @@ func colorize(color color, text string) string {
 }
 
 func Display(msg string, args ...any) {
+	if !enabled(slog.LevelInfo) {
+		return
+	}
 	fmt.Println(fmt.Sprintf(msg, args...))
 }
 
 func DisplaySuccess(msg string, args ...any) {
-	fmt.Println(colorize(colorGreen, fmt.Sprintf(msg, args...)))
-	fmt.Println()
+	DisplaySuccessTo(os.Stdout, msg, args...)
+}
+
+// DisplaySuccessTo writes a success message to w, e.g. the output writer of a command.
+func DisplaySuccessTo(w io.Writer, msg string, args ...any) {
+	if !enabled(slog.LevelInfo) {
+		return
+	}
+	fmt.Fprintln(w, colorize(colorGreen, fmt.Sprintf(msg, args...)))
+	fmt.Fprintln(w)
 }
 
 func DisplayError(msg string, args ...any) {
-	fmt.Fprint(os.Stderr, colorize(colorRed, fmt.Sprintf(msg, args...)))
-	fmt.Println()
+	DisplayErrorTo(os.Stderr, msg, args...)
+}
+
+// DisplayErrorTo writes an error message to w, e.g. the output writer of a command.
+func DisplayErrorTo(w io.Writer, msg string, args ...any) {
+	fmt.Fprintln(w, colorize(colorRed, fmt.Sprintf(msg, args...)))
 }
 
 func DisplayWarning(msg string, args ...any) {
+	if !enabled(slog.LevelWarn) {
+		return
+	}
 	fmt.Fprint(os.Stderr, colorize(colorYellow, fmt.Sprintf(msg, args...)))
 	fmt.Println()
 }
 
+// DisplayStderr writes the details of an error or a warning, so it is displayed at every log level.
 func DisplayStderr(msg string, args ...any) {
 	fmt.Fprintf(os.Stderr, msg, args...)
 }
@@ -0,0 +1,49 @@
+// Package exitcode defines the exit codes returned by tpmtb.
+//
+// The codes are part of the CLI contract: scripts wrapping tpmtb can rely on them
+// to react differently to a tampering signal and to a transient network failure.
+package exitcode
+
+import (
+	"errors"
+	"net"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+const (
+	// Success is returned when the command succeeds.
+	Success = 0
+	// Error is returned for any failure not covered by a more specific code.
+	Error = 1
+	// VerificationFailed is returned when a bundle fails its integrity or provenance verification.
+	VerificationFailed = 2
+	// BundleNotFound is returned when no bundle is published for the requested release.
+	BundleNotFound = 3
+	// ConfigInvalid is returned when a configuration file fails its validation or sanity checks.
+	ConfigInvalid = 4
+	// NetworkError is returned when a remote server cannot be reached or fails to answer.
+	NetworkError = 5
+)
+
+// FromError returns the exit code matching err.
+//
+// When err matches several classes (e.g., joined errors), the most severe one wins:
+// a verification failure is reported over a network error. An unavailable trusted root
+// is reported as a network error, since the bundle could not be verified.
+func FromError(err error) int {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return Success
+	case errors.Is(err, apiv1beta.ErrBundleVerificationFailed), errors.Is(err, apiv1beta.ErrDisallowedAlgorithm):
+		return VerificationFailed
+	case errors.Is(err, apiv1beta.ErrBundleNotFound):
+		return BundleNotFound
+	case errors.As(err, &netErr), errors.Is(err, utils.ErrHTTPGetError), errors.Is(err, apiv1beta.ErrTrustedRootUnavailable):
+		return NetworkError
+	default:
+		return Error
+	}
+}
@@ -0,0 +1,40 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+func TestFromError(t *testing.T) {
+	verificationErr := fmt.Errorf("%w: checksum mismatch", apiv1beta.ErrBundleVerificationFailed)
+	networkErr := fmt.Errorf("failed to fetch attestations: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, Success},
+		{"generic error", errors.New("boom"), Error},
+		{"verification failure", verificationErr, VerificationFailed},
+		{"disallowed algorithm", fmt.Errorf("%w: SHA1-RSA", apiv1beta.ErrDisallowedAlgorithm), VerificationFailed},
+		{"bundle not found", fmt.Errorf("%w: release 2020-01-01 not found", apiv1beta.ErrBundleNotFound), BundleNotFound},
+		{"network error", networkErr, NetworkError},
+		{"HTTP error", fmt.Errorf("%w: HTTP 503", utils.ErrHTTPGetError), NetworkError},
+		{"trusted root unavailable", fmt.Errorf("cosign verification failed: %w", apiv1beta.ErrTrustedRootUnavailable), NetworkError},
+		{"verification failure wins over network error", errors.Join(networkErr, verificationErr), VerificationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromError(tt.err); got != tt.want {
+				t.Errorf("FromError(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// LogLevels lists the accepted values of the --log-level flag.
+var LogLevels = []string{"error", "warn", "info", "debug"}
+
+var (
+	// logLevel is the minimum level displayed, shared by [Logger] and the Display helpers.
+	logLevel = new(slog.LevelVar)
+
+	// Allow mocking in tests
+	logger = newLogger(os.Stderr)
+)
+
+func newLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: logLevel}))
+}
+
+// Logger returns the logger honoring the --log-level flag.
+//
+// It is meant to be passed to the library (eg. [apiv1beta.GetConfig.Logger]).
+func Logger() *slog.Logger {
+	return logger
+}
+
+// SetLogLevel sets the minimum level displayed from its name (error, warn, info or debug).
+func SetLogLevel(name string) error {
+	var level slog.Level
+	switch strings.ToLower(name) {
+	case "error":
+		level = slog.LevelError
+	case "warn":
+		level = slog.LevelWarn
+	case "info":
+		level = slog.LevelInfo
+	case "debug":
+		level = slog.LevelDebug
+	default:
+		return fmt.Errorf("invalid log level %q, must be one of: %s", name, strings.Join(LogLevels, ", "))
+	}
+	logLevel.Set(level)
+	return nil
+}
+
+// enabled reports whether messages of the given level are displayed.
+func enabled(level slog.Level) bool {
+	return level >= logLevel.Level()
+}
+
+// DisplayDebug logs a debug message, only displayed with --log-level debug.
+func DisplayDebug(msg string, args ...any) {
+	logger.Debug(fmt.Sprintf(msg, args...))
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogLevel(t *testing.T) {
+	oldLogger, oldLevel := logger, logLevel.Level()
+	t.Cleanup(func() {
+		logger = oldLogger
+		logLevel.Set(oldLevel)
+	})
+
+	var buf bytes.Buffer
+	logger = newLogger(&buf)
+
+	for _, level := range []string{"error", "warn", "info"} {
+		if err := SetLogLevel(level); err != nil {
+			t.Fatalf("SetLogLevel(%q) error = %v", level, err)
+		}
+		DisplayDebug("cache path is %s", "/tmp")
+		Logger().Debug("library debug")
+		if buf.Len() != 0 {
+			t.Errorf("expected no debug output at %s level, got %q", level, buf.String())
+		}
+	}
+
+	if err := SetLogLevel("debug"); err != nil {
+		t.Fatalf("SetLogLevel(debug) error = %v", err)
+	}
+	DisplayDebug("cache path is %s", "/tmp")
+	Logger().Debug("library debug")
+	for _, want := range []string{"cache path is /tmp", "library debug"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("debug output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	oldLevel := logLevel.Level()
+	t.Cleanup(func() { logLevel.Set(oldLevel) })
+
+	if err := SetLogLevel("WARN"); err != nil {
+		t.Fatalf("SetLogLevel(WARN) error = %v", err)
+	}
+	if enabled(slog.LevelInfo) {
+		t.Error("info messages should not be displayed at warn level")
+	}
+	if !enabled(slog.LevelError) {
+		t.Error("error messages should be displayed at warn level")
+	}
+
+	if err := SetLogLevel("trace"); err == nil {
+		t.Error("SetLogLevel() expected error for invalid level")
+	}
+}
@@ -2,10 +2,12 @@ package download
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
@@ -14,10 +16,15 @@ import (
 // Client handles HTTPS certificate downloads.
 type Client struct {
 	HTTPClient utils.HTTPClient
+	// UserAgent overrides the User-Agent header sent with the downloads.
+	//
+	// Optional. Default: "tpmtb/<version>".
+	UserAgent string
 }
 
 var defaultClient = &http.Client{
-	Timeout: 5 * time.Second,
+	Timeout:       5 * time.Second,
+	CheckRedirect: CheckRedirect,
 }
 
 // NewClient creates a new download client with sensible defaults.
@@ -28,6 +35,118 @@ func NewClient(optionalClient ...utils.HTTPClient) *Client {
 	}
 }
 
+// HTTPClientOption configures the client returned by [NewPooledHTTPClient].
+type HTTPClientOption func(*http.Client)
+
+// WithTLSConfig customizes how the transport authenticates to the servers
+// (e.g., with a [tls.Config] built by [TLSOptions.TLSConfig]).
+//
+// The default TLS settings are kept when tlsConfig is nil.
+func WithTLSConfig(tlsConfig *tls.Config) HTTPClientOption {
+	return func(c *http.Client) {
+		if tlsConfig != nil {
+			c.Transport.(*http.Transport).TLSClientConfig = tlsConfig.Clone()
+		}
+	}
+}
+
+// WithoutRedirects makes the client fail on any redirect instead of following it
+// (see [RejectRedirects]).
+func WithoutRedirects() HTTPClientOption {
+	return func(c *http.Client) {
+		c.CheckRedirect = RejectRedirects
+	}
+}
+
+// NewPooledHTTPClient returns an [http.Client] tuned for many concurrent downloads.
+//
+// Its transport keeps up to maxConnsPerHost idle connections per host so that
+// parallel downloads from the same vendor CDN reuse TLS connections instead of
+// opening a new one for every certificate.
+//
+// Redirects are followed according to [CheckRedirect] unless [WithoutRedirects] is set.
+func NewPooledHTTPClient(maxConnsPerHost int, opts ...HTTPClientOption) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 100
+	transport.MaxIdleConnsPerHost = max(maxConnsPerHost, 1)
+	transport.IdleConnTimeout = 90 * time.Second
+
+	client := &http.Client{
+		Transport:     transport,
+		Timeout:       defaultClient.Timeout,
+		CheckRedirect: CheckRedirect,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// TLSOptions configures the TLS settings used to download certificates.
+type TLSOptions struct {
+	// ClientCertFile is the path to a PEM-encoded client certificate presented to
+	// servers requiring mutual TLS (e.g., enterprise vendor portals).
+	//
+	// Optional. Must be set together with ClientKeyFile.
+	ClientCertFile string
+
+	// ClientKeyFile is the path to the PEM-encoded private key of ClientCertFile.
+	//
+	// Optional. Must be set together with ClientCertFile.
+	ClientKeyFile string
+
+	// CAFile is the path to a PEM file holding the CA certificate(s) expected to have
+	// issued the server certificate. When set, it replaces the system trust store,
+	// pinning the servers trusted for downloads.
+	//
+	// Optional. If empty, the system roots are used.
+	CAFile string
+}
+
+// TLSConfig loads the files referenced by the options into a [tls.Config].
+//
+// It returns nil when no option is set, so that the default TLS settings are kept.
+func (o TLSOptions) TLSConfig() (*tls.Config, error) {
+	if (o.ClientCertFile == "") != (o.ClientKeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be provided together")
+	}
+	if o.ClientCertFile == "" && o.CAFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if o.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if o.CAFile != "" {
+		rootCAs, err := LoadCertPool(o.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	return tlsConfig, nil
+}
+
+// LoadCertPool returns a pool holding the certificates of the PEM file at path.
+//
+// The file must contain at least one certificate.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificate found in CA file %s", path)
+	}
+	return pool, nil
+}
+
 // DownloadCertificate downloads a certificate from the given HTTPS URL.
 //
 // It returns the raw certificate bytes (typically DER-encoded).
@@ -41,7 +160,7 @@ func NewClient(optionalClient ...utils.HTTPClient) *Client {
 //	    log.Fatal(err)
 //	}
 func (c *Client) DownloadCertificate(ctx context.Context, url string) (*x509.Certificate, error) {
-	data, err := utils.HttpGET(ctx, c.HTTPClient, url)
+	data, err := utils.HttpGET(ctx, utils.WithUserAgent(c.HTTPClient, c.UserAgent), url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download certificate from %s: %w", url, err)
 	}
@@ -54,6 +173,58 @@ func (c *Client) DownloadCertificate(ctx context.Context, url string) (*x509.Cer
 	return cert, nil
 }
 
+// DownloadCertificates downloads all the certificates served at the given HTTPS URL.
+//
+// Unlike [Client.DownloadCertificate], every certificate of a PEM file is returned
+// (e.g., a root followed by its intermediates), in the order they appear in the file.
+// The URL fragment, if any, is not sent to the server.
+//
+// Example:
+//
+//	client := download.NewClient()
+//	chain, err := client.DownloadCertificates(ctx, "https://example.com/chain.pem")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) DownloadCertificates(ctx context.Context, url string) ([]*x509.Certificate, error) {
+	data, err := utils.HttpGET(ctx, utils.WithUserAgent(c.HTTPClient, c.UserAgent), url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download certificate from %s: %w", url, err)
+	}
+
+	certs, err := ParseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate from %s: %w", url, err)
+	}
+
+	return certs, nil
+}
+
+// ParseCertificates parses a single DER certificate or all the CERTIFICATE blocks of a PEM file.
+//
+// Non-certificate PEM blocks are ignored. At least one certificate is returned on success.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	if cert, err := x509.ParseCertificate(data); err == nil {
+		return []*x509.Certificate{cert}, nil
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate #%d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("failed to decode PEM block and DER parsing also failed")
+	}
+	return certs, nil
+}
+
 // ParseCertificate attempts to parse a certificate from DER or PEM format.
 func ParseCertificate(data []byte) (*x509.Certificate, error) {
 	cert, err := x509.ParseCertificate(data)
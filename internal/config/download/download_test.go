@@ -1,11 +1,24 @@
 package download_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/download"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
@@ -75,6 +88,87 @@ func TestDownloadCertificate(t *testing.T) {
 	})
 }
 
+func TestDownloadCertificates(t *testing.T) {
+	rootDER, _ := testutil.GenerateTestCertWithCN(t, "Root CA")
+	intermediateDER, _ := testutil.GenerateTestCertWithCN(t, "Intermediate CA")
+	var chain []byte
+	for _, der := range [][]byte{rootDER, intermediateDER} {
+		chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(chain)
+	}))
+	defer server.Close()
+
+	client := download.NewClient(server.Client())
+	certs, err := client.DownloadCertificates(t.Context(), server.URL)
+	if err != nil {
+		t.Fatalf("DownloadCertificates() error = %v", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("DownloadCertificates() returned %d certificates, want 2", len(certs))
+	}
+	if certs[0].Subject.CommonName != "Root CA" || certs[1].Subject.CommonName != "Intermediate CA" {
+		t.Errorf("DownloadCertificates() unexpected order: %s, %s", certs[0].Subject.CommonName, certs[1].Subject.CommonName)
+	}
+
+	// The singular variant keeps returning the first certificate
+	cert, err := client.DownloadCertificate(t.Context(), server.URL)
+	if err != nil {
+		t.Fatalf("DownloadCertificate() error = %v", err)
+	}
+	if cert.Subject.CommonName != "Root CA" {
+		t.Errorf("DownloadCertificate() got %s, want Root CA", cert.Subject.CommonName)
+	}
+}
+
+func TestNewPooledHTTPClient(t *testing.T) {
+	t.Run("tunes transport", func(t *testing.T) {
+		client := download.NewPooledHTTPClient(8)
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("NewPooledHTTPClient() transport type = %T, want *http.Transport", client.Transport)
+		}
+		if transport.MaxIdleConnsPerHost != 8 {
+			t.Errorf("MaxIdleConnsPerHost = %d, want 8", transport.MaxIdleConnsPerHost)
+		}
+		if client.Timeout == 0 {
+			t.Error("NewPooledHTTPClient() should set a timeout")
+		}
+	})
+
+	t.Run("reuses connections to the same host", func(t *testing.T) {
+		testData, _ := testutil.GenerateTestCertDER(t)
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(testData)
+		}))
+		var newConns atomic.Int32
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				newConns.Add(1)
+			}
+		}
+		server.StartTLS()
+		defer server.Close()
+
+		httpClient := download.NewPooledHTTPClient(4)
+		httpClient.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+		client := download.NewClient(httpClient)
+		for range 5 {
+			if _, err := client.DownloadCertificate(t.Context(), server.URL); err != nil {
+				t.Fatalf("DownloadCertificate() error = %v", err)
+			}
+		}
+
+		if got := newConns.Load(); got != 1 {
+			t.Errorf("server accepted %d connections, want 1", got)
+		}
+	})
+}
+
 func TestParseCertificate(t *testing.T) {
 	certDER, _ := testutil.GenerateTestCertDER(t)
 
@@ -110,3 +204,217 @@ func TestParseCertificate(t *testing.T) {
 		}
 	})
 }
+
+// writeClientCertificate writes a self-signed client certificate and its key to dir.
+func writeClientCertificate(t *testing.T, dir string) (cert *x509.Certificate, certFile, keyFile string) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tpmtb client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return cert, certFile, keyFile
+}
+
+func TestDownloadCertificateMutualTLS(t *testing.T) {
+	clientCert, certFile, keyFile := writeClientCertificate(t, t.TempDir())
+
+	testData, _ := testutil.GenerateTestCertDER(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || !r.TLS.PeerCertificates[0].Equal(clientCert) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(testData)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// newClient returns a download client trusting the test server and using tlsConfig.
+	newClient := func(tlsConfig *tls.Config) *download.Client {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		tlsConfig.RootCAs.AddCert(server.Certificate())
+		return download.NewClient(download.NewPooledHTTPClient(1, download.WithTLSConfig(tlsConfig)))
+	}
+
+	t.Run("with client certificate", func(t *testing.T) {
+		tlsConfig, err := download.TLSOptions{ClientCertFile: certFile, ClientKeyFile: keyFile}.TLSConfig()
+		if err != nil {
+			t.Fatalf("TLSConfig() error = %v", err)
+		}
+		if _, err := newClient(tlsConfig).DownloadCertificate(t.Context(), server.URL); err != nil {
+			t.Fatalf("DownloadCertificate() error = %v", err)
+		}
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		tlsConfig, err := download.TLSOptions{}.TLSConfig()
+		if err != nil {
+			t.Fatalf("TLSConfig() error = %v", err)
+		}
+		if tlsConfig != nil {
+			t.Fatalf("TLSConfig() = %v, want nil", tlsConfig)
+		}
+		if _, err := newClient(nil).DownloadCertificate(t.Context(), server.URL); err == nil {
+			t.Fatal("DownloadCertificate() expected error without client certificate")
+		}
+	})
+
+	t.Run("key without certificate", func(t *testing.T) {
+		if _, err := (download.TLSOptions{ClientKeyFile: keyFile}).TLSConfig(); err == nil {
+			t.Fatal("TLSConfig() expected error when the certificate is missing")
+		}
+	})
+}
+
+func TestDownloadCertificatePinnedCA(t *testing.T) {
+	testData, _ := testutil.GenerateTestCertDER(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(testData)
+	}))
+	defer server.Close()
+
+	otherCA, _ := testutil.GenerateTestCertDER(t)
+	tests := []struct {
+		name    string
+		caDER   []byte
+		wantErr bool
+	}{
+		{"server CA", server.Certificate().Raw, false},
+		{"wrong CA", otherCA, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caFile := filepath.Join(t.TempDir(), "ca.pem")
+			if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tt.caDER}), 0600); err != nil {
+				t.Fatal(err)
+			}
+			tlsConfig, err := download.TLSOptions{CAFile: caFile}.TLSConfig()
+			if err != nil {
+				t.Fatalf("TLSConfig() error = %v", err)
+			}
+
+			client := download.NewClient(download.NewPooledHTTPClient(1, download.WithTLSConfig(tlsConfig)))
+			_, err = client.DownloadCertificate(t.Context(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DownloadCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "certificate signed by unknown authority") {
+				t.Errorf("DownloadCertificate() expected an unknown authority error, got: %v", err)
+			}
+		})
+	}
+
+	t.Run("file without certificate", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caFile, []byte("not a certificate"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := (download.TLSOptions{CAFile: caFile}).TLSConfig(); err == nil {
+			t.Fatal("TLSConfig() expected error for a file without certificate")
+		}
+	})
+}
+
+func TestDownloadCertificateRedirects(t *testing.T) {
+	testData, _ := testutil.GenerateTestCertDER(t)
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(testData)
+	}))
+	defer target.Close()
+	insecureTarget := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(testData)
+	}))
+	defer insecureTarget.Close()
+
+	// Both TLS test servers share the same certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/https":
+			http.Redirect(w, r, target.URL+"/cert.crt", http.StatusFound)
+		case "/http":
+			http.Redirect(w, r, insecureTarget.URL+"/cert.crt", http.StatusMovedPermanently)
+		default:
+			// Redirects to itself forever
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+		}
+	}))
+	defer server.Close()
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    []download.HTTPClientOption
+		wantErr string
+	}{
+		{name: "https to https", path: "/https"},
+		{name: "https to http", path: "/http", wantErr: "redirects to non-HTTPS URL"},
+		{name: "too many redirects", path: "/loop", wantErr: "stopped after 5 redirects"},
+		{name: "redirects disabled", path: "/https", opts: []download.HTTPClientOption{download.WithoutRedirects()}, wantErr: "following redirects is disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]download.HTTPClientOption{download.WithTLSConfig(tlsConfig)}, tt.opts...)
+			client := download.NewClient(download.NewPooledHTTPClient(1, opts...))
+
+			_, err := client.DownloadCertificate(t.Context(), server.URL+tt.path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("DownloadCertificate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, download.ErrRedirectRejected) {
+				t.Fatalf("DownloadCertificate() error = %v, want %v", err, download.ErrRedirectRejected)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DownloadCertificate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// MaxRedirects is the maximum number of redirects followed by [CheckRedirect].
+const MaxRedirects = 5
+
+// ErrRedirectRejected is returned when a download is redirected in a way the
+// redirect policy does not allow.
+var ErrRedirectRejected = errors.New("redirect rejected")
+
+// CheckRedirect is the default redirect policy of the download clients.
+//
+// Vendor certificate URLs sometimes redirect to a CDN, so redirects are followed as
+// long as they stay on HTTPS and do not exceed [MaxRedirects] hops. Any other redirect
+// fails the download with [ErrRedirectRejected].
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > MaxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrRedirectRejected, MaxRedirects)
+	}
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: %s redirects to non-HTTPS URL %s", ErrRedirectRejected, via[len(via)-1].URL, req.URL)
+	}
+	return nil
+}
+
+// RejectRedirects is a redirect policy failing the download on any redirect.
+func RejectRedirects(req *http.Request, via []*http.Request) error {
+	return fmt.Errorf("%w: %s redirects to %s and following redirects is disabled", ErrRedirectRejected, via[len(via)-1].URL, req.URL)
+}
@@ -0,0 +1,121 @@
+package format
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"go.yaml.in/yaml/v4"
+)
+
+// URLChange describes a certificate URL re-encoded by [Formatter.FixURLs].
+type URLChange struct {
+	// VendorID is the ID of the vendor owning the certificate.
+	VendorID string
+	// Certificate is the name of the certificate.
+	Certificate string
+	// Line is the line of the URL in the configuration file.
+	Line int
+	// Old is the URL as written in the configuration file.
+	Old string
+	// New is the re-encoded URL.
+	New string
+}
+
+// FixURLs re-encodes every certificate URL of a TPM roots configuration file
+// through [url.URL.String], the encoding expected by the validator.
+//
+// Unlike [Formatter.FormatFile], only the URLs are rewritten: vendor and certificate
+// order, comments and the remaining values are kept as is. The output file is only
+// written when at least one URL changed; an empty outputPath performs a dry run.
+//
+// Example:
+//
+//	formatter := format.NewFormatter()
+//	changes, err := formatter.FixURLs(".tpm-roots.yaml", ".tpm-roots.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, c := range changes {
+//	    fmt.Printf("%s: %s -> %s\n", c.Certificate, c.Old, c.New)
+//	}
+func (f *Formatter) FixURLs(inputPath, outputPath string) ([]URLChange, error) {
+	data, err := utils.ReadFile(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("failed to parse YAML: empty document")
+	}
+
+	var changes []URLChange
+	vendorsNode := mappingValue(doc.Content[0], "vendors")
+	if vendorsNode != nil {
+		for _, vendor := range vendorsNode.Content {
+			certsNode := mappingValue(vendor, "certificates")
+			if certsNode == nil {
+				continue
+			}
+			for _, cert := range certsNode.Content {
+				urlNode := mappingValue(cert, "url")
+				if urlNode == nil || urlNode.Kind != yaml.ScalarNode {
+					continue
+				}
+				encoded := f.encodeURL(urlNode.Value)
+				if encoded == urlNode.Value {
+					continue
+				}
+				changes = append(changes, URLChange{
+					VendorID:    scalarValue(mappingValue(vendor, "id")),
+					Certificate: scalarValue(mappingValue(cert, "name")),
+					Line:        urlNode.Line,
+					Old:         urlNode.Value,
+					New:         encoded,
+				})
+				urlNode.Value = encoded
+			}
+		}
+	}
+
+	if len(changes) == 0 || outputPath == "" {
+		return changes, nil
+	}
+
+	yamlData, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	yamlData = f.ensureYAMLDocumentMarker(yamlData)
+
+	if err := os.WriteFile(outputPath, yamlData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return changes, nil
+}
+
+// mappingValue returns the value of key in a mapping node, or nil if not found.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the value of a scalar node, or an empty string for nil.
+func scalarValue(node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return node.Value
+}
@@ -0,0 +1,168 @@
+package format
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config"
+)
+
+const malformedURLsConfig = `---
+# Comments are kept by fix-urls
+version: "alpha"
+vendors:
+    - id: "STM"
+      name: "STMicroelectronics"
+      certificates:
+        - name: "Space"
+          url: "https://example.com/certs/root cert.crt"
+          validation:
+            fingerprint:
+                sha256: "AA:BB"
+        - name: "Unicode"
+          url: "https://example.com/certs/racine-été.crt"
+          validation:
+            fingerprint:
+                sha256: "CC:DD"
+    - id: "IFX"
+      name: "Infineon"
+      certificates:
+        - name: "Mixed"
+          url: "https://example.com/my certs/ca[1] \"root\".cer"
+          validation:
+            fingerprint:
+                sha256: "EE:FF"
+        - name: "Already encoded"
+          url: "https://example.com/certs/root%20ca.cer"
+          validation:
+            fingerprint:
+                sha256: "11:22"
+`
+
+// assertSameResource checks that got is properly encoded and points to the same resource as want.
+func assertSameResource(t *testing.T, want, got string) {
+	t.Helper()
+
+	wantURL, err := url.Parse(want)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", want, err)
+	}
+	gotURL, err := url.Parse(got)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", got, err)
+	}
+	if gotURL.String() != got {
+		t.Errorf("URL %q is not properly encoded, expected %q", got, gotURL.String())
+	}
+	if gotURL.Scheme != wantURL.Scheme || gotURL.Host != wantURL.Host || gotURL.Path != wantURL.Path {
+		t.Errorf("URL %q does not point to the same resource as %q", got, want)
+	}
+	if strings.ContainsAny(got, " \"[]") || strings.ContainsFunc(got, func(r rune) bool { return r > 127 }) {
+		t.Errorf("URL %q still contains characters to encode", got)
+	}
+}
+
+func TestFixURLs(t *testing.T) {
+	f := NewFormatter()
+
+	original := map[string]string{
+		"Space":           "https://example.com/certs/root cert.crt",
+		"Unicode":         "https://example.com/certs/racine-été.crt",
+		"Mixed":           `https://example.com/my certs/ca[1] "root".cer`,
+		"Already encoded": "https://example.com/certs/root%20ca.cer",
+	}
+
+	tmpDir := t.TempDir()
+	inputPath := filepath.Join(tmpDir, "input.yaml")
+	if err := os.WriteFile(inputPath, []byte(malformedURLsConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("fix in place", func(t *testing.T) {
+		outputPath := filepath.Join(tmpDir, "output.yaml")
+		changes, err := f.FixURLs(inputPath, outputPath)
+		if err != nil {
+			t.Fatalf("FixURLs() error = %v", err)
+		}
+
+		if len(changes) != 3 {
+			t.Fatalf("expected 3 changes, got %d: %+v", len(changes), changes)
+		}
+		for _, c := range changes {
+			if c.Old != original[c.Certificate] {
+				t.Errorf("change for %q: expected old URL %q, got %q", c.Certificate, original[c.Certificate], c.Old)
+			}
+			assertSameResource(t, c.Old, c.New)
+		}
+		if changes[2].VendorID != "IFX" || changes[2].Certificate != "Mixed" || changes[2].Line != 22 {
+			t.Errorf("unexpected change location: %+v", changes[2])
+		}
+
+		cfg, err := config.LoadConfig(outputPath)
+		if err != nil {
+			t.Fatalf("failed to load fixed config: %v", err)
+		}
+		for _, vendor := range cfg.Vendors {
+			for _, cert := range vendor.Certificates {
+				assertSameResource(t, original[cert.Name], cert.URL)
+			}
+		}
+		// Vendor order is kept, unlike with FormatFile
+		if cfg.Vendors[0].ID != "STM" {
+			t.Errorf("expected vendor order to be kept, got %s first", cfg.Vendors[0].ID)
+		}
+
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(output), "---\n") || !strings.Contains(string(output), "# Comments are kept by fix-urls") {
+			t.Errorf("expected document marker and comments to be kept, got:\n%s", output)
+		}
+
+		// A second run has nothing left to fix
+		again, err := f.FixURLs(outputPath, outputPath)
+		if err != nil {
+			t.Fatalf("FixURLs() error = %v", err)
+		}
+		if len(again) != 0 {
+			t.Errorf("expected no changes on fixed file, got %+v", again)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		changes, err := f.FixURLs(inputPath, "")
+		if err != nil {
+			t.Fatalf("FixURLs() error = %v", err)
+		}
+		if len(changes) != 3 {
+			t.Errorf("expected 3 changes, got %d", len(changes))
+		}
+		data, err := os.ReadFile(inputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != malformedURLsConfig {
+			t.Error("dry run should not modify the input file")
+		}
+	})
+
+	t.Run("format re-encodes every URL", func(t *testing.T) {
+		outputPath := filepath.Join(tmpDir, "formatted.yaml")
+		if err := f.FormatFile(inputPath, outputPath); err != nil {
+			t.Fatalf("FormatFile() error = %v", err)
+		}
+		cfg, err := config.LoadConfig(outputPath)
+		if err != nil {
+			t.Fatalf("failed to load formatted config: %v", err)
+		}
+		for _, vendor := range cfg.Vendors {
+			for _, cert := range vendor.Certificates {
+				assertSameResource(t, original[cert.Name], cert.URL)
+			}
+		}
+	})
+}
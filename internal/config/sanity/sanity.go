@@ -2,7 +2,11 @@ package sanity
 
 import (
 	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -52,39 +56,99 @@ func (w ExpirationWarning) String() string {
 	return b.String()
 }
 
+// PolicyWarning represents a certificate that does not meet the cryptographic
+// hygiene policy (e.g. small RSA key or weak signature algorithm).
+//
+// A policy warning never invalidates a certificate whose pinned fingerprint
+// matches the configuration; it is only reported for review.
+type PolicyWarning struct {
+	VendorID   string
+	VendorName string
+	CertName   string
+	Reason     string
+}
+
+func (w PolicyWarning) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  Vendor: %s (%s)\n", w.VendorName, w.VendorID)
+	fmt.Fprintf(&b, "  Certificate: %s\n", w.CertName)
+	fmt.Fprintf(&b, "  Reason: %s\n", w.Reason)
+	return b.String()
+}
+
 // Result contains the results of a sanity check.
 type Result struct {
 	ValidationErrors   []ValidationError
 	ExpirationWarnings []ExpirationWarning
+	PolicyWarnings     []PolicyWarning
 }
 
 // HasIssues returns true if there are any validation errors or expiration warnings.
+//
+// Policy warnings are not issues: they are only reported for review.
 func (r *Result) HasIssues() bool {
 	return len(r.ValidationErrors) > 0 || len(r.ExpirationWarnings) > 0
 }
 
+// DefaultMinRSAKeySize is the minimum RSA key size (in bits) enforced by [NewChecker].
+const DefaultMinRSAKeySize = 2048
+
+// DefaultWeakSignatureAlgorithms returns the signature algorithms flagged by [NewChecker].
+func DefaultWeakSignatureAlgorithms() []x509.SignatureAlgorithm {
+	return []x509.SignatureAlgorithm{
+		x509.MD2WithRSA,
+		x509.MD5WithRSA,
+		x509.SHA1WithRSA,
+		x509.DSAWithSHA1,
+		x509.ECDSAWithSHA1,
+	}
+}
+
 // Checker performs sanity checks on TPM certificates.
 type Checker struct {
 	downloader *download.Client
+
+	// PerCertTimeout bounds the download of each individual certificate.
+	//
+	// When a download exceeds this deadline, the certificate is reported as a
+	// [ValidationError] instead of stalling the whole check.
+	//
+	// Optional. If zero, no per-certificate deadline is applied.
+	PerCertTimeout time.Duration
+
+	// MinRSAKeySize is the minimum accepted RSA public key size in bits.
+	//
+	// Optional. If zero, RSA key sizes are not checked.
+	MinRSAKeySize int
+
+	// WeakSignatureAlgorithms lists the signature algorithms reported as weak.
+	//
+	// Optional. If empty, signature algorithms are not checked.
+	WeakSignatureAlgorithms []x509.SignatureAlgorithm
 }
 
 // NewChecker creates a new sanity checker.
 func NewChecker() *Checker {
 	return &Checker{
-		downloader: download.NewClient(),
+		downloader:              download.NewClient(),
+		MinRSAKeySize:           DefaultMinRSAKeySize,
+		WeakSignatureAlgorithms: DefaultWeakSignatureAlgorithms(),
 	}
 }
 
 // NewCheckerWithClient creates a new sanity checker with a custom HTTP client.
 func NewCheckerWithClient(client utils.HTTPClient) *Checker {
 	return &Checker{
-		downloader: download.NewClient(client),
+		downloader:              download.NewClient(client),
+		MinRSAKeySize:           DefaultMinRSAKeySize,
+		WeakSignatureAlgorithms: DefaultWeakSignatureAlgorithms(),
 	}
 }
 
 // Check performs sanity checks on all certificates in the configuration.
 //
-// It validates fingerprints and checks for certificate expiration.
+// It validates fingerprints, checks for certificate expiration and reports
+// certificates that do not meet the key size and signature algorithm policy.
 // The process runs concurrently using the specified number of workers.
 // If workers is 0, it auto-detects the optimal count.
 func (c *Checker) Check(cfg *config.TPMRootsConfig, workers int, thresholdDays int) (*Result, error) {
@@ -98,14 +162,6 @@ func (c *Checker) Check(cfg *config.TPMRootsConfig, workers int, thresholdDays i
 		workers = 1
 	}
 
-	type certCheck struct {
-		vendorIdx int
-		certIdx   int
-		valErr    *ValidationError
-		expWarn   *ExpirationWarning
-		err       error
-	}
-
 	// Create a channel to limit concurrent vendor processing
 	vendorChan := make(chan int, workers)
 	resultsChan := make(chan certCheck, cfg.TotalCertificates())
@@ -123,14 +179,10 @@ func (c *Checker) Check(cfg *config.TPMRootsConfig, workers int, thresholdDays i
 
 			// Process certificates for this vendor sequentially
 			for certIdx, cert := range v.Certificates {
-				valErr, expWarn, err := c.checkCertificate(cert, v.ID, v.Name, thresholdDays)
-				resultsChan <- certCheck{
-					vendorIdx: vIdx,
-					certIdx:   certIdx,
-					valErr:    valErr,
-					expWarn:   expWarn,
-					err:       err,
-				}
+				check := c.checkCertificate(cert, v.ID, v.Name, thresholdDays)
+				check.vendorIdx = vIdx
+				check.certIdx = certIdx
+				resultsChan <- check
 			}
 		}(vendorIdx, vendor)
 	}
@@ -145,6 +197,7 @@ func (c *Checker) Check(cfg *config.TPMRootsConfig, workers int, thresholdDays i
 	result := &Result{
 		ValidationErrors:   make([]ValidationError, 0),
 		ExpirationWarnings: make([]ExpirationWarning, 0),
+		PolicyWarnings:     make([]PolicyWarning, 0),
 	}
 
 	for check := range resultsChan {
@@ -157,22 +210,53 @@ func (c *Checker) Check(cfg *config.TPMRootsConfig, workers int, thresholdDays i
 		if check.expWarn != nil {
 			result.ExpirationWarnings = append(result.ExpirationWarnings, *check.expWarn)
 		}
+		result.PolicyWarnings = append(result.PolicyWarnings, check.policyWarns...)
 	}
 
 	return result, nil
 }
 
-// checkCertificate validates a single certificate and checks its expiration.
-func (c *Checker) checkCertificate(cert config.Certificate, vendorID, vendorName string, thresholdDays int) (*ValidationError, *ExpirationWarning, error) {
-	x509Cert, err := c.downloader.DownloadCertificate(context.Background(), cert.URL)
+// certCheck holds the outcome of checking a single certificate.
+type certCheck struct {
+	vendorIdx   int
+	certIdx     int
+	valErr      *ValidationError
+	expWarn     *ExpirationWarning
+	policyWarns []PolicyWarning
+	err         error
+}
+
+// checkCertificate validates a single certificate, checks its expiration and
+// evaluates it against the key size and signature algorithm policy.
+func (c *Checker) checkCertificate(cert config.Certificate, vendorID, vendorName string, thresholdDays int) certCheck {
+	ctx := context.Background()
+	if c.PerCertTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.PerCertTimeout)
+		defer cancel()
+	}
+
+	x509Certs, err := c.downloader.DownloadCertificates(ctx, cert.URL)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to download certificate %q from vendor %q: %w", cert.Name, vendorName, err)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return certCheck{valErr: &ValidationError{
+				VendorID:   vendorID,
+				VendorName: vendorName,
+				CertName:   cert.Name,
+				Error:      fmt.Errorf("download timed out after %s: %w", c.PerCertTimeout, err),
+			}}
+		}
+		return certCheck{err: fmt.Errorf("failed to download certificate %q from vendor %q: %w", cert.Name, vendorName, err)}
 	}
 
-	// Check fingerprint
-	var valErr *ValidationError
-	if err := validate.ValidateFingerprint(x509Cert, cert.Validation.Fingerprint); err != nil {
-		valErr = &ValidationError{
+	var check certCheck
+
+	// Check fingerprint (the URL may serve a whole chain)
+	x509Cert, err := validate.SelectCertificate(x509Certs, cert.Validation.Fingerprint)
+	if err != nil {
+		// Keep checking the first certificate served
+		x509Cert = x509Certs[0]
+		check.valErr = &ValidationError{
 			VendorID:   vendorID,
 			VendorName: vendorName,
 			CertName:   cert.Name,
@@ -181,12 +265,11 @@ func (c *Checker) checkCertificate(cert config.Certificate, vendorID, vendorName
 	}
 
 	// Check expiration
-	var expWarn *ExpirationWarning
 	now := time.Now()
 	daysUntilExpiry := int(x509Cert.NotAfter.Sub(now).Hours() / 24)
 
 	if daysUntilExpiry < thresholdDays {
-		expWarn = &ExpirationWarning{
+		check.expWarn = &ExpirationWarning{
 			VendorID:   vendorID,
 			VendorName: vendorName,
 			CertName:   cert.Name,
@@ -196,5 +279,32 @@ func (c *Checker) checkCertificate(cert config.Certificate, vendorID, vendorName
 		}
 	}
 
-	return valErr, expWarn, nil
+	// Check policy
+	for _, reason := range c.checkPolicy(x509Cert) {
+		check.policyWarns = append(check.policyWarns, PolicyWarning{
+			VendorID:   vendorID,
+			VendorName: vendorName,
+			CertName:   cert.Name,
+			Reason:     reason,
+		})
+	}
+
+	return check
+}
+
+// checkPolicy returns the reasons why the certificate does not meet the policy.
+func (c *Checker) checkPolicy(cert *x509.Certificate) []string {
+	var reasons []string
+
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); ok && c.MinRSAKeySize > 0 {
+		if bits := pub.N.BitLen(); bits < c.MinRSAKeySize {
+			reasons = append(reasons, fmt.Sprintf("RSA key size %d bits is below the minimum of %d bits", bits, c.MinRSAKeySize))
+		}
+	}
+
+	if slices.Contains(c.WeakSignatureAlgorithms, cert.SignatureAlgorithm) {
+		reasons = append(reasons, fmt.Sprintf("weak signature algorithm %s", cert.SignatureAlgorithm))
+	}
+
+	return reasons
 }
@@ -1,6 +1,7 @@
 package sanity
 
 import (
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 	"net/http"
@@ -267,6 +268,79 @@ func TestChecker_Check(t *testing.T) {
 		}
 	})
 
+	t.Run("per-certificate timeout", func(t *testing.T) {
+		certDER, fingerprint := testutil.GenerateTestCertDER(t)
+		slowServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		}))
+		defer slowServer.Close()
+
+		fastServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(certDER)
+		}))
+		defer fastServer.Close()
+
+		// Both servers share the same test CA, so a single client trusts both.
+		cfg := &config.TPMRootsConfig{
+			Version: "test",
+			Vendors: []config.Vendor{
+				{
+					ID:   "TEST",
+					Name: "Test Vendor",
+					Certificates: []config.Certificate{
+						{
+							Name: "Slow Cert",
+							URL:  slowServer.URL,
+							Validation: config.Validation{
+								Fingerprint: config.Fingerprint{
+									SHA1: formatFingerprintWithColons(fingerprint),
+								},
+							},
+						},
+						{
+							Name: "Fast Cert",
+							URL:  fastServer.URL,
+							Validation: config.Validation{
+								Fingerprint: config.Fingerprint{
+									SHA1: formatFingerprintWithColons(fingerprint),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		checker := &Checker{
+			downloader:     &download.Client{HTTPClient: slowServer.Client()},
+			PerCertTimeout: 100 * time.Millisecond,
+		}
+
+		start := time.Now()
+		result, err := checker.Check(cfg, 1, 90)
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Check() took %v, expected the slow download to be cut short", elapsed)
+		}
+
+		if len(result.ValidationErrors) != 1 {
+			t.Fatalf("Check() expected 1 validation error, got %d", len(result.ValidationErrors))
+		}
+		valErr := result.ValidationErrors[0]
+		if valErr.CertName != "Slow Cert" {
+			t.Errorf("ValidationError.CertName = %q, want %q", valErr.CertName, "Slow Cert")
+		}
+		if !strings.Contains(valErr.Error.Error(), "timed out") {
+			t.Errorf("ValidationError.Error should mention timeout, got: %v", valErr.Error)
+		}
+	})
+
 	t.Run("multiple vendors concurrent", func(t *testing.T) {
 		certDER1, fp1 := testutil.GenerateTestCertDER(t)
 		certDER2, fp2 := testutil.GenerateTestCertDER(t)
@@ -418,6 +492,27 @@ func TestExpirationWarning_String(t *testing.T) {
 	})
 }
 
+func TestPolicyWarning_String(t *testing.T) {
+	warn := PolicyWarning{
+		VendorID:   "TEST",
+		VendorName: "Test Vendor",
+		CertName:   "Test Certificate",
+		Reason:     "weak signature algorithm SHA1-RSA",
+	}
+
+	s := warn.String()
+
+	if !strings.Contains(s, "Test Vendor (TEST)") {
+		t.Error("PolicyWarning.String() should contain vendor info")
+	}
+	if !strings.Contains(s, "Test Certificate") {
+		t.Error("PolicyWarning.String() should contain certificate name")
+	}
+	if !strings.Contains(s, "weak signature algorithm SHA1-RSA") {
+		t.Error("PolicyWarning.String() should contain the reason")
+	}
+}
+
 func TestResult_HasIssues(t *testing.T) {
 	t.Run("no issues", func(t *testing.T) {
 		r := &Result{
@@ -466,6 +561,127 @@ func TestResult_HasIssues(t *testing.T) {
 			t.Error("HasIssues() should return true when there are both validation errors and expiration warnings")
 		}
 	})
+
+	t.Run("policy warnings only", func(t *testing.T) {
+		r := &Result{
+			PolicyWarnings: []PolicyWarning{
+				{VendorID: "TEST", VendorName: "Test", CertName: "Cert", Reason: "weak signature algorithm SHA1-RSA"},
+			},
+		}
+		if r.HasIssues() {
+			t.Error("HasIssues() should return false when there are only policy warnings")
+		}
+	})
+}
+
+func TestChecker_CheckPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		genCert       func(t *testing.T) ([]byte, string)
+		minRSAKeySize int
+		weakAlgs      []x509.SignatureAlgorithm
+		wantReasons   []string
+	}{
+		{
+			name: "RSA-1024 key",
+			genCert: func(t *testing.T) ([]byte, string) {
+				return testutil.GenerateTestCertRSA(t, 1024, x509.SHA256WithRSA)
+			},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			weakAlgs:      DefaultWeakSignatureAlgorithms(),
+			wantReasons:   []string{"RSA key size 1024 bits is below the minimum of 2048 bits"},
+		},
+		{
+			name: "SHA1-signed certificate",
+			genCert: func(t *testing.T) ([]byte, string) {
+				return testutil.GenerateTestCertRSA(t, 2048, x509.SHA1WithRSA)
+			},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			weakAlgs:      DefaultWeakSignatureAlgorithms(),
+			wantReasons:   []string{"weak signature algorithm SHA1-RSA"},
+		},
+		{
+			name: "RSA-1024 and SHA1-signed certificate",
+			genCert: func(t *testing.T) ([]byte, string) {
+				return testutil.GenerateTestCertRSA(t, 1024, x509.SHA1WithRSA)
+			},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			weakAlgs:      DefaultWeakSignatureAlgorithms(),
+			wantReasons: []string{
+				"RSA key size 1024 bits is below the minimum of 2048 bits",
+				"weak signature algorithm SHA1-RSA",
+			},
+		},
+		{
+			name: "policy disabled",
+			genCert: func(t *testing.T) ([]byte, string) {
+				return testutil.GenerateTestCertRSA(t, 1024, x509.SHA1WithRSA)
+			},
+		},
+		{
+			name:          "compliant ECDSA certificate",
+			genCert:       testutil.GenerateTestCertDER,
+			minRSAKeySize: DefaultMinRSAKeySize,
+			weakAlgs:      DefaultWeakSignatureAlgorithms(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certDER, fingerprint := tt.genCert(t)
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+				w.Write(certDER)
+			}))
+			defer server.Close()
+
+			cfg := &config.TPMRootsConfig{
+				Version: "test",
+				Vendors: []config.Vendor{
+					{
+						ID:   "TEST",
+						Name: "Test Vendor",
+						Certificates: []config.Certificate{
+							{
+								Name: "Test Cert",
+								URL:  server.URL,
+								Validation: config.Validation{
+									Fingerprint: config.Fingerprint{
+										SHA1: formatFingerprintWithColons(fingerprint),
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			checker := &Checker{
+				downloader:              &download.Client{HTTPClient: server.Client()},
+				MinRSAKeySize:           tt.minRSAKeySize,
+				WeakSignatureAlgorithms: tt.weakAlgs,
+			}
+
+			result, err := checker.Check(cfg, 1, 90)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			// A matching pinned fingerprint remains authoritative
+			if len(result.ValidationErrors) != 0 {
+				t.Errorf("Check() expected no validation errors, got %d", len(result.ValidationErrors))
+			}
+
+			if len(result.PolicyWarnings) != len(tt.wantReasons) {
+				t.Fatalf("Check() expected %d policy warnings, got %d: %v", len(tt.wantReasons), len(result.PolicyWarnings), result.PolicyWarnings)
+			}
+			for i, want := range tt.wantReasons {
+				if got := result.PolicyWarnings[i].Reason; got != want {
+					t.Errorf("PolicyWarning.Reason = %q, want %q", got, want)
+				}
+			}
+		})
+	}
 }
 
 // formatFingerprintWithColons converts a hex string to colon-separated format.
@@ -39,6 +39,22 @@ func ValidateFingerprint(cert *x509.Certificate, fp config.Fingerprint) error {
 	return nil
 }
 
+// SelectCertificate returns the certificate matching the fingerprint among certs.
+//
+// It lets a certificate be picked from a file serving a whole chain. When none matches,
+// the error reports the mismatch against the first certificate, as [ValidateFingerprint] does.
+func SelectCertificate(certs []*x509.Certificate, fp config.Fingerprint) (*x509.Certificate, error) {
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate to validate")
+	}
+	for _, cert := range certs {
+		if ValidateFingerprint(cert, fp) == nil {
+			return cert, nil
+		}
+	}
+	return nil, ValidateFingerprint(certs[0], fp)
+}
+
 // ValidateFingerprintWithAlgorithm validates a certificate against an expected fingerprint using a specified algorithm.
 func ValidateFingerprintWithAlgorithm(cert *x509.Certificate, expectedFP string, algorithm string) error {
 	actualFP := fingerprint.New(cert.Raw, algorithm)
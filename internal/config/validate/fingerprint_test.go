@@ -1,6 +1,7 @@
 package validate_test
 
 import (
+	"crypto/x509"
 	"encoding/hex"
 	"strings"
 	"testing"
@@ -185,3 +186,28 @@ func TestValidateFingerprint(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectCertificate(t *testing.T) {
+	first, _ := testutil.GenerateTestCert(t)
+	second, secondFP := testutil.GenerateTestCert(t)
+	certs := []*x509.Certificate{first, second}
+
+	got, err := validate.SelectCertificate(certs, config.Fingerprint{SHA1: secondFP})
+	if err != nil {
+		t.Fatalf("SelectCertificate() error = %v", err)
+	}
+	if got != second {
+		t.Error("SelectCertificate() did not return the matching certificate")
+	}
+
+	_, err = validate.SelectCertificate(certs, config.Fingerprint{
+		SHA1: "00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00",
+	})
+	if err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Errorf("SelectCertificate() error = %v, want fingerprint mismatch", err)
+	}
+
+	if _, err := validate.SelectCertificate(nil, config.Fingerprint{SHA1: secondFP}); err == nil {
+		t.Error("SelectCertificate() expected error for empty list")
+	}
+}
@@ -22,17 +22,40 @@ type ValidationError struct {
 // YAMLValidator handles YAML validation operations.
 type YAMLValidator struct {
 	errors      []ValidationError
+	warnings    []ValidationError
 	maxErrors   int
 	lineMapping map[string]int
+	strict      bool
+}
+
+// YAMLValidatorOption configures a [YAMLValidator].
+type YAMLValidatorOption func(*YAMLValidator)
+
+// WithStrict reports warnings (e.g. a vendor without certificates) as validation errors.
+func WithStrict(strict bool) YAMLValidatorOption {
+	return func(v *YAMLValidator) {
+		v.strict = strict
+	}
 }
 
 // NewYAMLValidator creates a new YAML validator.
-func NewYAMLValidator() *YAMLValidator {
-	return &YAMLValidator{
+func NewYAMLValidator(opts ...YAMLValidatorOption) *YAMLValidator {
+	v := &YAMLValidator{
 		errors:      make([]ValidationError, 0),
 		maxErrors:   10,
 		lineMapping: make(map[string]int),
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Warnings returns the non-fatal issues found by [YAMLValidator.ValidateFile].
+//
+// It is always empty in strict mode (see [WithStrict]), where warnings are reported as errors.
+func (v *YAMLValidator) Warnings() []ValidationError {
+	return v.warnings
 }
 
 // ValidateFile validates a TPM roots configuration file.
@@ -48,6 +71,9 @@ func NewYAMLValidator() *YAMLValidator {
 //   - Fingerprints are formatted in uppercase with colon separators
 //   - String values are double-quoted
 //
+// It also warns about vendors without certificates, and certificates pinned under
+// several vendors (see [YAMLValidator.Warnings]).
+//
 // Returns the list of validation errors (max 10).
 //
 // Example:
@@ -80,8 +106,10 @@ func (v *YAMLValidator) ValidateFile(path string) ([]ValidationError, error) {
 	v.validateVendorIDs(cfg)
 	v.validateDuplicateVendorIDs(cfg)
 	v.validateVendorsSorting(cfg)
+	v.validateEmptyVendors(cfg)
 	v.validateCertificatesSorting(cfg)
 	v.validateDuplicateCertificates(cfg)
+	v.validateCrossVendorDuplicates(cfg)
 	v.validateURLEncoding(cfg)
 	v.validateFingerprintFormat(cfg)
 	v.validateQuotes(data)
@@ -171,6 +199,24 @@ func (v *YAMLValidator) addError(path, message string) {
 	})
 }
 
+// addWarning adds a non-fatal validation issue, or a validation error in strict mode.
+func (v *YAMLValidator) addWarning(path, message string) {
+	if v.strict {
+		v.addError(path, message)
+		return
+	}
+
+	line := v.lineMapping[path]
+	if line == 0 {
+		line = 1
+	}
+
+	v.warnings = append(v.warnings, ValidationError{
+		Line:    line,
+		Message: message,
+	})
+}
+
 // validateVendorIDs checks that all vendor IDs are valid according to TCG registry.
 func (v *YAMLValidator) validateVendorIDs(cfg *config.TPMRootsConfig) {
 	for i, vendor := range cfg.Vendors {
@@ -216,6 +262,18 @@ func (v *YAMLValidator) validateVendorsSorting(cfg *config.TPMRootsConfig) {
 	}
 }
 
+// validateEmptyVendors warns about vendors which contribute no certificate to the bundle.
+//
+// This is likely a mistake, but it is not an error so that a vendor can be added before its certificates.
+func (v *YAMLValidator) validateEmptyVendors(cfg *config.TPMRootsConfig) {
+	for i, vendor := range cfg.Vendors {
+		if len(vendor.Certificates) == 0 {
+			path := fmt.Sprintf("vendors[%d]", i)
+			v.addWarning(path, fmt.Sprintf("vendor %q has no certificates", vendor.ID))
+		}
+	}
+}
+
 // validateCertificatesSorting checks that certificates are sorted by name within each vendor.
 func (v *YAMLValidator) validateCertificatesSorting(cfg *config.TPMRootsConfig) {
 	for i, vendor := range cfg.Vendors {
@@ -254,6 +312,53 @@ func (v *YAMLValidator) validateDuplicateCertificates(cfg *config.TPMRootsConfig
 	}
 }
 
+// validateCrossVendorDuplicates warns about certificates pinned under more than one vendor,
+// by URL or fingerprint.
+//
+// This is usually a mis-attributed certificate, but a CA shared across brands is legitimate:
+// hence a warning rather than an error.
+func (v *YAMLValidator) validateCrossVendorDuplicates(cfg *config.TPMRootsConfig) {
+	type location struct {
+		vendorID string
+		path     string
+	}
+	urls := make(map[string]location)
+	fingerprints := make(map[string]location)
+
+	// check records the first location of key, and warns if it was already seen under another vendor
+	check := func(seen map[string]location, key string, loc location, message func(first location) string) {
+		first, exists := seen[key]
+		if !exists {
+			seen[key] = loc
+			return
+		}
+		if first.vendorID != loc.vendorID {
+			v.addWarning(loc.path, message(first))
+		}
+	}
+
+	for i, vendor := range cfg.Vendors {
+		for j, cert := range vendor.Certificates {
+			certPath := fmt.Sprintf("vendors[%d].certificates[%d]", i, j)
+
+			check(urls, cert.URL, location{vendor.ID, certPath + ".url"}, func(first location) string {
+				return fmt.Sprintf("URL of certificate %q in vendor %q is also pinned under vendor %q (line %d)",
+					cert.Name, vendor.ID, first.vendorID, v.lineMapping[first.path])
+			})
+
+			fp, hashAlg := cert.Validation.Fingerprint.GetFingerprintValue()
+			if fp == "" {
+				continue
+			}
+			fpPath := certPath + ".validation.fingerprint." + hashAlg
+			check(fingerprints, hashAlg+":"+strings.ToUpper(fp), location{vendor.ID, fpPath}, func(first location) string {
+				return fmt.Sprintf("fingerprint of certificate %q in vendor %q is also pinned under vendor %q (line %d)",
+					cert.Name, vendor.ID, first.vendorID, v.lineMapping[first.path])
+			})
+		}
+	}
+}
+
 // validateURLEncoding checks that URLs are properly encoded.
 func (v *YAMLValidator) validateURLEncoding(cfg *config.TPMRootsConfig) {
 	for i, vendor := range cfg.Vendors {
@@ -384,3 +384,110 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestYAMLValidator_EmptyVendor(t *testing.T) {
+	const yaml = `---
+version: "alpha"
+vendors:
+  - id: "IFX"
+    name: "Infineon"
+    certificates: []
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates:
+      - name: "Cert A"
+        url: "https://example.com/cert.cer"
+        validation:
+          fingerprint:
+            sha1: "AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD"
+`
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(testFile, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := validate.ValidationError{Line: 4, Message: `vendor "IFX" has no certificates`}
+
+	t.Run("warning by default", func(t *testing.T) {
+		validator := validate.NewYAMLValidator()
+		errors, err := validator.ValidateFile(testFile)
+		if err != nil {
+			t.Fatalf("ValidateFile() unexpected error: %v", err)
+		}
+		if len(errors) != 0 {
+			t.Errorf("ValidateFile() got %d errors, want 0: %v", len(errors), errors)
+		}
+		if warnings := validator.Warnings(); len(warnings) != 1 || warnings[0] != want {
+			t.Errorf("Warnings() = %v, want [%v]", warnings, want)
+		}
+	})
+
+	t.Run("error in strict mode", func(t *testing.T) {
+		validator := validate.NewYAMLValidator(validate.WithStrict(true))
+		errors, err := validator.ValidateFile(testFile)
+		if err != nil {
+			t.Fatalf("ValidateFile() unexpected error: %v", err)
+		}
+		if len(errors) != 1 || errors[0] != want {
+			t.Errorf("ValidateFile() = %v, want [%v]", errors, want)
+		}
+		if warnings := validator.Warnings(); len(warnings) != 0 {
+			t.Errorf("Warnings() = %v, want none", warnings)
+		}
+	})
+}
+
+func TestYAMLValidator_CrossVendorDuplicates(t *testing.T) {
+	const yaml = `---
+version: "alpha"
+vendors:
+  - id: "IFX"
+    name: "Infineon"
+    certificates:
+      - name: "Cert A"
+        url: "https://example.com/a.cer"
+        validation:
+          fingerprint:
+            sha256: "AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99"
+  - id: "STM"
+    name: "STMicroelectronics"
+    certificates:
+      - name: "Cert B"
+        url: "https://example.com/b.cer"
+        validation:
+          fingerprint:
+            sha256: "AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99"
+`
+	testFile := filepath.Join(t.TempDir(), "test.yaml")
+	if err := os.WriteFile(testFile, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want := validate.ValidationError{
+		Line:    19,
+		Message: `fingerprint of certificate "Cert B" in vendor "STM" is also pinned under vendor "IFX" (line 11)`,
+	}
+
+	t.Run("warning by default", func(t *testing.T) {
+		validator := validate.NewYAMLValidator()
+		errors, err := validator.ValidateFile(testFile)
+		if err != nil {
+			t.Fatalf("ValidateFile() unexpected error: %v", err)
+		}
+		if len(errors) != 0 {
+			t.Errorf("ValidateFile() got %d errors, want 0: %v", len(errors), errors)
+		}
+		if warnings := validator.Warnings(); len(warnings) != 1 || warnings[0] != want {
+			t.Errorf("Warnings() = %v, want [%v]", warnings, want)
+		}
+	})
+
+	t.Run("error in strict mode", func(t *testing.T) {
+		validator := validate.NewYAMLValidator(validate.WithStrict(true))
+		errors, err := validator.ValidateFile(testFile)
+		if err != nil {
+			t.Fatalf("ValidateFile() unexpected error: %v", err)
+		}
+		if len(errors) != 1 || errors[0] != want {
+			t.Errorf("ValidateFile() = %v, want [%v]", errors, want)
+		}
+	})
+}
@@ -27,3 +27,18 @@ func (id ID) Validate() error {
 func (id ID) String() string {
 	return string(id)
 }
+
+// Name returns the human-readable vendor name from the TCG registry (e.g., "Infineon" for IFX).
+//
+// It returns an empty string if the vendor ID is not in the registry.
+func (id ID) Name() string {
+	return registry[id].name
+}
+
+// ManufacturerCode returns the TCG manufacturer code of the vendor (e.g., "0x49465800" for IFX),
+// as reported by a TPM in its TPM_PT_MANUFACTURER property.
+//
+// It returns an empty string if the vendor ID is not in the registry.
+func (id ID) ManufacturerCode() string {
+	return registry[id].manufacturerCode
+}
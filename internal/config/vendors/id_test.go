@@ -73,3 +73,36 @@ func TestID_String(t *testing.T) {
 		})
 	}
 }
+
+func TestID_Name(t *testing.T) {
+	tests := []struct {
+		id       ID
+		wantName string
+		wantCode string
+	}{
+		{id: IFX, wantName: "Infineon", wantCode: "0x49465800"},
+		{id: NTC, wantName: "Nuvoton Technology", wantCode: "0x4E544300"},
+		{id: STM, wantName: "STMicroelectronics", wantCode: "0x53544D20"},
+		{id: "INVALID"},
+		{id: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.id), func(t *testing.T) {
+			if got := tt.id.Name(); got != tt.wantName {
+				t.Errorf("ID.Name() = %q, want %q", got, tt.wantName)
+			}
+			if got := tt.id.ManufacturerCode(); got != tt.wantCode {
+				t.Errorf("ID.ManufacturerCode() = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVendorNamesCoverRegistry(t *testing.T) {
+	for _, id := range ValidVendorIDs {
+		if id.Name() == "" {
+			t.Errorf("vendor %s has no name", id)
+		}
+	}
+}
@@ -74,6 +74,51 @@ var ValidVendorIDs = []ID{
 	WEC,
 }
 
+// registryEntry describes a vendor of the TCG registry.
+type registryEntry struct {
+	name             string
+	manufacturerCode string
+}
+
+// registry maps each vendor ID to its entry in the TCG registry.
+//
+// The manufacturer code is the vendor ID padded on 4 bytes, with zeros or spaces as listed
+// in the registry.
+//
+// Source: TCG TPM Vendor ID Registry Family 1.2 and 2.0, Version 1.07, Revision 0.02
+// https://trustedcomputinggroup.org/wp-content/uploads/TCG-TPM-Vendor-ID-Registry-Family-1.2-and-2.0-Version-1.07-Revision-0.02_pub.pdf
+var registry = map[ID]registryEntry{
+	AMD:  {name: "AMD", manufacturerCode: "0x414D4400"},
+	ANT:  {name: "Ant Group", manufacturerCode: "0x414E5400"},
+	ATML: {name: "Atmel", manufacturerCode: "0x41544D4C"},
+	BRCM: {name: "Broadcom", manufacturerCode: "0x4252434D"},
+	CSCO: {name: "Cisco", manufacturerCode: "0x4353434F"},
+	FLYS: {name: "Flyslice Technologies", manufacturerCode: "0x464C5953"},
+	GOOG: {name: "Google", manufacturerCode: "0x474F4F47"},
+	HPI:  {name: "HPI", manufacturerCode: "0x48504900"},
+	HPE:  {name: "HPE", manufacturerCode: "0x48504500"},
+	HISI: {name: "Huawei", manufacturerCode: "0x48495349"},
+	IBM:  {name: "IBM", manufacturerCode: "0x49424D00"},
+	IFX:  {name: "Infineon", manufacturerCode: "0x49465800"},
+	INTC: {name: "Intel", manufacturerCode: "0x494E5443"},
+	LEN:  {name: "Lenovo", manufacturerCode: "0x4C454E00"},
+	MSFT: {name: "Microsoft", manufacturerCode: "0x4D534654"},
+	NSG:  {name: "NSING", manufacturerCode: "0x4E534700"},
+	NSM:  {name: "National Semiconductor", manufacturerCode: "0x4E534D20"},
+	NTC:  {name: "Nuvoton Technology", manufacturerCode: "0x4E544300"},
+	NTZ:  {name: "Nationz", manufacturerCode: "0x4E545A00"},
+	QCOM: {name: "Qualcomm", manufacturerCode: "0x51434F4D"},
+	ROCC: {name: "Fuzhou Rockchip", manufacturerCode: "0x524F4343"},
+	SEAL: {name: "Wisekey", manufacturerCode: "0x5345414C"},
+	SECE: {name: "SecEdge", manufacturerCode: "0x53454345"},
+	SMSN: {name: "Samsung", manufacturerCode: "0x534D534E"},
+	SMSC: {name: "SMSC", manufacturerCode: "0x534D5343"},
+	SNS:  {name: "Sinosun", manufacturerCode: "0x534E5300"},
+	STM:  {name: "STMicroelectronics", manufacturerCode: "0x53544D20"},
+	TXN:  {name: "Texas Instruments", manufacturerCode: "0x54584E00"},
+	WEC:  {name: "Winbond", manufacturerCode: "0x57454300"},
+}
+
 // IsValidVendorID checks if the provided vendor ID is in the TCG registry.
 //
 // Example:
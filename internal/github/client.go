@@ -3,45 +3,120 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 var SourceRepo = Repo{Owner: "loicsikidi", Name: "tpm-ca-certificates"}
 
+// ErrReleaseNotFound is returned when the requested release does not exist.
+var ErrReleaseNotFound = errors.New("release not found")
+
 const (
 	ReleaseBundleWorkflowPath = ".github/workflows/release-bundle.yaml"
 	githubAPIBaseURL          = "https://api.github.com"
 	apiVersion                = "2022-11-28"
+
+	// maxDownloadAttempts is the number of attempts made by [HTTPClient.DownloadAssetToWriter]
+	// to download an asset when the connection drops.
+	maxDownloadAttempts = 3
+
+	// DefaultMaxAttestationBundleSize is the default maximum size of an attestation bundle
+	// downloaded from its URL (1 MiB). Bundles are a few KiB in practice.
+	DefaultMaxAttestationBundleSize int64 = 1024 * 1024
+
+	// DefaultAttestationBundleTimeout is the default time allowed to download an attestation bundle.
+	DefaultAttestationBundleTimeout = 30 * time.Second
+
+	// DefaultRequestTimeout is the default time allowed for a GitHub API request when
+	// the context has no deadline.
+	DefaultRequestTimeout = 15 * time.Second
 )
 
 // HTTPClient wraps the standard http.Client to implement attestation fetching.
 //
 // This client makes direct calls to the GitHub REST API without requiring
 // the gh CLI or authentication for public repositories.
+//
+// API requests whose context has no deadline are bounded by a per-request timeout
+// ([DefaultRequestTimeout] unless changed with [HTTPClient.WithRequestTimeout]), so that
+// a hung endpoint does not block forever. An explicit context deadline always wins.
 type HTTPClient struct {
 	client utils.HTTPClient
 	// used to avoid rate limiting on GitHub API in ci pipelines
-	token string
+	token          string
+	requestTimeout time.Duration
 }
 
 // NewHTTPClient creates a new GitHub attestation client.
 //
 // The client uses the provided http.Client for making requests.
-// If nil is provided, http.DefaultClient is used.
+// If nil is provided, the client returned by [utils.DefaultHTTPClient] is used.
 func NewHTTPClient(optionalClient ...utils.HTTPClient) *HTTPClient {
-	client := utils.OptionalArgWithDefault[utils.HTTPClient](optionalClient, http.DefaultClient)
+	client := utils.OptionalArgWithDefault[utils.HTTPClient](optionalClient, utils.DefaultHTTPClient())
 	return &HTTPClient{
-		client: client,
-		token:  os.Getenv("GITHUB_TOKEN"),
+		client:         client,
+		token:          os.Getenv("GITHUB_TOKEN"),
+		requestTimeout: DefaultRequestTimeout,
+	}
+}
+
+// WithRequestTimeout sets the timeout applied to API requests whose context has no deadline
+// and returns the client. A non-positive timeout restores [DefaultRequestTimeout].
+func (c *HTTPClient) WithRequestTimeout(timeout time.Duration) *HTTPClient {
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	c.requestTimeout = timeout
+	return c
+}
+
+// newAPIRequest creates a GET request to the GitHub REST API carrying the API headers,
+// the token (if any) and the active trace context.
+func (c *HTTPClient) newAPIRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	req.Header.Set("User-Agent", utils.UserAgent())
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	// Propagate the active trace (W3C traceparent/baggage) to the GitHub API.
+	// This is a no-op unless a propagator is registered (see observability.Initialize).
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, nil
+}
+
+// requestContext bounds ctx by timeout (or the client timeout if zero) unless ctx
+// already has a deadline, in which case the explicit deadline wins.
+func (c *HTTPClient) requestContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
 	}
+	if timeout == 0 {
+		timeout = c.requestTimeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
 }
 
 // GetAttestations fetches attestations for a given artifact digest from GitHub.
@@ -52,6 +127,10 @@ func NewHTTPClient(optionalClient ...utils.HTTPClient) *HTTPClient {
 // This implementation uses a simple approach without full pagination support,
 // as we expect a small number of attestations (typically < 5).
 //
+// Bundles only referenced by URL are downloaded with the size and time limits of
+// the optional [AttestationsOptions]. If ctx has no deadline, the API request is
+// bounded by the request timeout of the options.
+//
 // Example:
 //
 //	client := NewHTTPClient(nil)
@@ -59,22 +138,23 @@ func NewHTTPClient(optionalClient ...utils.HTTPClient) *HTTPClient {
 //	if err != nil {
 //	    return err
 //	}
-func (c *HTTPClient) GetAttestations(ctx context.Context, repo Repo, digest string) ([]*Attestation, error) {
+func (c *HTTPClient) GetAttestations(ctx context.Context, repo Repo, digest string, optionalOpts ...AttestationsOptions) ([]*Attestation, error) {
+	opts := utils.OptionalArg(optionalOpts)
+	if err := opts.CheckAndSetDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
 	// Build API URL
 	// Endpoint: GET /repos/{owner}/{repo}/attestations/{digest}
 	url := fmt.Sprintf("%s/repos/%s/attestations/%s", githubAPIBaseURL, repo.String(), digest)
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	// Bundles referenced by URL are bounded by opts.BundleTimeout instead
+	reqCtx, cancel := c.requestContext(ctx, opts.RequestTimeout)
+	defer cancel()
 
-	// Set required headers
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", apiVersion)
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	req, err := c.newAPIRequest(reqCtx, url)
+	if err != nil {
+		return nil, err
 	}
 
 	// Execute request
@@ -99,11 +179,12 @@ func (c *HTTPClient) GetAttestations(ctx context.Context, repo Repo, digest stri
 	// Process attestations - load bundles if they're provided via URL
 	for i, att := range attResp.Attestations {
 		if att.Bundle == nil && att.BundleURL != "" {
-			loadedBundle, err := c.fetchBundle(ctx, att.BundleURL)
+			loadedBundle, compression, err := c.fetchBundle(ctx, att.BundleURL, opts)
 			if err != nil {
 				return nil, fmt.Errorf("failed to fetch bundle %d: %w", i, err)
 			}
 			att.Bundle = loadedBundle
+			att.Compression = compression
 		}
 	}
 
@@ -112,30 +193,70 @@ func (c *HTTPClient) GetAttestations(ctx context.Context, repo Repo, digest stri
 
 // fetchBundle downloads and parses a bundle from a URL.
 //
-// GitHub stores bundles as snappy-compressed protobuf JSON at bundle_url.
-// However, for inline bundles in the API response, no decompression is needed.
-func (c *HTTPClient) fetchBundle(ctx context.Context, bundleURL string) (*bundle.Bundle, error) {
-	bundleBytes, err := utils.HttpGET(ctx, c.client, bundleURL)
+// GitHub stores bundles as snappy-compressed protobuf JSON at bundle_url, while
+// inline bundles in the API response are plain JSON. The encoding is taken from the
+// Content-Encoding response header, or detected from the payload when the header is
+// not set (see [detectCompression]), and returned alongside the bundle.
+//
+// The download is bounded by opts.MaxBundleSize and opts.BundleTimeout, as bundle_url
+// is taken from the API response.
+func (c *HTTPClient) fetchBundle(ctx context.Context, bundleURL string, opts AttestationsOptions) (*bundle.Bundle, Compression, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.BundleTimeout)
+	defer cancel()
+
+	resp, err := utils.HttpOpenWithRetry(ctx, c.client, bundleURL, nil, utils.RetryConfig{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch bundle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bundleBytes, err := io.ReadAll(io.LimitReader(resp.Body, opts.MaxBundleSize+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch bundle: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch bundle: %w", err)
+	}
+	if int64(len(bundleBytes)) > opts.MaxBundleSize {
+		return nil, "", fmt.Errorf("failed to fetch bundle: %w: bundle is larger than %d bytes", utils.ErrHTTPGetTooLarge, opts.MaxBundleSize)
+	}
+
+	compression, err := compressionFromContentEncoding(resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch bundle: %w", err)
+	}
+	return parseBundle(bundleBytes, compression)
+}
+
+// parseBundle decompresses data if needed and parses it as a Sigstore bundle.
+//
+// The encoding is detected from data when compression is empty.
+func parseBundle(data []byte, compression Compression) (*bundle.Bundle, Compression, error) {
+	detected := compression == ""
+	if detected {
+		compression = detectCompression(data)
+	}
+
+	bundleJSON, err := decodeSnappy(data, compression)
+	if err != nil {
+		if !detected || compression != CompressionSnappyBlock {
+			return nil, compression, fmt.Errorf("failed to decompress bundle (%s): %w", compression, err)
+		}
+		// Not snappy either: fall back to the raw JSON path to surface the parse error
+		compression = CompressionNone
+		bundleJSON = data
 	}
 
-	// Try to parse as JSON bundle directly
-	// Note: GitHub may serve bundles compressed or uncompressed depending on the endpoint
 	var loadedBundle bundle.Bundle
-	if err := loadedBundle.UnmarshalJSON(bundleBytes); err != nil {
-		// If JSON parsing fails, it might be compressed - but for now we'll return the error
-		// as GitHub typically serves bundles uncompressed in API responses
-		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	if err := loadedBundle.UnmarshalJSON(bundleJSON); err != nil {
+		return nil, compression, fmt.Errorf("failed to parse bundle (compression: %s): %w", compression, err)
 	}
 
-	return &loadedBundle, nil
+	return &loadedBundle, compression, nil
 }
 
 // GetReleases fetches releases with context support.
 //
 // Only releases with tags matching the YYYY-MM-DD format are returned.
 // The opts parameter allows customization of page size and sort order.
+// If ctx has no deadline, the request is bounded by opts.RequestTimeout.
 //
 // Example:
 //
@@ -151,16 +272,12 @@ func (c *HTTPClient) GetReleases(ctx context.Context, repo Repo, opts ReleasesOp
 	// Endpoint: GET /repos/{owner}/{repo}/releases
 	url := fmt.Sprintf("%s/repos/%s/releases?per_page=%d", githubAPIBaseURL, repo.String(), opts.PageSize)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	ctx, cancel := c.requestContext(ctx, opts.RequestTimeout)
+	defer cancel()
 
-	// Set required headers
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", apiVersion)
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	req, err := c.newAPIRequest(ctx, url)
+	if err != nil {
+		return nil, err
 	}
 
 	// Execute request
@@ -182,17 +299,32 @@ func (c *HTTPClient) GetReleases(ctx context.Context, repo Repo, opts ReleasesOp
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Filter releases to only include YYYY-MM-DD format tags
+	var since time.Time
+	if opts.Since != "" {
+		since, _ = time.Parse(time.DateOnly, opts.Since) // validated by CheckAndSetDefaults
+	}
+
+	// Filter releases to only include YYYY-MM-DD format tags (newer than opts.Since if set)
 	var bundleReleases []Release
 	for _, release := range releases {
-		if isDateTag(release.TagName) {
-			bundleReleases = append(bundleReleases, release)
+		if !isDateTag(release.TagName) {
+			continue
+		}
+		tagDate, err := time.Parse(time.DateOnly, release.TagName)
+		if err != nil {
+			continue
+		}
+		if !since.IsZero() && !tagDate.After(since) {
+			continue
 		}
+		bundleReleases = append(bundleReleases, release)
 	}
 
-	// Sort releases based on sort order
+	// Sort releases newest-first by tag date, then reverse for ascending order
+	slices.SortStableFunc(bundleReleases, func(a, b Release) int {
+		return strings.Compare(b.TagName, a.TagName)
+	})
 	if opts.SortOrder == SortOrderAsc {
-		// Reverse the slice for ascending order (GitHub API returns desc by default)
 		slices.Reverse(bundleReleases)
 	}
 
@@ -215,15 +347,12 @@ func (c *HTTPClient) GetReleases(ctx context.Context, repo Repo, opts ReleasesOp
 func (c *HTTPClient) ReleaseExists(ctx context.Context, repo Repo, tag string) error {
 	url := fmt.Sprintf("%s/repos/%s/releases/tags/%s", githubAPIBaseURL, repo.String(), tag)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	ctx, cancel := c.requestContext(ctx, 0)
+	defer cancel()
 
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", apiVersion)
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	req, err := c.newAPIRequest(ctx, url)
+	if err != nil {
+		return err
 	}
 
 	resp, err := c.client.Do(req)
@@ -233,7 +362,7 @@ func (c *HTTPClient) ReleaseExists(ctx context.Context, repo Repo, tag string) e
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("release not found")
+		return ErrReleaseNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -244,29 +373,138 @@ func (c *HTTPClient) ReleaseExists(ctx context.Context, repo Repo, tag string) e
 	return nil
 }
 
-// DownloadAsset downloads a release asset to the specified destination.
+// DownloadAssetToFile downloads a release asset to the specified destination.
 //
 // The asset is identified by its name within a specific release tag.
 // The destination should be a file path where the asset will be saved.
+// The asset is written to a temporary file in the same directory, which replaces
+// destination once the download succeeds: a failed download leaves an existing
+// destination untouched.
 //
 // Example:
 //
 //	client := NewHTTPClient(nil)
-//	err := client.DownloadAsset(ctx, repo, "2025-12-03", "tpm-ca-certificates.pem", "/tmp/bundle.pem")
+//	err := client.DownloadAssetToFile(ctx, repo, "2025-12-03", "tpm-ca-certificates.pem", "/tmp/bundle.pem")
 func (c *HTTPClient) DownloadAssetToFile(ctx context.Context, repo Repo, tag, assetName, destination string) error {
-	data, err := c.DownloadReleaseAsset(ctx, repo, tag, assetName)
+	f, err := os.CreateTemp(filepath.Dir(destination), "."+filepath.Base(destination)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(f.Name()) // no-op once renamed
+
+	_, err = c.DownloadAssetToWriter(ctx, repo, tag, assetName, f)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write file: %w", closeErr)
+	}
 	if err != nil {
 		return err
 	}
 
-	// Write to destination file
-	if err := os.WriteFile(destination, data, 0644); err != nil {
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(f.Name(), destination); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
-
 	return nil
 }
 
+// DownloadAssetToWriter streams a release asset to w and returns the number of bytes written.
+//
+// The asset is identified by its name within a specific release tag. The copy is
+// bounded by [utils.DefaultMaxFileSize] and stops as soon as ctx is done, in which
+// case the returned error wraps the context error.
+//
+// If the connection drops mid-download, the download is resumed from the last received
+// byte (up to [maxDownloadAttempts] attempts in total). A Range request is sent when the
+// server advertises "Accept-Ranges: bytes"; otherwise the asset is downloaded again and
+// the bytes already written to w are skipped.
+//
+// Example:
+//
+//	client := NewHTTPClient(nil)
+//	n, err := client.DownloadAssetToWriter(ctx, repo, "2025-12-03", "tpm-ca-certificates.pem", os.Stdout)
+func (c *HTTPClient) DownloadAssetToWriter(ctx context.Context, repo Repo, tag, assetName string, w io.Writer) (int64, error) {
+	asset, err := c.releaseAsset(ctx, repo, tag, assetName)
+	if err != nil {
+		return 0, err
+	}
+
+	maxLength := int64(utils.DefaultMaxFileSize)
+	var (
+		n        int64
+		useRange bool
+	)
+	for attempt := 1; ; attempt++ {
+		body, acceptRanges, err := c.openAsset(ctx, asset.BrowserDownloadURL, n, useRange)
+		if err != nil {
+			return n, err
+		}
+		useRange = acceptRanges
+
+		written, err := io.Copy(w, io.LimitReader(&contextReader{ctx: ctx, r: body}, maxLength+1-n))
+		body.Close()
+		n += written
+
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return n, fmt.Errorf("download of %s interrupted: %w", assetName, ctxErr)
+			}
+		} else if n >= asset.Size {
+			break
+		}
+
+		// The connection dropped, or was closed before the declared size was reached
+		if attempt == maxDownloadAttempts {
+			if err != nil {
+				return n, fmt.Errorf("failed to download file: %w", err)
+			}
+			break // reported by checkSize
+		}
+	}
+
+	if n > maxLength {
+		return n, fmt.Errorf("failed to download file: %w: %s is larger than %d bytes", utils.ErrHTTPGetTooLarge, assetName, maxLength)
+	}
+	if err := asset.checkSize(n); err != nil {
+		return n, err
+	}
+
+	return n, nil
+}
+
+// openAsset requests the content of an asset starting at offset and reports whether
+// the server supports range requests.
+//
+// The request is retried on transient errors (see [utils.HttpOpenWithRetry]). The Range
+// header is only sent when useRange is set. If the server returns the whole content
+// instead, the first offset bytes are discarded.
+func (c *HTTPClient) openAsset(ctx context.Context, url string, offset int64, useRange bool) (io.ReadCloser, bool, error) {
+	header := make(http.Header)
+	if useRange && offset > 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := utils.HttpOpenWithRetry(ctx, c.client, url, header, utils.RetryConfig{})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download file: %w", err)
+	}
+	acceptRanges := resp.Header.Get("Accept-Ranges") == "bytes"
+
+	if resp.StatusCode == http.StatusPartialContent {
+		if !useRange || offset == 0 || !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			resp.Body.Close()
+			return nil, false, fmt.Errorf("failed to resume download: unexpected Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+		return resp.Body, true, nil
+	}
+	if _, err := io.CopyN(io.Discard, &contextReader{ctx: ctx, r: resp.Body}, offset); err != nil {
+		resp.Body.Close()
+		return nil, false, fmt.Errorf("failed to resume download: %w", err)
+	}
+	return resp.Body, acceptRanges, nil
+}
+
 // DownloadReleaseAsset downloads a release asset to memory.
 //
 // The asset is identified by its name within a specific release tag.
@@ -277,18 +515,32 @@ func (c *HTTPClient) DownloadAssetToFile(ctx context.Context, repo Repo, tag, as
 //	client := NewHTTPClient(nil)
 //	data, err := client.DownloadReleaseAsset(ctx, repo, "2025-12-03", "tpm-ca-certificates.pem")
 func (c *HTTPClient) DownloadReleaseAsset(ctx context.Context, repo Repo, tag, assetName string) ([]byte, error) {
-	// First, fetch the release to get the asset URL
-	url := fmt.Sprintf("%s/repos/%s/releases/tags/%s", githubAPIBaseURL, repo.String(), tag)
+	asset, err := c.releaseAsset(ctx, repo, tag, assetName)
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	data, err := utils.HttpGET(ctx, c.client, asset.BrowserDownloadURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if err := asset.checkSize(int64(len(data))); err != nil {
+		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", apiVersion)
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	return data, nil
+}
+
+// releaseAsset resolves the metadata (download URL and size) of a release asset.
+func (c *HTTPClient) releaseAsset(ctx context.Context, repo Repo, tag, assetName string) (*Asset, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/tags/%s", githubAPIBaseURL, repo.String(), tag)
+
+	ctx, cancel := c.requestContext(ctx, 0)
+	defer cancel()
+
+	req, err := c.newAPIRequest(ctx, url)
+	if err != nil {
+		return nil, err
 	}
 
 	resp, err := c.client.Do(req)
@@ -307,24 +559,26 @@ func (c *HTTPClient) DownloadReleaseAsset(ctx context.Context, repo Repo, tag, a
 		return nil, fmt.Errorf("failed to decode release: %w", err)
 	}
 
-	var assetURL string
 	for _, asset := range release.Assets {
 		if asset.Name == assetName {
-			assetURL = asset.BrowserDownloadURL
-			break
+			return &asset, nil
 		}
 	}
 
-	if assetURL == "" {
-		return nil, fmt.Errorf("asset %q not found in release %q", assetName, tag)
-	}
+	return nil, fmt.Errorf("asset %q not found in release %q", assetName, tag)
+}
 
-	data, err := utils.HttpGET(ctx, c.client, assetURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download file: %w", err)
-	}
+// contextReader stops reading as soon as its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
 
-	return data, nil
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
 }
 
 var dateTagRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
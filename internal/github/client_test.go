@@ -1,6 +1,28 @@
 package github
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
 
 func TestIsDateTag(t *testing.T) {
 	tests := []struct {
@@ -74,3 +96,728 @@ func TestIsDateTag(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRepo(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "acme/tpm-ca-certificates", want: "acme/tpm-ca-certificates"},
+		{input: "acme", wantErr: true},
+		{input: "acme/", wantErr: true},
+		{input: "/tpm-ca-certificates", wantErr: true},
+		{input: "acme/tpm/ca", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repo, err := ParseRepo(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepo(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && repo.String() != tt.want {
+				t.Errorf("ParseRepo(%q) = %s, want %s", tt.input, repo.String(), tt.want)
+			}
+		})
+	}
+}
+
+// assetHTTPClient serves a release listing a single asset whose body is served by assetBody.
+//
+// size is the asset size declared in the release metadata (unknown when zero), and status,
+// if set, returns the status code of each asset download.
+type assetHTTPClient struct {
+	assetBody func() io.ReadCloser
+	size      int64
+	status    func() int
+}
+
+func (c *assetHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, "/releases/tags/") {
+		body := fmt.Sprintf(`{"tag_name":"2025-12-03","assets":[{"name":"asset.bin","browser_download_url":"https://example.com/asset.bin","size":%d}]}`, c.size)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	status := http.StatusOK
+	if c.status != nil {
+		status = c.status()
+	}
+	return &http.Response{StatusCode: status, Body: c.assetBody(), Header: make(http.Header)}, nil
+}
+
+// cancelingWriter cancels its context after the first write.
+type cancelingWriter struct {
+	n      int
+	cancel context.CancelFunc
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	defer w.cancel()
+	w.n += len(p)
+	return len(p), nil
+}
+
+func TestDownloadAssetToWriter(t *testing.T) {
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+
+	t.Run("streams the asset", func(t *testing.T) {
+		client := NewHTTPClient(&assetHTTPClient{assetBody: func() io.ReadCloser {
+			return io.NopCloser(strings.NewReader("asset content"))
+		}})
+
+		var buf bytes.Buffer
+		n, err := client.DownloadAssetToWriter(t.Context(), repo, "2025-12-03", "asset.bin", &buf)
+		if err != nil {
+			t.Fatalf("DownloadAssetToWriter() error = %v", err)
+		}
+		if n != int64(len("asset content")) || buf.String() != "asset content" {
+			t.Errorf("DownloadAssetToWriter() wrote %d bytes %q", n, buf.String())
+		}
+	})
+
+	t.Run("cancelled mid-stream", func(t *testing.T) {
+		// The body never ends: only cancellation can stop the copy
+		pr, pw := io.Pipe()
+		t.Cleanup(func() { pw.Close() })
+		go func() {
+			chunk := bytes.Repeat([]byte("a"), 1024)
+			for {
+				if _, err := pw.Write(chunk); err != nil {
+					return
+				}
+			}
+		}()
+		client := NewHTTPClient(&assetHTTPClient{assetBody: func() io.ReadCloser { return pr }})
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		w := &cancelingWriter{cancel: cancel}
+
+		n, err := client.DownloadAssetToWriter(ctx, repo, "2025-12-03", "asset.bin", w)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("DownloadAssetToWriter() error = %v, want %v", err, context.Canceled)
+		}
+		if n == 0 {
+			t.Error("Expected some bytes to be written before cancellation")
+		}
+	})
+
+	t.Run("existing file is kept on failure", func(t *testing.T) {
+		client := NewHTTPClient(&assetHTTPClient{assetBody: func() io.ReadCloser {
+			return io.NopCloser(strings.NewReader("asset content"))
+		}})
+		dir := t.TempDir()
+		destination := filepath.Join(dir, "asset.bin")
+		if err := os.WriteFile(destination, []byte("previous content"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		if err := client.DownloadAssetToFile(ctx, repo, "2025-12-03", "asset.bin", destination); err == nil {
+			t.Fatal("Expected error with cancelled context")
+		}
+		if data, err := os.ReadFile(destination); err != nil || string(data) != "previous content" {
+			t.Errorf("Expected %s to be kept, got %q (error = %v)", destination, data, err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("Expected the temporary file to be removed, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("file is replaced on success", func(t *testing.T) {
+		client := NewHTTPClient(&assetHTTPClient{assetBody: func() io.ReadCloser {
+			return io.NopCloser(strings.NewReader("asset content"))
+		}})
+		destination := filepath.Join(t.TempDir(), "asset.bin")
+		if err := os.WriteFile(destination, []byte("previous content"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if err := client.DownloadAssetToFile(t.Context(), repo, "2025-12-03", "asset.bin", destination); err != nil {
+			t.Fatalf("DownloadAssetToFile() error = %v", err)
+		}
+		if data, err := os.ReadFile(destination); err != nil || string(data) != "asset content" {
+			t.Errorf("Expected %s to hold the asset, got %q (error = %v)", destination, data, err)
+		}
+	})
+
+	t.Run("transient errors are retried", func(t *testing.T) {
+		var attempts int
+		client := NewHTTPClient(&assetHTTPClient{
+			assetBody: func() io.ReadCloser { return io.NopCloser(strings.NewReader("asset content")) },
+			status: func() int {
+				if attempts++; attempts == 1 {
+					return http.StatusServiceUnavailable
+				}
+				return http.StatusOK
+			},
+		})
+
+		var buf bytes.Buffer
+		if _, err := client.DownloadAssetToWriter(t.Context(), repo, "2025-12-03", "asset.bin", &buf); err != nil {
+			t.Fatalf("DownloadAssetToWriter() error = %v", err)
+		}
+		if attempts != 2 || buf.String() != "asset content" {
+			t.Errorf("DownloadAssetToWriter() made %d attempts and wrote %q", attempts, buf.String())
+		}
+	})
+}
+
+// droppingReader returns the first n bytes of r then fails as a dropped connection would.
+type droppingReader struct {
+	r io.Reader
+	n int
+}
+
+func (d *droppingReader) Read(p []byte) (int, error) {
+	if d.n <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(p) > d.n {
+		p = p[:d.n]
+	}
+	n, err := d.r.Read(p)
+	d.n -= n
+	return n, err
+}
+
+// resumableHTTPClient serves content as a release asset, dropping the connection of the
+// first download request after dropAfter bytes.
+type resumableHTTPClient struct {
+	content      string
+	dropAfter    int
+	acceptRanges bool
+	ranges       []string
+}
+
+func (c *resumableHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, "/releases/tags/") {
+		body := fmt.Sprintf(`{"tag_name":"2025-12-03","assets":[{"name":"asset.bin","browser_download_url":"https://example.com/asset.bin","size":%d}]}`, len(c.content))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+
+	header := make(http.Header)
+	if c.acceptRanges {
+		header.Set("Accept-Ranges", "bytes")
+	}
+	rangeHeader := req.Header.Get("Range")
+	c.ranges = append(c.ranges, rangeHeader)
+
+	if len(c.ranges) == 1 {
+		body := &droppingReader{r: strings.NewReader(c.content), n: c.dropAfter}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: header}, nil
+	}
+	if rangeHeader != "" {
+		var start int
+		if _, err := fmt.Sscanf(rangeHeader, "bytes=%d-", &start); err != nil {
+			return nil, err
+		}
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(c.content)-1, len(c.content)))
+		return &http.Response{StatusCode: http.StatusPartialContent, Body: io.NopCloser(strings.NewReader(c.content[start:])), Header: header}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(c.content)), Header: header}, nil
+}
+
+func TestDownloadAssetToWriterResume(t *testing.T) {
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+	content := strings.Repeat("0123456789", 100)
+
+	tests := []struct {
+		name         string
+		acceptRanges bool
+		wantRanges   []string
+	}{
+		{
+			name:         "resumes with a range request",
+			acceptRanges: true,
+			wantRanges:   []string{"", "bytes=400-"},
+		},
+		{
+			name:         "downloads again when ranges are not supported",
+			acceptRanges: false,
+			wantRanges:   []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &resumableHTTPClient{content: content, dropAfter: 400, acceptRanges: tt.acceptRanges}
+			client := NewHTTPClient(httpClient)
+
+			var buf bytes.Buffer
+			n, err := client.DownloadAssetToWriter(t.Context(), repo, "2025-12-03", "asset.bin", &buf)
+			if err != nil {
+				t.Fatalf("DownloadAssetToWriter() error = %v", err)
+			}
+			if n != int64(len(content)) || buf.String() != content {
+				t.Errorf("DownloadAssetToWriter() wrote %d bytes, want the %d bytes of the asset", n, len(content))
+			}
+			if !slices.Equal(httpClient.ranges, tt.wantRanges) {
+				t.Errorf("Range headers = %q, want %q", httpClient.ranges, tt.wantRanges)
+			}
+		})
+	}
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		client := NewHTTPClient(&assetHTTPClient{assetBody: func() io.ReadCloser {
+			return io.NopCloser(&droppingReader{r: strings.NewReader(content), n: 10})
+		}})
+
+		var buf bytes.Buffer
+		if _, err := client.DownloadAssetToWriter(t.Context(), repo, "2025-12-03", "asset.bin", &buf); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("DownloadAssetToWriter() error = %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+}
+
+func TestDownloadAssetSizeMismatch(t *testing.T) {
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+	newClient := func(size int64) *HTTPClient {
+		return NewHTTPClient(&assetHTTPClient{
+			assetBody: func() io.ReadCloser { return io.NopCloser(strings.NewReader("truncated")) },
+			size:      size,
+		})
+	}
+
+	var sizeErr *AssetSizeMismatchError
+	_, err := newClient(64).DownloadReleaseAsset(t.Context(), repo, "2025-12-03", "asset.bin")
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("DownloadReleaseAsset() error = %v, want %T", err, sizeErr)
+	}
+	if sizeErr.Name != "asset.bin" || sizeErr.Expected != 64 || sizeErr.Actual != int64(len("truncated")) {
+		t.Errorf("unexpected size mismatch error: %+v", sizeErr)
+	}
+
+	var buf bytes.Buffer
+	if _, err := newClient(64).DownloadAssetToWriter(t.Context(), repo, "2025-12-03", "asset.bin", &buf); !errors.As(err, &sizeErr) {
+		t.Errorf("DownloadAssetToWriter() error = %v, want %T", err, sizeErr)
+	}
+
+	if _, err := newClient(int64(len("truncated"))).DownloadReleaseAsset(t.Context(), repo, "2025-12-03", "asset.bin"); err != nil {
+		t.Errorf("DownloadReleaseAsset() with matching size error = %v", err)
+	}
+}
+
+// snappyFramed encodes data with the snappy framing format.
+func snappyFramed(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := snappy.NewBufferedWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeSnappy(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		data := []byte(strings.Repeat("abc", 100))
+		for compression, encoded := range map[Compression][]byte{
+			CompressionSnappyBlock:  snappy.Encode(nil, data),
+			CompressionSnappyFramed: snappyFramed(t, data),
+		} {
+			got, err := decodeSnappy(encoded, compression)
+			if err != nil {
+				t.Fatalf("decodeSnappy(%s) error = %v", compression, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("decodeSnappy(%s) = %q, want %q", compression, got, data)
+			}
+		}
+	})
+
+	t.Run("decoded size cap", func(t *testing.T) {
+		// The block header declares a decoded length above the cap
+		bomb := binary.AppendUvarint(nil, maxSnappyDecodedSize+1)
+		if _, err := decodeSnappy(bomb, CompressionSnappyBlock); err == nil || !strings.Contains(err.Error(), "exceeds") {
+			t.Errorf("decodeSnappy() error = %v, want a size error", err)
+		}
+
+		framed := snappyFramed(t, make([]byte, maxSnappyDecodedSize+1))
+		if _, err := decodeSnappy(framed, CompressionSnappyFramed); err == nil || !strings.Contains(err.Error(), "exceeds") {
+			t.Errorf("decodeSnappy() error = %v, want a size error", err)
+		}
+	})
+}
+
+func TestCompressionFromContentEncoding(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    Compression
+		wantErr bool
+	}{
+		{header: "", want: ""},
+		{header: "identity", want: CompressionNone},
+		{header: "snappy", want: CompressionSnappyBlock},
+		{header: "x-snappy-framed", want: CompressionSnappyFramed},
+		{header: "gzip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := compressionFromContentEncoding(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("compressionFromContentEncoding(%q) error = %v, wantErr %v", tt.header, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("compressionFromContentEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	provenance, err := testutil.ReadTestFile(testutil.ProvenanceFile)
+	if err != nil {
+		t.Fatalf("Failed to read provenance: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		input           []byte
+		compression     Compression
+		wantCompression Compression
+		wantErr         bool
+	}{
+		{name: "raw JSON", input: provenance, wantCompression: CompressionNone},
+		{name: "snappy block", input: snappy.Encode(nil, provenance), wantCompression: CompressionSnappyBlock},
+		{name: "snappy framed", input: snappyFramed(t, provenance), wantCompression: CompressionSnappyFramed},
+		{name: "neither JSON nor snappy", input: []byte("\x00garbage"), wantCompression: CompressionNone, wantErr: true},
+		{name: "declared snappy block", input: snappy.Encode(nil, provenance), compression: CompressionSnappyBlock, wantCompression: CompressionSnappyBlock},
+		{name: "declared encoding mismatch", input: provenance, compression: CompressionSnappyFramed, wantCompression: CompressionSnappyFramed, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, compression, err := parseBundle(tt.input, tt.compression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBundle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if compression != tt.wantCompression {
+				t.Errorf("parseBundle() compression = %s, want %s", compression, tt.wantCompression)
+			}
+			if !tt.wantErr && b.Bundle == nil {
+				t.Error("Expected a parsed bundle")
+			}
+		})
+	}
+
+	t.Run("framed checksum mismatch", func(t *testing.T) {
+		framed := snappyFramed(t, provenance)
+		framed[len(snappyStreamIdentifier)+4] ^= 0xff // corrupt the chunk checksum
+		if _, _, err := parseBundle(framed, ""); err == nil {
+			t.Fatal("Expected error with corrupted checksum")
+		}
+	})
+}
+
+// releasesHTTPClient serves a fixed list of releases.
+type releasesHTTPClient struct {
+	releases []Release
+}
+
+func (c *releasesHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := json.Marshal(c.releases)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Header: make(http.Header)}, nil
+}
+
+func TestGetReleasesSince(t *testing.T) {
+	client := NewHTTPClient(&releasesHTTPClient{releases: []Release{
+		{TagName: "2025-12-03"},
+		{TagName: "v1.2.0"},
+		{TagName: "2026-02-10"},
+		{TagName: "2025-11-20"},
+		{TagName: "2026-01-15"},
+	}})
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+
+	tests := []struct {
+		name    string
+		opts    ReleasesOptions
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no filter",
+			opts: ReleasesOptions{},
+			want: []string{"2026-02-10", "2026-01-15", "2025-12-03", "2025-11-20"},
+		},
+		{
+			name: "strictly after since",
+			opts: ReleasesOptions{Since: "2025-12-03"},
+			want: []string{"2026-02-10", "2026-01-15"},
+		},
+		{
+			name: "ascending order",
+			opts: ReleasesOptions{Since: "2025-12-03", SortOrder: SortOrderAsc},
+			want: []string{"2026-01-15", "2026-02-10"},
+		},
+		{
+			name: "nothing new",
+			opts: ReleasesOptions{Since: "2026-02-10"},
+			want: nil,
+		},
+		{
+			name:    "invalid since",
+			opts:    ReleasesOptions{Since: "2026-13-01"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releases, err := client.GetReleases(t.Context(), repo, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetReleases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, release := range releases {
+				got = append(got, release.TagName)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetReleases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// attestationsHTTPClient serves a single attestation referenced by URL, whose bundle is served by
+// bundleBody with the contentEncoding header, if set.
+type attestationsHTTPClient struct {
+	bundleBody      func(req *http.Request) io.ReadCloser
+	contentEncoding string
+}
+
+func (c *attestationsHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, "/attestations/") {
+		body := `{"attestations":[{"bundle_url":"https://example.com/bundle.json"}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	}
+	header := make(http.Header)
+	if c.contentEncoding != "" {
+		header.Set("Content-Encoding", c.contentEncoding)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: c.bundleBody(req), Header: header}, nil
+}
+
+// blockingReader blocks until its context is done.
+type blockingReader struct {
+	ctx context.Context
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	<-r.ctx.Done()
+	return 0, r.ctx.Err()
+}
+
+func TestGetAttestationsBundleURLLimits(t *testing.T) {
+	provenance, err := testutil.ReadTestFile(testutil.ProvenanceFile)
+	if err != nil {
+		t.Fatalf("Failed to read provenance: %v", err)
+	}
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+	const digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+	t.Run("within limits", func(t *testing.T) {
+		client := NewHTTPClient(&attestationsHTTPClient{bundleBody: func(*http.Request) io.ReadCloser {
+			return io.NopCloser(bytes.NewReader(provenance))
+		}})
+		attestations, err := client.GetAttestations(t.Context(), repo, digest, AttestationsOptions{MaxBundleSize: int64(len(provenance))})
+		if err != nil {
+			t.Fatalf("GetAttestations() error = %v", err)
+		}
+		if len(attestations) != 1 || attestations[0].Bundle == nil {
+			t.Fatalf("expected a single loaded attestation, got %v", attestations)
+		}
+	})
+
+	t.Run("declared content encoding", func(t *testing.T) {
+		client := NewHTTPClient(&attestationsHTTPClient{
+			bundleBody: func(*http.Request) io.ReadCloser {
+				return io.NopCloser(bytes.NewReader(snappy.Encode(nil, provenance)))
+			},
+			contentEncoding: "snappy",
+		})
+		attestations, err := client.GetAttestations(t.Context(), repo, digest)
+		if err != nil {
+			t.Fatalf("GetAttestations() error = %v", err)
+		}
+		if len(attestations) != 1 || attestations[0].Compression != CompressionSnappyBlock {
+			t.Fatalf("expected a single snappy attestation, got %v", attestations)
+		}
+	})
+
+	t.Run("oversized bundle", func(t *testing.T) {
+		const maxSize = 1024
+		client := NewHTTPClient(&attestationsHTTPClient{bundleBody: func(*http.Request) io.ReadCloser {
+			return io.NopCloser(bytes.NewReader(bytes.Repeat([]byte("a"), 10*maxSize)))
+		}})
+		_, err := client.GetAttestations(t.Context(), repo, digest, AttestationsOptions{MaxBundleSize: maxSize})
+		if !errors.Is(err, utils.ErrHTTPGetTooLarge) {
+			t.Fatalf("expected ErrHTTPGetTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("default size cap", func(t *testing.T) {
+		client := NewHTTPClient(&attestationsHTTPClient{bundleBody: func(*http.Request) io.ReadCloser {
+			return io.NopCloser(bytes.NewReader(make([]byte, DefaultMaxAttestationBundleSize+1)))
+		}})
+		_, err := client.GetAttestations(t.Context(), repo, digest)
+		if !errors.Is(err, utils.ErrHTTPGetTooLarge) {
+			t.Fatalf("expected ErrHTTPGetTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := NewHTTPClient(&attestationsHTTPClient{bundleBody: func(req *http.Request) io.ReadCloser {
+			return io.NopCloser(&blockingReader{ctx: req.Context()})
+		}})
+		_, err := client.GetAttestations(t.Context(), repo, digest, AttestationsOptions{BundleTimeout: 50 * time.Millisecond})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline error, got %v", err)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		client := NewHTTPClient(&attestationsHTTPClient{})
+		if _, err := client.GetAttestations(t.Context(), repo, digest, AttestationsOptions{MaxBundleSize: -1}); err == nil {
+			t.Fatal("expected an error for a negative size")
+		}
+	})
+}
+
+// hangingHTTPClient never responds: it blocks until the request context is done.
+type hangingHTTPClient struct {
+	// deadline records the deadline of the last request context.
+	deadline time.Time
+}
+
+func (c *hangingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.deadline, _ = req.Context().Deadline()
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestRequestTimeout(t *testing.T) {
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+
+	t.Run("releases options", func(t *testing.T) {
+		client := NewHTTPClient(&hangingHTTPClient{})
+		start := time.Now()
+		_, err := client.GetReleases(context.Background(), repo, ReleasesOptions{RequestTimeout: 50 * time.Millisecond})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("GetReleases() returned after %s", elapsed)
+		}
+	})
+
+	t.Run("client timeout", func(t *testing.T) {
+		client := NewHTTPClient(&hangingHTTPClient{}).WithRequestTimeout(50 * time.Millisecond)
+		if err := client.ReleaseExists(context.Background(), repo, "2025-12-03"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline error, got %v", err)
+		}
+		_, err := client.GetAttestations(context.Background(), repo, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline error, got %v", err)
+		}
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		mock := &hangingHTTPClient{}
+		client := NewHTTPClient(mock)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel() // return at once, the deadline is still recorded
+		_ = client.ReleaseExists(ctx, repo, "2025-12-03")
+		if mock.deadline.IsZero() || mock.deadline.After(time.Now().Add(DefaultRequestTimeout)) {
+			t.Errorf("expected a deadline within %s, got %v", DefaultRequestTimeout, mock.deadline)
+		}
+	})
+
+	t.Run("explicit deadline wins", func(t *testing.T) {
+		mock := &hangingHTTPClient{}
+		client := NewHTTPClient(mock).WithRequestTimeout(time.Millisecond)
+		deadline := time.Now().Add(100 * time.Millisecond)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		_, err := client.GetReleases(ctx, repo, ReleasesOptions{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a deadline error, got %v", err)
+		}
+		if !mock.deadline.Equal(deadline) {
+			t.Errorf("request deadline = %v, want %v", mock.deadline, deadline)
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		client := NewHTTPClient(&hangingHTTPClient{})
+		if _, err := client.GetReleases(context.Background(), repo, ReleasesOptions{RequestTimeout: -time.Second}); err == nil {
+			t.Fatal("expected an error for a negative timeout")
+		}
+	})
+}
+
+// traceparentRecorder records the traceparent header of the requests served by client.
+type traceparentRecorder struct {
+	client       utils.HTTPClient
+	traceparents []string
+}
+
+func (r *traceparentRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.traceparents = append(r.traceparents, req.Header.Get("traceparent"))
+	return r.client.Do(req)
+}
+
+func TestRequestsPropagateTraceContext(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	const want = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	repo := Repo{Owner: "acme", Name: "tpm-ca-certificates"}
+
+	recorder := &traceparentRecorder{client: &assetHTTPClient{assetBody: func() io.ReadCloser {
+		return io.NopCloser(strings.NewReader("asset content"))
+	}}}
+	client := NewHTTPClient(recorder)
+	if err := client.ReleaseExists(ctx, repo, "2025-12-03"); err != nil {
+		t.Fatalf("ReleaseExists() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := client.DownloadAssetToWriter(ctx, repo, "2025-12-03", "asset.bin", &buf); err != nil {
+		t.Fatalf("DownloadAssetToWriter() error = %v", err)
+	}
+
+	// Release lookup, release metadata and asset download
+	if len(recorder.traceparents) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(recorder.traceparents))
+	}
+	for i, got := range recorder.traceparents {
+		if got != want {
+			t.Errorf("request %d traceparent header = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestNewHTTPClientDefault(t *testing.T) {
+	c := NewHTTPClient()
+	if c.client == http.DefaultClient {
+		t.Fatal("expected the default client not to be http.DefaultClient")
+	}
+	if c.client != utils.DefaultHTTPClient() {
+		t.Errorf("expected the default client to be utils.DefaultHTTPClient()")
+	}
+}
@@ -0,0 +1,95 @@
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+)
+
+// Snappy decoding for attestation bundles served from bundle_url.
+//
+// Both the block format (https://github.com/google/snappy/blob/main/format_description.txt)
+// and the framing format (https://github.com/google/snappy/blob/main/framing_format.txt)
+// are supported.
+
+// maxSnappyDecodedSize bounds the decoded size to protect against decompression bombs.
+const maxSnappyDecodedSize = 32 << 20
+
+// Compression identifies how an attestation bundle was encoded.
+type Compression string
+
+const (
+	// CompressionNone is a plain JSON bundle.
+	CompressionNone Compression = "none"
+	// CompressionSnappyBlock is a bundle compressed with the snappy block format.
+	CompressionSnappyBlock Compression = "snappy"
+	// CompressionSnappyFramed is a bundle compressed with the snappy framing format.
+	CompressionSnappyFramed Compression = "snappy-framed"
+)
+
+// snappyStreamIdentifier is the mandatory first chunk of a framed snappy stream.
+var snappyStreamIdentifier = []byte("\xff\x06\x00\x00sNaPpY")
+
+// compressionFromContentEncoding returns the encoding declared by a Content-Encoding header.
+//
+// An empty Compression is returned when the header is not set.
+func compressionFromContentEncoding(header string) (Compression, error) {
+	switch strings.ToLower(strings.TrimSpace(header)) {
+	case "":
+		return "", nil
+	case "identity":
+		return CompressionNone, nil
+	case "snappy", "x-snappy":
+		return CompressionSnappyBlock, nil
+	case "snappy-framed", "x-snappy-framed":
+		return CompressionSnappyFramed, nil
+	default:
+		return "", fmt.Errorf("unsupported content encoding %q", header)
+	}
+}
+
+// detectCompression guesses the encoding of a bundle payload from its content, for
+// servers that do not declare it in a Content-Encoding header.
+//
+// JSON payloads start with '{' (possibly after whitespace); anything else is assumed
+// to be snappy, framed when it starts with the stream identifier.
+func detectCompression(data []byte) Compression {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return CompressionNone
+	case bytes.HasPrefix(data, snappyStreamIdentifier):
+		return CompressionSnappyFramed
+	default:
+		return CompressionSnappyBlock
+	}
+}
+
+// decodeSnappy decodes data encoded with compression, up to [maxSnappyDecodedSize] bytes.
+func decodeSnappy(data []byte, compression Compression) ([]byte, error) {
+	switch compression {
+	case CompressionSnappyBlock:
+		decodedLen, err := snappy.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if decodedLen > maxSnappyDecodedSize {
+			return nil, fmt.Errorf("snappy: decoded size %d exceeds %d bytes", decodedLen, maxSnappyDecodedSize)
+		}
+		return snappy.Decode(nil, data)
+	case CompressionSnappyFramed:
+		decoded, err := io.ReadAll(io.LimitReader(snappy.NewReader(bytes.NewReader(data)), maxSnappyDecodedSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(decoded) > maxSnappyDecodedSize {
+			return nil, fmt.Errorf("snappy: decoded size exceeds %d bytes", maxSnappyDecodedSize)
+		}
+		return decoded, nil
+	default:
+		return data, nil
+	}
+}
@@ -2,8 +2,12 @@ package github
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	bundlepkg "github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/sigstore/sigstore-go/pkg/bundle"
 )
 
@@ -27,6 +31,19 @@ func (r *Repo) String() string {
 	return r.Owner + "/" + r.Name
 }
 
+// ParseRepo parses a repository reference in the "owner/name" format.
+func ParseRepo(s string) (*Repo, error) {
+	owner, name, ok := strings.Cut(s, "/")
+	if !ok || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid repository %q: expected format 'owner/name'", s)
+	}
+	repo := &Repo{Owner: owner, Name: name}
+	if err := repo.CheckAndSetDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid repository %q: %w", s, err)
+	}
+	return repo, nil
+}
+
 // Attestation represents a GitHub attestation for an artifact.
 //
 // Attestations provide provenance information about how an artifact was built,
@@ -37,6 +54,10 @@ type Attestation struct {
 
 	// BundleURL is the URL to fetch the bundle (if not embedded)
 	BundleURL string `json:"bundle_url,omitempty"`
+
+	// Compression is the encoding detected when the bundle was fetched from BundleURL.
+	// It is empty for bundles embedded in the API response.
+	Compression Compression `json:"-"`
 }
 
 // AttestationsResponse represents the response from GitHub attestations API.
@@ -51,9 +72,29 @@ type Release struct {
 	TagName     string    `json:"tag_name"`
 	Name        string    `json:"name"`
 	PublishedAt time.Time `json:"published_at"`
+	Body        string    `json:"body"`
 	Assets      []Asset   `json:"assets"`
 }
 
+// releaseCommitRegex matches the commit referenced in the bundle release notes.
+var releaseCommitRegex = regexp.MustCompile(`generated at commit \[([0-9a-f]{40})\]`)
+
+// Commit returns the commit the release was generated from, as stated in the release notes.
+//
+// An empty string is returned if the release body does not reference a commit.
+func (r *Release) Commit() string {
+	match := releaseCommitRegex.FindStringSubmatch(r.Body)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// HasAsset reports whether an asset with the given name is attached to the release.
+func (r *Release) HasAsset(name string) bool {
+	return slices.ContainsFunc(r.Assets, func(a Asset) bool { return a.Name == name })
+}
+
 // Asset represents a release asset.
 type Asset struct {
 	Name               string `json:"name"`
@@ -61,6 +102,28 @@ type Asset struct {
 	Size               int64  `json:"size"`
 }
 
+// AssetSizeMismatchError is returned when a downloaded asset does not match
+// the size declared in the release metadata.
+type AssetSizeMismatchError struct {
+	Name     string
+	Expected int64
+	Actual   int64
+}
+
+func (e *AssetSizeMismatchError) Error() string {
+	return fmt.Sprintf("asset %s size mismatch: expected %d bytes, got %d", e.Name, e.Expected, e.Actual)
+}
+
+// checkSize verifies that n matches the declared size of the asset.
+//
+// The check is skipped when the size is unknown (zero).
+func (a *Asset) checkSize(n int64) error {
+	if a.Size > 0 && n != a.Size {
+		return &AssetSizeMismatchError{Name: a.Name, Expected: a.Size, Actual: n}
+	}
+	return nil
+}
+
 // SortOrder defines the sort order for releases.
 type SortOrder string
 
@@ -79,6 +142,17 @@ type ReleasesOptions struct {
 
 	// ReturnFirstValue indicates whether to return only the first value
 	ReturnFirstValue bool
+
+	// Since keeps only releases whose tag date (YYYY-MM-DD) is strictly after this date.
+	//
+	// Optional. If empty, no date filter is applied.
+	Since string
+
+	// RequestTimeout caps the time spent on the request when the context has no deadline.
+	// An explicit context deadline always wins.
+	//
+	// Optional. Default: the client timeout ([DefaultRequestTimeout] unless changed).
+	RequestTimeout time.Duration
 }
 
 // CheckAndSetDefaults validates and sets default values for ReleasesOptions.
@@ -95,6 +169,56 @@ func (o *ReleasesOptions) CheckAndSetDefaults() error {
 	if o.SortOrder != SortOrderAsc && o.SortOrder != SortOrderDesc {
 		o.SortOrder = SortOrderDesc
 	}
+	if o.Since != "" {
+		if err := bundlepkg.ValidateDate(o.Since); err != nil {
+			return fmt.Errorf("invalid since date: %w", err)
+		}
+	}
+	if o.RequestTimeout < 0 {
+		return fmt.Errorf("invalid request timeout: %s", o.RequestTimeout)
+	}
+	return nil
+}
+
+// AttestationsOptions configures how [HTTPClient.GetAttestations] loads the bundles
+// of attestations only referenced by URL.
+type AttestationsOptions struct {
+	// MaxBundleSize is the maximum size, in bytes, of a bundle downloaded from its URL.
+	// Larger bundles are rejected with [utils.ErrHTTPGetTooLarge].
+	//
+	// Optional. Default: [DefaultMaxAttestationBundleSize].
+	MaxBundleSize int64
+
+	// BundleTimeout caps the time spent downloading a single bundle. The deadline of the
+	// context passed to [HTTPClient.GetAttestations] is honored if it expires sooner.
+	//
+	// Optional. Default: [DefaultAttestationBundleTimeout].
+	BundleTimeout time.Duration
+
+	// RequestTimeout caps the time spent on the attestations API request when the context
+	// has no deadline. An explicit context deadline always wins.
+	//
+	// Optional. Default: the client timeout ([DefaultRequestTimeout] unless changed).
+	RequestTimeout time.Duration
+}
+
+// CheckAndSetDefaults validates and sets default values for AttestationsOptions.
+func (o *AttestationsOptions) CheckAndSetDefaults() error {
+	if o.MaxBundleSize < 0 {
+		return fmt.Errorf("invalid max bundle size: %d", o.MaxBundleSize)
+	}
+	if o.MaxBundleSize == 0 {
+		o.MaxBundleSize = DefaultMaxAttestationBundleSize
+	}
+	if o.BundleTimeout < 0 {
+		return fmt.Errorf("invalid bundle timeout: %s", o.BundleTimeout)
+	}
+	if o.BundleTimeout == 0 {
+		o.BundleTimeout = DefaultAttestationBundleTimeout
+	}
+	if o.RequestTimeout < 0 {
+		return fmt.Errorf("invalid request timeout: %s", o.RequestTimeout)
+	}
 	return nil
 }
 
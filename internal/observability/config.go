@@ -5,6 +5,8 @@ import (
 	"os"
 	"slices"
 	"strconv"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 const (
@@ -46,11 +48,26 @@ type Config struct {
 	// Valid values: "always_on", "always_off", "traceidratio"
 	Sampler string
 
+	// CustomSampler is a user-provided sampler (e.g., a rate-limiting sampler)
+	// used instead of the predefined ones.
+	//
+	// Optional. Cannot be combined with Sampler or the OTEL_TRACES_SAMPLER environment variable.
+	CustomSampler sdktrace.Sampler
+
 	// Enabled enables tracing.
 	//
 	// Optional. Defaults to false (tracing disabled).
 	// Can be overridden via OTEL_ENABLED environment variable.
 	Enabled bool
+
+	// PrometheusEnabled enables the Prometheus metrics exporter.
+	//
+	// Metrics are then exposed in the Prometheus exposition format by [MetricsHandler],
+	// which the embedder mounts on its own HTTP server (e.g., at /metrics).
+	//
+	// Optional. Defaults to false (metrics disabled).
+	// Can be overridden via PROMETHEUS_ENABLED environment variable.
+	PrometheusEnabled bool
 }
 
 // CheckAndSetDefaults validates and sets default values.
@@ -65,6 +82,14 @@ func (c *Config) CheckAndSetDefaults() error {
 		c.Enabled = enabled
 	}
 
+	if enabledStr := os.Getenv("PROMETHEUS_ENABLED"); enabledStr != "" {
+		enabled, err := strconv.ParseBool(enabledStr)
+		if err != nil {
+			return fmt.Errorf("invalid PROMETHEUS_ENABLED value: %w", err)
+		}
+		c.PrometheusEnabled = enabled
+	}
+
 	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
 		c.Endpoint = endpoint
 	}
@@ -82,6 +107,12 @@ func (c *Config) CheckAndSetDefaults() error {
 	if sampler := os.Getenv("OTEL_TRACES_SAMPLER"); sampler != "" {
 		c.Sampler = sampler
 	}
+	if c.CustomSampler != nil {
+		if c.Sampler != "" {
+			return fmt.Errorf("sampler %q cannot be combined with a custom sampler", c.Sampler)
+		}
+		return nil
+	}
 	if c.Sampler == "" {
 		c.Sampler = defaultSampler
 	}
@@ -2,6 +2,8 @@ package observability
 
 import (
 	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestConfig_CheckAndSetDefaults(t *testing.T) {
@@ -98,6 +100,27 @@ func TestConfig_CheckAndSetDefaults(t *testing.T) {
 		}
 	})
 
+	t.Run("custom sampler", func(t *testing.T) {
+		cfg := Config{CustomSampler: sdktrace.AlwaysSample()}
+		if err := cfg.CheckAndSetDefaults(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Sampler != "" {
+			t.Errorf("expected no predefined sampler, got %s", cfg.Sampler)
+		}
+
+		cfg = Config{CustomSampler: sdktrace.AlwaysSample(), Sampler: AlwaysOffSample}
+		if err := cfg.CheckAndSetDefaults(); err == nil {
+			t.Error("expected error when combining Sampler with a custom sampler")
+		}
+
+		t.Setenv("OTEL_TRACES_SAMPLER", AlwaysOffSample)
+		cfg = Config{CustomSampler: sdktrace.AlwaysSample()}
+		if err := cfg.CheckAndSetDefaults(); err == nil {
+			t.Error("expected error when combining OTEL_TRACES_SAMPLER with a custom sampler")
+		}
+	})
+
 	t.Run("handles invalid OTEL_ENABLED value", func(t *testing.T) {
 		t.Setenv("OTEL_ENABLED", "not-a-bool")
 
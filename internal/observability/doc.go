@@ -1,6 +1,7 @@
-// Package observability provides OpenTelemetry tracing instrumentation for tpmtb.
+// Package observability provides OpenTelemetry tracing and metrics instrumentation for tpmtb.
 //
-// It initializes an OTLP gRPC exporter and provides helpers for creating spans.
+// It initializes an OTLP gRPC exporter and the W3C trace context propagators, and
+// provides helpers for creating spans.
 // Configuration is done via [Config] struct or environment variables.
 //
 // # Tracing is disabled by default
@@ -16,6 +17,24 @@
 //   - OTEL_SERVICE_NAME: Service name in traces (default: tpmtb)
 //   - OTEL_TRACES_SAMPLER: Sampling strategy (default: always_on)
 //     Valid values: always_on, always_off, traceidratio
+//   - PROMETHEUS_ENABLED: Enable the Prometheus metrics exporter (default: false)
+//
+// # Prometheus Metrics
+//
+// When the Prometheus exporter is enabled, metric instruments created with [Meter] are
+// served in the Prometheus exposition format by [MetricsHandler], which the embedder
+// mounts on its own HTTP server:
+//
+//	shutdown, err := observability.Initialize(ctx, observability.Config{PrometheusEnabled: true})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer observability.Shutdown(shutdown)
+//	http.Handle("/metrics", observability.MetricsHandler())
+//
+// The bundle verifier records the tpmtb.verification.count counter and the
+// tpmtb.verification.duration histogram (in milliseconds), split by phase
+// (cosign, rekor when the inclusion proof is required, attestation) and outcome (success, failure).
 //
 // # Example
 //
@@ -2,6 +2,7 @@ package observability
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
@@ -25,6 +27,9 @@ var (
 	// NoOpShutdownFunc is a no-op shutdown function that does nothing.
 	NoOpShutdownFunc = func(context.Context) error { return nil }
 
+	// providersMu guards globalTracerProvider, globalMeterProvider and metricsHandler.
+	providersMu sync.RWMutex
+
 	// globalTracerProvider holds the configured tracer provider.
 	// It's either a real TracerProvider or noop.NewTracerProvider().
 	globalTracerProvider trace.TracerProvider = noop.NewTracerProvider()
@@ -41,14 +46,19 @@ var (
 
 // Initialize sets up OpenTelemetry tracing with OTLP gRPC exporter.
 //
-// It configures a [sdktrace.TracerProvider] and registers it globally. Returns a shutdown
+// It configures a [sdktrace.TracerProvider] and registers it globally, along with the W3C
+// trace context and baggage propagators used on outbound HTTP requests. Returns a shutdown
 // function that MUST be called before program exit to ensure all spans are exported.
 //
 // This function is thread-safe and can only be called once. Subsequent calls will return
 // the result of the first call (either success or error).
 //
-// If cfg.Enabled is false (default), this function returns immediately with a no-op
-// shutdown function, allowing the application to run without any tracing overhead.
+// If cfg.PrometheusEnabled is true, a Prometheus metrics exporter is set up as well and
+// its metrics are served by [MetricsHandler].
+//
+// If both cfg.Enabled and cfg.PrometheusEnabled are false (default), this function returns
+// immediately with a no-op shutdown function, allowing the application to run without any
+// observability overhead.
 //
 // If the OTLP endpoint is unreachable or initialization fails,
 // this function returns an error. The caller should handle this gracefully (e.g., log
@@ -76,10 +86,50 @@ func initialize(ctx context.Context, cfg Config) (ShutdownFunc, error) {
 		return nil, fmt.Errorf("invalid observability config: %w", err)
 	}
 
-	if !cfg.Enabled {
+	if !cfg.Enabled && !cfg.PrometheusEnabled {
 		return NoOpShutdownFunc, nil
 	}
 
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	var shutdowns []ShutdownFunc
+	if cfg.PrometheusEnabled {
+		shutdown, err := enablePrometheus(res)
+		if err != nil {
+			return nil, err
+		}
+		shutdowns = append(shutdowns, shutdown)
+	}
+	if cfg.Enabled {
+		shutdown, err := initTracing(ctx, cfg, res)
+		if err != nil {
+			return nil, errors.Join(err, shutdownAll(shutdowns)(ctx))
+		}
+		shutdowns = append(shutdowns, shutdown)
+	}
+
+	return shutdownAll(shutdowns), nil
+}
+
+// shutdownAll returns a [ShutdownFunc] calling every shutdown function.
+func shutdownAll(shutdowns []ShutdownFunc) ShutdownFunc {
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, shutdown := range shutdowns {
+			errs = append(errs, shutdown(ctx))
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func initTracing(ctx context.Context, cfg Config, res *resource.Resource) (ShutdownFunc, error) {
 	defaultOpts := []otlptracegrpc.Option{
 		otlptracegrpc.WithEndpoint(cfg.Endpoint),
 		otlptracegrpc.WithInsecure(),
@@ -94,30 +144,36 @@ func initialize(ctx context.Context, cfg Config) (ShutdownFunc, error) {
 		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
 	}
 
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(cfg.ServiceName),
-		),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
-	}
-
 	// Create TracerProvider with batching for performance
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(res),
-		sdktrace.WithSampler(createSampler(cfg.Sampler)),
-	)
+	tp := newTracerProvider(cfg, res, sdktrace.WithBatcher(exporter))
 
-	globalTracerProvider = tp
+	setTracerProvider(tp)
 
 	// Register globally so library code can use otel.GetTracerProvider()
 	otel.SetTracerProvider(tp)
 
+	// Propagate W3C trace context and baggage on outbound HTTP requests
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
 	return tp.Shutdown, nil
 }
 
+// newTracerProvider creates a [sdktrace.TracerProvider] sampling spans according to cfg.
+func newTracerProvider(cfg Config, res *resource.Resource, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	sampler := cfg.CustomSampler
+	if sampler == nil {
+		sampler = createSampler(cfg.Sampler)
+	}
+	opts = append(opts,
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
 func createSampler(samplerType string) sdktrace.Sampler {
 	switch samplerType {
 	case AlwaysOffSample:
@@ -132,5 +188,14 @@ func createSampler(samplerType string) sdktrace.Sampler {
 
 // Tracer returns a tracer for creating spans.
 func Tracer() trace.Tracer {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
 	return globalTracerProvider.Tracer(tracerName)
 }
+
+// setTracerProvider replaces the tracer provider used by [Tracer].
+func setTracerProvider(tp trace.TracerProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	globalTracerProvider = tp
+}
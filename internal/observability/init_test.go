@@ -3,8 +3,12 @@ package observability
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace/noop"
 )
 
@@ -75,6 +79,49 @@ func TestCreateSampler(t *testing.T) {
 	}
 }
 
+// dropSampler drops the spans named "dropped" and counts its calls.
+type dropSampler struct {
+	calls atomic.Int32
+}
+
+func (s *dropSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.calls.Add(1)
+	if p.Name == "dropped" {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop}
+	}
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample}
+}
+
+func (s *dropSampler) Description() string {
+	return "dropSampler"
+}
+
+func TestNewTracerProvider_CustomSampler(t *testing.T) {
+	sampler := &dropSampler{}
+	cfg := Config{Enabled: true, CustomSampler: sampler}
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := newTracerProvider(cfg, resource.Empty(), sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	tracer := tp.Tracer(tracerName)
+	for _, name := range []string{"kept", "dropped"} {
+		_, span := tracer.Start(context.Background(), name)
+		span.End()
+	}
+
+	if got := sampler.calls.Load(); got != 2 {
+		t.Errorf("expected custom sampler to be called twice, got %d", got)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "kept" {
+		t.Errorf("expected only the kept span to be exported, got %v", spans.Snapshots())
+	}
+}
+
 func TestTracer(t *testing.T) {
 	// Tracer should always return a valid tracer (no-op or real)
 	tracer := Tracer()
@@ -126,7 +173,7 @@ func TestNoopMode_TracerReturnsNoopSpan(t *testing.T) {
 // resetInitState resets the initialization state for testing Initialize().
 // This should only be used in tests that test Initialize() itself.
 func resetInitState() {
-	globalTracerProvider = noop.NewTracerProvider()
+	setTracerProvider(noop.NewTracerProvider())
 	initOnce = sync.Once{}
 	initErr = nil
 	initShutdown = nil
@@ -142,10 +189,10 @@ func resetInitState() {
 //	// Now Tracer() returns a noop tracer
 func setTracerProviderForTest() func() {
 	originalProvider := globalTracerProvider
-	globalTracerProvider = noop.NewTracerProvider()
+	setTracerProvider(noop.NewTracerProvider())
 
 	return func() {
-		globalTracerProvider = originalProvider
+		setTracerProvider(originalProvider)
 	}
 }
 
@@ -0,0 +1,124 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+var (
+	// globalMeterProvider holds the configured meter provider.
+	// It's either a real MeterProvider or metricnoop.NewMeterProvider().
+	globalMeterProvider metric.MeterProvider = metricnoop.NewMeterProvider()
+
+	// metricsHandler serves the metrics in the Prometheus exposition format.
+	// It responds with 404 until the Prometheus exporter is enabled.
+	metricsHandler http.Handler = http.NotFoundHandler()
+
+	// prometheusOnce ensures the Prometheus exporter is only set up once.
+	prometheusOnce sync.Once
+
+	// prometheusErr stores any error from the Prometheus exporter setup.
+	prometheusErr error
+)
+
+// Meter returns a meter for creating metric instruments.
+//
+// The same meter is returned until the meter provider changes, so that callers can create
+// their instruments once per meter.
+func Meter() metric.Meter {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return globalMeterProvider.Meter(tracerName)
+}
+
+// setMeterProvider replaces the meter provider used by [Meter] and the handler served by
+// [MetricsHandler].
+func setMeterProvider(mp metric.MeterProvider, handler http.Handler) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	globalMeterProvider = mp
+	metricsHandler = handler
+}
+
+// MetricsHandler returns an [http.Handler] serving the metrics in the Prometheus
+// exposition format, meant to be mounted at /metrics by the embedder.
+//
+// The handler responds with 404 Not Found until the Prometheus exporter is
+// enabled with [EnablePrometheus], or with [Config.PrometheusEnabled] (or PROMETHEUS_ENABLED)
+// when calling [Initialize].
+//
+// Example:
+//
+//	http.Handle("/metrics", observability.MetricsHandler())
+func MetricsHandler() http.Handler {
+	// Resolved on each request, so that the handler can be mounted before the exporter is enabled
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providersMu.RLock()
+		handler := metricsHandler
+		providersMu.RUnlock()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// EnablePrometheus sets up the Prometheus metrics exporter served by [MetricsHandler],
+// for embedders that do not call [Initialize]. The returned shutdown function flushes
+// and stops the exporter.
+//
+// The exporter is only set up once: later calls (or calls to [Initialize] with
+// [Config.PrometheusEnabled]) return a no-op shutdown function and the setup error, if any.
+func EnablePrometheus(ctx context.Context) (ShutdownFunc, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(defaultServiceName),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	return enablePrometheus(res)
+}
+
+// enablePrometheus calls [initPrometheus] on first use.
+func enablePrometheus(res *resource.Resource) (ShutdownFunc, error) {
+	shutdown := NoOpShutdownFunc
+	prometheusOnce.Do(func() {
+		shutdown, prometheusErr = initPrometheus(res)
+	})
+	if prometheusErr != nil {
+		return nil, prometheusErr
+	}
+	return shutdown, nil
+}
+
+func initPrometheus(res *resource.Resource) (ShutdownFunc, error) {
+	// A dedicated registry avoids leaking metrics to (or from) prometheus.DefaultRegisterer
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus exporter: %w", err)
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(res),
+	)
+
+	setMeterProvider(mp, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	// Register globally so library code can use otel.GetMeterProvider()
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, nil
+}
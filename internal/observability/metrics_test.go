@@ -0,0 +1,121 @@
+package observability
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+// resetMetricsState restores the metrics globals once the test completes.
+func resetMetricsState(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		setMeterProvider(metricnoop.NewMeterProvider(), http.NotFoundHandler())
+		prometheusOnce = sync.Once{}
+		prometheusErr = nil
+	})
+}
+
+func scrape(t *testing.T) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics response: %v", err)
+	}
+	return rec.Code, string(body)
+}
+
+func TestMetricsHandler_Disabled(t *testing.T) {
+	resetMetricsState(t)
+
+	if code, _ := scrape(t); code != http.StatusNotFound {
+		t.Errorf("expected status %d when Prometheus is disabled, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestMetricsHandler_Prometheus(t *testing.T) {
+	resetMetricsState(t)
+	ctx := context.Background()
+
+	shutdown, err := initialize(ctx, Config{PrometheusEnabled: true})
+	if err != nil {
+		t.Fatalf("initialize() error = %v", err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			t.Errorf("shutdown should not return error: %v", err)
+		}
+	}()
+
+	counter, err := Meter().Int64Counter("tpmtb.test.requests")
+	if err != nil {
+		t.Fatalf("failed to create counter: %v", err)
+	}
+	counter.Add(ctx, 3)
+
+	code, body := scrape(t)
+	if code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+	}
+	for _, want := range []string{
+		"# TYPE tpmtb_test_requests_total counter",
+		"tpmtb_test_requests_total{",
+		"} 3",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestEnablePrometheus(t *testing.T) {
+	resetMetricsState(t)
+	ctx := context.Background()
+
+	shutdown, err := EnablePrometheus(ctx)
+	if err != nil {
+		t.Fatalf("EnablePrometheus() error = %v", err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			t.Errorf("shutdown should not return error: %v", err)
+		}
+	}()
+	provider := globalMeterProvider
+
+	// Later calls keep the exporter in place
+	if _, err := EnablePrometheus(ctx); err != nil {
+		t.Fatalf("EnablePrometheus() error = %v", err)
+	}
+	if globalMeterProvider != provider {
+		t.Error("expected the exporter to be set up once")
+	}
+	if code, _ := scrape(t); code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, code)
+	}
+}
+
+func TestConfig_PrometheusEnabledFromEnv(t *testing.T) {
+	t.Setenv("PROMETHEUS_ENABLED", "true")
+
+	cfg := Config{}
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.PrometheusEnabled {
+		t.Error("expected PrometheusEnabled to be true from env var")
+	}
+
+	t.Setenv("PROMETHEUS_ENABLED", "not-a-bool")
+	if err := cfg.CheckAndSetDefaults(); err == nil {
+		t.Error("expected error for invalid PROMETHEUS_ENABLED value")
+	}
+}
@@ -2,24 +2,71 @@ package observability
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// DefaultShutdownTimeout is the default deadline given to [Shutdown] to flush pending telemetry.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// ErrShutdownTimeout is returned by [Shutdown] when the shutdown function did not complete in time.
+var ErrShutdownTimeout = errors.New("observability shutdown timed out")
+
+// ShutdownOption configures [Shutdown].
+type ShutdownOption func(*shutdownOptions)
+
+// shutdownOptions holds the inputs collected by [ShutdownOption] functions.
+type shutdownOptions struct {
+	timeout time.Duration
+}
+
+// WithShutdownTimeout sets the deadline given to the shutdown function.
+//
+// Non-positive values are ignored and [DefaultShutdownTimeout] is used.
+func WithShutdownTimeout(timeout time.Duration) ShutdownOption {
+	return func(o *shutdownOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
 // Shutdown gracefully shuts down the tracing provider with a timeout.
 //
-// This wrapper ensures spans are flushed before exit and provides a
-// sensible timeout to prevent hanging on shutdown.
+// This wrapper ensures spans are flushed before exit and bounds the time spent doing so:
+// a stuck collector never blocks the process termination for longer than the timeout
+// (see [WithShutdownTimeout]). [ErrShutdownTimeout] is returned when the deadline is hit.
 //
 // Example:
 //
 //	defer observability.Shutdown(shutdown)
-func Shutdown(shutdownFunc ShutdownFunc) error {
+func Shutdown(shutdownFunc ShutdownFunc, opts ...ShutdownOption) error {
 	if shutdownFunc == nil {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	o := shutdownOptions{timeout: DefaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
 	defer cancel()
 
-	return shutdownFunc(ctx)
+	// The shutdown function may not honor the context deadline
+	done := make(chan error, 1)
+	go func() {
+		done <- shutdownFunc(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s: %w", ErrShutdownTimeout, o.timeout, err)
+		}
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w after %s", ErrShutdownTimeout, o.timeout)
+	}
 }
@@ -0,0 +1,51 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("nil shutdown function", func(t *testing.T) {
+		if err := Shutdown(nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("returns the shutdown error", func(t *testing.T) {
+		want := errors.New("exporter failure")
+		err := Shutdown(func(context.Context) error { return want })
+		if !errors.Is(err, want) {
+			t.Errorf("expected %v, got %v", want, err)
+		}
+	})
+
+	t.Run("shutdown blocking past the timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		start := time.Now()
+		err := Shutdown(func(context.Context) error {
+			<-release // ignores the context deadline
+			return nil
+		}, WithShutdownTimeout(50*time.Millisecond))
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Fatalf("expected %v, got %v", ErrShutdownTimeout, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected Shutdown to return after the timeout, took %s", elapsed)
+		}
+	})
+
+	t.Run("shutdown honoring the deadline", func(t *testing.T) {
+		err := Shutdown(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, WithShutdownTimeout(50*time.Millisecond))
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Errorf("expected %v, got %v", ErrShutdownTimeout, err)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -44,6 +45,37 @@ func GenerateTestCertExpired(t *testing.T) ([]byte, string) {
 	return generateTestCertWithExpiry(t, expiryDate)
 }
 
+// GenerateTestCertWithCN generates a self-signed test certificate with the given subject common name.
+func GenerateTestCertWithCN(t testing.TB, commonName string) ([]byte, string) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := newTestCertTemplate(time.Now().Add(365 * 24 * time.Hour))
+	template.Subject.CommonName = commonName
+
+	return createSelfSignedCert(t, template, &priv.PublicKey, priv)
+}
+
+// GenerateTestCertRSA generates a self-signed RSA test certificate using the
+// given key size and signature algorithm (e.g. [x509.SHA1WithRSA]).
+func GenerateTestCertRSA(t *testing.T, bits int, sigAlg x509.SignatureAlgorithm) ([]byte, string) {
+	t.Helper()
+
+	priv, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := newTestCertTemplate(time.Now().Add(365 * 24 * time.Hour))
+	template.SignatureAlgorithm = sigAlg
+
+	return createSelfSignedCert(t, template, &priv.PublicKey, priv)
+}
+
 func generateTestCertWithExpiry(t *testing.T, notAfter time.Time) ([]byte, string) {
 	t.Helper()
 
@@ -52,7 +84,11 @@ func generateTestCertWithExpiry(t *testing.T, notAfter time.Time) ([]byte, strin
 		t.Fatal(err)
 	}
 
-	template := &x509.Certificate{
+	return createSelfSignedCert(t, newTestCertTemplate(notAfter), &priv.PublicKey, priv)
+}
+
+func newTestCertTemplate(notAfter time.Time) *x509.Certificate {
+	return &x509.Certificate{
 		SerialNumber: big.NewInt(1),
 		Subject: pkix.Name{
 			Organization: []string{"Test Org"},
@@ -63,8 +99,12 @@ func generateTestCertWithExpiry(t *testing.T, notAfter time.Time) ([]byte, strin
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
 	}
+}
+
+func createSelfSignedCert(t testing.TB, template *x509.Certificate, pub, priv any) ([]byte, string) {
+	t.Helper()
 
-	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, pub, priv)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -6,6 +6,7 @@ import (
 	"embed"
 	"encoding/json"
 	"io/fs"
+	"strings"
 	"sync"
 	"testing"
 
@@ -164,3 +165,31 @@ func CreateCacheDir(t *testing.T, configData []byte) string {
 
 	return tmpDir
 }
+
+// CorruptRootBundleCertificate returns the test root bundle with the n-th (0-based)
+// certificate replaced by undecodable DER, along with the line number (1-based) of
+// the corrupted block BEGIN marker.
+func CorruptRootBundleCertificate(t *testing.T, n int) ([]byte, int) {
+	t.Helper()
+
+	data, err := ReadTestFile(RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "-----BEGIN CERTIFICATE-----") {
+			continue
+		}
+		if n > 0 {
+			n--
+			continue
+		}
+		// Valid base64 decoding to zero bytes, which is not a valid certificate
+		lines[i+1] = strings.Repeat("A", len(lines[i+1]))
+		return []byte(strings.Join(lines, "\n")), i + 1
+	}
+	t.Fatalf("test bundle has fewer certificates than requested")
+	return nil, 0
+}
@@ -2,14 +2,35 @@ package cosign
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
 )
 
+var (
+	// ErrChecksumEntryMissing is returned when the checksums file has no entry for the
+	// artifact, e.g. the entry was stripped from the file.
+	ErrChecksumEntryMissing = errors.New("artifact not found in checksums data")
+
+	// ErrChecksumMismatch is returned when the checksums entry of the artifact doesn't
+	// match its digest.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrMalformedChecksums is returned when the checksums file cannot be parsed, e.g. a line
+	// is not a "<sha256-hex>  <filename>" entry or a filename is listed twice.
+	ErrMalformedChecksums = errors.New("malformed checksums file")
+)
+
 // ValidateChecksum verifies that the artifact's checksum matches the one in the checksums.
+//
+// Returns [ErrMalformedChecksums] if the checksums cannot be parsed (see [ParseChecksums]),
+// [ErrChecksumEntryMissing] if the artifact is not listed, and [ErrChecksumMismatch]
+// if its entry doesn't match the artifact digest.
 func ValidateChecksum(checksumData, artifactData []byte, artifactName string) error {
 	expectedChecksum, err := parseChecksumFile(checksumData, artifactName)
 	if err != nil {
@@ -18,8 +39,8 @@ func ValidateChecksum(checksumData, artifactData []byte, artifactName string) er
 
 	actualChecksum := computeDataSHA256(artifactData)
 	if actualChecksum != expectedChecksum {
-		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s",
-			artifactName, expectedChecksum, actualChecksum)
+		return fmt.Errorf("%w for %s: expected %s, got %s",
+			ErrChecksumMismatch, artifactName, expectedChecksum, actualChecksum)
 	}
 
 	return nil
@@ -27,38 +48,54 @@ func ValidateChecksum(checksumData, artifactData []byte, artifactName string) er
 
 // parseChecksumFile parses checksums data and extracts the checksum for the specified artifact.
 //
-// The file format is:
-//
-//	<sha256-hex>  <filename>
-//	<sha256-hex>  <filename>
+// The file format is described in [ParseChecksums].
 func parseChecksumFile(data []byte, artifactName string) (string, error) {
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	checksums, err := ParseChecksums(data)
+	if err != nil {
+		return "", err
+	}
+	checksum, ok := checksums[artifactName]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrChecksumEntryMissing, artifactName)
+	}
+	return checksum, nil
+}
+
+// ParseChecksums parses checksums data into a map of filename to lowercase SHA-256 hex digest.
+//
+// The file format is one "<sha256-hex>  <filename>" entry per line; the separator may be
+// any run of spaces or tabs. Empty lines are skipped. A malformed line or a filename listed
+// twice is an error wrapping [ErrMalformedChecksums].
+func ParseChecksums(data []byte) (map[string]string, error) {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
 	for scanner.Scan() {
+		lineNum++
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" {
 			continue
 		}
 
-		// Parse line: "<checksum>  <filename>"
-		// Note: checksums.txt typically uses two spaces between checksum and filename
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
+		// The line is trimmed: a separator is always followed by the filename
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("%w: line %d: malformed checksum entry %q: expected '<sha256-hex>  <filename>'", ErrMalformedChecksums, lineNum, line)
 		}
-
-		checksum := parts[0]
-		filename := parts[1]
-
-		if filename == artifactName {
-			return strings.ToLower(checksum), nil
+		checksum, filename := line[:i], strings.TrimLeft(line[i:], " \t")
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("%w: line %d: invalid SHA-256 checksum %q", ErrMalformedChecksums, lineNum, checksum)
 		}
+		if _, exists := checksums[filename]; exists {
+			return nil, fmt.Errorf("%w: line %d: duplicate checksum entry for %s", ErrMalformedChecksums, lineNum, filename)
+		}
+		checksums[filename] = strings.ToLower(checksum)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error reading checksums data: %w", err)
+		return nil, fmt.Errorf("error reading checksums data: %w", err)
 	}
-
-	return "", fmt.Errorf("artifact %s not found in checksums data", artifactName)
+	return checksums, nil
 }
 
 // computeDataSHA256 computes the SHA-256 checksum of data.
@@ -15,14 +15,28 @@ import (
 // that the artifact's checksum matches the one in the checksums file.
 //
 // It performs the following steps:
+//  1. Parses the checksums file to extract the expected checksum for the artifact
+//  2. Computes the actual checksum of the artifact and compares it (see [ValidateChecksum])
+//  3. Verifies the signature of the checksums file (see [VerifyChecksumSignature])
+//
+// Returns the verification result which contains certificate extensions with commit information.
+func VerifyChecksum(ctx context.Context, policyCfg policy.Config, verifierCfg verifier.Config, checksumData, signatureData, artifactData []byte, artifactName string) (*verify.VerificationResult, error) {
+	if err := ValidateChecksum(checksumData, artifactData, artifactName); err != nil {
+		return nil, fmt.Errorf("checksum validation failed: %w", err)
+	}
+	return VerifyChecksumSignature(ctx, policyCfg, verifierCfg, checksumData, signatureData)
+}
+
+// VerifyChecksumSignature verifies the Cosign signature of a checksum file, without
+// checking its entries.
+//
+// It performs the following steps:
 //  1. Loads the Sigstore bundle from the signature file
 //  2. Verifies the signature using the keyless Cosign workflow (SCT + transparency log + observer timestamps)
 //  3. Validates the certificate identity (OIDC issuer, workflow path) using shared policy
-//  4. Parses the checksums file to extract the expected checksum for the artifact
-//  5. Computes the actual checksum of the artifact and compares it
 //
 // Returns the verification result which contains certificate extensions with commit information.
-func VerifyChecksum(ctx context.Context, policyCfg policy.Config, verifierCfg verifier.Config, checksumData, signatureData, artifactData []byte, artifactName string) (*verify.VerificationResult, error) {
+func VerifyChecksumSignature(ctx context.Context, policyCfg policy.Config, verifierCfg verifier.Config, checksumData, signatureData []byte) (*verify.VerificationResult, error) {
 	var b bundle.Bundle
 	if err := b.UnmarshalJSON(signatureData); err != nil {
 		return nil, fmt.Errorf("failed to load signature bundle: %w", err)
@@ -43,10 +57,5 @@ func VerifyChecksum(ctx context.Context, policyCfg policy.Config, verifierCfg ve
 		return nil, fmt.Errorf("signature verification failed: %w", err)
 	}
 
-	// Now verify that the artifact's checksum matches the one in the checksums file
-	if err := ValidateChecksum(checksumData, artifactData, artifactName); err != nil {
-		return nil, fmt.Errorf("checksum validation failed: %w", err)
-	}
-
 	return result, nil
 }
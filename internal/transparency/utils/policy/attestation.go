@@ -12,7 +12,7 @@ import (
 //
 // This function builds a PolicyBuilder that validates:
 //   - Artifact digest matches the provided digest
-//   - Certificate identity (via BuildCertificateIdentity)
+//   - Certificate identity (via buildCertificateIdentities)
 //
 // The digest must be in the format "algorithm:hex" (e.g., "sha256:abc123...").
 //
@@ -45,13 +45,13 @@ func BuildAttestationPolicy(digest string, cfg Config) (verify.PolicyBuilder, er
 	artifactDigestOpt := verify.WithArtifactDigest(digestAlg, digestBytes)
 
 	// Build certificate identity policy
-	certID, err := buildCertificateIdentity(cfg)
+	certIDs, err := buildCertificateIdentities(cfg)
 	if err != nil {
 		return verify.PolicyBuilder{}, err
 	}
 
-	// Build policy - combine artifact digest and certificate identity
-	policy := verify.NewPolicy(artifactDigestOpt, verify.WithCertificateIdentity(certID))
+	// Build policy - combine artifact digest and certificate identities
+	policy := verify.NewPolicy(artifactDigestOpt, withCertificateIdentities(certIDs)...)
 
 	return policy, nil
 }
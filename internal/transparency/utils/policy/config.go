@@ -2,10 +2,14 @@ package policy
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 )
 
+// DefaultOIDCIssuer is the OIDC issuer of GitHub Actions on github.com.
+const DefaultOIDCIssuer = "https://token.actions.githubusercontent.com"
+
 // Config contains the common criteria for verification (GitHub Attestation and Cosign).
 //
 // It centralizes the security policy for both verification methods to ensure
@@ -16,10 +20,20 @@ type Config struct {
 	// Required.
 	SourceRepo *github.Repo
 
-	// OIDCIssuer is the expected OIDC issuer URL
-	// Default: https://token.actions.githubusercontent.com
+	// OIDCIssuer is an accepted OIDC issuer URL, added to AllowedOIDCIssuers if missing.
+	//
+	// Default: the first of AllowedOIDCIssuers if set, [DefaultOIDCIssuer] otherwise
 	OIDCIssuer string
 
+	// AllowedOIDCIssuers are the accepted OIDC issuer URLs: the policy passes if the
+	// certificate issuer is one of them, e.g. both issuers during a migration.
+	//
+	// The certificates must still be issued to a workflow of SourceRepo on github.com
+	// (see [Config.BuildSANRegex]).
+	//
+	// Default: [OIDCIssuer]
+	AllowedOIDCIssuers []string
+
 	// PredicateType is the expected attestation predicate type (GitHub Attestation only)
 	//
 	// Default: https://slsa.dev/provenance/v1
@@ -55,9 +69,20 @@ func (c *Config) CheckAndSetDefaults() error {
 		return fmt.Errorf("invalid input: 'Tag' is required")
 	}
 
+	// An empty issuer would match any issuer
+	if slices.Contains(c.AllowedOIDCIssuers, "") {
+		return fmt.Errorf("invalid input: 'AllowedOIDCIssuers' cannot contain an empty issuer")
+	}
+
 	// Set defaults
 	if c.OIDCIssuer == "" {
-		c.OIDCIssuer = "https://token.actions.githubusercontent.com"
+		c.OIDCIssuer = DefaultOIDCIssuer
+		if len(c.AllowedOIDCIssuers) > 0 {
+			c.OIDCIssuer = c.AllowedOIDCIssuers[0]
+		}
+	}
+	if !slices.Contains(c.AllowedOIDCIssuers, c.OIDCIssuer) {
+		c.AllowedOIDCIssuers = append(slices.Clone(c.AllowedOIDCIssuers), c.OIDCIssuer)
 	}
 
 	if c.PredicateType == "" {
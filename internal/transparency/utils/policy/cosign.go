@@ -11,7 +11,7 @@ import (
 //
 // This function builds a Policy that validates:
 //   - Artifact content (via io.Reader)
-//   - Certificate identity (via BuildCertificateIdentity)
+//   - Certificate identity (via buildCertificateIdentities)
 //
 // The artifact reader should provide access to the file being verified (e.g., checksums.txt).
 //
@@ -38,13 +38,13 @@ func BuildCosignPolicy(artifact io.Reader, cfg Config) (verify.PolicyBuilder, er
 	artifactPolicy := verify.WithArtifact(artifact)
 
 	// Build certificate identity policy
-	certID, err := buildCertificateIdentity(cfg)
+	certIDs, err := buildCertificateIdentities(cfg)
 	if err != nil {
 		return verify.PolicyBuilder{}, err
 	}
 
-	// Build policy - combine artifact and certificate identity
-	policy := verify.NewPolicy(artifactPolicy, verify.WithCertificateIdentity(certID))
+	// Build policy - combine artifact and certificate identities
+	policy := verify.NewPolicy(artifactPolicy, withCertificateIdentities(certIDs)...)
 
 	return policy, nil
 }
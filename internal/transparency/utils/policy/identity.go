@@ -7,31 +7,27 @@ import (
 	"github.com/sigstore/sigstore-go/pkg/verify"
 )
 
-// buildCertificateIdentity creates a certificate identity policy for GitHub Actions OIDC.
+// buildCertificateIdentities creates the certificate identity policies for GitHub Actions OIDC,
+// one per allowed OIDC issuer (see [Config.AllowedOIDCIssuers]).
 //
-// This function builds a CertificateIdentity that validates:
+// Each CertificateIdentity validates:
 //   - Subject Alternative Name (SAN) matches the GitHub repository pattern
-//   - OIDC Issuer matches GitHub's token service
+//   - OIDC Issuer matches the allowed issuer
 //   - Build workflow URI matches the expected workflow path and tag
 //   - Source repository URI matches the expected repository
 //
 // The certificate identity is used by both GitHub Attestation and Cosign verification
-// to ensure that signatures come from the expected GitHub Actions workflow.
-func buildCertificateIdentity(cfg Config) (verify.CertificateIdentity, error) {
+// to ensure that signatures come from the expected GitHub Actions workflow: a signature
+// passes if it matches any of the identities.
+func buildCertificateIdentities(cfg Config) ([]verify.CertificateIdentity, error) {
 	if err := cfg.CheckAndSetDefaults(); err != nil {
-		return verify.CertificateIdentity{}, fmt.Errorf("invalid config: %w", err)
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	// Build SAN matcher - matches the repository pattern
 	sanMatcher, err := verify.NewSANMatcher("", cfg.BuildSANRegex())
 	if err != nil {
-		return verify.CertificateIdentity{}, fmt.Errorf("failed to create SAN matcher: %w", err)
-	}
-
-	// Build issuer matcher - exact match for GitHub's OIDC token service
-	issuerMatcher, err := verify.NewIssuerMatcher(cfg.OIDCIssuer, "")
-	if err != nil {
-		return verify.CertificateIdentity{}, fmt.Errorf("failed to create issuer matcher: %w", err)
+		return nil, fmt.Errorf("failed to create SAN matcher: %w", err)
 	}
 
 	// Build certificate extensions - validates the workflow and repository URIs
@@ -44,11 +40,30 @@ func buildCertificateIdentity(cfg Config) (verify.CertificateIdentity, error) {
 		SourceRepositoryURI: cfg.BuildSignerRepoURL(),
 	}
 
-	// Create certificate identity combining all matchers and extensions
-	certID, err := verify.NewCertificateIdentity(sanMatcher, issuerMatcher, extensions)
-	if err != nil {
-		return verify.CertificateIdentity{}, fmt.Errorf("failed to create certificate identity: %w", err)
+	certIDs := make([]verify.CertificateIdentity, 0, len(cfg.AllowedOIDCIssuers))
+	for _, issuer := range cfg.AllowedOIDCIssuers {
+		// Build issuer matcher - exact match for the OIDC token service
+		issuerMatcher, err := verify.NewIssuerMatcher(issuer, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create issuer matcher: %w", err)
+		}
+
+		// Create certificate identity combining all matchers and extensions
+		certID, err := verify.NewCertificateIdentity(sanMatcher, issuerMatcher, extensions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create certificate identity: %w", err)
+		}
+		certIDs = append(certIDs, certID)
 	}
 
-	return certID, nil
+	return certIDs, nil
+}
+
+// withCertificateIdentities returns the policy options requiring any of the identities.
+func withCertificateIdentities(certIDs []verify.CertificateIdentity) []verify.PolicyOption {
+	opts := make([]verify.PolicyOption, 0, len(certIDs))
+	for _, certID := range certIDs {
+		opts = append(opts, verify.WithCertificateIdentity(certID))
+	}
+	return opts
 }
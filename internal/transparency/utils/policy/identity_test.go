@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+func TestBuildCertificateIdentitiesAllowedOIDCIssuers(t *testing.T) {
+	const alternateIssuer = "https://token.actions.example.com"
+
+	newConfig := func(issuers ...string) Config {
+		return Config{
+			SourceRepo:         &github.Repo{Owner: "loicsikidi", Name: "tpm-ca-certificates"},
+			AllowedOIDCIssuers: issuers,
+			BuildWorkflow:      github.ReleaseBundleWorkflowPath,
+			Tag:                "2025-12-05",
+		}
+	}
+	// summary returns the identity of a certificate issued to the release workflow by issuer
+	summary := func(cfg Config, issuer string) certificate.Summary {
+		return certificate.Summary{
+			SubjectAlternativeName: cfg.BuildFullWorkflowURI(),
+			Extensions: certificate.Extensions{
+				Issuer:              issuer,
+				BuildSignerURI:      cfg.BuildFullWorkflowURI(),
+				SourceRepositoryURI: cfg.BuildSignerRepoURL(),
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		issuers  []string
+		issuer   string
+		accepted bool
+	}{
+		{"default issuer", nil, DefaultOIDCIssuer, true},
+		{"alternate issuer not configured", nil, alternateIssuer, false},
+		{"alternate issuer configured", []string{alternateIssuer}, alternateIssuer, true},
+		{"default issuer replaced", []string{alternateIssuer}, DefaultOIDCIssuer, false},
+		{"both issuers during a migration", []string{DefaultOIDCIssuer, alternateIssuer}, alternateIssuer, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig(tt.issuers...)
+			certIDs, err := buildCertificateIdentities(cfg)
+			if err != nil {
+				t.Fatalf("buildCertificateIdentities() error = %v", err)
+			}
+
+			_, err = verify.CertificateIdentities(certIDs).Verify(summary(cfg, tt.issuer))
+			if tt.accepted && err != nil {
+				t.Errorf("expected issuer %s to be accepted, got %v", tt.issuer, err)
+			}
+			if !tt.accepted && err == nil {
+				t.Errorf("expected issuer %s to be rejected", tt.issuer)
+			}
+		})
+	}
+
+	if _, err := buildCertificateIdentities(newConfig("")); err == nil {
+		t.Error("expected an empty issuer, which would match any issuer, to be rejected")
+	}
+}
+
+func TestConfigCheckAndSetDefaultsOIDCIssuer(t *testing.T) {
+	cfg := Config{
+		SourceRepo:    &github.Repo{Owner: "loicsikidi", Name: "tpm-ca-certificates"},
+		BuildWorkflow: github.ReleaseBundleWorkflowPath,
+		Tag:           "2025-12-05",
+	}
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		t.Fatalf("CheckAndSetDefaults() error = %v", err)
+	}
+	if cfg.OIDCIssuer != DefaultOIDCIssuer || len(cfg.AllowedOIDCIssuers) != 1 || cfg.AllowedOIDCIssuers[0] != DefaultOIDCIssuer {
+		t.Errorf("expected the default issuer only, got %q and %v", cfg.OIDCIssuer, cfg.AllowedOIDCIssuers)
+	}
+}
+
+func TestConfigCheckAndSetDefaultsFoldsOIDCIssuer(t *testing.T) {
+	const alternateIssuer = "https://token.actions.example.com"
+	cfg := Config{
+		SourceRepo:         &github.Repo{Owner: "loicsikidi", Name: "tpm-ca-certificates"},
+		OIDCIssuer:         alternateIssuer,
+		AllowedOIDCIssuers: []string{DefaultOIDCIssuer},
+		BuildWorkflow:      github.ReleaseBundleWorkflowPath,
+		Tag:                "2025-12-05",
+	}
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		t.Fatalf("CheckAndSetDefaults() error = %v", err)
+	}
+	if want := []string{DefaultOIDCIssuer, alternateIssuer}; !slices.Equal(cfg.AllowedOIDCIssuers, want) {
+		t.Errorf("AllowedOIDCIssuers = %v, want %v", cfg.AllowedOIDCIssuers, want)
+	}
+}
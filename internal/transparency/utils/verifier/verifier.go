@@ -1,7 +1,9 @@
 package verifier
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"sync"
 
@@ -67,15 +69,27 @@ func NewDefaultRoot(optionalClient ...utils.HTTPClient) (root.TrustedMaterial, e
 
 // GetDefaultTUFOptions returns TUF options with sane defaults for Sigstore usage.
 func GetDefaultTUFOptions(optionalClient ...utils.HTTPClient) *tuf.Options {
+	return getTUFOptions(tuf.DefaultMirror, nil, optionalClient...)
+}
+
+// getTUFOptions returns TUF options fetching metadata from the TUF repository at repositoryURL.
+//
+// initialRoot bootstraps trust in the repository when its root.json is not cached yet.
+// If empty, the root of Sigstore's public-good TUF repository is used.
+func getTUFOptions(repositoryURL string, initialRoot []byte, optionalClient ...utils.HTTPClient) *tuf.Options {
 	client := utils.OptionalArg(optionalClient)
 	opts := tuf.DefaultOptions()
+	opts.RepositoryBaseURL = repositoryURL
+	if len(initialRoot) > 0 {
+		opts.Root = initialRoot
+	}
 
 	// Store TUF cache in a directory owned by tpmtb for better isolation
 	opts.CachePath = filepath.Join(cache.CacheDir(), ".sigstore", "root")
 
 	// Attempt to load the trusted root from the local cache if it exists
 	// Note: it can happen that the `root.json` included in `tuf` package is outdated
-	rootPath := filepath.Join(opts.CachePath, tuf.URLToPath("https://tuf-repo-cdn.sigstore.dev"), "root.json")
+	rootPath := filepath.Join(opts.CachePath, tuf.URLToPath(repositoryURL), "root.json")
 	if utils.FileExists(rootPath) {
 		if b, err := utils.ReadFile(rootPath); err == nil {
 			opts.Root = b
@@ -98,6 +112,70 @@ func GetDefaultTUFOptions(optionalClient ...utils.HTTPClient) *tuf.Options {
 	return opts
 }
 
+// TUFMirror is a TUF repository serving the Sigstore trusted root in place of
+// Sigstore's public-good repository, such as an internal mirror.
+type TUFMirror struct {
+	// URL is the base URL of the TUF repository.
+	//
+	// Required. Must be an absolute HTTPS URL without query nor fragment.
+	URL string `json:"url"`
+
+	// Root is the initial root.json of the TUF repository, used to bootstrap trust
+	// in its metadata until a newer root is cached.
+	//
+	// Optional. Default: the root of Sigstore's public-good TUF repository, which
+	// suits mirrors serving its metadata as is.
+	Root []byte `json:"root,omitempty"`
+}
+
+// CheckAndSetDefaults validates the mirror configuration.
+func (m *TUFMirror) CheckAndSetDefaults() error {
+	if m.URL == "" {
+		return fmt.Errorf("mirror URL cannot be empty")
+	}
+	u, err := url.Parse(m.URL)
+	if err != nil {
+		return fmt.Errorf("invalid mirror URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid mirror URL %q: must be an absolute HTTPS URL", m.URL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("invalid mirror URL %q: must not contain credentials, query nor fragment", m.URL)
+	}
+	if len(m.Root) > 0 && !json.Valid(m.Root) {
+		return fmt.Errorf("invalid mirror root: not a JSON document")
+	}
+	return nil
+}
+
+// NewMirrorRoot fetches the trusted root from the TUF repository of mirror.
+//
+// Unlike [NewDefaultRoot], the result is not shared across calls. When disableLocalCache
+// is set, the TUF metadata is neither read from nor written to the local cache.
+//
+// Example:
+//
+//	trustedRoot, err := verifier.NewMirrorRoot(verifier.TUFMirror{
+//	    URL: "https://tuf.internal.example.com",
+//	}, false)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewMirrorRoot(mirror TUFMirror, disableLocalCache bool, optionalClient ...utils.HTTPClient) (root.TrustedMaterial, error) {
+	if err := mirror.CheckAndSetDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid TUF mirror: %w", err)
+	}
+
+	opts := getTUFOptions(mirror.URL, mirror.Root, optionalClient...)
+	opts.DisableLocalCache = disableLocalCache
+	trustedRoot, err := root.FetchTrustedRootWithOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trusted root from %s: %w", mirror.URL, err)
+	}
+	return trustedRoot, nil
+}
+
 // FetchTrustedRoot fetches the Sigstore trusted_root.json from TUF and returns it as a compact JSON buffer.
 //
 // This is useful for offline verification scenarios where the trusted root needs to be cached locally.
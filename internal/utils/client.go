@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultHTTPTimeout is the total time limit of a request sent by a client returned by
+	// [NewHTTPClient], including reading the response body.
+	DefaultHTTPTimeout = 30 * time.Second
+
+	// maxHTTPRedirects is the maximum number of redirects followed by a client returned by [NewHTTPClient].
+	maxHTTPRedirects = 10
+)
+
+// ErrInsecureRedirect is returned when a client returned by [NewHTTPClient] is redirected
+// from HTTPS to HTTP.
+var ErrInsecureRedirect = errors.New("redirect from HTTPS to HTTP rejected")
+
+// defaultHTTPClient is the client used in place of [http.DefaultClient], see [DefaultHTTPClient].
+var defaultHTTPClient = NewHTTPClient()
+
+// DefaultHTTPClient returns the shared client built by [NewHTTPClient], used when no client is provided.
+func DefaultHTTPClient() *http.Client {
+	return defaultHTTPClient
+}
+
+// NewHTTPClient returns an [http.Client] with production defaults.
+//
+// Unlike [http.DefaultClient], the client:
+//   - aborts requests after [DefaultHTTPTimeout];
+//   - keeps a bounded number of idle connections;
+//   - rejects redirects from HTTPS to HTTP with [ErrInsecureRedirect], and stops after 10 redirects;
+//   - sends the default User-Agent (see [UserAgent]) unless a request sets its own.
+//
+// The options are applied before the User-Agent is set up, so they may replace the transport.
+func NewHTTPClient(opts ...func(*http.Client)) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = 10
+	transport.MaxIdleConnsPerHost = 2
+	transport.IdleConnTimeout = 90 * time.Second
+
+	client := &http.Client{
+		Transport:     transport,
+		Timeout:       DefaultHTTPTimeout,
+		CheckRedirect: checkRedirect,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	client.Transport = &userAgentTransport{base: client.Transport}
+	return client
+}
+
+// checkRedirect is the redirect policy of the clients returned by [NewHTTPClient].
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxHTTPRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+	}
+	if previous := via[len(via)-1].URL; previous.Scheme == "https" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: %s redirects to %s", ErrInsecureRedirect, previous, req.URL)
+	}
+	return nil
+}
+
+// userAgentTransport sets the default User-Agent on requests which do not set one.
+type userAgentTransport struct {
+	base http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		// A RoundTripper must not modify the request it is given
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", UserAgent())
+	}
+	return t.base.RoundTrip(req)
+}
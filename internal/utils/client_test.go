@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	client := DefaultHTTPClient()
+	if client == http.DefaultClient {
+		t.Fatal("expected the default client not to be http.DefaultClient")
+	}
+	if client.Timeout != DefaultHTTPTimeout {
+		t.Errorf("Timeout = %s, want %s", client.Timeout, DefaultHTTPTimeout)
+	}
+
+	previous := &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}}
+	downgrade := &http.Request{URL: &url.URL{Scheme: "http", Host: "example.com"}}
+	if err := client.CheckRedirect(downgrade, []*http.Request{previous}); !errors.Is(err, ErrInsecureRedirect) {
+		t.Errorf("CheckRedirect() error = %v, want %v", err, ErrInsecureRedirect)
+	}
+
+	if client := NewHTTPClient(func(c *http.Client) { c.Timeout = 0 }); client.Timeout != 0 {
+		t.Errorf("Timeout = %s, want 0", client.Timeout)
+	}
+}
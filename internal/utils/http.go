@@ -5,12 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"slices"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 var (
@@ -20,6 +23,9 @@ var (
 
 const (
 	maxRetries = 4 // Total attempts: 1 initial + 3 retries
+
+	// DefaultMaxElapsedTime caps the total time spent retrying a single request.
+	DefaultMaxElapsedTime = 30 * time.Second
 )
 
 // DefaultBackoffConfig holds the default exponential backoff configuration for HTTP retries.
@@ -28,110 +34,311 @@ var DefaultBackoffConfig = &backoff.ExponentialBackOff{
 	InitialInterval:     100 * time.Millisecond,
 	MaxInterval:         500 * time.Millisecond,
 	Multiplier:          2.0, // Double the interval each retry
-	RandomizationFactor: 0.5, // Default randomization factor (±50%)
+	RandomizationFactor: 0.5, // Default randomization factor (±50%), only used when jitter is disabled
 }
 
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-func HttpGET(ctx context.Context, client HTTPClient, url string, optionalMaxLength ...int64) ([]byte, error) {
-	maxLength := OptionalArgWithDefault(optionalMaxLength, DefaultMaxFileSize)
-	c := client
-	if c == nil {
-		c = http.DefaultClient
+// RetryConfig configures how [HttpGETWithRetry] retries transient failures.
+type RetryConfig struct {
+	// MaxTries is the maximum number of attempts, including the initial one.
+	//
+	// Optional. Default: 4.
+	MaxTries uint
+	// MaxElapsedTime caps the total time spent retrying. Once the next wait would
+	// exceed it, retries stop even if the context deadline is further out.
+	//
+	// Optional. Default: [DefaultMaxElapsedTime].
+	MaxElapsedTime time.Duration
+	// DisableJitter turns off full jitter.
+	//
+	// By default, each wait is drawn uniformly from [0, interval] so that many
+	// clients failing at the same time do not retry in lockstep. When disabled,
+	// the interval is randomized using [DefaultBackoffConfig] RandomizationFactor.
+	//
+	// Optional. Default: false.
+	DisableJitter bool
+	// RetryableStatusCodes lists the HTTP status codes that are retried. A "Retry-After"
+	// header on such a response overrides the backoff interval.
+	//
+	// Optional. Default: 429 (Too Many Requests) and every 5xx status code.
+	RetryableStatusCodes []int
+}
+
+// CheckAndSetDefaults validates the configuration and sets default values.
+func (c *RetryConfig) CheckAndSetDefaults() error {
+	if c.MaxTries == 0 {
+		c.MaxTries = maxRetries
+	}
+	if c.MaxElapsedTime == 0 {
+		c.MaxElapsedTime = DefaultMaxElapsedTime
+	}
+	if c.MaxElapsedTime < 0 {
+		return fmt.Errorf("invalid max elapsed time: %s", c.MaxElapsedTime)
+	}
+	for _, code := range c.RetryableStatusCodes {
+		if code < 400 || code > 599 {
+			return fmt.Errorf("invalid retryable status code: %d", code)
+		}
 	}
+	return nil
+}
 
+// isRetryable reports whether a response with the given status code is retried.
+func (c *RetryConfig) isRetryable(statusCode int) bool {
+	if len(c.RetryableStatusCodes) == 0 {
+		return statusCode == http.StatusTooManyRequests || (statusCode >= 500 && statusCode < 600)
+	}
+	return slices.Contains(c.RetryableStatusCodes, statusCode)
+}
+
+// newBackOff builds the backoff strategy described by the configuration.
+func (c *RetryConfig) newBackOff() backoff.BackOff {
 	expBackoff := &backoff.ExponentialBackOff{
 		InitialInterval:     DefaultBackoffConfig.InitialInterval,
 		MaxInterval:         DefaultBackoffConfig.MaxInterval,
 		Multiplier:          DefaultBackoffConfig.Multiplier,
 		RandomizationFactor: DefaultBackoffConfig.RandomizationFactor,
 	}
+	if c.DisableJitter {
+		return expBackoff
+	}
 
-	operation := func() ([]byte, error) {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, backoff.Permanent(err)
-		}
+	expBackoff.RandomizationFactor = 0
+	return &fullJitterBackOff{BackOff: expBackoff}
+}
 
-		res, err := c.Do(req)
-		if err != nil {
-			return nil, backoff.Permanent(err)
-		}
+// fullJitterBackOff draws each wait uniformly from [0, d] where d is the
+// interval returned by the wrapped backoff.
+type fullJitterBackOff struct {
+	backoff.BackOff
+}
 
-		if res.StatusCode >= 500 && res.StatusCode < 600 {
-			res.Body.Close()
-			return nil, fmt.Errorf("failed to download from %s: HTTP %d", url, res.StatusCode)
+func (b *fullJitterBackOff) NextBackOff() time.Duration {
+	d := b.BackOff.NextBackOff()
+	if d <= 0 {
+		return d
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
+
+// deadlineBackOff stops retrying as soon as the next wait would end past the context deadline.
+//
+// Sleeping until the deadline is pointless: the request would fail with a context error
+// hiding the HTTP error that triggered the retry.
+type deadlineBackOff struct {
+	backoff.BackOff
+	ctx context.Context
+	// exceeded is true once a retry was skipped because of the deadline.
+	exceeded bool
+}
+
+func (b *deadlineBackOff) NextBackOff() time.Duration {
+	d := b.BackOff.NextBackOff()
+	if d == backoff.Stop {
+		return d
+	}
+	if deadline, ok := b.ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		b.exceeded = true
+		return backoff.Stop
+	}
+	return d
+}
+
+// retryableStatusError is returned for a response whose status code is retried.
+type retryableStatusError struct {
+	url        string
+	statusCode int
+	// retryAfter is set when the response carries a valid "Retry-After" header.
+	retryAfter *backoff.RetryAfterError
+}
+
+func (e *retryableStatusError) Error() string {
+	return fmt.Sprintf("failed to download from %s: HTTP %d", e.url, e.statusCode)
+}
+
+// Unwrap exposes the "Retry-After" delay to [backoff.Retry].
+func (e *retryableStatusError) Unwrap() error {
+	if e.retryAfter == nil {
+		return nil
+	}
+	return e.retryAfter
+}
+
+// parseRetryAfter parses a "Retry-After" header, either in seconds or as an HTTP date.
+//
+// It returns false when the header is missing or invalid.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
 		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(date), 0), true
+}
 
+// HttpGET performs a GET request using the default retry configuration.
+//
+// See [HttpGETWithRetry] for details.
+func HttpGET(ctx context.Context, client HTTPClient, url string, optionalMaxLength ...int64) ([]byte, error) {
+	return HttpGETWithRetry(ctx, client, url, RetryConfig{}, optionalMaxLength...)
+}
+
+// HttpGETWithRetry performs a GET request, retrying 429 and 5xx responses (or the
+// status codes of retryCfg) according to retryCfg.
+//
+// A "Retry-After" header on a retried response is honored. Network errors and other
+// status codes are not retried. Retries stop early when the next wait would exceed
+// the context deadline, returning the last HTTP error.
+func HttpGETWithRetry(ctx context.Context, client HTTPClient, url string, retryCfg RetryConfig, optionalMaxLength ...int64) ([]byte, error) {
+	maxLength := OptionalArgWithDefault(optionalMaxLength, DefaultMaxFileSize)
+	return getWithRetry(ctx, client, url, nil, retryCfg, func(res *http.Response) ([]byte, error) {
+		defer res.Body.Close()
 		if res.StatusCode != http.StatusOK {
-			res.Body.Close()
 			err := fmt.Errorf("failed to download from %s: HTTP %d", url, res.StatusCode)
-			return nil, backoff.Permanent(fmt.Errorf("%w: %v", ErrHTTPGetError, err))
+			return nil, fmt.Errorf("%w: %v", ErrHTTPGetError, err)
 		}
 
 		// Process successful response
-		var length int64
 		if header := res.Header.Get("Content-Length"); header != "" {
-			length, err = strconv.ParseInt(header, 10, 0)
+			length, err := strconv.ParseInt(header, 10, 0)
 			if err != nil {
-				res.Body.Close()
-				return nil, backoff.Permanent(err)
+				return nil, err
 			}
 			if length > maxLength {
-				res.Body.Close()
 				err := fmt.Errorf("download failed for %s, length %d is larger than expected %d", url, length, maxLength)
-				return nil, backoff.Permanent(fmt.Errorf("%w: %v", ErrHTTPGetTooLarge, err))
+				return nil, fmt.Errorf("%w: %v", ErrHTTPGetTooLarge, err)
 			}
 		}
 
 		// Although the size has been checked above, use a LimitReader in case
 		// the reported size is inaccurate.
 		data, err := io.ReadAll(io.LimitReader(res.Body, maxLength+1))
-		res.Body.Close()
 		if err != nil {
-			return nil, backoff.Permanent(err)
+			return nil, err
 		}
 
-		length = int64(len(data))
-		if int64(length) > maxLength {
+		if length := int64(len(data)); length > maxLength {
 			err := fmt.Errorf("download failed for %s, length %d is larger than expected %d", url, length, maxLength)
-			return nil, backoff.Permanent(fmt.Errorf("%w: %v", ErrHTTPGetTooLarge, err))
+			return nil, fmt.Errorf("%w: %v", ErrHTTPGetTooLarge, err)
 		}
 		return data, nil
+	})
+}
+
+// HttpOpenWithRetry performs a GET request with the retry behavior of [HttpGETWithRetry],
+// but returns the response instead of reading its body so that it can be streamed.
+//
+// header is added to the request (e.g. a "Range" header). Only 200 (OK) and 206 (Partial
+// Content) responses are returned; the caller must close their body.
+func HttpOpenWithRetry(ctx context.Context, client HTTPClient, url string, header http.Header, retryCfg RetryConfig) (*http.Response, error) {
+	return getWithRetry(ctx, client, url, header, retryCfg, func(res *http.Response) (*http.Response, error) {
+		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+			res.Body.Close()
+			err := fmt.Errorf("failed to download from %s: HTTP %d", url, res.StatusCode)
+			return nil, fmt.Errorf("%w: %v", ErrHTTPGetError, err)
+		}
+		return res, nil
+	})
+}
+
+// getWithRetry performs a GET request, retrying the responses whose status code is
+// retryable according to retryCfg, and hands the first other response to handle.
+//
+// handle owns the response body. Its errors are not retried.
+func getWithRetry[T any](ctx context.Context, client HTTPClient, url string, header http.Header, retryCfg RetryConfig, handle func(*http.Response) (T, error)) (T, error) {
+	var zero T
+	if err := retryCfg.CheckAndSetDefaults(); err != nil {
+		return zero, fmt.Errorf("invalid retry configuration: %w", err)
+	}
+	c := client
+	if c == nil {
+		c = DefaultHTTPClient()
+	}
+
+	b := &deadlineBackOff{BackOff: retryCfg.newBackOff(), ctx: ctx}
+	operation := func() (T, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return zero, backoff.Permanent(err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		// Propagate the active trace (W3C traceparent/baggage) to the remote server.
+		// This is a no-op unless a propagator is registered (see observability.Initialize).
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		req.Header.Set("User-Agent", UserAgent())
+
+		res, err := c.Do(req)
+		if err != nil {
+			return zero, backoff.Permanent(err)
+		}
+
+		if retryCfg.isRetryable(res.StatusCode) {
+			res.Body.Close()
+			err := &retryableStatusError{url: url, statusCode: res.StatusCode}
+			if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+					b.exceeded = true
+					return zero, backoff.Permanent(err)
+				}
+				err.retryAfter = &backoff.RetryAfterError{Duration: d}
+			}
+			return zero, err
+		}
+
+		result, err := handle(res)
+		if err != nil {
+			return zero, backoff.Permanent(err)
+		}
+		return result, nil
 	}
 
-	data, err := backoff.Retry(ctx, operation, backoff.WithBackOff(expBackoff), backoff.WithMaxTries(maxRetries))
+	result, err := backoff.Retry(ctx, operation,
+		backoff.WithBackOff(b),
+		backoff.WithMaxTries(retryCfg.MaxTries),
+		backoff.WithMaxElapsedTime(retryCfg.MaxElapsedTime),
+	)
 	if err != nil {
 		// backoff.Retry automatically unwraps permanent errors
 		// So errors here are either:
 		// 1. Already unwrapped permanent errors (client errors, ErrHTTPGetError, ErrHTTPGetTooLarge)
 		// 2. Context errors (canceled, deadline exceeded)
-		// 3. Retryable errors that exhausted max retries (5xx server errors)
+		// 3. Retryable errors that exhausted max retries or max elapsed time (429 and 5xx by default)
 
 		// Return context errors directly
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			return nil, err
+			return zero, err
 		}
 
 		// Return errors already wrapped with sentinel errors
 		if errors.Is(err, ErrHTTPGetError) || errors.Is(err, ErrHTTPGetTooLarge) {
-			return nil, err
+			return zero, err
 		}
 
-		// Check if this is a retryable 5xx error that exhausted retries
-		// These need to be wrapped with ErrHTTPGetError
-		errMsg := err.Error()
-		for code := 500; code < 600; code++ {
-			if strings.Contains(errMsg, "HTTP "+strconv.Itoa(code)) {
-				return nil, fmt.Errorf("%w: %v", ErrHTTPGetError, err)
+		// Retryable errors that exhausted retries need to be wrapped with ErrHTTPGetError
+		var statusErr *retryableStatusError
+		if errors.As(err, &statusErr) {
+			if b.exceeded {
+				return zero, fmt.Errorf("%w: %v (next retry would exceed the context deadline)", ErrHTTPGetError, statusErr)
 			}
+			return zero, fmt.Errorf("%w: %v", ErrHTTPGetError, statusErr)
 		}
 
 		// All other errors (client, network, etc.) return as-is
-		return nil, err
+		return zero, err
 	}
 
-	return data, nil
+	return result, nil
 }
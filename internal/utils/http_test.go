@@ -9,6 +9,11 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type mockHTTPClient struct {
@@ -70,6 +75,16 @@ func (m *mockHTTPClientWithAttempts) Do(req *http.Request) (*http.Response, erro
 	return m.responses[idx], err
 }
 
+// headerCapturingHTTPClient records the headers of the last request it served.
+type headerCapturingHTTPClient struct {
+	header http.Header
+}
+
+func (m *headerCapturingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.header = req.Header.Clone()
+	return makeResponse(http.StatusOK, "ok", nil), nil
+}
+
 func makeResponse(statusCode int, body string, headers map[string]string) *http.Response {
 	resp := &http.Response{
 		StatusCode: statusCode,
@@ -477,33 +492,40 @@ func TestHttpGET(t *testing.T) {
 		}
 	})
 
-	t.Run("context timeout during retry backoff", func(t *testing.T) {
-		// Create a context with a timeout shorter than the total retry duration
-		// With 3 retries and exponential backoff (100ms, 200ms, 400ms), total would be ~700ms
-		// Set timeout to 50ms to ensure it times out during first backoff
+	t.Run("backoff exceeding context deadline returns the HTTP error", func(t *testing.T) {
+		originalRandomization := DefaultBackoffConfig.RandomizationFactor
+		defer func() { DefaultBackoffConfig.RandomizationFactor = originalRandomization }()
+		DefaultBackoffConfig.RandomizationFactor = 0
+
+		// The first backoff (100ms) does not fit within the 50ms deadline
 		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 		defer cancel()
 
 		client := &mockHTTPClientWithAttempts{
 			responses: []*http.Response{
-				makeResponse(http.StatusGatewayTimeout, "", nil),
-				makeResponse(http.StatusGatewayTimeout, "", nil),
+				makeResponse(http.StatusServiceUnavailable, "", nil),
 				makeResponse(http.StatusOK, "success", nil),
 			},
 		}
 
-		_, err := HttpGET(ctx, client, "http://example.com/test")
+		start := time.Now()
+		_, err := HttpGETWithRetry(ctx, client, "http://example.com/test", RetryConfig{DisableJitter: true})
+		elapsed := time.Since(start)
+
 		if err == nil {
-			t.Fatal("HttpGET() error = nil, want error for timeout during retry")
+			t.Fatal("HttpGETWithRetry() error = nil, want error when the backoff exceeds the deadline")
 		}
-
-		if !errors.Is(err, context.DeadlineExceeded) {
-			t.Errorf("HttpGET() error = %v, want context.DeadlineExceeded", err)
+		if !strings.Contains(err.Error(), "HTTP 503") || !errors.Is(err, ErrHTTPGetError) {
+			t.Errorf("HttpGETWithRetry() error = %v, want the HTTP 503 error", err)
 		}
-
-		// Should have attempted at least once before timeout
-		if client.attempt < 1 {
-			t.Errorf("Expected at least 1 attempt, got %d", client.attempt)
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("HttpGETWithRetry() error = %v, should not be a context deadline error", err)
+		}
+		if elapsed > 40*time.Millisecond {
+			t.Errorf("HttpGETWithRetry() took %v, expected to return without sleeping", elapsed)
+		}
+		if client.attempt != 1 {
+			t.Errorf("Expected 1 attempt, got %d", client.attempt)
 		}
 	})
 
@@ -527,7 +549,7 @@ func TestHttpGET(t *testing.T) {
 		}
 
 		start := time.Now()
-		_, err := HttpGET(context.Background(), client, "http://example.com/test")
+		_, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", RetryConfig{DisableJitter: true})
 		elapsed := time.Since(start)
 
 		if err != nil {
@@ -552,3 +574,362 @@ func TestHttpGET(t *testing.T) {
 		}
 	})
 }
+
+func TestHttpGETWithRetry(t *testing.T) {
+	t.Run("full jitter never exceeds the exponential intervals", func(t *testing.T) {
+		client := &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusGatewayTimeout, "", nil),
+				makeResponse(http.StatusInternalServerError, "", nil),
+				makeResponse(http.StatusServiceUnavailable, "", nil),
+				makeResponse(http.StatusOK, "success", nil),
+			},
+		}
+
+		start := time.Now()
+		_, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", RetryConfig{})
+		elapsed := time.Since(start)
+
+		if err != nil {
+			t.Fatalf("HttpGETWithRetry() error = %v, want nil after retries", err)
+		}
+
+		// Full jitter draws each wait from [0, interval], so the total is at most 100ms + 200ms + 400ms
+		expectedMax := 1000 * time.Millisecond
+		if elapsed > expectedMax {
+			t.Errorf("HttpGETWithRetry() took too long: %v, expected at most %v", elapsed, expectedMax)
+		}
+
+		if client.attempt != 4 {
+			t.Errorf("Expected 4 attempts, got %d", client.attempt)
+		}
+	})
+
+	t.Run("stops retrying at max elapsed time", func(t *testing.T) {
+		originalInterval := DefaultBackoffConfig.MaxInterval
+		originalRandomization := DefaultBackoffConfig.RandomizationFactor
+		defer func() {
+			DefaultBackoffConfig.MaxInterval = originalInterval
+			DefaultBackoffConfig.RandomizationFactor = originalRandomization
+		}()
+
+		// Constant 100ms interval for predictable timing
+		DefaultBackoffConfig.MaxInterval = DefaultBackoffConfig.InitialInterval
+		DefaultBackoffConfig.RandomizationFactor = 0
+
+		client := &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusServiceUnavailable, "", nil),
+			},
+		}
+
+		// The context deadline is far beyond MaxElapsedTime
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		cfg := RetryConfig{
+			MaxTries:       100,
+			MaxElapsedTime: 350 * time.Millisecond,
+			DisableJitter:  true,
+		}
+
+		start := time.Now()
+		_, err := HttpGETWithRetry(ctx, client, "http://example.com/test", cfg)
+		elapsed := time.Since(start)
+
+		if err == nil {
+			t.Fatal("HttpGETWithRetry() error = nil, want error after max elapsed time")
+		}
+		if !errors.Is(err, ErrHTTPGetError) {
+			t.Errorf("HttpGETWithRetry() error should wrap ErrHTTPGetError, got %v", err)
+		}
+
+		// Attempts at 0ms, 100ms, 200ms and 300ms; the next wait would end past 350ms
+		expectedMin := 300 * time.Millisecond
+		expectedMax := 600 * time.Millisecond
+		if elapsed < expectedMin {
+			t.Errorf("HttpGETWithRetry() completed too quickly: %v, expected at least %v", elapsed, expectedMin)
+		}
+		if elapsed > expectedMax {
+			t.Errorf("HttpGETWithRetry() took too long: %v, expected at most %v", elapsed, expectedMax)
+		}
+
+		if client.attempt != 4 {
+			t.Errorf("Expected 4 attempts, got %d", client.attempt)
+		}
+	})
+
+	t.Run("retries 429 honoring Retry-After", func(t *testing.T) {
+		client := &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusTooManyRequests, "", map[string]string{"Retry-After": "1"}),
+				makeResponse(http.StatusOK, "success", nil),
+			},
+		}
+
+		start := time.Now()
+		data, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", RetryConfig{})
+		elapsed := time.Since(start)
+
+		if err != nil {
+			t.Fatalf("HttpGETWithRetry() error = %v, want nil after retry", err)
+		}
+		if string(data) != "success" {
+			t.Errorf("HttpGETWithRetry() = %q, want %q", data, "success")
+		}
+		if client.attempt != 2 {
+			t.Errorf("Expected 2 attempts, got %d", client.attempt)
+		}
+		// The backoff interval (at most 100ms) is overridden by Retry-After
+		if elapsed < 900*time.Millisecond {
+			t.Errorf("HttpGETWithRetry() retried after %v, expected Retry-After (1s) to be honored", elapsed)
+		}
+	})
+
+	t.Run("Retry-After past the context deadline", func(t *testing.T) {
+		client := &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusTooManyRequests, "", map[string]string{"Retry-After": "60"}),
+				makeResponse(http.StatusOK, "success", nil),
+			},
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err := HttpGETWithRetry(ctx, client, "http://example.com/test", RetryConfig{})
+		if !errors.Is(err, ErrHTTPGetError) {
+			t.Fatalf("HttpGETWithRetry() error = %v, want ErrHTTPGetError", err)
+		}
+		if !strings.Contains(err.Error(), "HTTP 429") {
+			t.Errorf("HttpGETWithRetry() error should report the HTTP status, got %v", err)
+		}
+		if client.attempt != 1 {
+			t.Errorf("Expected 1 attempt, got %d", client.attempt)
+		}
+	})
+
+	t.Run("custom retryable status codes", func(t *testing.T) {
+		cfg := RetryConfig{RetryableStatusCodes: []int{http.StatusRequestTimeout}}
+
+		client := &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusRequestTimeout, "", nil),
+				makeResponse(http.StatusOK, "success", nil),
+			},
+		}
+		if _, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", cfg); err != nil {
+			t.Fatalf("HttpGETWithRetry() error = %v, want nil after retry", err)
+		}
+		if client.attempt != 2 {
+			t.Errorf("Expected 2 attempts, got %d", client.attempt)
+		}
+
+		// Status codes outside the custom set are not retried, even 5xx
+		client = &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusServiceUnavailable, "", nil),
+				makeResponse(http.StatusOK, "success", nil),
+			},
+		}
+		_, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", cfg)
+		if !errors.Is(err, ErrHTTPGetError) {
+			t.Fatalf("HttpGETWithRetry() error = %v, want ErrHTTPGetError", err)
+		}
+		if client.attempt != 1 {
+			t.Errorf("Expected 1 attempt, got %d", client.attempt)
+		}
+	})
+
+	t.Run("invalid retryable status code", func(t *testing.T) {
+		client := &mockHTTPClient{response: makeResponse(http.StatusOK, "success", nil)}
+
+		_, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", RetryConfig{RetryableStatusCodes: []int{http.StatusOK}})
+		if err == nil {
+			t.Fatal("HttpGETWithRetry() error = nil, want error for a non-error status code")
+		}
+	})
+
+	t.Run("invalid max elapsed time", func(t *testing.T) {
+		client := &mockHTTPClient{response: makeResponse(http.StatusOK, "success", nil)}
+
+		_, err := HttpGETWithRetry(context.Background(), client, "http://example.com/test", RetryConfig{MaxElapsedTime: -1})
+		if err == nil {
+			t.Fatal("HttpGETWithRetry() error = nil, want error for negative max elapsed time")
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", header: "120", want: 2 * time.Minute, wantOK: true},
+		{name: "past date", header: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "empty", header: ""},
+		{name: "negative", header: "-1"},
+		{name: "invalid", header: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.header)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("future date", func(t *testing.T) {
+		got, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		if !ok || got <= 59*time.Minute || got > time.Hour {
+			t.Errorf("parseRetryAfter() = %v, %v, want about 1h", got, ok)
+		}
+	})
+}
+
+func TestFullJitterBackOff(t *testing.T) {
+	cfg := RetryConfig{}
+	b := cfg.newBackOff()
+	b.Reset()
+
+	reference := &backoff.ExponentialBackOff{
+		InitialInterval:     DefaultBackoffConfig.InitialInterval,
+		MaxInterval:         DefaultBackoffConfig.MaxInterval,
+		Multiplier:          DefaultBackoffConfig.Multiplier,
+		RandomizationFactor: 0,
+	}
+	reference.Reset()
+
+	allEqual := true
+	for range 20 {
+		got := b.NextBackOff()
+		upper := reference.NextBackOff()
+		if got < 0 || got > upper {
+			t.Errorf("NextBackOff() = %v, want within [0, %v]", got, upper)
+		}
+		if got != upper {
+			allEqual = false
+		}
+	}
+	if allEqual {
+		t.Error("NextBackOff() never applied jitter")
+	}
+}
+
+func TestHttpGETPropagatesTraceContext(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	t.Run("active span", func(t *testing.T) {
+		client := &headerCapturingHTTPClient{}
+		ctx := trace.ContextWithSpanContext(t.Context(), spanCtx)
+		if _, err := HttpGET(ctx, client, "https://example.com/file"); err != nil {
+			t.Fatalf("HttpGET() error = %v", err)
+		}
+
+		want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		if got := client.header.Get("traceparent"); got != want {
+			t.Errorf("traceparent header = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no active span", func(t *testing.T) {
+		client := &headerCapturingHTTPClient{}
+		if _, err := HttpGET(t.Context(), client, "https://example.com/file"); err != nil {
+			t.Fatalf("HttpGET() error = %v", err)
+		}
+		if got := client.header.Get("traceparent"); got != "" {
+			t.Errorf("unexpected traceparent header %q", got)
+		}
+	})
+}
+
+func TestHttpGETUserAgent(t *testing.T) {
+	t.Cleanup(func() { SetUserAgentVersion("") })
+	SetUserAgentVersion("v1.2.3")
+
+	t.Run("default", func(t *testing.T) {
+		client := &headerCapturingHTTPClient{}
+		if _, err := HttpGET(t.Context(), client, "https://example.com/file"); err != nil {
+			t.Fatalf("HttpGET() error = %v", err)
+		}
+		if got := client.header.Get("User-Agent"); got != "tpmtb/v1.2.3" {
+			t.Errorf("User-Agent header = %q, want %q", got, "tpmtb/v1.2.3")
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		client := &headerCapturingHTTPClient{}
+		if _, err := HttpGET(t.Context(), WithUserAgent(client, "my-app/1.0"), "https://example.com/file"); err != nil {
+			t.Fatalf("HttpGET() error = %v", err)
+		}
+		if got := client.header.Get("User-Agent"); got != "my-app/1.0" {
+			t.Errorf("User-Agent header = %q, want %q", got, "my-app/1.0")
+		}
+	})
+
+	t.Run("empty override", func(t *testing.T) {
+		client := &headerCapturingHTTPClient{}
+		if got := WithUserAgent(client, ""); got != client {
+			t.Fatal("expected the client to be returned as is")
+		}
+	})
+}
+
+func TestHttpOpenWithRetry(t *testing.T) {
+	t.Run("retries then streams the response", func(t *testing.T) {
+		client := &mockHTTPClientWithAttempts{
+			responses: []*http.Response{
+				makeResponse(http.StatusServiceUnavailable, "", nil),
+				makeResponse(http.StatusPartialContent, "partial", map[string]string{"Content-Range": "bytes 10-16/17"}),
+			},
+		}
+		header := make(http.Header)
+		header.Set("Range", "bytes=10-")
+
+		res, err := HttpOpenWithRetry(t.Context(), client, "http://example.com/test", header, RetryConfig{})
+		if err != nil {
+			t.Fatalf("HttpOpenWithRetry() error = %v", err)
+		}
+		defer res.Body.Close()
+		if client.attempt != 2 {
+			t.Errorf("HttpOpenWithRetry() made %d attempts, want 2", client.attempt)
+		}
+		if body, _ := io.ReadAll(res.Body); string(body) != "partial" {
+			t.Errorf("HttpOpenWithRetry() body = %q, want %q", body, "partial")
+		}
+	})
+
+	t.Run("sends the extra headers", func(t *testing.T) {
+		client := &headerCapturingHTTPClient{}
+		header := make(http.Header)
+		header.Set("Range", "bytes=10-")
+
+		res, err := HttpOpenWithRetry(t.Context(), client, "http://example.com/test", header, RetryConfig{})
+		if err != nil {
+			t.Fatalf("HttpOpenWithRetry() error = %v", err)
+		}
+		res.Body.Close()
+		if got := client.header.Get("Range"); got != "bytes=10-" {
+			t.Errorf("Range header = %q, want %q", got, "bytes=10-")
+		}
+	})
+
+	t.Run("non-success status", func(t *testing.T) {
+		client := &mockHTTPClient{response: makeResponse(http.StatusNotFound, "", nil)}
+
+		if _, err := HttpOpenWithRetry(t.Context(), client, "http://example.com/test", nil, RetryConfig{}); !errors.Is(err, ErrHTTPGetError) {
+			t.Errorf("HttpOpenWithRetry() error = %v, want %v", err, ErrHTTPGetError)
+		}
+	})
+}
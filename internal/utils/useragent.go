@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// userAgentProduct is the product token of the default User-Agent.
+const userAgentProduct = "tpmtb"
+
+// userAgent holds the default User-Agent sent with outbound requests.
+var userAgent atomic.Value
+
+func init() {
+	userAgent.Store(userAgentProduct + "/dev")
+}
+
+// SetUserAgentVersion sets the version reported in the default User-Agent ("tpmtb/<version>").
+//
+// It is meant to be called once at startup with the build version. An empty version
+// reports "dev".
+func SetUserAgentVersion(version string) {
+	if version == "" {
+		version = "dev"
+	}
+	userAgent.Store(userAgentProduct + "/" + version)
+}
+
+// UserAgent returns the default User-Agent sent with outbound requests.
+func UserAgent() string {
+	return userAgent.Load().(string)
+}
+
+// userAgentClient overrides the User-Agent of every request sent through the wrapped client.
+type userAgentClient struct {
+	client    HTTPClient
+	userAgent string
+}
+
+func (c *userAgentClient) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.userAgent)
+	return c.client.Do(req)
+}
+
+// WithUserAgent returns a client sending userAgent instead of the default User-Agent.
+//
+// A nil client is replaced by [DefaultHTTPClient]. If userAgent is empty, client is returned as is.
+func WithUserAgent(client HTTPClient, userAgent string) HTTPClient {
+	if userAgent == "" {
+		return client
+	}
+	if client == nil {
+		client = DefaultHTTPClient()
+	}
+	if c, ok := client.(*userAgentClient); ok {
+		client = c.client
+	}
+	return &userAgentClient{client: client, userAgent: userAgent}
+}
@@ -2,23 +2,31 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	goversion "github.com/caarlos0/go-version"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle"
+	cacheCmd "github.com/loicsikidi/tpm-ca-certificates/cmd/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/cert"
 	"github.com/loicsikidi/tpm-ca-certificates/cmd/config"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/serve"
 	versionCmd "github.com/loicsikidi/tpm-ca-certificates/cmd/version"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cli"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cli/exitcode"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/observability"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 	"github.com/spf13/cobra"
 )
 
 const website = "https://github.com/loicsikidi/tpm-ca-certificates"
 
 var (
-	version = ""
-	builtBy = ""
+	version  = ""
+	builtBy  = ""
+	logLevel string
 )
 
 func main() {
@@ -36,6 +44,9 @@ func main() {
 		}
 	}()
 
+	info := buildVersion(version, builtBy)
+	utils.SetUserAgentVersion(info.GitVersion)
+
 	rootCmd := &cobra.Command{
 		Use:   "tpmtb",
 		Short: "TPM Trust Bundle",
@@ -52,15 +63,23 @@ Notes:
     * A checksum of each release artifact is signed using Sigstore (ie. integrity).
 `,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return cli.SetLogLevel(logLevel)
+		},
 	}
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info",
+		fmt.Sprintf("Minimum level of the displayed messages (%s)", strings.Join(cli.LogLevels, ", ")))
 
 	rootCmd.AddCommand(bundle.NewCommand())
-	rootCmd.AddCommand(versionCmd.NewCommand(buildVersion(version, builtBy)))
+	rootCmd.AddCommand(versionCmd.NewCommand(info))
 	rootCmd.AddCommand(config.NewCommand())
+	rootCmd.AddCommand(cacheCmd.NewCommand())
+	rootCmd.AddCommand(cert.NewCommand())
+	rootCmd.AddCommand(serve.NewCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		cli.DisplayError("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitcode.FromError(err))
 	}
 }
 
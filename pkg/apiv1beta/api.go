@@ -2,12 +2,16 @@ package apiv1beta
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -16,15 +20,26 @@ import (
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/observability"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
 	verifierutils "github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/verifier"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 )
 
 type VerifyResult = verifier.VerifyResult
 
+// RekorEntry describes a Rekor transparency log entry of the Cosign signature, see [VerifyResult.RekorEntries].
+type RekorEntry = verifier.RekorEntry
+
+// TUFMirror is a TUF repository serving the Sigstore trusted root, such as an internal mirror.
+type TUFMirror = verifierutils.TUFMirror
+
 var (
-	mu         sync.RWMutex
-	httpClient = http.DefaultClient // default HTTP client
+	mu sync.RWMutex
+	// httpClient is the fallback client used when a config does not set its own HTTPClient.
+	httpClient = defaultHTTPClient
+
+	// defaultHTTPClient is the fallback client restored by [SetHTTPClient] when given nil.
+	defaultHTTPClient = NewHTTPClient()
 )
 
 const (
@@ -43,17 +58,117 @@ var (
 
 	// ErrCannotPersistTrustedBundle is returned when the bundle cannot be persisted due to disabled local cache.
 	ErrCannotPersistTrustedBundle = errors.New("local cache is disabled; cannot persist bundle")
+
+	// ErrEmptyVendorFilter is returned when [GetConfig.RequireNonEmpty] is set and
+	// none of the requested vendors has a root certificate in the bundle.
+	ErrEmptyVendorFilter = errors.New("vendor filter matches no root certificate")
+
+	// ErrSkipVerifyNotAcknowledged is returned when [GetConfig.SkipVerify] or [LoadConfig.SkipVerify]
+	// is set without the matching AcknowledgeSkipVerify field.
+	ErrSkipVerifyNotAcknowledged = errors.New("SkipVerify requires AcknowledgeSkipVerify to be set")
+
+	// ErrRefreshUnsupported is returned by [TrustedBundle.RefreshNow] when the bundle cannot
+	// be updated (e.g. created with [NewTrustedBundle] or loaded in offline mode).
+	ErrRefreshUnsupported = errors.New("bundle cannot be refreshed")
+
+	// ErrIntegrityCheckFailed is reported to [AutoUpdateConfig.OnUpdate] when the bundle held
+	// in memory no longer matches the bundle retrieved, see [AutoUpdateConfig.IntegrityCheckInterval].
+	ErrIntegrityCheckFailed = errors.New("bundle integrity check failed")
+
+	// ErrDisallowedAlgorithm is returned by [VerifyTrustedBundle] when a certificate of the
+	// bundle is signed with an algorithm missing from [VerifyConfig.AllowedSignatureAlgorithms].
+	ErrDisallowedAlgorithm = errors.New("certificate signature algorithm not allowed")
+
+	// ErrMalformedBundle is returned by [VerifyTrustedBundle] when [VerifyConfig.CollectAllFailures]
+	// is set and the bundle is not structurally correct, see [ValidateBundle].
+	ErrMalformedBundle = errors.New("malformed bundle")
+
+	// ErrInvalidCacheConfig is returned by [LoadTrustedBundle] when the persisted cache
+	// configuration (config.json) is malformed, e.g. hand-edited or truncated.
+	ErrInvalidCacheConfig = errors.New("invalid cache config")
+
+	// ErrSubjectDigestMismatch is returned by [VerifyTrustedBundle] when the provenance
+	// attestation was issued for another artifact than the bundle being verified.
+	ErrSubjectDigestMismatch = verifier.ErrSubjectDigestMismatch
+
+	// ErrChecksumEntryMissing is returned by [VerifyTrustedBundle] when the signed
+	// checksums file has no entry for the bundle filename.
+	ErrChecksumEntryMissing = verifier.ErrChecksumEntryMissing
+
+	// ErrChecksumMismatch is returned by [VerifyTrustedBundle] when the checksums entry
+	// of the bundle filename doesn't match the bundle digest.
+	ErrChecksumMismatch = verifier.ErrChecksumMismatch
+
+	// ErrMalformedChecksums is returned by [VerifyTrustedBundle] and [ParseChecksums] when
+	// the checksums file cannot be parsed, e.g. a line is malformed or a filename is duplicated.
+	ErrMalformedChecksums = verifier.ErrMalformedChecksums
+
+	// ErrTrustedRootUnavailable is returned by [VerifyTrustedBundle] when the Sigstore trusted root
+	// cannot be fetched, e.g. the TUF repository is unreachable. The bundle could not be verified,
+	// so the error doesn't match [ErrBundleVerificationFailed].
+	ErrTrustedRootUnavailable = verifier.ErrTrustedRootUnavailable
+
+	// ErrInsecureRedirect is returned when a client returned by [NewHTTPClient] is redirected
+	// from HTTPS to HTTP.
+	ErrInsecureRedirect = utils.ErrInsecureRedirect
 )
 
-// HTTPClient returns the current HTTP client used for requests.
+// skipVerifyWarning reports a skipped verification once when no logger is set.
+var skipVerifyWarning sync.Once
+
+// warnSkipVerify reports that the verification of the bundle version is skipped.
+//
+// The warning is sent to logger on every call. Without a logger, it is sent once
+// to [slog.Default] so that a skipped verification never goes unnoticed.
+func warnSkipVerify(ctx context.Context, logger *slog.Logger, version string) {
+	warn := func(logger *slog.Logger) {
+		logger.WarnContext(ctx, "bundle verification is skipped: authenticity and integrity are not checked",
+			slog.String("version", version),
+		)
+	}
+	if logger != nil {
+		warn(logger)
+		return
+	}
+	skipVerifyWarning.Do(func() { warn(slog.Default()) })
+}
+
+// CommitMismatchError is returned when a downloaded bundle was not generated from
+// the commit pinned with [GetConfig.ExpectedCommit].
+type CommitMismatchError struct {
+	// Date is the release date of the bundle.
+	Date string
+	// Expected is the pinned commit.
+	Expected string
+	// Actual is the commit referenced in the bundle metadata.
+	Actual string
+}
+
+func (e *CommitMismatchError) Error() string {
+	return fmt.Sprintf("bundle %s was generated at commit %s, expected %s", e.Date, e.Actual, e.Expected)
+}
+
+// HTTPClient returns the fallback HTTP client used by calls whose config does not set
+// its own HTTPClient.
 func HTTPClient() *http.Client {
 	mu.RLock()
 	defer mu.RUnlock()
 	return httpClient
 }
 
-// SetHTTPClient sets a custom HTTP client for requests.
+// SetHTTPClient sets the fallback HTTP client used by calls whose config does not set
+// its own HTTPClient. Passing nil restores the default client, built by [NewHTTPClient].
+//
+// The fallback is resolved once when a call starts: changing it does not affect calls
+// already in flight, nor calls (or auto-update watchers) configured with their own client.
+//
+// Deprecated: mutating package-level state can surprise concurrent callers; set the
+// HTTPClient field of [GetConfig], [VerifyConfig] or [SaveConfig] instead. That client
+// is resolved once per call, so concurrent calls using different clients never interfere.
 func SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = defaultHTTPClient
+	}
 	mu.Lock()
 	defer mu.Unlock()
 	httpClient = client
@@ -106,17 +221,34 @@ func GetTrustedBundle(ctx context.Context, cfg GetConfig) (TrustedBundle, error)
 		return nil, err
 	}
 
+	// Read from the first cache holding the release: CachePath, then the read-only fallbacks
+	cachePath, ok := findCachePath(releaseTag, append([]string{cfg.CachePath}, cfg.FallbackCachePaths...)...)
+	if !ok {
+		cachePath = cfg.CachePath
+	}
+
 	assetsCfg := cfg.toAssetsConfig()
 	assetsCfg.tag = releaseTag
+	assetsCfg.cachePath = cachePath
 	assets, err := getAssets(ctx, assetsCfg)
 	if err != nil {
 		observability.RecordError(span, err)
 		return nil, err
 	}
 
-	if !cfg.SkipVerify {
+	// The pinned commit is enforced regardless of SkipVerify
+	if cfg.ExpectedCommit != "" {
+		if err := checkExpectedCommit(assets, cfg.ExpectedCommit); err != nil {
+			observability.RecordError(span, err)
+			return nil, err
+		}
+	}
+
+	if cfg.SkipVerify {
+		warnSkipVerify(ctx, cfg.Logger, releaseTag)
+	} else {
 		// Verify root bundle
-		if _, err := VerifyTrustedBundle(ctx, VerifyConfig{
+		result, err := VerifyTrustedBundle(ctx, VerifyConfig{
 			Bundle:            assets.rootBundleData,
 			Checksum:          assets.checksum,
 			ChecksumSignature: assets.checksumSignature,
@@ -124,10 +256,15 @@ func GetTrustedBundle(ctx context.Context, cfg GetConfig) (TrustedBundle, error)
 			sourceRepo:        cfg.sourceRepo,
 			HTTPClient:        cfg.HTTPClient,
 			DisableLocalCache: cfg.DisableLocalCache,
-		}); err != nil {
+			TrustedRoot:       cfg.trustedRoot,
+			TUFMirror:         cfg.TUFMirror,
+			attestations:      assets.attestations,
+		})
+		if err != nil {
 			observability.RecordError(span, err)
 			return nil, fmt.Errorf("root bundle verification failed: %w", err)
 		}
+		assets.useVerifiedProvenance(result)
 
 		// Verify intermediate bundle if present
 		if len(assets.intermediateBundleData) > 0 {
@@ -139,14 +276,20 @@ func GetTrustedBundle(ctx context.Context, cfg GetConfig) (TrustedBundle, error)
 				sourceRepo:        cfg.sourceRepo,
 				HTTPClient:        cfg.HTTPClient,
 				DisableLocalCache: cfg.DisableLocalCache,
+				TrustedRoot:       cfg.trustedRoot,
+				TUFMirror:         cfg.TUFMirror,
 			}); err != nil {
 				observability.RecordError(span, err)
 				return nil, fmt.Errorf("intermediate bundle verification failed: %w", err)
 			}
 		}
+
+		if !cfg.DisableLocalCache {
+			assets.cacheAttestation(assetsCfg.writableCachePath)
+		}
 	}
 
-	tb, err := newTrustedBundle(ctx, assets.rootBundleData, assets.intermediateBundleData)
+	tb, err := newTrustedBundle(ctx, cfg.Logger, assets.rootBundleData, assets.intermediateBundleData)
 	if err != nil {
 		observability.RecordError(span, err)
 		return nil, err
@@ -155,9 +298,14 @@ func GetTrustedBundle(ctx context.Context, cfg GetConfig) (TrustedBundle, error)
 	// Cache additional config to the trusted bundle
 	tbImpl := tb.(*trustedBundle)
 	tbImpl.disableLocalCache = cfg.DisableLocalCache
-	tbImpl.vendorFilter = cfg.VendorIDs
+	tbImpl.sourceRepo = cfg.sourceRepo
+	tbImpl.tufMirror = cfg.TUFMirror
+	tbImpl.setVendorFilter(cfg.VendorIDs)
 	tbImpl.autoUpdateCfg = &cfg.AutoUpdate
+	tbImpl.rootsOnly = cfg.RootsOnly
+	tbImpl.skipVerify = cfg.SkipVerify
 	tbImpl.assets = assets
+	tbImpl.updater = cfg
 
 	// Parse intermediate bundle metadata if present
 	if len(assets.intermediateBundleData) > 0 {
@@ -169,9 +317,30 @@ func GetTrustedBundle(ctx context.Context, cfg GetConfig) (TrustedBundle, error)
 		tbImpl.intermediateMetadata = intermediateMetadata
 	}
 
+	// A filter matching no vendor silently yields an empty trust store
+	if len(cfg.VendorIDs) > 0 && len(tbImpl.GetVendors()) == 0 {
+		if cfg.RequireNonEmpty {
+			err := fmt.Errorf("%w: %v in bundle %s", ErrEmptyVendorFilter, cfg.VendorIDs, tbImpl.rootMetadata.Date)
+			observability.RecordError(span, err)
+			return nil, err
+		}
+		if cfg.Logger != nil {
+			cfg.Logger.WarnContext(ctx, ErrEmptyVendorFilter.Error(),
+				slog.Any("vendor_ids", cfg.VendorIDs),
+				slog.String("version", tbImpl.rootMetadata.Date),
+			)
+		}
+	}
+
 	if !cfg.DisableLocalCache {
-		// Persist only if not already cached
-		if !checkCacheExists(cfg.CachePath, releaseTag) {
+		// Persist only if not already cached, if the cache lacks the intermediate bundle,
+		// if the cache holds unverified bytes while this bundle was verified, or if it was
+		// persisted for another source repository or TUF mirror.
+		// Fallback caches are read-only: the bundle is always persisted to CachePath.
+		if !checkCacheExists(cachePath, releaseTag) ||
+			(!cfg.RootsOnly && checkCacheRootsOnly(cachePath)) ||
+			(!cfg.SkipVerify && checkCacheSkipVerify(cachePath)) ||
+			!checkCacheSource(cachePath, cfg.sourceRepo, cfg.TUFMirror) {
 			if err := tbImpl.Persist(ctx, cfg.CachePath); err != nil {
 				observability.RecordError(span, err)
 				return nil, fmt.Errorf("failed to persist bundle to cache (if running on read-only filesystem, set DisableLocalCache=true): %w", err)
@@ -179,13 +348,89 @@ func GetTrustedBundle(ctx context.Context, cfg GetConfig) (TrustedBundle, error)
 		}
 	}
 
-	if !cfg.AutoUpdate.DisableAutoUpdate {
-		tbImpl.startWatcher(ctx, cfg, cfg.AutoUpdate.Interval)
+	if update, integrityCheck := cfg.AutoUpdate.watchIntervals(); update > 0 || integrityCheck > 0 {
+		tbImpl.startWatcher(ctx, cfg, update, integrityCheck)
 	}
 
 	return tb, nil
 }
 
+// UpdateInfo is the outcome of [CheckForUpdate].
+type UpdateInfo struct {
+	// CurrentVersion is the version (YYYY-MM-DD format) of the cached bundle.
+	// It is empty if no bundle is cached.
+	CurrentVersion string
+
+	// LatestVersion is the version (YYYY-MM-DD format) of the latest released bundle.
+	LatestVersion string
+
+	// UpdateAvailable reports whether LatestVersion is newer than CurrentVersion
+	// (always true when no bundle is cached).
+	UpdateAvailable bool
+}
+
+// CheckForUpdate reports whether a bundle newer than the cached one has been released.
+//
+// Only the latest release tag is resolved: nothing is downloaded nor written to the cache,
+// which makes it a cheap probe for monitoring compared to [GetTrustedBundle].
+//
+// Example:
+//
+//	info, err := apiv1beta.CheckForUpdate(context.Background(), apiv1beta.CheckForUpdateConfig{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if info.UpdateAvailable {
+//	    fmt.Printf("bundle %s is available (cached: %s)\n", info.LatestVersion, info.CurrentVersion)
+//	}
+func CheckForUpdate(ctx context.Context, cfg CheckForUpdateConfig) (*UpdateInfo, error) {
+	ctx, span := observability.StartSpan(ctx, "tpmtb.CheckForUpdate")
+	defer span.End()
+
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		observability.RecordError(span, err)
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	latest, err := getReleaseTag(ctx, GetConfig{HTTPClient: cfg.HTTPClient, sourceRepo: cfg.sourceRepo})
+	if err != nil {
+		observability.RecordError(span, err)
+		return nil, err
+	}
+
+	info := &UpdateInfo{LatestVersion: latest, UpdateAvailable: true}
+	cacheCfg, err := getCacheConfig(cfg.CachePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return info, nil
+		}
+		observability.RecordError(span, err)
+		return nil, fmt.Errorf("failed to read cache config: %w", err)
+	}
+	info.CurrentVersion = cacheCfg.Version
+	// Versions are release dates (YYYY-MM-DD): the lexical order is the chronological one
+	info.UpdateAvailable = latest > cacheCfg.Version
+	return info, nil
+}
+
+// ComputeBundleDigest returns the digest of a TPM trust bundle, as used by verification
+// to look up its GitHub attestations.
+//
+// The digest is computed over the full file content, metadata header included, and
+// is formatted as "sha256:HEX" where HEX is the lowercase hexadecimal SHA-256 hash.
+//
+// Example:
+//
+//	bundleData, err := os.ReadFile("tpm-ca-certificates.pem")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(apiv1beta.ComputeBundleDigest(bundleData))
+//	// Output: sha256:f5c7f9e9c59d65f1a889b1cdc712a3ea674df84bd8dc15081165b41ac2496ed2
+func ComputeBundleDigest(data []byte) string {
+	return digest.ComputeSHA256(data)
+}
+
 // VerifyTrustedBundle verifies the authenticity and integrity of a TPM trust bundle.
 //
 // The function performs cryptographic verification using both Cosign signatures
@@ -218,8 +463,49 @@ func VerifyTrustedBundle(ctx context.Context, cfg VerifyConfig) (*VerifyResult,
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	// Checked first as they do not need any network access
+	localErr := checkSignatureAlgorithms(cfg.Bundle, cfg.AllowedSignatureAlgorithms)
+	if localErr != nil && !cfg.CollectAllFailures {
+		observability.RecordError(span, localErr)
+		return nil, localErr
+	}
+	// The structural check only runs to diagnose a broken bundle: releases are validated
+	// before being signed, so a bundle passing the authenticity checks is well-formed
+	if cfg.CollectAllFailures {
+		localErr = errors.Join(localErr, checkStructure(cfg.Bundle))
+	}
+
+	verifierCfg := verifier.Config{
+		Date:                  cfg.BundleMetadata.Date,
+		Commit:                cfg.BundleMetadata.Commit,
+		SourceRepo:            cfg.sourceRepo,
+		WorkflowFilename:      github.ReleaseBundleWorkflowPath,
+		WorkflowFilenames:     cfg.WorkflowFilenames,
+		AllowedOIDCIssuers:    cfg.AllowedOIDCIssuers,
+		HTTPClient:            cfg.HTTPClient,
+		DisableLocalCache:     cfg.DisableLocalCache,
+		TrustedRoot:           cfg.TrustedRoot,
+		TUFMirror:             cfg.TUFMirror,
+		PinnedKey:             cfg.PinnedKey,
+		RequireRekorInclusion: cfg.RequireRekorInclusion,
+		CollectAllFailures:    cfg.CollectAllFailures,
+	}
+
+	v, err := verifier.New(verifierCfg)
+	if err != nil {
+		observability.RecordError(span, err)
+		return nil, fmt.Errorf("failed to create verifier: %w", err)
+	}
+
+	if cfg.CacheVerification && localErr == nil {
+		if verifiedAt, ok := loadVerification(&cfg); ok {
+			return &VerifyResult{Policy: v.GetPolicyConfig(), CachedAt: verifiedAt}, nil
+		}
+	}
+
+	var assets *assets
 	if cfg.shouldFetchVerificationAssets() {
-		assets, err := getAssets(ctx, cfg.toAssetsConfig())
+		assets, err = getAssets(ctx, cfg.toAssetsConfig())
 		if err != nil {
 			observability.RecordError(span, err)
 			return nil, fmt.Errorf("failed to download verification assets: %w", err)
@@ -232,23 +518,18 @@ func VerifyTrustedBundle(ctx context.Context, cfg VerifyConfig) (*VerifyResult,
 		}
 		if len(cfg.Provenance) == 0 {
 			cfg.Provenance = assets.provenance
+			cfg.attestations = assets.attestations
 		}
 	}
 
-	verifierCfg := verifier.Config{
-		Date:              cfg.BundleMetadata.Date,
-		Commit:            cfg.BundleMetadata.Commit,
-		SourceRepo:        cfg.sourceRepo,
-		WorkflowFilename:  github.ReleaseBundleWorkflowPath,
-		HTTPClient:        cfg.HTTPClient,
-		DisableLocalCache: cfg.DisableLocalCache,
-		TrustedRoot:       cfg.TrustedRoot,
-	}
-
-	v, err := verifier.New(verifierCfg)
-	if err != nil {
-		observability.RecordError(span, err)
-		return nil, fmt.Errorf("failed to create verifier: %w", err)
+	if cfg.Logger != nil {
+		policyCfg := v.GetPolicyConfig()
+		cfg.Logger.InfoContext(ctx, "enforcing verification policy",
+			slog.String("source_repo", policyCfg.SourceRepo.String()),
+			slog.Any("allowed_oidc_issuers", policyCfg.AllowedOIDCIssuers),
+			slog.String("workflow_ref", policyCfg.BuildWorkflowRef()),
+			slog.String("tag", policyCfg.Tag),
+		)
 	}
 
 	verifyCfg := verifier.VerifyConfig{
@@ -257,16 +538,95 @@ func VerifyTrustedBundle(ctx context.Context, cfg VerifyConfig) (*VerifyResult,
 		ChecksumsSigData: cfg.ChecksumSignature,
 		ProvenanceData:   cfg.Provenance,
 	}
+	if len(cfg.attestations) > 0 {
+		verifyCfg.ProvenanceData, verifyCfg.Attestations = nil, cfg.attestations
+	}
 
 	result, err := v.Verify(ctx, verifyCfg)
+	if err != nil && !trustedRootUnavailable(err) {
+		err = fmt.Errorf("%w: %w", ErrBundleVerificationFailed, err)
+	}
+	if localErr != nil {
+		err = errors.Join(localErr, err)
+	}
 	if err != nil {
 		observability.RecordError(span, err)
-		return nil, fmt.Errorf("%w: %v", ErrBundleVerificationFailed, err)
+		return nil, err
+	}
+
+	if assets != nil {
+		assets.useVerifiedProvenance(result)
+		if !cfg.DisableLocalCache {
+			assets.cacheAttestation(cfg.CachePath)
+		}
+	}
+	if cfg.CacheVerification {
+		saveVerification(&cfg, time.Now())
 	}
 
 	return result, nil
 }
 
+// trustedRootUnavailable reports whether every failure joined in err comes from
+// [ErrTrustedRootUnavailable], i.e. the bundle could not be verified at all.
+func trustedRootUnavailable(err error) bool {
+	switch x := err.(type) {
+	case interface{ Unwrap() []error }:
+		errs := x.Unwrap()
+		if slices.Contains(errs, ErrTrustedRootUnavailable) {
+			return true
+		}
+		for _, e := range errs {
+			if !trustedRootUnavailable(e) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	case interface{ Unwrap() error }:
+		return trustedRootUnavailable(x.Unwrap())
+	default:
+		return err == ErrTrustedRootUnavailable
+	}
+}
+
+// checkSignatureAlgorithms returns [ErrDisallowedAlgorithm] if a certificate of the bundle
+// is signed with an algorithm outside allowed. Any algorithm is accepted when allowed is empty.
+func checkSignatureAlgorithms(bundleData []byte, allowed []x509.SignatureAlgorithm) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	catalog, err := bundle.ParseBundle(bundleData)
+	if err != nil {
+		return fmt.Errorf("failed to parse bundle certificates: %w", err)
+	}
+	for _, vendorID := range slices.Sorted(maps.Keys(catalog)) {
+		for _, cert := range catalog[vendorID] {
+			if !slices.Contains(allowed, cert.SignatureAlgorithm) {
+				return fmt.Errorf("%w: certificate %q of vendor %s is signed with %s",
+					ErrDisallowedAlgorithm, cert.Subject.String(), vendorID, cert.SignatureAlgorithm)
+			}
+		}
+	}
+	return nil
+}
+
+// checkStructure reports the structural issues of the bundle, see [ValidateBundle].
+func checkStructure(bundleData []byte) error {
+	issues, err := ValidateBundle(bundleData)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedBundle, err)
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(issues))
+	for _, issue := range issues {
+		errs = append(errs, errors.New(issue.String()))
+	}
+	return fmt.Errorf("%w: %w", ErrMalformedBundle, errors.Join(errs...))
+}
+
 // SaveResponse contains all assets required for offline verification of a TPM bundle.
 type SaveResponse struct {
 	// RootBundle is the TPM root CA certificates bundle (PEM format).
@@ -322,6 +682,8 @@ func (sr *SaveResponse) Persist(ctx context.Context, optionalOutputDir ...string
 //
 // This function downloads the bundle, verifies it, fetches the TUF trust chains from Rekor,
 // and returns a [SaveResponse] containing all necessary files for offline verification.
+// When [SaveConfig.OfflineTrustedRoot] is set, the provided trusted root is reused and
+// Sigstore's TUF repository is never contacted.
 //
 // The returned [SaveResponse] can be persisted to disk using the Persist method, which will
 // save all assets to the local cache directory ($HOME/.tpmtb by default).
@@ -344,6 +706,12 @@ func (sr *SaveResponse) Persist(ctx context.Context, optionalOutputDir ...string
 //	    Date:      "2025-12-05",
 //	    VendorIDs: []apiv1beta.VendorID{apiv1beta.IFX, apiv1beta.NTC},
 //	})
+//
+//	// Reuse an already cached trusted root (no TUF request)
+//	trustedRoot, _ := os.ReadFile(filepath.Join(cacheDir, "trusted-root.json"))
+//	resp, err = apiv1beta.SaveTrustedBundle(context.Background(), apiv1beta.SaveConfig{
+//	    OfflineTrustedRoot: trustedRoot,
+//	})
 func SaveTrustedBundle(ctx context.Context, cfg SaveConfig) (*SaveResponse, error) {
 	if err := cfg.CheckAndSetDefaults(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -359,15 +727,19 @@ func SaveTrustedBundle(ctx context.Context, cfg SaveConfig) (*SaveResponse, erro
 		AutoUpdate: AutoUpdateConfig{
 			DisableAutoUpdate: true,
 		},
+		trustedRoot: cfg.OfflineTrustedRoot,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trusted bundle: %w", err)
 	}
 
-	// Fetch the Sigstore trusted_root.json from TUF
-	trustedRoot, err := verifierutils.FetchTrustedRoot(cfg.HTTPClient)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch trusted root: %w", err)
+	trustedRoot := cfg.OfflineTrustedRoot
+	if len(trustedRoot) == 0 {
+		// Fetch the Sigstore trusted_root.json from TUF
+		trustedRoot, err = verifierutils.FetchTrustedRoot(cfg.HTTPClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch trusted root: %w", err)
+		}
 	}
 
 	// Extract assets from the trusted bundle
@@ -1,12 +1,28 @@
 package apiv1beta
 
 import (
+	"bytes"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 )
 
 func TestCheckCacheExists(t *testing.T) {
@@ -307,3 +323,1426 @@ func TestVerifyTrustedBundleWithCustomTrustedRoot(t *testing.T) {
 		}
 	})
 }
+
+func TestVerifyTrustedBundleCollectAllFailures(t *testing.T) {
+	files := readerTestFiles(t)
+	metadata, err := bundle.ParseMetadata(files[testutil.RootBundleFile])
+	if err != nil {
+		t.Fatalf("Failed to parse bundle metadata: %v", err)
+	}
+	newConfig := func(collectAll bool) VerifyConfig {
+		return VerifyConfig{
+			Bundle:                     files[testutil.RootBundleFile],
+			BundleMetadata:             &bundle.Metadata{Date: metadata.Date, Commit: strings.Repeat("0", 40)},
+			Checksum:                   files[testutil.ChecksumFile],
+			ChecksumSignature:          files[testutil.ChecksumSigstoreFile],
+			Provenance:                 files[testutil.ProvenanceFile],
+			TrustedRoot:                files[testutil.TrustedRootFile],
+			DisableLocalCache:          true,
+			AllowedSignatureAlgorithms: []x509.SignatureAlgorithm{x509.PureEd25519},
+			CollectAllFailures:         collectAll,
+		}
+	}
+
+	t.Run("fail fast", func(t *testing.T) {
+		_, err := VerifyTrustedBundle(t.Context(), newConfig(false))
+		if !errors.Is(err, ErrDisallowedAlgorithm) {
+			t.Fatalf("VerifyTrustedBundle() error = %v, want %v", err, ErrDisallowedAlgorithm)
+		}
+		if errors.Is(err, ErrBundleVerificationFailed) {
+			t.Errorf("expected verification to stop at the signature algorithm check, got: %v", err)
+		}
+	})
+
+	t.Run("all failures", func(t *testing.T) {
+		_, err := VerifyTrustedBundle(t.Context(), newConfig(true))
+		if !errors.Is(err, ErrDisallowedAlgorithm) || !errors.Is(err, ErrBundleVerificationFailed) {
+			t.Fatalf("VerifyTrustedBundle() error = %v, want both %v and %v", err, ErrDisallowedAlgorithm, ErrBundleVerificationFailed)
+		}
+		for _, want := range []string{"cosign verification failed", "github attestation verification failed"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q to be reported, got: %v", want, err)
+			}
+		}
+	})
+
+	t.Run("structural failure", func(t *testing.T) {
+		cfg := newConfig(true)
+		// The certificate metadata no longer matches the certificate
+		cfg.Bundle = bytes.Replace(cfg.Bundle, []byte("# Serial Number: 4 (0x4)"), []byte("# Serial Number: 5 (0x5)"), 1)
+		_, err := VerifyTrustedBundle(t.Context(), cfg)
+		if !errors.Is(err, ErrMalformedBundle) || !errors.Is(err, ErrBundleVerificationFailed) {
+			t.Fatalf("VerifyTrustedBundle() error = %v, want both %v and %v", err, ErrMalformedBundle, ErrBundleVerificationFailed)
+		}
+
+		cfg.CollectAllFailures = false
+		if _, err := VerifyTrustedBundle(t.Context(), cfg); errors.Is(err, ErrMalformedBundle) {
+			t.Errorf("expected the structural check to be skipped by default, got: %v", err)
+		}
+	})
+}
+
+func TestVerifyTrustedBundleHermetic(t *testing.T) {
+	previous := HTTPClient()
+	t.Cleanup(func() { SetHTTPClient(previous) })
+	SetHTTPClient(&http.Client{Transport: failingRoundTripper{}})
+
+	files := readerTestFiles(t)
+	client := &failingHTTPClient{}
+	cfg := VerifyConfig{
+		Bundle:            files[testutil.RootBundleFile],
+		Checksum:          files[testutil.ChecksumFile],
+		ChecksumSignature: files[testutil.ChecksumSigstoreFile],
+		Provenance:        files[testutil.ProvenanceFile],
+		TrustedRoot:       files[testutil.TrustedRootFile],
+		HTTPClient:        client,
+		DisableLocalCache: true,
+	}
+	if cfg.shouldFetchVerificationAssets() {
+		t.Fatal("no verification asset should be fetched when all of them are provided")
+	}
+
+	result, err := VerifyTrustedBundle(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("VerifyTrustedBundle() error = %v", err)
+	}
+	if result.CosignResult == nil || len(result.GithubAttestationResults) == 0 {
+		t.Error("expected Cosign and GitHub attestation results")
+	}
+	if client.calls != 0 {
+		t.Errorf("expected no HTTP request, got %d", client.calls)
+	}
+}
+
+func TestVerifyTrustedBundleCacheVerification(t *testing.T) {
+	t.Cleanup(verifiedBundles.clear)
+
+	files := readerTestFiles(t)
+	cachePath := t.TempDir()
+
+	result, err := VerifyTrustedBundle(t.Context(), VerifyConfig{
+		Bundle:            files[testutil.RootBundleFile],
+		Checksum:          files[testutil.ChecksumFile],
+		ChecksumSignature: files[testutil.ChecksumSigstoreFile],
+		Provenance:        files[testutil.ProvenanceFile],
+		TrustedRoot:       files[testutil.TrustedRootFile],
+		CachePath:         cachePath,
+		CacheVerification: true,
+	})
+	if err != nil {
+		t.Fatalf("VerifyTrustedBundle() error = %v", err)
+	}
+	if !result.CachedAt.IsZero() {
+		t.Fatal("first verification must not be served from the cache")
+	}
+
+	// Without checksums nor provenance, a full verification would download them
+	verifyCached := func(t *testing.T) {
+		t.Helper()
+		client := &releaseHTTPClient{}
+		result, err := VerifyTrustedBundle(t.Context(), VerifyConfig{
+			Bundle:            files[testutil.RootBundleFile],
+			TrustedRoot:       files[testutil.TrustedRootFile],
+			CachePath:         cachePath,
+			HTTPClient:        client,
+			CacheVerification: true,
+		})
+		if err != nil {
+			t.Fatalf("VerifyTrustedBundle() error = %v", err)
+		}
+		if result.CachedAt.IsZero() {
+			t.Error("expected the result to be served from the cache")
+		}
+		if client.requests != 0 {
+			t.Errorf("expected no HTTP request, got %d", client.requests)
+		}
+	}
+
+	t.Run("in-memory hit", verifyCached)
+
+	t.Run("nothing is persisted", func(t *testing.T) {
+		entries, err := os.ReadDir(cachePath)
+		if err != nil {
+			t.Fatalf("failed to read cache directory: %v", err)
+		}
+		for _, entry := range entries {
+			if entry.Name() == "verifications" {
+				t.Error("expected verifications to be kept in memory only")
+			}
+		}
+	})
+
+	t.Run("other policy misses", func(t *testing.T) {
+		client := &releaseHTTPClient{}
+		_, _ = VerifyTrustedBundle(t.Context(), VerifyConfig{
+			Bundle:            files[testutil.RootBundleFile],
+			TrustedSourceRepo: "example/fork",
+			CachePath:         cachePath,
+			HTTPClient:        client,
+			CacheVerification: true,
+		})
+		if client.requests == 0 {
+			t.Error("expected a full verification for another trusted repository")
+		}
+	})
+}
+
+// recordingHTTPClient answers GitHub release lookups and records every other request.
+type recordingHTTPClient struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (c *recordingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "api.github.com" {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString("{}")),
+			Header:     make(http.Header),
+		}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestSaveTrustedBundleWithOfflineTrustedRoot(t *testing.T) {
+	t.Run("makes no TUF request when a trusted root is supplied", func(t *testing.T) {
+		cacheCfg := CacheConfig{
+			Version: testutil.BundleVersion,
+		}
+		configData, _ := json.Marshal(cacheCfg)
+		tmpDir := testutil.CreateCacheDir(t, configData)
+
+		trustedRootData, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+		if err != nil {
+			t.Fatalf("Failed to read trusted root: %v", err)
+		}
+
+		client := &recordingHTTPClient{}
+		resp, err := SaveTrustedBundle(t.Context(), SaveConfig{
+			Date:               testutil.BundleVersion,
+			CachePath:          tmpDir,
+			HTTPClient:         client,
+			OfflineTrustedRoot: trustedRootData,
+		})
+		if err != nil {
+			t.Fatalf("SaveTrustedBundle() error = %v", err)
+		}
+
+		if len(client.requests) != 0 {
+			t.Errorf("Expected no request outside GitHub API, got: %v", client.requests)
+		}
+		if !bytes.Equal(resp.TrustedRoot, trustedRootData) {
+			t.Error("Expected SaveResponse.TrustedRoot to be the supplied trusted root")
+		}
+	})
+
+	t.Run("fails with invalid trusted root", func(t *testing.T) {
+		_, err := SaveTrustedBundle(t.Context(), SaveConfig{
+			HTTPClient:         &recordingHTTPClient{},
+			OfflineTrustedRoot: []byte("invalid json"),
+		})
+		if err == nil {
+			t.Fatal("Expected error with invalid trusted root")
+		}
+	})
+}
+
+// failingHTTPClient fails every request and counts how many were made.
+type failingHTTPClient struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *failingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return nil, errors.New("network disabled")
+}
+
+func TestDownloadProvenanceCache(t *testing.T) {
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %v", err)
+	}
+	provenance := []byte(`{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json"}`)
+
+	newConfig := func(cachePath string, disableLocalCache bool) assetsConfig {
+		cfg := assetsConfig{
+			tag:               testutil.BundleVersion,
+			cachePath:         cachePath,
+			disableLocalCache: disableLocalCache,
+			httpClient:        &failingHTTPClient{},
+		}
+		if err := cfg.CheckAndSetDefaults(); err != nil {
+			t.Fatalf("CheckAndSetDefaults() error = %v", err)
+		}
+		return cfg
+	}
+
+	t.Run("cache hit makes no request", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if err := cache.SaveAttestation(tmpDir, digest.ComputeSHA256(bundleData), provenance); err != nil {
+			t.Fatalf("SaveAttestation() error = %v", err)
+		}
+
+		cfg := newConfig(tmpDir, false)
+		got, downloaded, err := downloadProvenance(t.Context(), github.NewHTTPClient(cfg.httpClient), cfg, bundleData)
+		if err != nil {
+			t.Fatalf("downloadProvenance() error = %v", err)
+		}
+		if len(got) != 1 || !bytes.Equal(got[0], provenance) {
+			t.Errorf("downloadProvenance() = %s, want %s", got, provenance)
+		}
+		if downloaded {
+			t.Error("downloadProvenance() reported a download for a cached attestation")
+		}
+		if calls := cfg.httpClient.(*failingHTTPClient).calls; calls != 0 {
+			t.Errorf("Expected no HTTP request, got %d", calls)
+		}
+	})
+
+	t.Run("disabled local cache bypasses cached attestation", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		if err := cache.SaveAttestation(tmpDir, digest.ComputeSHA256(bundleData), provenance); err != nil {
+			t.Fatalf("SaveAttestation() error = %v", err)
+		}
+
+		cfg := newConfig(tmpDir, true)
+		if _, _, err := downloadProvenance(t.Context(), github.NewHTTPClient(cfg.httpClient), cfg, bundleData); err == nil {
+			t.Fatal("Expected error since the attestation must be fetched")
+		}
+		if calls := cfg.httpClient.(*failingHTTPClient).calls; calls == 0 {
+			t.Error("Expected an HTTP request when local cache is disabled")
+		}
+	})
+}
+
+func TestVerifyTrustedBundleReturnsPolicy(t *testing.T) {
+	readFile := func(name string) []byte {
+		data, err := testutil.ReadTestFile(name)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		return data
+	}
+
+	var logs bytes.Buffer
+	result, err := VerifyTrustedBundle(t.Context(), VerifyConfig{
+		Bundle:            readFile(testutil.RootBundleFile),
+		Checksum:          readFile(testutil.ChecksumFile),
+		ChecksumSignature: readFile(testutil.ChecksumSigstoreFile),
+		Provenance:        readFile(testutil.ProvenanceFile),
+		TrustedRoot:       readFile(testutil.TrustedRootFile),
+		Logger:            slog.New(slog.NewJSONHandler(&logs, nil)),
+	})
+	if err != nil {
+		t.Fatalf("VerifyTrustedBundle() error = %v", err)
+	}
+
+	wantWorkflowRef := github.ReleaseBundleWorkflowPath + "@refs/tags/" + testutil.BundleVersion
+	if got := result.Policy.SourceRepo.String(); got != github.SourceRepo.String() {
+		t.Errorf("Policy.SourceRepo = %s, want %s", got, github.SourceRepo.String())
+	}
+	if got := result.Policy.BuildWorkflowRef(); got != wantWorkflowRef {
+		t.Errorf("Policy.BuildWorkflowRef() = %s, want %s", got, wantWorkflowRef)
+	}
+	if result.Policy.Tag != testutil.BundleVersion {
+		t.Errorf("Policy.Tag = %s, want %s", result.Policy.Tag, testutil.BundleVersion)
+	}
+	if result.Policy.OIDCIssuer == "" {
+		t.Error("Expected Policy.OIDCIssuer to be set")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode log entry %q: %v", logs.String(), err)
+	}
+	if entry["workflow_ref"] != wantWorkflowRef {
+		t.Errorf("logged workflow_ref = %v, want %s", entry["workflow_ref"], wantWorkflowRef)
+	}
+	if _, ok := entry["oidc_issuer"]; ok {
+		t.Error("expected only the allowed OIDC issuers to be logged")
+	}
+	if issuers, _ := entry["allowed_oidc_issuers"].([]any); len(issuers) != 1 || issuers[0] != result.Policy.OIDCIssuer {
+		t.Errorf("logged allowed_oidc_issuers = %v, want [%s]", entry["allowed_oidc_issuers"], result.Policy.OIDCIssuer)
+	}
+}
+
+func TestVerifyTrustedBundleWorkflowFilenames(t *testing.T) {
+	files := readerTestFiles(t)
+	newConfig := func(workflows ...string) VerifyConfig {
+		return VerifyConfig{
+			Bundle:            files[testutil.RootBundleFile],
+			Checksum:          files[testutil.ChecksumFile],
+			ChecksumSignature: files[testutil.ChecksumSigstoreFile],
+			Provenance:        files[testutil.ProvenanceFile],
+			TrustedRoot:       files[testutil.TrustedRootFile],
+			WorkflowFilenames: workflows,
+		}
+	}
+
+	result, err := VerifyTrustedBundle(t.Context(), newConfig(".github/workflows/release.yaml"))
+	if err != nil {
+		t.Fatalf("VerifyTrustedBundle() error = %v", err)
+	}
+	if result.Policy.BuildWorkflow != github.ReleaseBundleWorkflowPath {
+		t.Errorf("Policy.BuildWorkflow = %s, want %s", result.Policy.BuildWorkflow, github.ReleaseBundleWorkflowPath)
+	}
+
+	if _, err := VerifyTrustedBundle(t.Context(), newConfig("")); err == nil {
+		t.Error("VerifyTrustedBundle() expected error for an empty workflow filename")
+	}
+
+	withWorkflow, withoutWorkflow := newConfig(".github/workflows/release.yaml"), newConfig()
+	for _, cfg := range []*VerifyConfig{&withWorkflow, &withoutWorkflow} {
+		if err := cfg.CheckAndSetDefaults(); err != nil {
+			t.Fatalf("CheckAndSetDefaults() error = %v", err)
+		}
+	}
+	if verificationKey(&withWorkflow) == verificationKey(&withoutWorkflow) {
+		t.Error("expected the accepted workflows to be part of the verification cache key")
+	}
+}
+
+func TestVerifyTrustedBundleWithTrustedSourceRepo(t *testing.T) {
+	readFile := func(name string) []byte {
+		data, err := testutil.ReadTestFile(name)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		return data
+	}
+	newConfig := func(trustedSourceRepo string, logs *bytes.Buffer) VerifyConfig {
+		return VerifyConfig{
+			Bundle:            readFile(testutil.RootBundleFile),
+			Checksum:          readFile(testutil.ChecksumFile),
+			ChecksumSignature: readFile(testutil.ChecksumSigstoreFile),
+			Provenance:        readFile(testutil.ProvenanceFile),
+			TrustedRoot:       readFile(testutil.TrustedRootFile),
+			TrustedSourceRepo: trustedSourceRepo,
+			Logger:            slog.New(slog.NewJSONHandler(logs, nil)),
+		}
+	}
+
+	t.Run("builds the policy for the fork", func(t *testing.T) {
+		var logs bytes.Buffer
+		_, err := VerifyTrustedBundle(t.Context(), newConfig("acme/tpm-ca-certificates", &logs))
+		// Upstream assets must not satisfy a policy built for the fork
+		if !errors.Is(err, ErrBundleVerificationFailed) {
+			t.Fatalf("VerifyTrustedBundle() error = %v, want %v", err, ErrBundleVerificationFailed)
+		}
+
+		var entry map[string]any
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode log entry %q: %v", logs.String(), err)
+		}
+		if entry["source_repo"] != "acme/tpm-ca-certificates" {
+			t.Errorf("logged source_repo = %v, want acme/tpm-ca-certificates", entry["source_repo"])
+		}
+	})
+
+	t.Run("explicit upstream repository", func(t *testing.T) {
+		var logs bytes.Buffer
+		result, err := VerifyTrustedBundle(t.Context(), newConfig(github.SourceRepo.String(), &logs))
+		if err != nil {
+			t.Fatalf("VerifyTrustedBundle() error = %v", err)
+		}
+		if got := result.Policy.SourceRepo.String(); got != github.SourceRepo.String() {
+			t.Errorf("Policy.SourceRepo = %s, want %s", got, github.SourceRepo.String())
+		}
+	})
+
+	t.Run("invalid repository", func(t *testing.T) {
+		cfg := newConfig("not-a-repo", &bytes.Buffer{})
+		if err := cfg.CheckAndSetDefaults(); err == nil {
+			t.Fatal("Expected error with invalid trusted source repository")
+		}
+
+		getCfg := GetConfig{TrustedSourceRepo: "not-a-repo"}
+		if err := getCfg.CheckAndSetDefaults(); err == nil {
+			t.Fatal("Expected error with invalid trusted source repository")
+		}
+	})
+}
+
+// releaseHTTPClient serves a GitHub release whose assets are held in memory,
+// counting the requests and recording the downloaded assets.
+type releaseHTTPClient struct {
+	// assets are the release assets by name; if nil, the release carries the
+	// embedded test bundle, its checksums file and its signature
+	assets map[string][]byte
+	// attestations, if set, are served by the attestations API
+	attestations [][]byte
+
+	mu         sync.Mutex
+	requests   int
+	userAgents []string
+	downloaded []string
+}
+
+// newIntermediateReleaseHTTPClient returns a client serving a release carrying both a root
+// and an intermediate bundle.
+func newIntermediateReleaseHTTPClient(t *testing.T) *releaseHTTPClient {
+	t.Helper()
+	root, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	return &releaseHTTPClient{assets: map[string][]byte{
+		CacheRootBundleFilename:         root,
+		CacheIntermediateBundleFilename: bytes.Replace(root, []byte(CacheRootBundleFilename), []byte(CacheIntermediateBundleFilename), 1),
+		// Verification is skipped: only the listed filenames matter
+		CacheChecksumsFilename: []byte("0  " + CacheRootBundleFilename + "\n0  " + CacheIntermediateBundleFilename + "\n"),
+	}}
+}
+
+func (c *releaseHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	respond := func(status int, body []byte) (*http.Response, error) {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(body)), Header: make(http.Header)}, nil
+	}
+
+	c.mu.Lock()
+	c.requests++
+	c.userAgents = append(c.userAgents, req.Header.Get("User-Agent"))
+	c.mu.Unlock()
+
+	if req.URL.Host == "api.github.com" && strings.Contains(req.URL.Path, "/attestations/") {
+		if c.attestations == nil {
+			return respond(http.StatusNotFound, nil)
+		}
+		bundles := make([]string, len(c.attestations))
+		for i, attestation := range c.attestations {
+			bundles[i] = `{"bundle":` + string(attestation) + `}`
+		}
+		return respond(http.StatusOK, []byte(`{"attestations":[`+strings.Join(bundles, ",")+`]}`))
+	}
+	if req.URL.Host == "api.github.com" {
+		release := github.Release{TagName: testutil.BundleVersion}
+		for _, name := range c.assetNames() {
+			release.Assets = append(release.Assets, github.Asset{Name: name, BrowserDownloadURL: "https://assets.example.com/" + name})
+		}
+		var body []byte
+		if strings.HasSuffix(req.URL.Path, "/releases") {
+			body, _ = json.Marshal([]github.Release{release}) // latest release lookup
+		} else {
+			body, _ = json.Marshal(release)
+		}
+		return respond(http.StatusOK, body)
+	}
+
+	name := filepath.Base(req.URL.Path)
+	c.mu.Lock()
+	c.downloaded = append(c.downloaded, name)
+	c.mu.Unlock()
+	if c.assets == nil {
+		data, err := testutil.ReadTestFile(name)
+		if err != nil {
+			return respond(http.StatusNotFound, nil)
+		}
+		return respond(http.StatusOK, data)
+	}
+	data, ok := c.assets[name]
+	if !ok {
+		return respond(http.StatusNotFound, nil)
+	}
+	return respond(http.StatusOK, data)
+}
+
+// assetNames returns the names of the release assets.
+func (c *releaseHTTPClient) assetNames() []string {
+	if c.assets == nil {
+		return []string{testutil.RootBundleFile, testutil.ChecksumFile, testutil.ChecksumSigstoreFile}
+	}
+	return slices.Collect(maps.Keys(c.assets))
+}
+
+func (c *releaseHTTPClient) hasDownloaded(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Contains(c.downloaded, name)
+}
+
+func TestGetTrustedBundleSkipVerifyAcknowledgement(t *testing.T) {
+	t.Run("SkipVerify without acknowledgement is rejected", func(t *testing.T) {
+		_, err := GetTrustedBundle(t.Context(), GetConfig{
+			Date:       testutil.BundleVersion,
+			CachePath:  t.TempDir(),
+			SkipVerify: true,
+			HTTPClient: &releaseHTTPClient{},
+		})
+		if !errors.Is(err, ErrSkipVerifyNotAcknowledged) {
+			t.Fatalf("GetTrustedBundle() error = %v, want %v", err, ErrSkipVerifyNotAcknowledged)
+		}
+	})
+
+	t.Run("acknowledged SkipVerify is logged on every call", func(t *testing.T) {
+		var logs bytes.Buffer
+		cfg := GetConfig{
+			Date:                  testutil.BundleVersion,
+			CachePath:             t.TempDir(),
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+			Logger:                slog.New(slog.NewTextHandler(&logs, nil)),
+		}
+		for range 2 {
+			tb, err := GetTrustedBundle(t.Context(), cfg)
+			if err != nil {
+				t.Fatalf("GetTrustedBundle() error = %v", err)
+			}
+			tb.Stop()
+		}
+
+		if n := strings.Count(logs.String(), "bundle verification is skipped"); n != 2 {
+			t.Errorf("Expected the skipped verification to be logged on each call, got %d:\n%s", n, logs.String())
+		}
+		if !strings.Contains(logs.String(), "level=WARN") {
+			t.Errorf("Expected a warning, got:\n%s", logs.String())
+		}
+	})
+}
+
+func TestGetTrustedBundleRecordsCacheLookups(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := GetConfig{
+		Date:                  testutil.BundleVersion,
+		CachePath:             t.TempDir(),
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
+		HTTPClient:            &releaseHTTPClient{},
+		AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		Logger:                slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	for range 2 {
+		tb, err := GetTrustedBundle(t.Context(), cfg)
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		tb.Stop()
+	}
+
+	type cacheRecord struct {
+		Msg     string `json:"msg"`
+		Version string `json:"version"`
+		Hit     bool   `json:"hit"`
+	}
+	var records []cacheRecord
+	decoder := json.NewDecoder(&logs)
+	for decoder.More() {
+		var record cacheRecord
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("failed to decode log record: %v", err)
+		}
+		// Ignore the skipped verification warning
+		if strings.HasPrefix(record.Msg, "bundle cache") {
+			records = append(records, record)
+		}
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 cache lookup records, got %d: %+v", len(records), records)
+	}
+	if records[0].Hit || records[0].Msg != "bundle cache miss" {
+		t.Errorf("first lookup = %+v, want a cache miss", records[0])
+	}
+	if !records[1].Hit || records[1].Msg != "bundle cache hit" {
+		t.Errorf("second lookup = %+v, want a cache hit", records[1])
+	}
+	for _, record := range records {
+		if record.Version != testutil.BundleVersion {
+			t.Errorf("record version = %q, want %q", record.Version, testutil.BundleVersion)
+		}
+	}
+}
+
+func TestVerifyTrustedBundleCachesVerifiedAttestation(t *testing.T) {
+	files := readerTestFiles(t)
+	trustedRoot, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+	if err != nil {
+		t.Fatalf("failed to read trusted root: %v", err)
+	}
+	digest := ComputeBundleDigest(files[testutil.RootBundleFile])
+
+	verifyWith := func(cachePath string, checksumSignature []byte, attestations ...[]byte) error {
+		_, err := VerifyTrustedBundle(t.Context(), VerifyConfig{
+			Bundle:            files[testutil.RootBundleFile],
+			Checksum:          files[testutil.ChecksumFile],
+			ChecksumSignature: checksumSignature,
+			TrustedRoot:       trustedRoot,
+			CachePath:         cachePath,
+			HTTPClient: &releaseHTTPClient{
+				assets:       map[string][]byte{testutil.ChecksumFile: files[testutil.ChecksumFile]},
+				attestations: attestations,
+			},
+		})
+		return err
+	}
+	verify := func(cachePath string, checksumSignature []byte) error {
+		return verifyWith(cachePath, checksumSignature, files[testutil.ProvenanceFile])
+	}
+
+	t.Run("failed verification", func(t *testing.T) {
+		cachePath := t.TempDir()
+		// The provenance is not a signature of checksums.txt
+		if err := verify(cachePath, files[testutil.ProvenanceFile]); err == nil {
+			t.Fatal("VerifyTrustedBundle() expected error")
+		}
+		if _, err := cache.LoadAttestation(cachePath, digest, cache.DefaultAttestationTTL); !errors.Is(err, cache.ErrAttestationCacheMiss) {
+			t.Errorf("LoadAttestation() error = %v, want %v", err, cache.ErrAttestationCacheMiss)
+		}
+	})
+
+	t.Run("successful verification", func(t *testing.T) {
+		cachePath := t.TempDir()
+		if err := verify(cachePath, files[testutil.ChecksumSigstoreFile]); err != nil {
+			t.Fatalf("VerifyTrustedBundle() error = %v", err)
+		}
+		if _, err := cache.LoadAttestation(cachePath, digest, cache.DefaultAttestationTTL); err != nil {
+			t.Errorf("LoadAttestation() error = %v", err)
+		}
+	})
+
+	t.Run("only the matching attestation is cached", func(t *testing.T) {
+		cachePath := t.TempDir()
+		// A valid Sigstore bundle which is not an attestation of the bundle
+		unrelated := files[testutil.ChecksumSigstoreFile]
+		if err := verifyWith(cachePath, files[testutil.ChecksumSigstoreFile], unrelated, files[testutil.ProvenanceFile]); err != nil {
+			t.Fatalf("VerifyTrustedBundle() error = %v", err)
+		}
+		cached, err := cache.LoadAttestation(cachePath, digest, cache.DefaultAttestationTTL)
+		if err != nil {
+			t.Fatalf("LoadAttestation() error = %v", err)
+		}
+		want, err := utils.JsonCompact(files[testutil.ProvenanceFile])
+		if err != nil {
+			t.Fatalf("failed to compact provenance: %v", err)
+		}
+		if !bytes.Equal(cached, want) {
+			t.Errorf("expected the cached attestation to be the matching one, got %s", cached)
+		}
+	})
+}
+
+func TestGetTrustedBundleAppliesPatch(t *testing.T) {
+	const previousVersion = "2025-12-01"
+
+	target, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	checksums, err := testutil.ReadTestFile(testutil.ChecksumFile)
+	if err != nil {
+		t.Fatalf("failed to read checksums: %v", err)
+	}
+
+	// The previous version lacks the first certificate of the test bundle
+	entries, err := bundle.ParseEntries(target)
+	if err != nil || len(entries) < 2 {
+		t.Fatalf("failed to parse bundle entries: %v", err)
+	}
+	lines := strings.SplitAfter(string(target), "\n")
+	header := strings.Replace(strings.Join(lines[:entries[0].Line-1], ""),
+		bundle.MetadataKeyDate.String()+testutil.BundleVersion, bundle.MetadataKeyDate.String()+previousVersion, 1)
+	previous := []byte(header + strings.Join(lines[entries[1].Line-1:], ""))
+
+	patch, err := bundle.DiffBundles(previous, target)
+	if err != nil {
+		t.Fatalf("DiffBundles() error = %v", err)
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("failed to marshal patch: %v", err)
+	}
+	patchFilename := bundle.PatchFilename(bundleFilename, previousVersion)
+
+	// newCache returns a cache holding the previous version of the bundle
+	newCache := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		configData, _ := json.Marshal(CacheConfig{Version: previousVersion, SkipVerify: true, RootsOnly: true})
+		if err := cache.SaveFile(dir, cache.ConfigFilename, configData); err != nil {
+			t.Fatalf("failed to write cache config: %v", err)
+		}
+		if err := cache.SaveFile(dir, cache.RootBundleFilename, previous); err != nil {
+			t.Fatalf("failed to write cached bundle: %v", err)
+		}
+		return dir
+	}
+	getBundle := func(t *testing.T, client *releaseHTTPClient) []byte {
+		t.Helper()
+		tb, err := GetTrustedBundle(t.Context(), GetConfig{
+			Date:                  testutil.BundleVersion,
+			CachePath:             newCache(t),
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			RootsOnly:             true,
+			HTTPClient:            client,
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		})
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		defer tb.Stop()
+		return tb.GetRawRoot()
+	}
+
+	tests := []struct {
+		name             string
+		patch            []byte
+		wantFullDownload bool
+	}{
+		{name: "patch applied", patch: patchData},
+		{name: "integrity check failure", patch: bytes.ReplaceAll(patchData, []byte(testutil.BundleVersion), []byte("2025-12-31")), wantFullDownload: true},
+		{name: "invalid patch", patch: []byte("not a patch"), wantFullDownload: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &releaseHTTPClient{assets: map[string][]byte{
+				checksumsFile:  append(bytes.Clone(checksums), []byte(strings.TrimPrefix(digest.ComputeSHA256(tt.patch), "sha256:")+"  "+patchFilename+"\n")...),
+				bundleFilename: target,
+				patchFilename:  tt.patch,
+			}}
+
+			if got := getBundle(t, client); !bytes.Equal(got, target) {
+				t.Fatal("bundle differs from the full download")
+			}
+			if !slices.Contains(client.downloaded, patchFilename) {
+				t.Errorf("expected the patch to be downloaded, got %v", client.downloaded)
+			}
+			if got := slices.Contains(client.downloaded, bundleFilename); got != tt.wantFullDownload {
+				t.Errorf("full bundle downloaded = %v, want %v", got, tt.wantFullDownload)
+			}
+		})
+	}
+
+	t.Run("no patch published", func(t *testing.T) {
+		client := &releaseHTTPClient{assets: map[string][]byte{
+			checksumsFile:  checksums,
+			bundleFilename: target,
+		}}
+		if got := getBundle(t, client); !bytes.Equal(got, target) {
+			t.Fatal("bundle differs from the full download")
+		}
+		if !slices.Equal(client.downloaded, []string{checksumsFile, bundleFilename}) {
+			t.Errorf("unexpected downloads: %v", client.downloaded)
+		}
+	})
+}
+
+func TestGetTrustedBundleEmptyVendorFilter(t *testing.T) {
+	// The test bundle only contains IFX, INTC, NTC and STM root certificates
+	newConfig := func() GetConfig {
+		return GetConfig{
+			Date:                  testutil.BundleVersion,
+			VendorIDs:             []VendorID{MSFT},
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			DisableLocalCache:     true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		}
+	}
+
+	t.Run("warns by default", func(t *testing.T) {
+		var logs bytes.Buffer
+		cfg := newConfig()
+		cfg.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+		tb, err := GetTrustedBundle(t.Context(), cfg)
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		defer tb.Stop()
+
+		if len(tb.GetVendors()) != 0 {
+			t.Errorf("expected no vendor, got %v", tb.GetVendors())
+		}
+		if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), ErrEmptyVendorFilter.Error()) {
+			t.Errorf("expected an empty vendor filter warning, got %q", logs.String())
+		}
+	})
+
+	t.Run("fails with RequireNonEmpty", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.RequireNonEmpty = true
+
+		_, err := GetTrustedBundle(t.Context(), cfg)
+		if !errors.Is(err, ErrEmptyVendorFilter) {
+			t.Fatalf("expected ErrEmptyVendorFilter, got %v", err)
+		}
+	})
+
+	t.Run("partial match is accepted", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.VendorIDs = append(cfg.VendorIDs, IFX)
+		cfg.RequireNonEmpty = true
+
+		tb, err := GetTrustedBundle(t.Context(), cfg)
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		tb.Stop()
+	})
+}
+
+func TestGetTrustedBundleCacheNamespace(t *testing.T) {
+	cachePath := t.TempDir()
+	namespaces := []string{"root-only", "ifx"}
+
+	for _, namespace := range namespaces {
+		cfg := GetConfig{
+			Date:                  testutil.BundleVersion,
+			CachePath:             cachePath,
+			CacheNamespace:        namespace,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		}
+		if namespace == "ifx" {
+			cfg.VendorIDs = []VendorID{IFX}
+		}
+		tb, err := GetTrustedBundle(t.Context(), cfg)
+		if err != nil {
+			t.Fatalf("GetTrustedBundle(%q) error = %v", namespace, err)
+		}
+		tb.Stop()
+	}
+
+	// The flat layout must be left untouched
+	if _, err := os.Stat(filepath.Join(cachePath, cache.ConfigFilename)); !os.IsNotExist(err) {
+		t.Errorf("expected no %s at the cache root, got err = %v", cache.ConfigFilename, err)
+	}
+
+	for _, namespace := range namespaces {
+		for _, filename := range []string{cache.ConfigFilename, cache.RootBundleFilename} {
+			if _, err := os.Stat(filepath.Join(cachePath, namespace, filename)); err != nil {
+				t.Errorf("expected %s in namespace %q: %v", filename, namespace, err)
+			}
+		}
+
+		tb, err := LoadTrustedBundle(t.Context(), LoadConfig{
+			CachePath:             cachePath,
+			CacheNamespace:        namespace,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+		})
+		if err != nil {
+			t.Fatalf("LoadTrustedBundle(%q) error = %v", namespace, err)
+		}
+		tb.Stop()
+	}
+
+	configData, err := os.ReadFile(filepath.Join(cachePath, "ifx", cache.ConfigFilename))
+	if err != nil {
+		t.Fatalf("failed to read cache config: %v", err)
+	}
+	var cacheCfg CacheConfig
+	if err := json.Unmarshal(configData, &cacheCfg); err != nil {
+		t.Fatalf("failed to unmarshal cache config: %v", err)
+	}
+	if len(cacheCfg.VendorIDs) != 1 || cacheCfg.VendorIDs[0] != IFX {
+		t.Errorf("cache config vendor IDs = %v, want [%s]", cacheCfg.VendorIDs, IFX)
+	}
+
+	for _, namespace := range []string{"../escape", "a/b", ".hidden"} {
+		_, err := GetTrustedBundle(t.Context(), GetConfig{
+			CachePath:      cachePath,
+			CacheNamespace: namespace,
+			HTTPClient:     &releaseHTTPClient{},
+		})
+		if err == nil || !strings.Contains(err.Error(), "invalid cache namespace") {
+			t.Errorf("GetTrustedBundle(%q) error = %v, want invalid cache namespace", namespace, err)
+		}
+	}
+}
+
+func TestGetTrustedBundleExpectedCommit(t *testing.T) {
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	metadata, err := bundle.ParseMetadata(bundleData)
+	if err != nil {
+		t.Fatalf("failed to parse bundle metadata: %v", err)
+	}
+
+	getBundle := func(expectedCommit string) (TrustedBundle, error) {
+		return GetTrustedBundle(t.Context(), GetConfig{
+			Date:                  testutil.BundleVersion,
+			ExpectedCommit:        expectedCommit,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			DisableLocalCache:     true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		})
+	}
+
+	tb, err := getBundle(metadata.Commit)
+	if err != nil {
+		t.Fatalf("GetTrustedBundle() with matching commit error = %v", err)
+	}
+	tb.Stop()
+
+	wrongCommit := strings.Repeat("0", 40)
+	_, err = getBundle(wrongCommit)
+	var mismatchErr *CommitMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("GetTrustedBundle() error = %v, want %T", err, mismatchErr)
+	}
+	if mismatchErr.Expected != wrongCommit || mismatchErr.Actual != metadata.Commit || mismatchErr.Date != testutil.BundleVersion {
+		t.Errorf("unexpected commit mismatch error: %+v", mismatchErr)
+	}
+
+	if _, err := GetTrustedBundle(t.Context(), GetConfig{ExpectedCommit: metadata.Commit}); err == nil {
+		t.Error("GetTrustedBundle() expected error when ExpectedCommit is set without Date")
+	}
+}
+
+// failingRoundTripper rejects every request.
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("fallback HTTP client must not be used")
+}
+
+func TestGetTrustedBundlePerConfigHTTPClient(t *testing.T) {
+	previous := HTTPClient()
+	t.Cleanup(func() { SetHTTPClient(previous) })
+	SetHTTPClient(&http.Client{Transport: failingRoundTripper{}})
+
+	clients := []*releaseHTTPClient{{}, {}}
+	var wg sync.WaitGroup
+	errs := make([]error, len(clients))
+	for i, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tb, err := GetTrustedBundle(t.Context(), GetConfig{
+				Date:                  testutil.BundleVersion,
+				SkipVerify:            true,
+				AcknowledgeSkipVerify: true,
+				DisableLocalCache:     true,
+				HTTPClient:            client,
+				AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+			})
+			if err == nil {
+				tb.Stop()
+			}
+			errs[i] = err
+		}()
+		// Swapping the fallback client while calls are in flight must not affect them
+		SetHTTPClient(&http.Client{Transport: failingRoundTripper{}})
+	}
+	wg.Wait()
+
+	for i, client := range clients {
+		if errs[i] != nil {
+			t.Errorf("GetTrustedBundle() with client %d error = %v", i, errs[i])
+		}
+		if client.requests == 0 {
+			t.Errorf("client %d served no request", i)
+		}
+	}
+	if clients[0].requests != clients[1].requests {
+		t.Errorf("clients served %d and %d requests, want the same number", clients[0].requests, clients[1].requests)
+	}
+
+	SetHTTPClient(nil)
+	if HTTPClient() != defaultHTTPClient {
+		t.Error("SetHTTPClient(nil) should restore the default client")
+	}
+}
+
+func TestGetTrustedBundleRootsOnly(t *testing.T) {
+	cachePath := t.TempDir()
+	getBundle := func(t *testing.T, client *releaseHTTPClient, rootsOnly bool) TrustedBundle {
+		t.Helper()
+		tb, err := GetTrustedBundle(t.Context(), GetConfig{
+			Date:                  testutil.BundleVersion,
+			CachePath:             cachePath,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			RootsOnly:             rootsOnly,
+			HTTPClient:            client,
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		})
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		t.Cleanup(func() { tb.Stop() })
+		return tb
+	}
+
+	t.Run("intermediate bundle is not fetched", func(t *testing.T) {
+		client := newIntermediateReleaseHTTPClient(t)
+		tb := getBundle(t, client, true)
+
+		if client.hasDownloaded(CacheIntermediateBundleFilename) {
+			t.Error("intermediate bundle must not be downloaded when RootsOnly is set")
+		}
+		if len(tb.GetRawIntermediate()) != 0 || tb.GetIntermediateMetadata() != nil {
+			t.Error("expected no intermediate bundle")
+		}
+		if !tb.GetIntermediateCertPool().Equal(x509.NewCertPool()) {
+			t.Error("expected an empty intermediate pool")
+		}
+
+		cacheCfg, err := getCacheConfig(cachePath)
+		if err != nil {
+			t.Fatalf("failed to read cache config: %v", err)
+		}
+		if !cacheCfg.RootsOnly {
+			t.Error("expected RootsOnly to be persisted in the cache config")
+		}
+	})
+
+	t.Run("load ignores a stale intermediate bundle", func(t *testing.T) {
+		root, err := testutil.ReadTestFile(testutil.RootBundleFile)
+		if err != nil {
+			t.Fatalf("failed to read test bundle: %v", err)
+		}
+		intermediate := bytes.Replace(root, []byte(CacheRootBundleFilename), []byte(CacheIntermediateBundleFilename), 1)
+		if err := os.WriteFile(filepath.Join(cachePath, CacheIntermediateBundleFilename), intermediate, 0644); err != nil {
+			t.Fatalf("failed to write stale intermediate bundle: %v", err)
+		}
+
+		tb, err := LoadTrustedBundle(t.Context(), LoadConfig{CachePath: cachePath, SkipVerify: true, AcknowledgeSkipVerify: true})
+		if err != nil {
+			t.Fatalf("LoadTrustedBundle() error = %v", err)
+		}
+		defer tb.Stop()
+		if len(tb.GetRawIntermediate()) != 0 {
+			t.Error("expected the intermediate bundle to be ignored by a roots-only cache")
+		}
+	})
+
+	t.Run("full bundle refreshes a roots-only cache", func(t *testing.T) {
+		client := newIntermediateReleaseHTTPClient(t)
+		tb := getBundle(t, client, false)
+
+		if !client.hasDownloaded(CacheIntermediateBundleFilename) {
+			t.Error("expected the intermediate bundle to be downloaded")
+		}
+		if len(tb.GetRawIntermediate()) == 0 {
+			t.Error("expected an intermediate bundle")
+		}
+		if checkCacheRootsOnly(cachePath) {
+			t.Error("expected the cache to be refreshed with the intermediate bundle")
+		}
+	})
+}
+
+func TestGetTrustedBundleSkipVerifyCacheNotReused(t *testing.T) {
+	files := readerTestFiles(t)
+	trustedRoot, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+	if err != nil {
+		t.Fatalf("failed to read trusted root: %v", err)
+	}
+	cachePath := t.TempDir()
+	// Serve the provenance from the attestation cache rather than the GitHub API
+	if err := cache.SaveAttestation(cachePath, ComputeBundleDigest(files[testutil.RootBundleFile]), files[testutil.ProvenanceFile]); err != nil {
+		t.Fatalf("failed to cache attestation: %v", err)
+	}
+	newClient := func() *releaseHTTPClient {
+		return &releaseHTTPClient{assets: map[string][]byte{
+			testutil.RootBundleFile:       files[testutil.RootBundleFile],
+			testutil.ChecksumFile:         files[testutil.ChecksumFile],
+			testutil.ChecksumSigstoreFile: files[testutil.ChecksumSigstoreFile],
+		}}
+	}
+
+	// Populate the cache without verification, then tamper with the unverified bytes
+	tb, err := GetTrustedBundle(t.Context(), GetConfig{
+		Date:                  testutil.BundleVersion,
+		CachePath:             cachePath,
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
+		RootsOnly:             true,
+		HTTPClient:            newClient(),
+		AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+	})
+	if err != nil {
+		t.Fatalf("GetTrustedBundle() error = %v", err)
+	}
+	tb.Stop()
+	if !checkCacheSkipVerify(cachePath) {
+		t.Fatal("expected the cache to record the skipped verification")
+	}
+	poisoned := bytes.Replace(files[testutil.RootBundleFile], []byte("Owner: IFX"), []byte("Owner: STM"), 1)
+	if err := os.WriteFile(filepath.Join(cachePath, CacheRootBundleFilename), poisoned, 0644); err != nil {
+		t.Fatalf("failed to tamper with the cached bundle: %v", err)
+	}
+
+	client := newClient()
+	tb, err = GetTrustedBundle(t.Context(), GetConfig{
+		Date:        testutil.BundleVersion,
+		CachePath:   cachePath,
+		RootsOnly:   true,
+		HTTPClient:  client,
+		AutoUpdate:  AutoUpdateConfig{DisableAutoUpdate: true},
+		trustedRoot: trustedRoot,
+	})
+	if err != nil {
+		t.Fatalf("GetTrustedBundle() error = %v", err)
+	}
+	defer tb.Stop()
+
+	if !slices.Contains(client.downloaded, testutil.RootBundleFile) {
+		t.Error("expected the bundle to be downloaded again")
+	}
+	if !bytes.Equal(tb.GetRawRoot(), files[testutil.RootBundleFile]) {
+		t.Error("expected the verified bundle, got the cached one")
+	}
+	cached, err := os.ReadFile(filepath.Join(cachePath, CacheRootBundleFilename))
+	if err != nil {
+		t.Fatalf("failed to read cached bundle: %v", err)
+	}
+	if !bytes.Equal(cached, files[testutil.RootBundleFile]) || checkCacheSkipVerify(cachePath) {
+		t.Error("expected the cache to be overwritten with the verified bundle")
+	}
+}
+
+func TestGetTrustedBundleReadOnlyFallbackCache(t *testing.T) {
+	files := readerTestFiles(t)
+	trustedRoot, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+	if err != nil {
+		t.Fatalf("failed to read trusted root: %v", err)
+	}
+	// The fallback holds the release but was persisted without verification
+	fallbackPath := testutil.CreateCacheDir(t, []byte(`{"version":"`+testutil.BundleVersion+`","lastTimestamp":"2025-12-05T00:00:00Z","skipVerify":true}`))
+	writablePath := t.TempDir()
+
+	tb, err := GetTrustedBundle(t.Context(), GetConfig{
+		Date:               testutil.BundleVersion,
+		CachePath:          writablePath,
+		FallbackCachePaths: []string{fallbackPath},
+		HTTPClient:         &releaseHTTPClient{attestations: [][]byte{files[testutil.ProvenanceFile]}},
+		AutoUpdate:         AutoUpdateConfig{DisableAutoUpdate: true},
+		trustedRoot:        trustedRoot,
+	})
+	if err != nil {
+		t.Fatalf("GetTrustedBundle() error = %v", err)
+	}
+	defer tb.Stop()
+
+	digest := ComputeBundleDigest(files[testutil.RootBundleFile])
+	if _, err := cache.LoadAttestation(writablePath, digest, cache.DefaultAttestationTTL); err != nil {
+		t.Errorf("expected the attestation to be cached in the writable cache: %v", err)
+	}
+	if _, err := cache.LoadAttestation(fallbackPath, digest, cache.DefaultAttestationTTL); !errors.Is(err, cache.ErrAttestationCacheMiss) {
+		t.Errorf("fallback cache must not be written, LoadAttestation() error = %v", err)
+	}
+}
+
+func TestVerifyTrustedBundleAllowedSignatureAlgorithms(t *testing.T) {
+	t.Run("SHA-1 signed certificate", func(t *testing.T) {
+		der, _ := testutil.GenerateTestCertRSA(t, 2048, x509.SHA1WithRSA)
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Failed to parse certificate: %v", err)
+		}
+		bundleData := bundle.BuildBundleHeader("", testutil.BundleVersion, strings.Repeat("a", 40), bundle.TypeRoot) +
+			bundle.BuildCertificateHeader(cert, "SHA-1 Root", "STM") + string(bundle.EncodePEM(cert))
+
+		_, err = VerifyTrustedBundle(t.Context(), VerifyConfig{
+			Bundle:                     []byte(bundleData),
+			HTTPClient:                 &http.Client{Transport: failingRoundTripper{}},
+			DisableLocalCache:          true,
+			AllowedSignatureAlgorithms: []x509.SignatureAlgorithm{x509.SHA256WithRSA},
+		})
+		if !errors.Is(err, ErrDisallowedAlgorithm) {
+			t.Fatalf("VerifyTrustedBundle() error = %v, want %v", err, ErrDisallowedAlgorithm)
+		}
+		if !strings.Contains(err.Error(), x509.SHA1WithRSA.String()) {
+			t.Errorf("expected the error to name the algorithm, got: %v", err)
+		}
+	})
+
+	t.Run("allowed algorithms", func(t *testing.T) {
+		readFile := func(name string) []byte {
+			data, err := testutil.ReadTestFile(name)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", name, err)
+			}
+			return data
+		}
+		bundleData := readFile(testutil.RootBundleFile)
+		catalog, err := bundle.ParseBundle(bundleData)
+		if err != nil {
+			t.Fatalf("Failed to parse bundle: %v", err)
+		}
+		var allowed []x509.SignatureAlgorithm
+		for _, certs := range catalog {
+			for _, cert := range certs {
+				if !slices.Contains(allowed, cert.SignatureAlgorithm) {
+					allowed = append(allowed, cert.SignatureAlgorithm)
+				}
+			}
+		}
+
+		if _, err := VerifyTrustedBundle(t.Context(), VerifyConfig{
+			Bundle:                     bundleData,
+			Checksum:                   readFile(testutil.ChecksumFile),
+			ChecksumSignature:          readFile(testutil.ChecksumSigstoreFile),
+			Provenance:                 readFile(testutil.ProvenanceFile),
+			TrustedRoot:                readFile(testutil.TrustedRootFile),
+			DisableLocalCache:          true,
+			AllowedSignatureAlgorithms: allowed,
+		}); err != nil {
+			t.Fatalf("VerifyTrustedBundle() error = %v", err)
+		}
+	})
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	newCache := func(t *testing.T, version string) string {
+		t.Helper()
+		dir := t.TempDir()
+		configData, _ := json.Marshal(CacheConfig{Version: version})
+		if err := cache.SaveFile(dir, cache.ConfigFilename, configData); err != nil {
+			t.Fatalf("failed to write cache config: %v", err)
+		}
+		return dir
+	}
+
+	tests := []struct {
+		name        string
+		cachePath   string
+		wantCurrent string
+		wantUpdate  bool
+	}{
+		{"cache at an older version", newCache(t, "2025-01-01"), "2025-01-01", true},
+		{"cache at the latest version", newCache(t, testutil.BundleVersion), testutil.BundleVersion, false},
+		{"no cache", t.TempDir(), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &releaseHTTPClient{assets: map[string][]byte{bundleFilename: nil}}
+			info, err := CheckForUpdate(t.Context(), CheckForUpdateConfig{
+				CachePath:  tt.cachePath,
+				HTTPClient: client,
+			})
+			if err != nil {
+				t.Fatalf("CheckForUpdate() error = %v", err)
+			}
+			want := UpdateInfo{CurrentVersion: tt.wantCurrent, LatestVersion: testutil.BundleVersion, UpdateAvailable: tt.wantUpdate}
+			if *info != want {
+				t.Errorf("CheckForUpdate() = %+v, want %+v", *info, want)
+			}
+			if len(client.downloaded) > 0 {
+				t.Errorf("expected no asset to be downloaded, got %v", client.downloaded)
+			}
+		})
+	}
+}
+
+func TestGetTrustedBundleVendorFilterReducesCatalog(t *testing.T) {
+	getBundle := func(t *testing.T, vendorIDs ...VendorID) *trustedBundle {
+		t.Helper()
+		tb, err := GetTrustedBundle(t.Context(), GetConfig{
+			Date:                  testutil.BundleVersion,
+			VendorIDs:             vendorIDs,
+			DisableLocalCache:     true,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		})
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		t.Cleanup(func() { tb.Stop() })
+		return tb.(*trustedBundle)
+	}
+
+	all := getBundle(t)
+	filtered := getBundle(t, IFX, STM)
+
+	if got := slices.Sorted(maps.Keys(filtered.rootCatalog)); !slices.Equal(got, []VendorID{IFX, STM}) {
+		t.Errorf("filtered catalog vendors = %v, want [IFX STM]", got)
+	}
+	countCerts := func(tb *trustedBundle) (n int) {
+		for _, certs := range tb.rootCatalog {
+			n += len(certs)
+		}
+		return n
+	}
+	if countCerts(filtered) >= countCerts(all) {
+		t.Errorf("expected the filter to reduce the catalog, got %d certificates (unfiltered: %d)", countCerts(filtered), countCerts(all))
+	}
+	if !bytes.Equal(filtered.GetRawRoot(), all.GetRawRoot()) {
+		t.Error("expected the raw bundle to be kept untouched")
+	}
+
+	// The filtered certificates are still the ones exposed
+	for _, vendorID := range []VendorID{IFX, STM} {
+		for _, cert := range all.rootCatalog[vendorID] {
+			if !filtered.Contains(cert) {
+				t.Errorf("filtered bundle misses %s certificate %s", vendorID, cert.Subject)
+			}
+		}
+	}
+}
+
+func TestComputeBundleDigest(t *testing.T) {
+	// Digest of the test bundle, metadata header included
+	const expectedDigest = "sha256:f5c7f9e9c59d65f1a889b1cdc712a3ea674df84bd8dc15081165b41ac2496ed2"
+
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+
+	if got := ComputeBundleDigest(bundleData); got != expectedDigest {
+		t.Errorf("ComputeBundleDigest() = %s, want %s", got, expectedDigest)
+	}
+
+	// The digest covers the metadata header, not only the certificates
+	entries, err := bundle.ParseEntries(bundleData)
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("failed to parse bundle entries: %v", err)
+	}
+	lines := strings.SplitAfter(string(bundleData), "\n")
+	certificates := strings.Join(lines[entries[0].Line-1:], "")
+	if ComputeBundleDigest([]byte(certificates)) == expectedDigest {
+		t.Error("expected the metadata header to be part of the digest")
+	}
+}
+
+func TestTrustedRootUnavailable(t *testing.T) {
+	unavailable := fmt.Errorf("cosign verification failed: failed to produce sigstore verifier config: %w",
+		fmt.Errorf("%w: %w", ErrTrustedRootUnavailable, errors.New("connection refused")))
+	failed := fmt.Errorf("github attestation verification failed: %w", ErrSubjectDigestMismatch)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"trusted root unavailable", unavailable, true},
+		{"every phase unavailable", errors.Join(unavailable, unavailable), true},
+		{"verification failure", failed, false},
+		{"verification failure and unavailable trusted root", errors.Join(unavailable, failed), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trustedRootUnavailable(tt.err); got != tt.want {
+				t.Errorf("trustedRootUnavailable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package apiv1beta
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+)
+
+// Archive writes all assets to w as a gzip-compressed tar archive, each asset being stored
+// under its cache filename (see [CacheFilenames]).
+//
+// It bundles the assets required for offline verification in a single file, e.g. to move
+// them to an air-gapped environment. Use [LoadFromArchive] to load the bundle from the archive.
+func (sr *SaveResponse) Archive(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{cache.RootBundleFilename, sr.RootBundle},
+		{cache.IntermediateBundleFilename, sr.IntermediateBundle},
+		{cache.ChecksumsFilename, sr.Checksum},
+		{cache.ChecksumsSigFilename, sr.ChecksumSignature},
+		{cache.ProvenanceFilename, sr.Provenance},
+		{cache.TrustedRootFilename, sr.TrustedRoot},
+		{cache.ConfigFilename, sr.CacheConfig},
+	}
+	for _, f := range files {
+		// The intermediate bundle is missing from the first releases
+		if len(f.data) == 0 {
+			continue
+		}
+		header := &tar.Header{
+			Name:     f.name,
+			Mode:     0644,
+			Size:     int64(len(f.data)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s header: %w", f.name, err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	return nil
+}
+
+// LoadFromArchive reads a [TrustedBundle] from an archive written by [SaveResponse.Archive]
+// and verifies its integrity, like [LoadTrustedBundle].
+//
+// The archive is extracted into a temporary directory, removed once the bundle is loaded.
+// The loaded bundle is a snapshot: it is not auto-updated and [TrustedBundle.RefreshNow]
+// returns [ErrRefreshUnsupported]. The cache location fields of cfg must be left empty.
+//
+// Example:
+//
+//	f, err := os.Open("tpm-ca-certificates.tar.gz")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	tb, err := apiv1beta.LoadFromArchive(context.Background(), f, apiv1beta.LoadConfig{
+//	    OfflineMode: true,
+//	})
+func LoadFromArchive(ctx context.Context, r io.Reader, cfg LoadConfig) (TrustedBundle, error) {
+	if cfg.CachePath != "" || cfg.CacheNamespace != "" || len(cfg.FallbackCachePaths) > 0 {
+		return nil, fmt.Errorf("cache path cannot be set when loading from an archive")
+	}
+
+	dir, err := os.MkdirTemp("", "tpmtb-archive-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractArchive(r, dir); err != nil {
+		return nil, err
+	}
+
+	cfg.CachePath = dir
+	cfg.snapshot = true
+	return LoadTrustedBundle(ctx, cfg)
+}
+
+// extractArchive extracts the cache files of a gzip-compressed tar archive into dir.
+//
+// Any other entry, including directories and links, is rejected.
+func extractArchive(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !slices.Contains(CacheFilenames, header.Name) {
+			return fmt.Errorf("unexpected archive entry %q", header.Name)
+		}
+		if header.Size > utils.DefaultMaxFileSize {
+			return fmt.Errorf("archive entry %q is too large: exceeds %d bytes", header.Name, utils.DefaultMaxFileSize)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read archive entry %q: %w", header.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, header.Name), data, 0600); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package apiv1beta
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	configData, _ := json.Marshal(CacheConfig{Version: testutil.BundleVersion})
+	cachePath := testutil.CreateCacheDir(t, configData)
+
+	trustedRootData, err := testutil.ReadTestFile(testutil.TrustedRootFile)
+	if err != nil {
+		t.Fatalf("Failed to read trusted root: %v", err)
+	}
+
+	resp, err := SaveTrustedBundle(t.Context(), SaveConfig{
+		Date:               testutil.BundleVersion,
+		CachePath:          cachePath,
+		HTTPClient:         &recordingHTTPClient{},
+		OfflineTrustedRoot: trustedRootData,
+	})
+	if err != nil {
+		t.Fatalf("SaveTrustedBundle() error = %v", err)
+	}
+
+	var archive bytes.Buffer
+	if err := resp.Archive(&archive); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+
+	tb, err := LoadFromArchive(t.Context(), &archive, LoadConfig{OfflineMode: true})
+	if err != nil {
+		t.Fatalf("LoadFromArchive() error = %v", err)
+	}
+	defer tb.Stop()
+
+	if got := tb.GetRootMetadata().Date; got != testutil.BundleVersion {
+		t.Errorf("bundle date = %s, want %s", got, testutil.BundleVersion)
+	}
+	if !bytes.Equal(tb.GetRawRoot(), resp.RootBundle) {
+		t.Error("expected the loaded bundle to match the saved one")
+	}
+	if _, err := tb.RefreshNow(t.Context()); !errors.Is(err, ErrRefreshUnsupported) {
+		t.Errorf("RefreshNow() error = %v, want %v", err, ErrRefreshUnsupported)
+	}
+}
+
+func TestLoadFromArchiveRejectsInvalidArchives(t *testing.T) {
+	newArchive := func(t *testing.T, header *tar.Header, data []byte) *bytes.Buffer {
+		t.Helper()
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := gw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return &buf
+	}
+
+	tests := []struct {
+		name    string
+		archive func(t *testing.T) *bytes.Buffer
+		cfg     LoadConfig
+	}{
+		{
+			name: "path traversal",
+			archive: func(t *testing.T) *bytes.Buffer {
+				return newArchive(t, &tar.Header{Name: "../" + CacheRootBundleFilename, Mode: 0644, Size: 1, Typeflag: tar.TypeReg}, []byte("x"))
+			},
+		},
+		{
+			name: "unknown file",
+			archive: func(t *testing.T) *bytes.Buffer {
+				return newArchive(t, &tar.Header{Name: "other.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}, []byte("x"))
+			},
+		},
+		{
+			name: "not gzip",
+			archive: func(t *testing.T) *bytes.Buffer {
+				return bytes.NewBufferString("not an archive")
+			},
+		},
+		{
+			name: "cache path set",
+			archive: func(t *testing.T) *bytes.Buffer {
+				return &bytes.Buffer{}
+			},
+			cfg: LoadConfig{CachePath: t.TempDir()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadFromArchive(t.Context(), tt.archive(t), tt.cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
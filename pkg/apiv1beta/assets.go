@@ -6,14 +6,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"slices"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/observability"
-	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/cosign"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -26,12 +29,18 @@ type assetsConfig struct {
 	bundle                []byte
 	httpClient            utils.HTTPClient
 	sourceRepo            *github.Repo
-	cachePath             string
 	disableLocalCache     bool
 	tag                   string
 	needChecksums         bool
 	needChecksumSignature bool
 	needProvenance        bool
+	rootsOnly             bool
+	logger                *slog.Logger
+
+	// cachePath is the cache the assets are read from, possibly a read-only fallback cache.
+	cachePath string
+	// writableCachePath is the cache the assets are written to: the configured CachePath.
+	writableCachePath string
 }
 
 func (c *assetsConfig) CheckAndSetDefaults() error {
@@ -44,6 +53,9 @@ func (c *assetsConfig) CheckAndSetDefaults() error {
 	if c.cachePath == "" {
 		c.cachePath = cache.CacheDir()
 	}
+	if c.writableCachePath == "" {
+		c.writableCachePath = c.cachePath
+	}
 	if c.sourceRepo == nil {
 		c.sourceRepo = &github.Repo{
 			Owner: github.SourceRepo.Owner,
@@ -60,12 +72,57 @@ func (c *assetsConfig) shouldFetchVerificationAssets() bool {
 	return c.needChecksums || c.needChecksumSignature || c.needProvenance
 }
 
+// recordCacheLookup logs whether the assets of the requested version were served from the local cache.
+func (c *assetsConfig) recordCacheLookup(ctx context.Context, hit bool) {
+	if c.logger == nil {
+		return
+	}
+	msg := "bundle cache miss"
+	if hit {
+		msg = "bundle cache hit"
+	}
+	c.logger.DebugContext(ctx, msg, slog.String("version", c.tag), slog.Bool("hit", hit))
+}
+
 type assets struct {
 	rootBundleData         []byte
 	intermediateBundleData []byte
 	checksum               []byte
 	checksumSignature      []byte
 	provenance             []byte
+
+	// attestations holds every attestation downloaded for the bundle digest when there are
+	// several. provenance is then the first of them until verification picks the one
+	// matching the policy (see [assets.useVerifiedProvenance]).
+	attestations [][]byte
+
+	// provenanceDownloaded is true when provenance was fetched from the GitHub API
+	// rather than from the attestation cache, and is not cached yet.
+	provenanceDownloaded bool
+}
+
+// cacheAttestation stores the provenance downloaded from the GitHub API in the attestation
+// cache of cacheDir.
+//
+// It must only be called once the provenance has been verified: the attestation cache is
+// trusted to serve attestations without fetching them again.
+func (a *assets) cacheAttestation(cacheDir string) {
+	if !a.provenanceDownloaded || len(a.provenance) == 0 {
+		return
+	}
+	// Caching is best-effort: a failure must not fail the verification
+	_ = cache.SaveAttestation(cacheDir, ComputeBundleDigest(a.rootBundleData), a.provenance)
+	a.provenanceDownloaded = false
+}
+
+// useVerifiedProvenance keeps the attestation which matched the policy during verification,
+// so that provenance.json and the attestation cache hold a single attestation.
+func (a *assets) useVerifiedProvenance(result *VerifyResult) {
+	if result == nil || len(result.Provenance) == 0 {
+		return
+	}
+	a.provenance = result.Provenance
+	a.attestations = nil
 }
 
 func getAssets(ctx context.Context, cfg assetsConfig) (*assets, error) {
@@ -90,7 +147,9 @@ func getAssets(ctx context.Context, cfg assetsConfig) (*assets, error) {
 				return nil, fmt.Errorf("failed to load from cache: %w", err)
 			}
 		}
+		cfg.recordCacheLookup(ctx, assets != nil)
 	}
+	span.SetAttributes(attribute.Bool("tpmtb.cache.hit", assets != nil))
 
 	if assets == nil {
 		assets, err = getAssetsFromGitHub(ctx, cfg)
@@ -113,10 +172,19 @@ func getAssetsFromCache(ctx context.Context, cfg assetsConfig) (*assets, error)
 		observability.RecordError(span, err)
 		return nil, err
 	}
-	intermediateBundleData, err := cache.LoadFile(cfg.cachePath, cache.IntermediateBundleFilename)
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		observability.RecordError(span, err)
-		return nil, err
+
+	var intermediateBundleData []byte
+	if !cfg.rootsOnly {
+		// A roots-only cache lacks the intermediate bundle: fallback to GitHub
+		if checkCacheRootsOnly(cfg.cachePath) {
+			observability.RecordError(span, ErrIncompleteCache)
+			return nil, ErrIncompleteCache
+		}
+		intermediateBundleData, err = cache.LoadFile(cfg.cachePath, cache.IntermediateBundleFilename)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			observability.RecordError(span, err)
+			return nil, err
+		}
 	}
 
 	result := &assets{
@@ -226,8 +294,7 @@ func getAssetsFromGitHub(ctx context.Context, cfg assetsConfig) (*assets, error)
 	// Step 5: Download provenance if needed
 	if cfg.needProvenance {
 		provenanceCtx, provenanceSpan := observability.StartSpan(ctx, "tpmtb.downloadProvenance")
-		var provenanceErr error
-		response.provenance, provenanceErr = downloadProvenance(provenanceCtx, client, cfg, response.rootBundleData)
+		attestations, downloaded, provenanceErr := downloadProvenance(provenanceCtx, client, cfg, response.rootBundleData)
 		if provenanceErr != nil {
 			observability.RecordError(provenanceSpan, provenanceErr)
 			provenanceSpan.End()
@@ -235,6 +302,10 @@ func getAssetsFromGitHub(ctx context.Context, cfg assetsConfig) (*assets, error)
 			return nil, provenanceErr
 		}
 		provenanceSpan.End()
+		response.provenance, response.provenanceDownloaded = attestations[0], downloaded
+		if len(attestations) > 1 {
+			response.attestations = attestations
+		}
 	}
 
 	return response, nil
@@ -276,7 +347,7 @@ func downloadMissingBundles(ctx context.Context, client *github.HTTPClient, cfg
 		g.Go(func() error {
 			ctx, span := observability.StartSpan(gctx, "tpmtb.downloadRootBundle")
 			defer span.End()
-			data, err := client.DownloadReleaseAsset(ctx, *cfg.sourceRepo, cfg.tag, bundleFilename)
+			data, err := downloadBundle(ctx, client, cfg, checksum, bundleFilename)
 			if err != nil {
 				observability.RecordError(span, err)
 				return fmt.Errorf("failed to download bundle: %w", err)
@@ -286,11 +357,11 @@ func downloadMissingBundles(ctx context.Context, client *github.HTTPClient, cfg
 		})
 	}
 
-	if providedType != bundle.TypeIntermediate && hasBundle(checksum, bundle.TypeIntermediate) {
+	if !cfg.rootsOnly && providedType != bundle.TypeIntermediate && hasBundle(checksum, bundle.TypeIntermediate) {
 		g.Go(func() error {
 			ctx, span := observability.StartSpan(gctx, "tpmtb.downloadIntermediateBundle")
 			defer span.End()
-			data, err := client.DownloadReleaseAsset(ctx, *cfg.sourceRepo, cfg.tag, intermediateBundleFilename)
+			data, err := downloadBundle(ctx, client, cfg, checksum, intermediateBundleFilename)
 			if err != nil {
 				observability.RecordError(span, err)
 				return fmt.Errorf("failed to download intermediate bundle: %w", err)
@@ -314,28 +385,108 @@ func downloadMissingBundles(ctx context.Context, client *github.HTTPClient, cfg
 	return nil
 }
 
-// downloadProvenance downloads and returns the provenance attestation for the given bundle.
-func downloadProvenance(ctx context.Context, client *github.HTTPClient, cfg assetsConfig, rootBundleData []byte) ([]byte, error) {
-	if len(rootBundleData) == 0 {
-		return nil, fmt.Errorf("root bundle data is required for provenance verification")
+// errNoPatch is returned by [patchCachedBundle] when no patch applies to the cached bundle.
+var errNoPatch = errors.New("no bundle patch available")
+
+// downloadBundle downloads the bundle named filename from the release.
+//
+// When the local cache holds a previous version of the bundle and the release publishes a
+// patch from that version, the patch is applied instead of downloading the whole bundle.
+// The full bundle is downloaded if the patch cannot be applied.
+func downloadBundle(ctx context.Context, client *github.HTTPClient, cfg assetsConfig, checksum []byte, filename string) ([]byte, error) {
+	data, err := patchCachedBundle(ctx, client, cfg, checksum, filename)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, errNoPatch) && cfg.logger != nil {
+		cfg.logger.DebugContext(ctx, "bundle patch not applied, falling back to full download",
+			slog.String("bundle", filename),
+			slog.String("version", cfg.tag),
+			slog.Any("error", err),
+		)
+	}
+	return client.DownloadReleaseAsset(ctx, *cfg.sourceRepo, cfg.tag, filename)
+}
+
+// patchCachedBundle rebuilds the bundle named filename by applying the patch published in the
+// release to the cached version of the bundle.
+//
+// Patches are not signed: the patched bundle is only returned if it matches its checksum
+// listed in checksums.txt (which is verified along with the bundle).
+func patchCachedBundle(ctx context.Context, client *github.HTTPClient, cfg assetsConfig, checksum []byte, filename string) ([]byte, error) {
+	if cfg.disableLocalCache {
+		return nil, errNoPatch
+	}
+	cacheCfg, err := getCacheConfig(cfg.cachePath)
+	if err != nil || cacheCfg.Version == cfg.tag {
+		return nil, errNoPatch
+	}
+	patchFilename := bundle.PatchFilename(filename, cacheCfg.Version)
+	if !bytes.Contains(checksum, []byte(patchFilename)) {
+		return nil, errNoPatch
+	}
+	base, err := cache.LoadFile(cfg.cachePath, filename)
+	if err != nil {
+		return nil, errNoPatch
 	}
 
-	bundleDigest := digest.ComputeSHA256(rootBundleData)
-	attestations, err := client.GetAttestations(ctx, *cfg.sourceRepo, bundleDigest)
+	data, err := client.DownloadReleaseAsset(ctx, *cfg.sourceRepo, cfg.tag, patchFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download patch: %w", err)
+	}
+	var patch bundle.Patch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("failed to parse patch: %w", err)
+	}
+	patched, err := bundle.ApplyPatch(base, &patch)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get attestations: %w", err)
+		return nil, fmt.Errorf("failed to apply patch: %w", err)
 	}
-	if len(attestations) == 0 {
-		return nil, fmt.Errorf("no attestations found for digest %s", bundleDigest)
+	if err := cosign.ValidateChecksum(checksum, patched, filename); err != nil {
+		return nil, fmt.Errorf("patched bundle integrity check failed: %w", err)
+	}
+	return patched, nil
+}
+
+// downloadProvenance downloads and returns the provenance attestations for the given bundle.
+//
+// Every attestation is returned when several exist for the bundle digest: verification picks
+// the one matching the policy. downloaded reports whether they were fetched from the GitHub
+// API: they are not cached until verified (see [assets.cacheAttestation]).
+func downloadProvenance(ctx context.Context, client *github.HTTPClient, cfg assetsConfig, rootBundleData []byte) (attestations [][]byte, downloaded bool, err error) {
+	if len(rootBundleData) == 0 {
+		return nil, false, fmt.Errorf("root bundle data is required for provenance verification")
+	}
+
+	// Attestations are immutable for a given artifact, so unchanged bundle bytes
+	// can reuse the attestation fetched during a previous verification.
+	bundleDigest := ComputeBundleDigest(rootBundleData)
+	if !cfg.disableLocalCache {
+		// Verified attestations are cached in the writable cache, a fallback cache may hold some as well
+		for _, cachePath := range slices.Compact([]string{cfg.writableCachePath, cfg.cachePath}) {
+			if provenance, err := cache.LoadAttestation(cachePath, bundleDigest, cache.DefaultAttestationTTL); err == nil {
+				return [][]byte{provenance}, false, nil
+			}
+		}
 	}
 
-	provenanceJSON, err := json.Marshal(attestations[0].Bundle)
+	found, err := client.GetAttestations(ctx, *cfg.sourceRepo, bundleDigest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal provenance: %w", err)
+		return nil, false, fmt.Errorf("failed to get attestations: %w", err)
+	}
+	if len(found) == 0 {
+		return nil, false, fmt.Errorf("no attestations found for digest %s", bundleDigest)
 	}
 
-	compactJSON, _ := utils.JsonCompact(provenanceJSON) // should never fail
-	return compactJSON, nil
+	attestations = make([][]byte, len(found))
+	for i, attestation := range found {
+		provenanceJSON, err := json.Marshal(attestation.Bundle)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to marshal provenance: %w", err)
+		}
+		attestations[i], _ = utils.JsonCompact(provenanceJSON) // should never fail
+	}
+	return attestations, true, nil
 }
 
 // hasBundle checks if the checksums.txt file contains an entry for the specified bundle type.
@@ -356,6 +507,9 @@ func getReleaseTag(ctx context.Context, cfg GetConfig) (string, error) {
 	if cfg.Date != "" {
 		if err := client.ReleaseExists(ctx, *cfg.sourceRepo, cfg.Date); err != nil {
 			observability.RecordError(span, err)
+			if errors.Is(err, github.ErrReleaseNotFound) {
+				return "", fmt.Errorf("%w: release %s not found", ErrBundleNotFound, cfg.Date)
+			}
 			return "", fmt.Errorf("release %s not found: %w", cfg.Date, err)
 		}
 		return cfg.Date, nil
@@ -380,3 +534,20 @@ func getReleaseTag(ctx context.Context, cfg GetConfig) (string, error) {
 
 	return releases[0].TagName, nil
 }
+
+// checkExpectedCommit asserts that the downloaded bundles were generated from the expected commit.
+func checkExpectedCommit(a *assets, expected string) error {
+	for _, data := range [][]byte{a.rootBundleData, a.intermediateBundleData} {
+		if len(data) == 0 {
+			continue
+		}
+		metadata, err := bundle.ParseMetadata(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse bundle metadata: %w", err)
+		}
+		if metadata.Commit != expected {
+			return &CommitMismatchError{Date: metadata.Date, Expected: expected, Actual: metadata.Commit}
+		}
+	}
+	return nil
+}
@@ -1,12 +1,15 @@
 package apiv1beta
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"time"
 
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 )
 
@@ -25,6 +28,38 @@ const (
 // This is an alias for backward compatibility.
 var CacheFilenames = cache.Filenames
 
+// RequiredCacheFiles returns the cache files [LoadTrustedBundle] needs to load a verified bundle,
+// offline tells whether the bundle is loaded in offline mode (see [LoadConfig.OfflineMode]),
+// which also requires the Sigstore trusted root.
+//
+// A bundle persisted with verification skipped (see [CacheConfig.SkipVerify]) only needs the
+// root bundle and the cache configuration.
+func RequiredCacheFiles(offline bool) []string {
+	files := []string{
+		cache.RootBundleFilename,
+		cache.ChecksumsFilename,
+		cache.ChecksumsSigFilename,
+		cache.ProvenanceFilename,
+		cache.ConfigFilename,
+	}
+	if offline {
+		files = append(files, cache.TrustedRootFilename)
+	}
+	return files
+}
+
+// OptionalCacheFiles returns the cache files which may be missing when a bundle is loaded online:
+// the intermediate bundle, absent from the first releases, and the Sigstore trusted root,
+// only required in offline mode.
+//
+// Together with [RequiredCacheFiles] (online), it lists all [CacheFilenames].
+func OptionalCacheFiles() []string {
+	return []string{
+		cache.IntermediateBundleFilename,
+		cache.TrustedRootFilename,
+	}
+}
+
 // CacheConfig represents the persisted configuration for a [TrustedBundle].
 type CacheConfig struct {
 	// Version is the bundle version (YYYY-MM-DD format).
@@ -34,11 +69,28 @@ type CacheConfig struct {
 	AutoUpdate *AutoUpdateConfig `json:"autoUpdate,omitempty"`
 
 	// SkipVerify indicates whether bundle verification was skipped.
+	//
+	// A cache stored with SkipVerify is never reused by a call requesting verification:
+	// the bundle is downloaded and verified again, then the cache is overwritten.
 	SkipVerify bool `json:"skipVerify,omitempty"`
 
 	// VendorIDs is the list of vendor IDs to filter.
 	VendorIDs []VendorID `json:"vendorIDs,omitempty"`
 
+	// RootsOnly indicates whether the intermediate bundle was skipped.
+	RootsOnly bool `json:"rootsOnly,omitempty"`
+
+	// SourceRepo is the GitHub repository ("owner/name") trusted to produce the bundle,
+	// see [GetConfig.TrustedSourceRepo].
+	//
+	// [LoadTrustedBundle] verifies the bundle and fetches its updates against this repository.
+	// It is empty for bundles persisted by previous releases (upstream is then trusted).
+	SourceRepo string `json:"sourceRepo,omitempty"`
+
+	// TUFMirror is the TUF repository the Sigstore trusted root is fetched from,
+	// see [GetConfig.TUFMirror].
+	TUFMirror *TUFMirror `json:"tufMirror,omitempty"`
+
 	// LastTimestamp is the timestamp of the last update.
 	LastTimestamp time.Time `json:"lastTimestamp"`
 }
@@ -61,6 +113,85 @@ func (c *CacheConfig) CheckAndSetDefaults() error {
 	return nil
 }
 
+// parseCacheConfig strictly decodes a persisted cache configuration and validates it.
+//
+// Unknown fields are rejected, so that a hand-edited or truncated config.json is reported
+// up front instead of causing confusing downstream errors. Errors wrap [ErrInvalidCacheConfig].
+func parseCacheConfig(data []byte) (*CacheConfig, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var cfg CacheConfig
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCacheConfig, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected data after JSON object", ErrInvalidCacheConfig)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCacheConfig, err)
+	}
+	return &cfg, nil
+}
+
+// validate checks the fields set when the configuration is persisted.
+func (c *CacheConfig) validate() error {
+	if err := bundle.ValidateDate(c.Version); err != nil {
+		return fmt.Errorf("invalid version: %w", err)
+	}
+	for _, vendorID := range c.VendorIDs {
+		if err := vendorID.Validate(); err != nil {
+			return fmt.Errorf("invalid vendor ID: %w", err)
+		}
+	}
+	if c.LastTimestamp.IsZero() {
+		return fmt.Errorf("last timestamp cannot be empty")
+	}
+	if c.SourceRepo != "" {
+		if _, err := github.ParseRepo(c.SourceRepo); err != nil {
+			return fmt.Errorf("invalid source repository: %w", err)
+		}
+	}
+	if c.TUFMirror != nil {
+		if err := c.TUFMirror.CheckAndSetDefaults(); err != nil {
+			return fmt.Errorf("invalid TUF mirror: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkCacheRootsOnly reports whether the cache was persisted without the intermediate bundle.
+func checkCacheRootsOnly(cachePath string) bool {
+	cfg, err := getCacheConfig(cachePath)
+	if err != nil {
+		return false
+	}
+	return cfg.RootsOnly
+}
+
+// checkCacheSkipVerify reports whether the cache was persisted without being verified.
+func checkCacheSkipVerify(cachePath string) bool {
+	cfg, err := getCacheConfig(cachePath)
+	if err != nil {
+		return false
+	}
+	return cfg.SkipVerify
+}
+
+// checkCacheSource reports whether the cache was persisted for the bundles released by
+// sourceRepo, with the Sigstore trusted root fetched from tufMirror.
+func checkCacheSource(cachePath string, sourceRepo *github.Repo, tufMirror *TUFMirror) bool {
+	cfg, err := getCacheConfig(cachePath)
+	if err != nil {
+		return false
+	}
+	cachedRepo, err := trustedSourceRepo(cfg.SourceRepo)
+	if err != nil {
+		return false
+	}
+	return cachedRepo.String() == sourceRepo.String() && tufMirrorKey(cfg.TUFMirror) == tufMirrorKey(tufMirror)
+}
+
 // checkCacheExists verifies if a cache exists for the specified version.
 func checkCacheExists(cachePath string, version string) bool {
 	cfg, err := getCacheConfig(cachePath)
@@ -87,6 +218,21 @@ func checkCacheExists(cachePath string, version string) bool {
 	return true
 }
 
+// findCachePath returns the first of cachePaths holding a cached bundle of version,
+// or holding any cached bundle if version is empty.
+func findCachePath(version string, cachePaths ...string) (string, bool) {
+	for _, cachePath := range cachePaths {
+		if version == "" {
+			if utils.FileExists(filepath.Join(cachePath, CacheConfigFilename)) {
+				return cachePath, true
+			}
+		} else if checkCacheExists(cachePath, version) {
+			return cachePath, true
+		}
+	}
+	return "", false
+}
+
 // getCacheConfig loads the cache configuration from the specified cache path.
 func getCacheConfig(cachePath string) (*CacheConfig, error) {
 	configData, err := cache.LoadFile(cachePath, cache.ConfigFilename)
@@ -129,15 +275,16 @@ func persistAllBundleAssets(
 	if err := cache.SaveFile(outputDir, cache.ProvenanceFilename, provenance); err != nil {
 		return err
 	}
-	if err := cache.SaveFile(outputDir, cache.ConfigFilename, cacheConfig); err != nil {
-		return err
-	}
 	if err := cache.SaveFile(outputDir, cache.IntermediateBundleFilename, intermediateBundle); err != nil {
 		return err
 	}
 	if err := cache.SaveFile(outputDir, cache.TrustedRootFilename, trustedRoot); err != nil {
 		return err
 	}
+	// Written last: watchers of the cache (e.g. tpmtb serve) reload once it changes
+	if err := cache.SaveFile(outputDir, cache.ConfigFilename, cacheConfig); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,35 @@
+package apiv1beta
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRequiredCacheFiles(t *testing.T) {
+	online := RequiredCacheFiles(false)
+	offline := RequiredCacheFiles(true)
+
+	for _, files := range [][]string{online, offline} {
+		if !slices.Contains(files, CacheRootBundleFilename) || !slices.Contains(files, CacheConfigFilename) {
+			t.Errorf("expected %v to include %s and %s", files, CacheRootBundleFilename, CacheConfigFilename)
+		}
+		if slices.Contains(files, CacheIntermediateBundleFilename) {
+			t.Errorf("expected %v not to include the optional %s", files, CacheIntermediateBundleFilename)
+		}
+	}
+	if slices.Contains(online, CacheTrustedRootFilename) {
+		t.Errorf("expected %s to be optional online", CacheTrustedRootFilename)
+	}
+	if !slices.Contains(offline, CacheTrustedRootFilename) {
+		t.Errorf("expected %s to be required offline", CacheTrustedRootFilename)
+	}
+
+	// Required (online) and optional files partition the cache files
+	all := append(online, OptionalCacheFiles()...)
+	slices.Sort(all)
+	expected := slices.Clone(CacheFilenames)
+	slices.Sort(expected)
+	if !slices.Equal(all, expected) {
+		t.Errorf("required and optional files = %v, want %v", all, expected)
+	}
+}
@@ -0,0 +1,35 @@
+package apiv1beta
+
+import (
+	"fmt"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/cosign"
+)
+
+// ParseChecksums parses a checksums file (see [CacheChecksumsFilename]) and returns
+// the SHA-256 digest of each listed file, as a lowercase hex string keyed by filename.
+//
+// Each line is a "<sha256-hex>  <filename>" entry; the separator may be any run of
+// spaces or tabs. Empty lines are skipped, while a malformed line or a filename
+// listed twice is an error wrapping [ErrMalformedChecksums].
+//
+// ParseChecksums does NOT check the signature of the checksums file, see [VerifyTrustedBundle].
+//
+// Example:
+//
+//	checksums, err := apiv1beta.ParseChecksums(checksumsData)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(checksums[apiv1beta.CacheRootBundleFilename])
+func ParseChecksums(data []byte) (map[string]string, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("checksums cannot be empty")
+	}
+
+	checksums, err := cosign.ParseChecksums(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse checksums: %w", err)
+	}
+	return checksums, nil
+}
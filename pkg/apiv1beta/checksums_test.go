@@ -0,0 +1,99 @@
+package apiv1beta
+
+import (
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestParseChecksums(t *testing.T) {
+	const (
+		rootDigest         = "f5c7f9e9c59d65f1a889b1cdc712a3ea674df84bd8dc15081165b41ac2496ed2"
+		intermediateDigest = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	)
+
+	releaseChecksums, err := testutil.ReadTestFile(testutil.ChecksumFile)
+	if err != nil {
+		t.Fatalf("failed to read checksums: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "release checksums",
+			data: string(releaseChecksums),
+			want: map[string]string{CacheRootBundleFilename: rootDigest},
+		},
+		{
+			name: "two-space separated",
+			data: rootDigest + "  " + CacheRootBundleFilename + "\n\n" +
+				strings.ToUpper(intermediateDigest) + "  " + CacheIntermediateBundleFilename + "\n",
+			want: map[string]string{
+				CacheRootBundleFilename:         rootDigest,
+				CacheIntermediateBundleFilename: intermediateDigest,
+			},
+		},
+		{
+			name: "tab separated",
+			data: rootDigest + "\t" + CacheRootBundleFilename + "\r\n" +
+				intermediateDigest + "\t" + CacheIntermediateBundleFilename,
+			want: map[string]string{
+				CacheRootBundleFilename:         rootDigest,
+				CacheIntermediateBundleFilename: intermediateDigest,
+			},
+		},
+		{
+			name:    "empty",
+			data:    "",
+			wantErr: "checksums cannot be empty",
+		},
+		{
+			name:    "missing filename",
+			data:    rootDigest + "\n",
+			wantErr: "line 1: malformed checksum entry",
+		},
+		{
+			name:    "invalid digest",
+			data:    rootDigest + "  " + CacheRootBundleFilename + "\nnot-a-digest  " + CacheIntermediateBundleFilename + "\n",
+			wantErr: `line 2: invalid SHA-256 checksum "not-a-digest"`,
+		},
+		{
+			name:    "truncated digest",
+			data:    rootDigest[:40] + "  " + CacheRootBundleFilename + "\n",
+			wantErr: "line 1: invalid SHA-256 checksum",
+		},
+		{
+			name:    "duplicate filename",
+			data:    rootDigest + "  " + CacheRootBundleFilename + "\n" + intermediateDigest + "  " + CacheRootBundleFilename + "\n",
+			wantErr: "line 2: duplicate checksum entry for " + CacheRootBundleFilename,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChecksums([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseChecksums() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				if len(tt.data) > 0 && !errors.Is(err, ErrMalformedChecksums) {
+					t.Errorf("ParseChecksums() error = %v, want %v", err, ErrMalformedChecksums)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseChecksums() unexpected error: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("ParseChecksums() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package apiv1beta
 
 import (
+	"crypto/x509"
 	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle/verifier"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
@@ -17,6 +23,15 @@ type GetConfig struct {
 	// Optional. If empty, the latest release will be fetched.
 	Date string
 
+	// ExpectedCommit pins the commit the bundle released at Date must have been generated from.
+	//
+	// A [CommitMismatchError] is returned if the downloaded bundle references another commit,
+	// which would indicate a re-release or tampering. The check is performed even when
+	// SkipVerify is true, providing a lightweight pinning mechanism.
+	//
+	// Optional. Requires Date to be set.
+	ExpectedCommit string
+
 	// AutoUpdate configures automatic updates of the bundle.
 	//
 	// Optional. If not set, auto-update is enabled with a default interval of 24 hours.
@@ -25,16 +40,43 @@ type GetConfig struct {
 	// VendorIDs specifies the list of vendor IDs to filter when calling 'TrustedBundle.GetRoots()'.
 	//
 	// It can be helpful when your TPM chips comes from specific vendors and you want to adopt
-	// a least-privilege approach.
+	// a least-privilege approach. The certificates of the other vendors are not kept in memory;
+	// note that the whole bundle is still downloaded and verified.
 	//
 	// Optional. If empty, all vendors will be included.
 	VendorIDs []VendorID
 
+	// RequireNonEmpty makes [GetTrustedBundle] return [ErrEmptyVendorFilter] when none of
+	// the VendorIDs has a root certificate in the bundle, instead of silently returning a
+	// bundle with an empty root pool. Without it, a warning is sent to Logger.
+	//
+	// Optional. Default is false. Ignored if VendorIDs is empty.
+	RequireNonEmpty bool
+
 	// CachePath is the location on disk for tpmtb cache.
 	//
 	// Optional. If empty, the default cache path is used ($HOME/.tpmtb).
 	CachePath string
 
+	// CacheNamespace isolates the cache in a subdirectory of CachePath.
+	//
+	// Set it when several differently-configured bundles (e.g. root-only and vendor-filtered)
+	// share the same CachePath, so that they do not overwrite each other's cached files.
+	// Only letters, digits, '.', '_' and '-' are allowed.
+	//
+	// Optional. If empty, files are stored directly in CachePath.
+	CacheNamespace string
+
+	// FallbackCachePaths are read-only caches looked up, in order, after CachePath
+	// (e.g. a cache baked into a container image while CachePath is a mounted volume).
+	//
+	// The bundle is read from the first cache holding the requested release, but it is
+	// only ever persisted (including by auto-updates) to CachePath. CacheNamespace applies
+	// to each of them.
+	//
+	// Optional.
+	FallbackCachePaths []string
+
 	// DisableLocalCache mode allows to work on a read-only
 	// files system if this is set, cache path is ignored.
 	//
@@ -43,34 +85,158 @@ type GetConfig struct {
 
 	// SkipVerify disables bundle verification.
 	//
+	// AcknowledgeSkipVerify must be set as well, and a warning is logged to Logger on every
+	// call skipping the verification (once to [slog.Default] if Logger is nil).
+	//
 	// Optional. By default the bundle will be verified using Cosign and GitHub Attestations.
 	SkipVerify bool
 
+	// AcknowledgeSkipVerify confirms that skipping the verification is deliberate.
+	//
+	// It prevents an unverified bundle from being used in production by accident:
+	// [GetTrustedBundle] returns [ErrSkipVerifyNotAcknowledged] if SkipVerify is set alone.
+	//
+	// Optional. Required if SkipVerify is set.
+	AcknowledgeSkipVerify bool
+
+	// RootsOnly skips the intermediate bundle entirely: it is neither downloaded, verified
+	// nor parsed, and [TrustedBundle.GetIntermediateCertPool] returns an empty pool.
+	//
+	// It saves a download and a verification for consumers that only need root certificates.
+	// The setting is persisted in the local cache, so [LoadTrustedBundle] and auto-updates honor it.
+	//
+	// Optional. Default is false (the intermediate bundle is fetched when available).
+	RootsOnly bool
+
 	// HTTPClient is the HTTP client to use for requests.
 	//
-	// Optional. If nil, [http.DefaultClient] will be used.
+	// Optional. If nil, the fallback returned by [HTTPClient] is used (see [SetHTTPClient]).
 	HTTPClient utils.HTTPClient
 
+	// UserAgent is the User-Agent header sent with every request made through HTTPClient.
+	//
+	// Optional. Default: "tpmtb/<version>".
+	UserAgent string
+
+	// TUFMirror is the TUF repository the Sigstore trusted root is fetched from, in place
+	// of Sigstore's public-good repository (e.g. an internal mirror for offline-first
+	// environments). The mirror URL must be an absolute HTTPS URL.
+	//
+	// Optional. If nil, the trusted root is fetched from Sigstore's public-good TUF repository.
+	TUFMirror *TUFMirror
+
+	// TrustedSourceRepo is the GitHub repository ("owner/name") trusted to produce bundles.
+	//
+	// Set it to verify bundles released by a fork or mirror running its own signed releases:
+	// bundles are then downloaded from this repository and the Cosign and GitHub attestation
+	// policies expect its release workflow identity. Bundles from any other repository,
+	// including upstream, are rejected.
+	//
+	// Optional. Default: loicsikidi/tpm-ca-certificates.
+	TrustedSourceRepo string
+
+	// Logger receives a debug record for every bundle cache hit or miss (tagged with the
+	// bundle version), which helps to assess whether the local cache is effective, and a
+	// warning when VendorIDs matches no root certificate or a certificate of the bundle
+	// fails to parse (such certificates are skipped) or the verification is skipped.
+	//
+	// Optional. If nil, only a skipped verification is reported, once, to [slog.Default].
+	Logger *slog.Logger
+
 	// sourceRepo is the GitHub repository to fetch bundles from.
 	//
-	// This field is internal for security reasons and should not be set by users.
+	// This field is internal and derived from TrustedSourceRepo.
 	sourceRepo *github.Repo
+
+	// trustedRoot is the content of a Sigstore trusted-root.json file used to verify
+	// the bundle instead of fetching it from TUF.
+	//
+	// This field is internal and only set by [SaveTrustedBundle].
+	trustedRoot []byte
+
+	// namespaced is true once CacheNamespace has been applied to CachePath.
+	namespaced bool
+}
+
+// trustedSourceRepo returns the repository to trust, defaulting to upstream when ref is empty.
+func trustedSourceRepo(ref string) (*github.Repo, error) {
+	if ref == "" {
+		return &github.Repo{
+			Owner: github.SourceRepo.Owner,
+			Name:  github.SourceRepo.Name,
+		}, nil
+	}
+	repo, err := github.ParseRepo(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted source repository: %w", err)
+	}
+	return repo, nil
+}
+
+// cacheNamespaceRegex restricts cache namespaces to a single, portable path element.
+var cacheNamespaceRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// namespacedCachePath returns the cache directory dedicated to namespace within cachePath.
+//
+// The flat layout (cachePath itself) is kept when namespace is empty.
+func namespacedCachePath(cachePath, namespace string) (string, error) {
+	if namespace == "" {
+		return cachePath, nil
+	}
+	if !cacheNamespaceRegex.MatchString(namespace) {
+		return "", fmt.Errorf("invalid cache namespace %q: only letters, digits, '.', '_' and '-' are allowed", namespace)
+	}
+	return filepath.Join(cachePath, namespace), nil
+}
+
+// namespacedCachePaths applies [namespacedCachePath] to each of cachePaths.
+func namespacedCachePaths(cachePaths []string, namespace string) ([]string, error) {
+	result := make([]string, 0, len(cachePaths))
+	for _, cachePath := range cachePaths {
+		if cachePath == "" {
+			return nil, fmt.Errorf("fallback cache path cannot be empty")
+		}
+		namespaced, err := namespacedCachePath(cachePath, namespace)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, namespaced)
+	}
+	return result, nil
 }
 
 // CheckAndSetDefaults validates and sets default values.
 func (c *GetConfig) CheckAndSetDefaults() error {
 	if c.sourceRepo == nil {
-		c.sourceRepo = &github.Repo{
-			Owner: github.SourceRepo.Owner,
-			Name:  github.SourceRepo.Name,
+		sourceRepo, err := trustedSourceRepo(c.TrustedSourceRepo)
+		if err != nil {
+			return err
 		}
+		c.sourceRepo = sourceRepo
 	}
 	if err := c.sourceRepo.CheckAndSetDefaults(); err != nil {
 		return fmt.Errorf("invalid source repository: %w", err)
 	}
+	if c.SkipVerify && !c.AcknowledgeSkipVerify {
+		return ErrSkipVerifyNotAcknowledged
+	}
+	if c.TUFMirror != nil {
+		if err := c.TUFMirror.CheckAndSetDefaults(); err != nil {
+			return fmt.Errorf("invalid TUF mirror: %w", err)
+		}
+	}
+	if c.ExpectedCommit != "" {
+		if c.Date == "" {
+			return fmt.Errorf("expected commit requires a date to be set")
+		}
+		if err := bundle.ValidateCommit(c.ExpectedCommit); err != nil {
+			return fmt.Errorf("invalid expected commit: %w", err)
+		}
+	}
 	if c.HTTPClient == nil {
 		c.HTTPClient = HTTPClient()
 	}
+	c.HTTPClient = utils.WithUserAgent(c.HTTPClient, c.UserAgent)
 	if err := c.AutoUpdate.CheckAndSetDefaults(); err != nil {
 		return fmt.Errorf("invalid auto-update config: %w", err)
 	}
@@ -82,6 +248,19 @@ func (c *GetConfig) CheckAndSetDefaults() error {
 	if c.CachePath == "" {
 		c.CachePath = cache.CacheDir()
 	}
+	if !c.namespaced {
+		cachePath, err := namespacedCachePath(c.CachePath, c.CacheNamespace)
+		if err != nil {
+			return err
+		}
+		c.CachePath = cachePath
+		fallbackCachePaths, err := namespacedCachePaths(c.FallbackCachePaths, c.CacheNamespace)
+		if err != nil {
+			return err
+		}
+		c.FallbackCachePaths = fallbackCachePaths
+		c.namespaced = true
+	}
 	return nil
 }
 
@@ -101,12 +280,27 @@ func (c GetConfig) GetCachePath() string {
 	return c.CachePath
 }
 
+func (c GetConfig) GetFallbackCachePaths() []string {
+	return c.FallbackCachePaths
+}
+
+func (c GetConfig) GetUserAgent() string {
+	return c.UserAgent
+}
+
+func (c GetConfig) GetLogger() *slog.Logger {
+	return c.Logger
+}
+
 func (c *GetConfig) toAssetsConfig() assetsConfig {
 	cfg := assetsConfig{
 		httpClient:        c.HTTPClient,
 		cachePath:         c.CachePath,
+		writableCachePath: c.CachePath,
 		disableLocalCache: c.DisableLocalCache,
 		sourceRepo:        c.sourceRepo,
+		rootsOnly:         c.RootsOnly,
+		logger:            c.Logger,
 	}
 	if !c.SkipVerify {
 		cfg.needChecksums = true
@@ -117,6 +311,11 @@ func (c *GetConfig) toAssetsConfig() assetsConfig {
 }
 
 // VerifyConfig configures the bundle verification.
+//
+// Verification is hermetic when Checksum, ChecksumSignature, Provenance and TrustedRoot
+// are all provided along with Bundle (e.g. embedded at compile time): no asset is
+// downloaded and the Sigstore trusted root is not fetched from TUF, so HTTPClient is
+// never used. Set DisableLocalCache as well to keep the disk untouched.
 type VerifyConfig struct {
 	// Bundle is the content of the trusted bundle to verify.
 	//
@@ -152,9 +351,14 @@ type VerifyConfig struct {
 
 	// HTTPClient is the HTTP client to use for requests.
 	//
-	// Optional. If nil, http.DefaultClient will be used.
+	// Optional. If nil, the fallback returned by [HTTPClient] is used (see [SetHTTPClient]).
 	HTTPClient utils.HTTPClient
 
+	// UserAgent is the User-Agent header sent with every request made through HTTPClient.
+	//
+	// Optional. Default: "tpmtb/<version>".
+	UserAgent string
+
 	// DisableLocalCache mode allows to work on a read-only
 	// files system if this is set, cache path is ignored.
 	//
@@ -170,10 +374,119 @@ type VerifyConfig struct {
 	// Optional. If not provided, the trusted root will be fetched from Sigstore's TUF repository.
 	TrustedRoot []byte
 
+	// TUFMirror is the TUF repository the Sigstore trusted root is fetched from, in place
+	// of Sigstore's public-good repository (e.g. an internal mirror for offline-first
+	// environments). The mirror URL must be an absolute HTTPS URL.
+	// It cannot be combined with TrustedRoot.
+	//
+	// Optional. If nil, the trusted root is fetched from Sigstore's public-good TUF repository.
+	TUFMirror *TUFMirror
+
+	// PinnedKey is a PEM-encoded public key or certificate expected to have signed both
+	// the checksums file and the provenance attestation. It targets air-gapped deployments
+	// that cannot reach Sigstore's TUF, Fulcio and Rekor infrastructure.
+	//
+	// Security tradeoff: keyless verification ties each signature to the GitHub Actions
+	// workflow identity and to an entry authenticated by Rekor. With a pinned key, anyone
+	// holding the private key can produce a valid release, and the release date is read
+	// from the transparency log entries embedded in the bundles without authenticating them.
+	// The commit and release date checks are still enforced. [VerifyConfig.TrustedRoot]
+	// is ignored when this field is set.
+	//
+	// Optional. If not provided, keyless Sigstore verification is used.
+	PinnedKey []byte
+
+	// RequireRekorInclusion fails the verification unless the Rekor inclusion proof of the
+	// Cosign signature verifies against the Sigstore trusted root, rather than only relying on
+	// the timestamp of the transparency log entry. [VerifyResult.RekorEntries] then reports
+	// the log index and inclusion proof status of each entry.
+	//
+	// Optional. Default is false. Cannot be combined with PinnedKey.
+	RequireRekorInclusion bool
+
+	// Logger receives the verification policy actually enforced (source repository,
+	// OIDC issuer, workflow reference and tag), which helps to debug policy mismatches.
+	//
+	// Optional. If nil, nothing is logged.
+	Logger *slog.Logger
+
+	// TrustedSourceRepo is the GitHub repository ("owner/name") whose signer identity is trusted.
+	//
+	// The Cosign and GitHub attestation policies expect the certificates to be issued to the
+	// release workflow of this repository. Set it to verify bundles released by a fork or mirror
+	// running its own signed releases: bundles signed by any other repository, including
+	// upstream, are rejected.
+	//
+	// Optional. Default: loicsikidi/tpm-ca-certificates.
+	TrustedSourceRepo string
+
+	// AllowedOIDCIssuers are the OIDC issuers accepted for the certificates signing the
+	// checksums and the provenance attestation: the verification passes if the issuer is
+	// one of them, e.g. both issuers during an identity provider migration. The certificates
+	// must still be issued to a workflow of TrustedSourceRepo on github.com: GitHub
+	// Enterprise Server is not supported.
+	//
+	// Optional. Default: https://token.actions.githubusercontent.com (GitHub Actions on github.com).
+	// Ignored when PinnedKey is set.
+	AllowedOIDCIssuers []string
+
+	// WorkflowFilenames lists additional GitHub Actions workflow file names accepted as
+	// signer besides the release workflow (.github/workflows/release-bundle.yaml), such as
+	// the former path of a renamed release workflow. The checksums signature and the
+	// provenance attestation must come from the same workflow.
+	//
+	// Optional. Ignored when PinnedKey is set.
+	WorkflowFilenames []string
+
+	// CacheVerification records successful verifications keyed by the bundle digest, in memory
+	// only. Verifying identical bytes under the same policy within VerificationCacheTTL then
+	// skips the network-bound Cosign and GitHub attestation checks, and the returned
+	// [VerifyResult] has CachedAt set. The cache is bounded: the oldest entries are evicted.
+	//
+	// Security caveat: a cached result ignores any revocation or Sigstore key rotation
+	// happening after the original verification, so keep VerificationCacheTTL short.
+	//
+	// Optional. Default is false (every call performs a full verification).
+	CacheVerification bool
+
+	// VerificationCacheTTL is the lifetime of a cached verification.
+	//
+	// Optional. Default: 1 hour. Ignored unless CacheVerification is set.
+	VerificationCacheTTL time.Duration
+
+	// AllowedSignatureAlgorithms restricts the signature algorithms accepted for the
+	// certificates of the bundle, for compliance regimes requiring approved algorithms.
+	//
+	// When set, the bundle is rejected with [ErrDisallowedAlgorithm] if any certificate
+	// is signed with another algorithm. The check is also enforced when CacheVerification
+	// finds a cached result.
+	//
+	// Optional. If empty, any signature algorithm is accepted.
+	AllowedSignatureAlgorithms []x509.SignatureAlgorithm
+
+	// CollectAllFailures keeps verifying after a failed check, to report every problem of a
+	// broken bundle at once when diagnosing it.
+	//
+	// All checks (signature algorithms, structure, Cosign signature, Rekor inclusion, GitHub
+	// attestation, commit and release date) then run, and the returned error joins all their
+	// failures. [errors.Is] still matches [ErrBundleVerificationFailed], [ErrDisallowedAlgorithm]
+	// and [ErrMalformedBundle]. The structural check (see [ValidateBundle]) only runs in this
+	// mode: releases are validated before being signed, so the authenticity checks cover it.
+	// A cached result (see CacheVerification) is ignored when the signature algorithm check fails.
+	//
+	// Optional. Default is false (verification stops at the first failure).
+	CollectAllFailures bool
+
 	// sourceRepo is the GitHub repository to fetch bundles from.
 	//
-	// This field is internal for security reasons and should not be set by users.
+	// This field is internal and derived from TrustedSourceRepo.
 	sourceRepo *github.Repo
+
+	// attestations are the attestations downloaded for the bundle digest when there are
+	// several: they are verified instead of Provenance, which is only the first of them.
+	//
+	// This field is internal and set along with Provenance from the downloaded assets.
+	attestations [][]byte
 }
 
 func (c *VerifyConfig) shouldFetchVerificationAssets() bool {
@@ -185,6 +498,7 @@ func (c *VerifyConfig) toAssetsConfig() assetsConfig {
 		bundle:                c.Bundle,
 		httpClient:            c.HTTPClient,
 		cachePath:             c.CachePath,
+		writableCachePath:     c.CachePath,
 		disableLocalCache:     c.DisableLocalCache,
 		tag:                   c.BundleMetadata.Date,
 		sourceRepo:            c.sourceRepo,
@@ -210,12 +524,35 @@ func (c *VerifyConfig) CheckAndSetDefaults() error {
 	if err := c.BundleMetadata.Check(); err != nil {
 		return fmt.Errorf("invalid bundle metadata: %w", err)
 	}
+	if len(c.PinnedKey) > 0 {
+		if _, err := verifier.LoadPinnedKey(c.PinnedKey); err != nil {
+			return fmt.Errorf("invalid pinned key: %w", err)
+		}
+		if c.RequireRekorInclusion {
+			return fmt.Errorf("rekor inclusion cannot be required with a pinned key")
+		}
+	}
+	if c.TUFMirror != nil {
+		if len(c.TrustedRoot) > 0 {
+			return fmt.Errorf("trusted root and TUF mirror are mutually exclusive")
+		}
+		if err := c.TUFMirror.CheckAndSetDefaults(); err != nil {
+			return fmt.Errorf("invalid TUF mirror: %w", err)
+		}
+	}
+	if slices.Contains(c.AllowedOIDCIssuers, "") {
+		return fmt.Errorf("allowed OIDC issuers cannot contain an empty issuer")
+	}
+	if slices.Contains(c.WorkflowFilenames, "") {
+		return fmt.Errorf("workflow filenames cannot contain an empty filename")
+	}
 
 	if c.sourceRepo == nil {
-		c.sourceRepo = &github.Repo{
-			Owner: github.SourceRepo.Owner,
-			Name:  github.SourceRepo.Name,
+		sourceRepo, err := trustedSourceRepo(c.TrustedSourceRepo)
+		if err != nil {
+			return err
 		}
+		c.sourceRepo = sourceRepo
 	}
 	if err := c.sourceRepo.CheckAndSetDefaults(); err != nil {
 		return fmt.Errorf("invalid source repository: %w", err)
@@ -223,9 +560,16 @@ func (c *VerifyConfig) CheckAndSetDefaults() error {
 	if c.HTTPClient == nil {
 		c.HTTPClient = HTTPClient()
 	}
+	c.HTTPClient = utils.WithUserAgent(c.HTTPClient, c.UserAgent)
 	if c.CachePath == "" {
 		c.CachePath = cache.CacheDir()
 	}
+	if c.VerificationCacheTTL < 0 {
+		return fmt.Errorf("verification cache TTL cannot be negative")
+	}
+	if c.VerificationCacheTTL == 0 {
+		c.VerificationCacheTTL = defaultVerificationCacheTTL
+	}
 
 	return nil
 }
@@ -252,8 +596,21 @@ type SaveConfig struct {
 
 	// HTTPClient is the HTTP client to use for requests.
 	//
-	// Optional. If nil, [http.DefaultClient] will be used.
+	// Optional. If nil, the fallback returned by [HTTPClient] is used (see [SetHTTPClient]).
 	HTTPClient utils.HTTPClient
+
+	// UserAgent is the User-Agent header sent with every request made through HTTPClient.
+	//
+	// Optional. Default: "tpmtb/<version>".
+	UserAgent string
+
+	// OfflineTrustedRoot is the content of an already available trusted-root.json file
+	// (e.g. from a previous save). When provided, it is used both to verify the bundle and
+	// as the trusted root returned in [SaveResponse], so no request is made to Sigstore's
+	// TUF repository.
+	//
+	// Optional. If not provided, the trusted root will be fetched from Sigstore's TUF repository.
+	OfflineTrustedRoot []byte
 }
 
 // CheckAndSetDefaults validates and sets default values.
@@ -261,6 +618,12 @@ func (c *SaveConfig) CheckAndSetDefaults() error {
 	if c.HTTPClient == nil {
 		c.HTTPClient = HTTPClient()
 	}
+	c.HTTPClient = utils.WithUserAgent(c.HTTPClient, c.UserAgent)
+	if len(c.OfflineTrustedRoot) > 0 {
+		if _, err := verifier.LoadTrustedRoot(c.OfflineTrustedRoot); err != nil {
+			return fmt.Errorf("invalid offline trusted root: %w", err)
+		}
+	}
 	if c.CachePath == "" {
 		c.CachePath = cache.CacheDir()
 	}
@@ -279,6 +642,34 @@ type LoadConfig struct {
 	// Optional. If empty, the default cache path is used ($HOME/.tpmtb).
 	CachePath string
 
+	// CacheNamespace isolates the cache in a subdirectory of CachePath.
+	//
+	// Set it when several differently-configured bundles (e.g. root-only and vendor-filtered)
+	// share the same CachePath, so that they do not overwrite each other's cached files.
+	// Only letters, digits, '.', '_' and '-' are allowed.
+	//
+	// Optional. If empty, files are stored directly in CachePath.
+	CacheNamespace string
+
+	// FallbackCachePaths are read-only caches looked up, in order, after CachePath
+	// (e.g. a cache baked into a container image while CachePath is a mounted volume).
+	//
+	// The bundle is loaded from the first cache holding one, while auto-updates are only
+	// persisted to CachePath. CacheNamespace applies to each of them.
+	//
+	// Optional.
+	FallbackCachePaths []string
+
+	// HTTPClient is the HTTP client used to verify the cached bundle and to fetch its updates.
+	//
+	// Optional. If nil, the fallback default returned by [HTTPClient] is used (built by [NewHTTPClient]).
+	HTTPClient utils.HTTPClient
+
+	// UserAgent is the User-Agent header sent with every request made through HTTPClient.
+	//
+	// Optional. Default: "tpmtb/<version>".
+	UserAgent string
+
 	// DisableLocalCache mode allows to work on a read-only
 	// files system if this is set, cache path is ignored.
 	//
@@ -287,9 +678,34 @@ type LoadConfig struct {
 
 	// SkipVerify disables bundle verification.
 	//
+	// AcknowledgeSkipVerify must be set as well. A warning is logged to Logger whenever the
+	// verification is skipped, be it by SkipVerify or by the cached configuration (once to
+	// [slog.Default] if Logger is nil).
+	//
 	// Optional. By default the bundle will be verified using Cosign and GitHub Attestations.
 	SkipVerify bool
 
+	// AcknowledgeSkipVerify confirms that skipping the verification is deliberate.
+	//
+	// [LoadTrustedBundle] returns [ErrSkipVerifyNotAcknowledged] if SkipVerify is set alone.
+	//
+	// Optional. Required if SkipVerify is set.
+	AcknowledgeSkipVerify bool
+
+	// TUFMirror is the TUF repository the Sigstore trusted root is fetched from, in place
+	// of Sigstore's public-good repository (see [GetConfig.TUFMirror]).
+	//
+	// Optional. Default: the mirror the bundle was retrieved with, as persisted in the cache.
+	// Ignored in offline mode, which relies on the cached trusted root.
+	TUFMirror *TUFMirror
+
+	// TrustedSourceRepo is the GitHub repository ("owner/name") trusted to produce bundles
+	// (see [GetConfig.TrustedSourceRepo]): the cached bundle and its updates are verified against it.
+	//
+	// Optional. Default: the repository the bundle was retrieved from, as persisted in the cache
+	// (loicsikidi/tpm-ca-certificates for caches persisted by previous releases).
+	TrustedSourceRepo string
+
 	// OfflineMode enables offline verification mode using assets stored in the cache directory.
 	//
 	// This mode automatically disables auto-update since the cached trusted-root.json may not work
@@ -297,6 +713,26 @@ type LoadConfig struct {
 	//
 	// Optional. Default is false (online mode).
 	OfflineMode bool
+
+	// Logger receives a warning when a certificate of the cached bundle fails to parse
+	// (such certificates are skipped) or the verification is skipped.
+	//
+	// Optional. If nil, only a skipped verification is reported, once, to [slog.Default].
+	Logger *slog.Logger
+
+	// namespaced is true once CacheNamespace has been applied to CachePath.
+	namespaced bool
+
+	// sourceRepo is the GitHub repository to verify the bundle against, if set by the user.
+	//
+	// This field is internal and derived from TrustedSourceRepo.
+	sourceRepo *github.Repo
+
+	// snapshot is true when CachePath is a temporary copy of the cache: the bundle is
+	// neither auto-updated nor refreshable.
+	//
+	// This field is internal and only set by [LoadFromArchive].
+	snapshot bool
 }
 
 // CheckAndSetDefaults validates and sets default values.
@@ -304,17 +740,49 @@ func (c *LoadConfig) CheckAndSetDefaults() error {
 	if c.CachePath == "" {
 		c.CachePath = cache.CacheDir()
 	}
-	if !utils.DirExists(c.CachePath) {
+	if !c.namespaced {
+		cachePath, err := namespacedCachePath(c.CachePath, c.CacheNamespace)
+		if err != nil {
+			return err
+		}
+		c.CachePath = cachePath
+		fallbackCachePaths, err := namespacedCachePaths(c.FallbackCachePaths, c.CacheNamespace)
+		if err != nil {
+			return err
+		}
+		c.FallbackCachePaths = fallbackCachePaths
+		c.namespaced = true
+	}
+	if !utils.DirExists(c.CachePath) && !slices.ContainsFunc(c.FallbackCachePaths, utils.DirExists) {
 		return fmt.Errorf("cache directory does not exist: %s", c.CachePath)
 	}
 	if c.OfflineMode && c.DisableLocalCache {
 		return fmt.Errorf("offline mode requires local cache to be enabled")
 	}
+	if c.SkipVerify && !c.AcknowledgeSkipVerify {
+		return ErrSkipVerifyNotAcknowledged
+	}
+	if c.TUFMirror != nil {
+		if err := c.TUFMirror.CheckAndSetDefaults(); err != nil {
+			return fmt.Errorf("invalid TUF mirror: %w", err)
+		}
+	}
+	if c.sourceRepo == nil && c.TrustedSourceRepo != "" {
+		sourceRepo, err := trustedSourceRepo(c.TrustedSourceRepo)
+		if err != nil {
+			return err
+		}
+		c.sourceRepo = sourceRepo
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = HTTPClient()
+	}
+	c.HTTPClient = utils.WithUserAgent(c.HTTPClient, c.UserAgent)
 	return nil
 }
 
 func (c LoadConfig) GetHTTPClient() utils.HTTPClient {
-	return nil
+	return c.HTTPClient
 }
 
 func (c LoadConfig) GetSkipVerify() bool {
@@ -329,6 +797,84 @@ func (c LoadConfig) GetCachePath() string {
 	return c.CachePath
 }
 
+func (c LoadConfig) GetFallbackCachePaths() []string {
+	return c.FallbackCachePaths
+}
+
+func (c LoadConfig) GetUserAgent() string {
+	return c.UserAgent
+}
+
+func (c LoadConfig) GetLogger() *slog.Logger {
+	return c.Logger
+}
+
+// CheckForUpdateConfig configures the bundle freshness probe.
+type CheckForUpdateConfig struct {
+	// CachePath is the location on disk for tpmtb cache.
+	//
+	// Optional. If empty, the default cache path is used ($HOME/.tpmtb).
+	CachePath string
+
+	// CacheNamespace selects the subdirectory of CachePath holding the cached bundle.
+	//
+	// Optional. If empty, the cached bundle is read directly from CachePath.
+	CacheNamespace string
+
+	// HTTPClient is the HTTP client to use for requests.
+	//
+	// Optional. If nil, the fallback default returned by [HTTPClient] is used (built by [NewHTTPClient]).
+	HTTPClient utils.HTTPClient
+
+	// UserAgent is the User-Agent header sent with every request made through HTTPClient.
+	//
+	// Optional. Default: "tpmtb/<version>".
+	UserAgent string
+
+	// TrustedSourceRepo is the GitHub repository ("owner/name") releasing the bundles.
+	//
+	// Optional. Default: loicsikidi/tpm-ca-certificates.
+	TrustedSourceRepo string
+
+	// sourceRepo is the GitHub repository to look up releases from.
+	//
+	// This field is internal and derived from TrustedSourceRepo.
+	sourceRepo *github.Repo
+
+	// namespaced is true once CacheNamespace has been applied to CachePath.
+	namespaced bool
+}
+
+// CheckAndSetDefaults validates and sets default values.
+func (c *CheckForUpdateConfig) CheckAndSetDefaults() error {
+	if c.sourceRepo == nil {
+		sourceRepo, err := trustedSourceRepo(c.TrustedSourceRepo)
+		if err != nil {
+			return err
+		}
+		c.sourceRepo = sourceRepo
+	}
+	if err := c.sourceRepo.CheckAndSetDefaults(); err != nil {
+		return fmt.Errorf("invalid source repository: %w", err)
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = HTTPClient()
+	}
+	c.HTTPClient = utils.WithUserAgent(c.HTTPClient, c.UserAgent)
+	if c.CachePath == "" {
+		c.CachePath = cache.CacheDir()
+	}
+	if !c.namespaced {
+		cachePath, err := namespacedCachePath(c.CachePath, c.CacheNamespace)
+		if err != nil {
+			return err
+		}
+		c.CachePath = cachePath
+		c.namespaced = true
+	}
+	return nil
+}
+
 // AutoUpdateConfig configures automatic updates of the bundle.
 type AutoUpdateConfig struct {
 	// DisableAutoUpdate disables automatic updates of the bundle.
@@ -340,6 +886,26 @@ type AutoUpdateConfig struct {
 	//
 	// Optional. If zero, the default interval of 24 hours is used.
 	Interval time.Duration `json:"interval"`
+
+	// OnUpdate is called after each update check with its outcome, and the error
+	// which caused it when the outcome is [UpdateOutcomeFailed].
+	//
+	// It is called from the watcher goroutine, and by [TrustedBundle.RefreshNow].
+	//
+	// Optional.
+	OnUpdate func(outcome UpdateOutcome, err error) `json:"-"`
+
+	// IntegrityCheckInterval specifies how often the digests of the bundle held in memory,
+	// both the raw bundles and the parsed certificates, are recomputed and compared against
+	// the reference digests: the ones listed in the verified checksums file for the raw
+	// bundles, or the ones computed when the bundle was retrieved if the verification was
+	// skipped. It is a defense in depth against memory corruption. A mismatch is reported to OnUpdate
+	// as [UpdateOutcomeFailed] with an error wrapping [ErrIntegrityCheckFailed].
+	//
+	// The check runs even if DisableAutoUpdate is set.
+	//
+	// Optional. If zero, the integrity is not checked.
+	IntegrityCheckInterval time.Duration `json:"integrityCheckInterval,omitempty"`
 }
 
 // CheckAndSetDefaults validates and sets default values.
@@ -347,5 +913,17 @@ func (c *AutoUpdateConfig) CheckAndSetDefaults() error {
 	if c.Interval == 0 && !c.DisableAutoUpdate {
 		c.Interval = 24 * time.Hour
 	}
+	if c.IntegrityCheckInterval < 0 {
+		return fmt.Errorf("integrity check interval cannot be negative")
+	}
 	return nil
 }
+
+// watchIntervals returns the update and integrity check intervals of the watcher,
+// each being zero when disabled.
+func (c *AutoUpdateConfig) watchIntervals() (update, integrityCheck time.Duration) {
+	if !c.DisableAutoUpdate {
+		update = c.Interval
+	}
+	return update, c.IntegrityCheckInterval
+}
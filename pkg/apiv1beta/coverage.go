@@ -0,0 +1,108 @@
+package apiv1beta
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/fingerprint"
+)
+
+// CoverageError is returned by [AssertCoverage] when required certificates are missing from a bundle.
+type CoverageError struct {
+	// Missing maps each vendor to its required SHA-256 fingerprints absent from the bundle,
+	// formatted as uppercase colon-separated hex (e.g., "AA:BB:...").
+	Missing map[VendorID][]string
+}
+
+func (e *CoverageError) Error() string {
+	vendorIDs := make([]VendorID, 0, len(e.Missing))
+	for vendorID := range e.Missing {
+		vendorIDs = append(vendorIDs, vendorID)
+	}
+	slices.SortFunc(vendorIDs, cmp.Compare)
+
+	parts := make([]string, 0, len(vendorIDs))
+	for _, vendorID := range vendorIDs {
+		parts = append(parts, fmt.Sprintf("%s: %s", vendorID, strings.Join(e.Missing[vendorID], ", ")))
+	}
+	return fmt.Sprintf("bundle is missing required certificates (%s)", strings.Join(parts, "; "))
+}
+
+// AssertCoverage checks that the bundle holds every required certificate of each vendor.
+//
+// required maps vendor IDs to SHA-256 fingerprints, in "AA:BB:..." or "aabb..." format.
+// A certificate only counts for the vendor it belongs to, and certificates excluded by
+// the vendor filter of the bundle are ignored. It lets a deployment fail closed when an
+// expected root is dropped from a release.
+//
+// Returns a [*CoverageError] listing the missing fingerprints.
+//
+// Example:
+//
+//	err := apiv1beta.AssertCoverage(tb, map[apiv1beta.VendorID][]string{
+//	    apiv1beta.IFX: {"CF:EB:02:FE:..."},
+//	})
+//	var coverageErr *apiv1beta.CoverageError
+//	if errors.As(err, &coverageErr) {
+//	    log.Fatalf("missing certificates: %v", coverageErr.Missing)
+//	}
+func AssertCoverage(tb TrustedBundle, required map[VendorID][]string) error {
+	impl, ok := tb.(*trustedBundle)
+	if !ok {
+		return fmt.Errorf("unsupported trusted bundle implementation %T", tb)
+	}
+
+	expected := make(map[VendorID][]string, len(required))
+	for vendorID, fingerprints := range required {
+		if err := vendorID.Validate(); err != nil {
+			return fmt.Errorf("invalid vendor ID: %w", err)
+		}
+		for _, fp := range fingerprints {
+			normalized := fingerprint.FormatFingerprint(fp)
+			if len(normalized) != 3*sha256.Size-1 || !fingerprint.IsValid(normalized) {
+				return fmt.Errorf("invalid SHA-256 fingerprint %q for vendor %s", fp, vendorID)
+			}
+			expected[vendorID] = append(expected[vendorID], normalized)
+		}
+	}
+
+	present := impl.fingerprints()
+	missing := make(map[VendorID][]string)
+	for vendorID, fingerprints := range expected {
+		for _, fp := range fingerprints {
+			if !present[vendorID][fp] {
+				missing[vendorID] = append(missing[vendorID], fp)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return &CoverageError{Missing: missing}
+	}
+	return nil
+}
+
+// fingerprints returns the SHA-256 fingerprints of the root and intermediate certificates
+// of the bundle, by vendor.
+func (tb *trustedBundle) fingerprints() map[VendorID]map[string]bool {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	result := make(map[VendorID]map[string]bool)
+	collect := func(vendorID vendors.ID, c *x509.Certificate) bool {
+		if result[vendorID] == nil {
+			result[vendorID] = make(map[string]bool)
+		}
+		hash := sha256.Sum256(c.Raw)
+		result[vendorID][fingerprint.FormatFingerprint(hex.EncodeToString(hash[:]))] = true
+		return true
+	}
+	tb.forEachVendorCert(tb.rootCatalog, collect)
+	tb.forEachVendorCert(tb.intermediateCatalog, collect)
+	return result
+}
@@ -0,0 +1,88 @@
+package apiv1beta
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+const (
+	// Fingerprints of certificates of the test bundle
+	ifxFingerprint  = "CF:EB:02:FE:CD:55:AD:7A:73:C6:E1:D1:19:85:D4:C4:7D:EE:24:8A:B6:3D:CB:66:09:1A:24:89:66:04:43:C3"
+	intcFingerprint = "BE:B4:0B:B7:50:7B:33:96:72:26:AA:80:E0:84:74:9F:BB:65:93:89:3C:64:2E:81:8D:68:2E:9A:8D:07:FC:24"
+	// unknownFingerprint matches no certificate of the test bundle
+	unknownFingerprint = "00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF"
+)
+
+func TestAssertCoverage(t *testing.T) {
+	bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	tb, err := newTrustedBundle(t.Context(), nil, bundleData)
+	if err != nil {
+		t.Fatalf("failed to create trusted bundle: %v", err)
+	}
+
+	t.Run("complete coverage", func(t *testing.T) {
+		err := AssertCoverage(tb, map[VendorID][]string{
+			IFX: {ifxFingerprint},
+			// Fingerprints without colons nor uppercase are accepted
+			INTC: {strings.ToLower(strings.ReplaceAll(intcFingerprint, ":", ""))},
+		})
+		if err != nil {
+			t.Fatalf("AssertCoverage() error = %v", err)
+		}
+	})
+
+	t.Run("missing required fingerprint", func(t *testing.T) {
+		err := AssertCoverage(tb, map[VendorID][]string{
+			IFX:  {ifxFingerprint, unknownFingerprint},
+			INTC: {intcFingerprint},
+			// A certificate only counts for the vendor it belongs to
+			NTC: {ifxFingerprint},
+		})
+		var coverageErr *CoverageError
+		if !errors.As(err, &coverageErr) {
+			t.Fatalf("expected a CoverageError, got %v", err)
+		}
+		if len(coverageErr.Missing) != 2 {
+			t.Fatalf("expected 2 vendors with missing certificates, got %v", coverageErr.Missing)
+		}
+		if !slices.Equal(coverageErr.Missing[IFX], []string{unknownFingerprint}) {
+			t.Errorf("unexpected missing IFX certificates: %v", coverageErr.Missing[IFX])
+		}
+		if !slices.Equal(coverageErr.Missing[NTC], []string{ifxFingerprint}) {
+			t.Errorf("unexpected missing NTC certificates: %v", coverageErr.Missing[NTC])
+		}
+		if !strings.Contains(err.Error(), unknownFingerprint) {
+			t.Errorf("expected the error to list the missing fingerprint, got %q", err)
+		}
+	})
+
+	t.Run("vendor filter", func(t *testing.T) {
+		filtered, err := newTrustedBundle(t.Context(), nil, bundleData)
+		if err != nil {
+			t.Fatalf("failed to create trusted bundle: %v", err)
+		}
+		filtered.(*trustedBundle).setVendorFilter([]VendorID{IFX})
+
+		err = AssertCoverage(filtered, map[VendorID][]string{INTC: {intcFingerprint}})
+		var coverageErr *CoverageError
+		if !errors.As(err, &coverageErr) {
+			t.Fatalf("expected a CoverageError for a filtered out vendor, got %v", err)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		if err := AssertCoverage(tb, map[VendorID][]string{IFX: {"AA:BB"}}); err == nil {
+			t.Error("expected an error for a truncated fingerprint")
+		}
+		if err := AssertCoverage(tb, map[VendorID][]string{"INVALID": {ifxFingerprint}}); err == nil {
+			t.Error("expected an error for an invalid vendor ID")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package apiv1beta
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+)
+
+// BundleEntry is a certificate of a bundle paired with the metadata headers curated in the bundle.
+type BundleEntry struct {
+	// Line is the 1-based line number where the entry starts in the bundle.
+	Line int
+	// Name is the value of the "Certificate" header.
+	Name string
+	// VendorID is the value of the "Owner" header.
+	VendorID VendorID
+	// Headers maps each metadata header key (e.g., "Issuer", "Serial Number",
+	// "Fingerprint (SHA-256)") to its value, as written in the bundle.
+	Headers map[string]string
+	// Certificate is the parsed certificate. It is nil if the PEM block is invalid.
+	Certificate *x509.Certificate
+	// Err is non-nil when the entry is partial: malformed or missing headers, or an invalid certificate.
+	Err error
+}
+
+// ParseBundleEntries parses a TPM trust bundle and returns each certificate with its metadata headers.
+//
+// It lets tools display the vendor-curated metadata without re-deriving it from the certificates.
+// Malformed entries don't abort the parsing: they are returned with [BundleEntry.Err] set, along
+// with whatever could be parsed. Use [ValidateBundle] for a strict structural check.
+//
+// ParseBundleEntries does NOT check signatures nor provenance, see [VerifyTrustedBundle].
+//
+// Example:
+//
+//	entries, err := apiv1beta.ParseBundleEntries(bundleData)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, entry := range entries {
+//	    if entry.Err != nil {
+//	        log.Printf("line %d: %v", entry.Line, entry.Err)
+//	        continue
+//	    }
+//	    fmt.Printf("%s (%s): %s\n", entry.Name, entry.VendorID, entry.Headers["Serial Number"])
+//	}
+func ParseBundleEntries(data []byte) ([]BundleEntry, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("bundle cannot be empty")
+	}
+
+	entries, err := bundle.ParseEntries(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	result := make([]BundleEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, BundleEntry{
+			Line:        e.Line,
+			Name:        e.Headers[bundle.CertMetadataKeyCertificate.Key()],
+			VendorID:    VendorID(e.Headers[bundle.CertMetadataKeyOwner.Key()]),
+			Headers:     e.Headers,
+			Certificate: e.Certificate,
+			Err:         e.Err,
+		})
+	}
+	return result, nil
+}
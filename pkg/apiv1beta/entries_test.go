@@ -0,0 +1,99 @@
+package apiv1beta
+
+import (
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+func TestParseBundleEntries(t *testing.T) {
+	t.Run("well-formed bundle", func(t *testing.T) {
+		data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+		if err != nil {
+			t.Fatalf("failed to read test bundle: %v", err)
+		}
+		catalog, err := bundle.ParseBundle(data)
+		if err != nil {
+			t.Fatalf("failed to parse test bundle: %v", err)
+		}
+		var want int
+		for _, certs := range catalog {
+			want += len(certs)
+		}
+
+		entries, err := ParseBundleEntries(data)
+		if err != nil {
+			t.Fatalf("ParseBundleEntries() error = %v", err)
+		}
+		if len(entries) != want {
+			t.Fatalf("expected %d entries, got %d", want, len(entries))
+		}
+		for _, entry := range entries {
+			if entry.Err != nil {
+				t.Errorf("line %d: unexpected error: %v", entry.Line, entry.Err)
+				continue
+			}
+			if entry.Certificate == nil || entry.Name == "" || entry.VendorID.Validate() != nil {
+				t.Errorf("line %d: incomplete entry: %+v", entry.Line, entry)
+				continue
+			}
+			if got := entry.Headers["Subject"]; got != entry.Certificate.Subject.String() {
+				t.Errorf("line %d: Subject header = %q, want %q", entry.Line, got, entry.Certificate.Subject.String())
+			}
+			if !strings.HasPrefix(entry.Headers["Serial Number"], entry.Certificate.SerialNumber.String()) {
+				t.Errorf("line %d: unexpected Serial Number header %q", entry.Line, entry.Headers["Serial Number"])
+			}
+		}
+	})
+
+	t.Run("missing headers", func(t *testing.T) {
+		certDER, _ := testutil.GenerateTestCertDER(t)
+		certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+		data := validGlobalMetadata + `
+#
+# Certificate: Test Certificate
+# Owner: STM
+# Issuer
+#
+` + certPEM + `
+` + certPEM
+
+		entries, err := ParseBundleEntries([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseBundleEntries() error = %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(entries))
+		}
+
+		partial := entries[0]
+		if partial.Certificate == nil || partial.Name != "Test Certificate" || partial.VendorID != STM {
+			t.Errorf("expected a partial entry with the certificate and parsed headers, got %+v", partial)
+		}
+		if partial.Err == nil {
+			t.Fatal("expected an error for the partial entry")
+		}
+		for _, want := range []string{`malformed metadata header "Issuer"`, `missing required "Serial Number" header`} {
+			if !strings.Contains(partial.Err.Error(), want) {
+				t.Errorf("entry error %q does not contain %q", partial.Err, want)
+			}
+		}
+
+		orphan := entries[1]
+		if orphan.Certificate == nil || len(orphan.Headers) != 0 {
+			t.Errorf("expected a certificate without headers, got %+v", orphan)
+		}
+		if orphan.Err == nil || !strings.Contains(orphan.Err.Error(), "without metadata block") {
+			t.Errorf("unexpected error for certificate without metadata: %v", orphan.Err)
+		}
+	})
+
+	t.Run("empty bundle", func(t *testing.T) {
+		if _, err := ParseBundleEntries(nil); err == nil {
+			t.Fatal("ParseBundleEntries() error = nil, want error for empty bundle")
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package apiv1beta
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+)
+
+// DefaultHTTPTimeout is the total time limit of a request sent by a client returned by
+// [NewHTTPClient], including reading the response body.
+const DefaultHTTPTimeout = utils.DefaultHTTPTimeout
+
+// HTTPClientOption configures the client returned by [NewHTTPClient].
+type HTTPClientOption func(*http.Client)
+
+// WithHTTPTimeout overrides [DefaultHTTPTimeout]. A zero timeout disables it.
+func WithHTTPTimeout(timeout time.Duration) HTTPClientOption {
+	return func(c *http.Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithHTTPTransport sets the transport sending the requests (e.g. to go through a proxy or
+// trust a private CA), in place of a clone of [http.DefaultTransport].
+//
+// The default transport is kept when transport is nil.
+func WithHTTPTransport(transport http.RoundTripper) HTTPClientOption {
+	return func(c *http.Client) {
+		if transport != nil {
+			c.Transport = transport
+		}
+	}
+}
+
+// NewHTTPClient returns an [http.Client] with production defaults, used as the fallback
+// client of every call whose config does not set its own HTTPClient (see [HTTPClient]).
+//
+// Unlike [http.DefaultClient], the client:
+//   - aborts requests after [DefaultHTTPTimeout];
+//   - keeps a bounded number of idle connections;
+//   - rejects redirects from HTTPS to HTTP with [ErrInsecureRedirect], and stops after 10 redirects;
+//   - sends the project User-Agent ("tpmtb/<version>") unless a request sets its own.
+//
+// Callers needing other settings can still pass their own client in the HTTPClient field of
+// [GetConfig], [LoadConfig], [VerifyConfig] or [SaveConfig].
+func NewHTTPClient(opts ...HTTPClientOption) *http.Client {
+	clientOpts := make([]func(*http.Client), 0, len(opts))
+	for _, opt := range opts {
+		clientOpts = append(clientOpts, opt)
+	}
+	return utils.NewHTTPClient(clientOpts...)
+}
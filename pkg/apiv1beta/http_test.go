@@ -0,0 +1,68 @@
+package apiv1beta
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("default client", func(t *testing.T) {
+		client := HTTPClient()
+		if client == http.DefaultClient {
+			t.Fatal("expected the default client not to be http.DefaultClient")
+		}
+		if client.Timeout == 0 {
+			t.Error("expected the default client to have a timeout")
+		}
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		var insecureHits atomic.Int32
+		insecure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			insecureHits.Add(1)
+		}))
+		defer insecure.Close()
+
+		var userAgent atomic.Value
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/downgrade":
+				http.Redirect(w, r, insecure.URL, http.StatusFound)
+			case "/redirect":
+				http.Redirect(w, r, "/target", http.StatusFound)
+			default:
+				userAgent.Store(r.UserAgent())
+			}
+		}))
+		defer server.Close()
+
+		client := NewHTTPClient(WithHTTPTransport(server.Client().Transport))
+
+		resp, err := client.Get(server.URL + "/redirect")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		resp.Body.Close()
+		if got := userAgent.Load(); got != utils.UserAgent() {
+			t.Errorf("User-Agent = %v, want %s", got, utils.UserAgent())
+		}
+
+		if _, err := client.Get(server.URL + "/downgrade"); !errors.Is(err, ErrInsecureRedirect) {
+			t.Errorf("Get() error = %v, want %v", err, ErrInsecureRedirect)
+		}
+		if insecureHits.Load() != 0 {
+			t.Error("expected the HTTP server not to be reached")
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		if client := NewHTTPClient(WithHTTPTimeout(0)); client.Timeout != 0 {
+			t.Errorf("Timeout = %s, want 0", client.Timeout)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package apiv1beta
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/observability"
+)
+
+// EnablePrometheusMetrics enables the Prometheus exporter of the metrics recorded by this
+// package (e.g. the verification count and duration), which are then served by [MetricsHandler].
+//
+// The returned function flushes and stops the exporter. The exporter is only set up once:
+// later calls return a no-op function.
+func EnablePrometheusMetrics(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	return observability.EnablePrometheus(ctx)
+}
+
+// MetricsHandler returns an [http.Handler] serving the metrics in the Prometheus exposition
+// format, meant to be mounted at /metrics by the embedder.
+//
+// The handler responds with 404 Not Found until the exporter is enabled with
+// [EnablePrometheusMetrics].
+//
+// Example:
+//
+//	shutdown, err := apiv1beta.EnablePrometheusMetrics(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer shutdown(context.Background())
+//	http.Handle("/metrics", apiv1beta.MetricsHandler())
+func MetricsHandler() http.Handler {
+	return observability.MetricsHandler()
+}
@@ -0,0 +1,222 @@
+package apiv1beta
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// BundleOption configures [NewTrustedBundle].
+type BundleOption func(*bundleOptions)
+
+// bundleOptions holds the inputs collected by [BundleOption] functions.
+type bundleOptions struct {
+	intermediate      io.Reader
+	checksum          io.Reader
+	checksumSignature io.Reader
+	provenance        io.Reader
+	trustedRoot       io.Reader
+	vendorIDs         []VendorID
+	skipVerify        bool
+	logger            *slog.Logger
+}
+
+// WithIntermediate provides the intermediate bundle (PEM format).
+func WithIntermediate(r io.Reader) BundleOption {
+	return func(o *bundleOptions) {
+		o.intermediate = r
+	}
+}
+
+// WithChecksum provides the checksums.txt file content.
+func WithChecksum(r io.Reader) BundleOption {
+	return func(o *bundleOptions) {
+		o.checksum = r
+	}
+}
+
+// WithChecksumSignature provides the checksums.txt.sigstore.json file content.
+func WithChecksumSignature(r io.Reader) BundleOption {
+	return func(o *bundleOptions) {
+		o.checksumSignature = r
+	}
+}
+
+// WithProvenance provides the GitHub Attestation provenance bound to the bundle.
+func WithProvenance(r io.Reader) BundleOption {
+	return func(o *bundleOptions) {
+		o.provenance = r
+	}
+}
+
+// WithTrustedRoot provides the Sigstore trusted_root.json used to verify the bundle.
+func WithTrustedRoot(r io.Reader) BundleOption {
+	return func(o *bundleOptions) {
+		o.trustedRoot = r
+	}
+}
+
+// WithVendorIDs restricts the certificate pools to the given vendors.
+func WithVendorIDs(vendorIDs ...VendorID) BundleOption {
+	return func(o *bundleOptions) {
+		o.vendorIDs = vendorIDs
+	}
+}
+
+// WithLogger sets the logger receiving a warning for every certificate that fails to parse.
+func WithLogger(logger *slog.Logger) BundleOption {
+	return func(o *bundleOptions) {
+		o.logger = logger
+	}
+}
+
+// WithSkipVerify disables the bundle verification.
+//
+// Use with caution: the bundle authenticity and integrity are not checked.
+func WithSkipVerify() BundleOption {
+	return func(o *bundleOptions) {
+		o.skipVerify = true
+	}
+}
+
+// NewTrustedBundle creates a [TrustedBundle] from a root bundle reader.
+//
+// The root reader may also carry a combined root + intermediate bundle.
+// Unless [WithSkipVerify] is set, the checksum, checksum signature, provenance and
+// trusted root must all be provided: the bundle is verified offline and this function
+// never touches the disk nor the network. Verification applies to the bundles as published,
+// so a combined bundle can only be used with [WithSkipVerify].
+//
+// The returned bundle never auto-updates.
+//
+// Example:
+//
+//	tb, err := apiv1beta.NewTrustedBundle(rootReader,
+//	    apiv1beta.WithIntermediate(intermediateReader),
+//	    apiv1beta.WithChecksum(checksumReader),
+//	    apiv1beta.WithChecksumSignature(checksumSigReader),
+//	    apiv1beta.WithProvenance(provenanceReader),
+//	    apiv1beta.WithTrustedRoot(trustedRootReader),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewTrustedBundle(root io.Reader, opts ...BundleOption) (TrustedBundle, error) {
+	var o bundleOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for _, vendorID := range o.vendorIDs {
+		if err := vendorID.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid vendor ID: %w", err)
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("root bundle cannot be nil")
+	}
+	rootBundleData, err := io.ReadAll(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read root bundle: %w", err)
+	}
+	if len(rootBundleData) == 0 {
+		return nil, fmt.Errorf("root bundle cannot be empty")
+	}
+
+	intermediateBundleData, err := readOptional(o.intermediate, "intermediate bundle")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var checksumData, checksumSigData, provenanceData []byte
+	if !o.skipVerify {
+		var missing []string
+		for _, asset := range []struct {
+			name string
+			r    io.Reader
+		}{
+			{"checksum", o.checksum},
+			{"checksum signature", o.checksumSignature},
+			{"provenance", o.provenance},
+			{"trusted root", o.trustedRoot},
+		} {
+			if asset.r == nil {
+				missing = append(missing, asset.name)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("missing verification assets: %s (use WithSkipVerify to skip verification)", strings.Join(missing, ", "))
+		}
+
+		if checksumData, err = readOptional(o.checksum, "checksum"); err != nil {
+			return nil, err
+		}
+		if checksumSigData, err = readOptional(o.checksumSignature, "checksum signature"); err != nil {
+			return nil, err
+		}
+		if provenanceData, err = readOptional(o.provenance, "provenance"); err != nil {
+			return nil, err
+		}
+		trustedRootData, err := readOptional(o.trustedRoot, "trusted root")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := VerifyTrustedBundle(ctx, VerifyConfig{
+			Bundle:            rootBundleData,
+			Checksum:          checksumData,
+			ChecksumSignature: checksumSigData,
+			Provenance:        provenanceData,
+			TrustedRoot:       trustedRootData,
+			DisableLocalCache: true,
+		}); err != nil {
+			return nil, fmt.Errorf("root bundle verification failed: %w", err)
+		}
+		if len(intermediateBundleData) > 0 {
+			if _, err := VerifyTrustedBundle(ctx, VerifyConfig{
+				Bundle:            intermediateBundleData,
+				Checksum:          checksumData,
+				ChecksumSignature: checksumSigData,
+				Provenance:        provenanceData,
+				TrustedRoot:       trustedRootData,
+				DisableLocalCache: true,
+			}); err != nil {
+				return nil, fmt.Errorf("intermediate bundle verification failed: %w", err)
+			}
+		}
+	}
+
+	tb, err := newTrustedBundle(ctx, o.logger, rootBundleData, intermediateBundleData)
+	if err != nil {
+		return nil, err
+	}
+
+	tbImpl := tb.(*trustedBundle)
+	if tbImpl.rootMetadata == nil {
+		return nil, fmt.Errorf("root bundle not found")
+	}
+	tbImpl.setVendorFilter(o.vendorIDs)
+	tbImpl.autoUpdateCfg = &AutoUpdateConfig{DisableAutoUpdate: true}
+	tbImpl.skipVerify = o.skipVerify
+	tbImpl.assets.checksum = checksumData
+	tbImpl.assets.checksumSignature = checksumSigData
+	tbImpl.assets.provenance = provenanceData
+
+	return tb, nil
+}
+
+// readOptional reads r entirely, returning nil if r is nil.
+func readOptional(r io.Reader, name string) ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,112 @@
+package apiv1beta
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+// readerTestFiles reads the assets required for offline verification.
+func readerTestFiles(t *testing.T) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	for _, name := range []string{
+		testutil.RootBundleFile,
+		testutil.ChecksumFile,
+		testutil.ChecksumSigstoreFile,
+		testutil.ProvenanceFile,
+		testutil.TrustedRootFile,
+	} {
+		data, err := testutil.ReadTestFile(name)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		files[name] = data
+	}
+	return files
+}
+
+func TestNewTrustedBundle(t *testing.T) {
+	files := readerTestFiles(t)
+
+	t.Run("verified bundle", func(t *testing.T) {
+		tb, err := NewTrustedBundle(bytes.NewReader(files[testutil.RootBundleFile]),
+			WithChecksum(bytes.NewReader(files[testutil.ChecksumFile])),
+			WithChecksumSignature(bytes.NewReader(files[testutil.ChecksumSigstoreFile])),
+			WithProvenance(bytes.NewReader(files[testutil.ProvenanceFile])),
+			WithTrustedRoot(bytes.NewReader(files[testutil.TrustedRootFile])),
+		)
+		if err != nil {
+			t.Fatalf("NewTrustedBundle() error = %v", err)
+		}
+		defer tb.Stop()
+
+		if got := tb.GetRootMetadata().Date; got != testutil.BundleVersion {
+			t.Errorf("expected date %s, got %s", testutil.BundleVersion, got)
+		}
+		if !bytes.Equal(tb.GetRawRoot(), files[testutil.RootBundleFile]) {
+			t.Error("raw root bundle does not match the input")
+		}
+		if len(tb.GetVendors()) == 0 {
+			t.Error("expected at least one vendor")
+		}
+	})
+
+	t.Run("skip verify", func(t *testing.T) {
+		tb, err := NewTrustedBundle(bytes.NewReader(files[testutil.RootBundleFile]),
+			WithSkipVerify(),
+			WithVendorIDs(IFX),
+		)
+		if err != nil {
+			t.Fatalf("NewTrustedBundle() error = %v", err)
+		}
+		defer tb.Stop()
+
+		if tb.GetIntermediateMetadata() != nil {
+			t.Error("expected no intermediate bundle")
+		}
+		if tb.GetRootCertPool() == nil {
+			t.Error("expected a root certificate pool")
+		}
+	})
+
+	t.Run("missing verification assets", func(t *testing.T) {
+		_, err := NewTrustedBundle(bytes.NewReader(files[testutil.RootBundleFile]),
+			WithChecksum(bytes.NewReader(files[testutil.ChecksumFile])),
+		)
+		if err == nil {
+			t.Fatal("expected error when verification assets are missing")
+		}
+		if !strings.Contains(err.Error(), "checksum signature, provenance, trusted root") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("tampered bundle fails verification", func(t *testing.T) {
+		tampered := append(bytes.Clone(files[testutil.RootBundleFile]), '\n')
+		_, err := NewTrustedBundle(bytes.NewReader(tampered),
+			WithChecksum(bytes.NewReader(files[testutil.ChecksumFile])),
+			WithChecksumSignature(bytes.NewReader(files[testutil.ChecksumSigstoreFile])),
+			WithProvenance(bytes.NewReader(files[testutil.ProvenanceFile])),
+			WithTrustedRoot(bytes.NewReader(files[testutil.TrustedRootFile])),
+		)
+		if !errors.Is(err, ErrBundleVerificationFailed) {
+			t.Fatalf("expected ErrBundleVerificationFailed, got %v", err)
+		}
+	})
+
+	t.Run("invalid inputs", func(t *testing.T) {
+		if _, err := NewTrustedBundle(nil, WithSkipVerify()); err == nil {
+			t.Error("expected error for nil root reader")
+		}
+		if _, err := NewTrustedBundle(strings.NewReader(""), WithSkipVerify()); err == nil {
+			t.Error("expected error for empty root bundle")
+		}
+		if _, err := NewTrustedBundle(bytes.NewReader(files[testutil.RootBundleFile]), WithSkipVerify(), WithVendorIDs("XXX")); err == nil {
+			t.Error("expected error for invalid vendor ID")
+		}
+	})
+}
@@ -1,11 +1,18 @@
 package apiv1beta
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"crypto"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -15,6 +22,7 @@ import (
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/observability"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 )
@@ -100,6 +108,115 @@ type TrustedBundle interface {
 	//
 	// Returns the first matching certificate from either the root or intermediate catalogs, or nil if no match is found.
 	FindFunc(fn func(c *x509.Certificate) bool) *x509.Certificate
+
+	// ExpiringCertificates returns the certificates of the bundle expiring within the given window.
+	//
+	// Already expired certificates are included. Returns nil if no certificate expires within the window.
+	ExpiringCertificates(within time.Duration) []CertificateEntry
+
+	// GetRootCertPoolValidAt is like GetRootCertPool but only keeps the root certificates
+	// valid at t, which drops expired (or not yet valid) trust anchors.
+	//
+	// Expired roots are kept in the bundle since they may still anchor older chains, e.g.
+	// when verifying a certificate at a past instant: filter them only if your use case
+	// never needs it.
+	GetRootCertPoolValidAt(t time.Time) *x509.CertPool
+
+	// PublicKeys returns the public keys of the root certificates of the bundle.
+	//
+	// If vendorIDs are provided, only the keys of those vendors are returned.
+	// Entries are sorted by vendor then subject.
+	PublicKeys(vendorIDs ...VendorID) []PublicKeyEntry
+
+	// FindByPublicKey returns the first certificate whose public key matches pub.
+	//
+	// Certificates are matched by SubjectPublicKeyInfo rather than by fingerprint, so a certificate
+	// re-encoded by its vendor with the same key pair is still found. Root certificates are checked
+	// before intermediate ones.
+	FindByPublicKey(pub crypto.PublicKey) (*CertificateEntry, bool)
+
+	// Stats returns a summary of the root certificates of the bundle.
+	Stats() BundleStats
+
+	// RefreshNow checks for a newer bundle and updates it immediately, without waiting
+	// for the auto-update interval. [AutoUpdateConfig.OnUpdate] is called with the outcome.
+	//
+	// Returns [ErrRefreshUnsupported] if the bundle was created with [NewTrustedBundle]
+	// or loaded in offline mode.
+	RefreshNow(ctx context.Context) (UpdateOutcome, error)
+}
+
+// UpdateOutcome is the outcome of an update check.
+type UpdateOutcome int
+
+const (
+	// UpdateOutcomeUpToDate means no newer bundle was published.
+	UpdateOutcomeUpToDate UpdateOutcome = iota
+	// UpdateOutcomeUpdated means the bundle was replaced by a newer one.
+	UpdateOutcomeUpdated
+	// UpdateOutcomeFailed means the latest bundle could not be retrieved; the current bundle is kept.
+	// It is also reported when the integrity check of the bundle fails, see [ErrIntegrityCheckFailed].
+	UpdateOutcomeFailed
+)
+
+// String returns the string representation of the update outcome.
+func (o UpdateOutcome) String() string {
+	switch o {
+	case UpdateOutcomeUpToDate:
+		return "up-to-date"
+	case UpdateOutcomeUpdated:
+		return "updated"
+	case UpdateOutcomeFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("UpdateOutcome(%d)", int(o))
+	}
+}
+
+// CertificateEntry describes a certificate of the bundle.
+type CertificateEntry struct {
+	// VendorID is the vendor the certificate belongs to.
+	VendorID VendorID
+	// Subject is the certificate subject.
+	Subject string
+	// NotAfter is the certificate expiration date.
+	NotAfter time.Time
+	// Intermediate is true if the certificate comes from the intermediate bundle.
+	Intermediate bool
+	// Certificate is a copy of the certificate.
+	Certificate *x509.Certificate
+}
+
+// PublicKeyEntry describes the public key of a root certificate of the bundle.
+type PublicKeyEntry struct {
+	// VendorID is the vendor the certificate belongs to.
+	VendorID VendorID
+	// Subject is the certificate subject.
+	Subject string
+	// Algorithm is the public key algorithm (eg. [x509.RSA] or [x509.ECDSA]).
+	Algorithm x509.PublicKeyAlgorithm
+	// PublicKey is the certificate public key (eg. *rsa.PublicKey or *ecdsa.PublicKey).
+	PublicKey crypto.PublicKey
+}
+
+// BundleStats summarizes the root certificates of a bundle.
+type BundleStats struct {
+	// TotalCertificates is the number of root certificates.
+	TotalCertificates int
+	// Vendors is the number of root certificates per vendor.
+	Vendors map[VendorID]int
+	// EarliestNotAfter is the expiration date of the root certificate expiring first.
+	//
+	// Zero if the bundle contains no certificate.
+	EarliestNotAfter time.Time
+	// LatestNotAfter is the expiration date of the root certificate expiring last.
+	//
+	// Zero if the bundle contains no certificate.
+	LatestNotAfter time.Time
+	// IntermediateCertificates is the number of intermediate certificates.
+	IntermediateCertificates int
+	// HasIntermediate reports whether an intermediate bundle is loaded.
+	HasIntermediate bool
 }
 
 // trustedBundle is the internal implementation of [TrustedBundle].
@@ -118,7 +235,29 @@ type trustedBundle struct {
 	autoUpdateCfg     *AutoUpdateConfig
 	disableLocalCache bool
 
+	// rootsOnly is true when the intermediate bundle is skipped, see [GetConfig.RootsOnly].
+	rootsOnly bool
+
+	// skipVerify is true when the bundle was handed out without being verified.
+	// It is recorded when persisting, so that a verifying call never reuses the cached bytes.
+	skipVerify bool
+
+	// sourceRepo is the repository trusted to produce bundles, reused by auto-update.
+	// If nil, the upstream repository is used.
+	sourceRepo *github.Repo
+
+	// tufMirror is the TUF repository serving the Sigstore trusted root, reused by auto-update.
+	// If nil, Sigstore's public-good TUF repository is used.
+	tufMirror *TUFMirror
+
+	// referenceDigests are the digests the bundle held in memory is checked against,
+	// see [AutoUpdateConfig.IntegrityCheckInterval].
+	referenceDigests bundleDigests
+
 	// Auto-update fields
+	// updater is nil when the bundle cannot be refreshed, see [ErrRefreshUnsupported].
+	updater     updaterConfig
+	refreshMu   sync.Mutex
 	stopChan    chan struct{}
 	stoppedChan chan struct{}
 	stopOnce    sync.Once
@@ -214,14 +353,38 @@ func (tb *trustedBundle) GetIntermediateCertPool() *x509.CertPool {
 	return tb.buildCertPool(tb.intermediateCatalog)
 }
 
+// GetRootCertPoolValidAt returns an x509.CertPool containing the root certificates valid at t.
+//
+// The vendor filter of the bundle is applied, as with GetRootCertPool.
+func (tb *trustedBundle) GetRootCertPoolValidAt(t time.Time) *x509.CertPool {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	pool := x509.NewCertPool()
+	tb.forEachCert(tb.rootCatalog, func(cert *x509.Certificate) bool {
+		if !t.Before(cert.NotBefore) && !t.After(cert.NotAfter) {
+			pool.AddCert(cert)
+		}
+		return true
+	})
+	return pool
+}
+
 // forEachCert iterates over certificates in the catalog, applying vendor filters if configured.
 // The callback function is called for each certificate. If the callback returns false, iteration stops.
 func (tb *trustedBundle) forEachCert(catalog map[vendors.ID][]*x509.Certificate, fn func(*x509.Certificate) bool) {
+	tb.forEachVendorCert(catalog, func(_ vendors.ID, cert *x509.Certificate) bool {
+		return fn(cert)
+	})
+}
+
+// forEachVendorCert is like forEachCert but also passes the vendor ID of each certificate to the callback.
+func (tb *trustedBundle) forEachVendorCert(catalog map[vendors.ID][]*x509.Certificate, fn func(vendors.ID, *x509.Certificate) bool) {
 	// If no vendor filter, iterate all certificates
 	if len(tb.vendorFilter) == 0 {
-		for _, certs := range catalog {
+		for vendorID, certs := range catalog {
 			for _, cert := range certs {
-				if !fn(cert) {
+				if !fn(vendorID, cert) {
 					return
 				}
 			}
@@ -233,7 +396,7 @@ func (tb *trustedBundle) forEachCert(catalog map[vendors.ID][]*x509.Certificate,
 	for _, vendorID := range tb.vendorFilter {
 		if certs, ok := catalog[vendorID]; ok {
 			for _, cert := range certs {
-				if !fn(cert) {
+				if !fn(vendorID, cert) {
 					return
 				}
 			}
@@ -340,6 +503,138 @@ func (tb *trustedBundle) findFuncInCatalog(fn func(c *x509.Certificate) bool, ca
 	return result
 }
 
+// ExpiringCertificates returns the certificates whose NotAfter falls within the given window from now.
+//
+// Both root and intermediate certificates are checked. If the bundle was created with VendorIDs filter,
+// only certificates from those vendors are checked. Entries are sorted by expiration date (soonest first).
+func (tb *trustedBundle) ExpiringCertificates(within time.Duration) []CertificateEntry {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	deadline := time.Now().Add(within)
+
+	var entries []CertificateEntry
+	collect := func(catalog map[vendors.ID][]*x509.Certificate, intermediate bool) {
+		tb.forEachVendorCert(catalog, func(vendorID vendors.ID, c *x509.Certificate) bool {
+			if c.NotAfter.After(deadline) {
+				return true
+			}
+			// Return a copy to prevent external modifications
+			certCopy := *c
+			entries = append(entries, CertificateEntry{
+				VendorID:     vendorID,
+				Subject:      c.Subject.String(),
+				NotAfter:     c.NotAfter,
+				Intermediate: intermediate,
+				Certificate:  &certCopy,
+			})
+			return true
+		})
+	}
+	collect(tb.rootCatalog, false)
+	collect(tb.intermediateCatalog, true)
+
+	slices.SortFunc(entries, func(a, b CertificateEntry) int {
+		return a.NotAfter.Compare(b.NotAfter)
+	})
+	return entries
+}
+
+// PublicKeys returns the public keys of the root certificates.
+//
+// If the bundle was created with VendorIDs filter, only keys from those vendors are returned
+// and vendorIDs further restricts them.
+func (tb *trustedBundle) PublicKeys(vendorIDs ...VendorID) []PublicKeyEntry {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	var entries []PublicKeyEntry
+	tb.forEachVendorCert(tb.rootCatalog, func(vendorID vendors.ID, c *x509.Certificate) bool {
+		if len(vendorIDs) > 0 && !slices.Contains(vendorIDs, vendorID) {
+			return true
+		}
+		entries = append(entries, PublicKeyEntry{
+			VendorID:  vendorID,
+			Subject:   c.Subject.String(),
+			Algorithm: c.PublicKeyAlgorithm,
+			PublicKey: c.PublicKey,
+		})
+		return true
+	})
+
+	slices.SortFunc(entries, func(a, b PublicKeyEntry) int {
+		return cmp.Or(cmp.Compare(a.VendorID, b.VendorID), cmp.Compare(a.Subject, b.Subject))
+	})
+	return entries
+}
+
+// FindByPublicKey returns the first certificate whose SubjectPublicKeyInfo matches pub.
+//
+// If the bundle was created with VendorIDs filter, only certificates from those vendors are checked.
+func (tb *trustedBundle) FindByPublicKey(pub crypto.PublicKey) (*CertificateEntry, bool) {
+	want, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, false
+	}
+
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	var entry *CertificateEntry
+	find := func(catalog map[vendors.ID][]*x509.Certificate, intermediate bool) {
+		tb.forEachVendorCert(catalog, func(vendorID vendors.ID, c *x509.Certificate) bool {
+			spki, err := x509.MarshalPKIXPublicKey(c.PublicKey)
+			if err != nil || !bytes.Equal(spki, want) {
+				return true
+			}
+			// Return a copy to prevent external modifications
+			certCopy := *c
+			entry = &CertificateEntry{
+				VendorID:     vendorID,
+				Subject:      c.Subject.String(),
+				NotAfter:     c.NotAfter,
+				Intermediate: intermediate,
+				Certificate:  &certCopy,
+			}
+			return false
+		})
+	}
+	find(tb.rootCatalog, false)
+	if entry == nil {
+		find(tb.intermediateCatalog, true)
+	}
+	return entry, entry != nil
+}
+
+// Stats returns a summary of the root certificates.
+//
+// If the bundle was created with VendorIDs filter, only certificates from those vendors are counted.
+func (tb *trustedBundle) Stats() BundleStats {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	stats := BundleStats{
+		Vendors:         make(map[VendorID]int),
+		HasIntermediate: tb.intermediateMetadata != nil,
+	}
+	tb.forEachVendorCert(tb.rootCatalog, func(vendorID vendors.ID, c *x509.Certificate) bool {
+		stats.TotalCertificates++
+		stats.Vendors[vendorID]++
+		if stats.EarliestNotAfter.IsZero() || c.NotAfter.Before(stats.EarliestNotAfter) {
+			stats.EarliestNotAfter = c.NotAfter
+		}
+		if c.NotAfter.After(stats.LatestNotAfter) {
+			stats.LatestNotAfter = c.NotAfter
+		}
+		return true
+	})
+	tb.forEachCert(tb.intermediateCatalog, func(*x509.Certificate) bool {
+		stats.IntermediateCertificates++
+		return true
+	})
+	return stats
+}
+
 // Persist writes the bundle and its configuration to disk.
 func (tb *trustedBundle) Persist(ctx context.Context, optionalCachePath ...string) error {
 	_, span := observability.StartSpan(ctx, "tpmtb.Persist")
@@ -365,7 +660,7 @@ func (tb *trustedBundle) Persist(ctx context.Context, optionalCachePath ...strin
 		}
 	}
 
-	skipVerify := (len(tb.assets.checksum) == 0 &&
+	skipVerify := tb.skipVerify || (len(tb.assets.checksum) == 0 &&
 		len(tb.assets.checksumSignature) == 0 &&
 		len(tb.assets.provenance) == 0)
 
@@ -375,6 +670,11 @@ func (tb *trustedBundle) Persist(ctx context.Context, optionalCachePath ...strin
 		VendorIDs:     tb.vendorFilter,
 		LastTimestamp: time.Now(),
 		SkipVerify:    skipVerify,
+		RootsOnly:     tb.rootsOnly,
+		TUFMirror:     tb.tufMirror,
+	}
+	if tb.sourceRepo != nil {
+		cfg.SourceRepo = tb.sourceRepo.String()
 	}
 
 	configData, err := json.Marshal(cfg)
@@ -421,18 +721,127 @@ func (tb *trustedBundle) Stop() error {
 }
 
 // update atomically updates the bundle data.
-func (tb *trustedBundle) update(assets *assets, metadata *bundle.Metadata, intermediateMetadata *bundle.Metadata, catalog map[vendors.ID][]*x509.Certificate) {
+func (tb *trustedBundle) update(assets *assets, metadata *bundle.Metadata, intermediateMetadata *bundle.Metadata, catalog, intermediateCatalog map[vendors.ID][]*x509.Certificate) {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
 
 	tb.assets = assets
 	tb.rootMetadata = metadata
 	tb.intermediateMetadata = intermediateMetadata
-	tb.rootCatalog = catalog
+	tb.rootCatalog = filterCatalog(catalog, tb.vendorFilter)
+	tb.intermediateCatalog = filterCatalog(intermediateCatalog, tb.vendorFilter)
+	tb.referenceDigests = tb.expectedDigests()
+}
+
+// bundleDigests holds the digests of the raw bundles and of the certificates parsed from them.
+type bundleDigests struct {
+	root                string
+	intermediate        string
+	rootCatalog         string
+	intermediateCatalog string
+}
+
+// digests computes the digests of the bundle held in memory.
+//
+// The caller must hold tb.mu.
+func (tb *trustedBundle) digests() bundleDigests {
+	return bundleDigests{
+		root:                ComputeBundleDigest(tb.assets.rootBundleData),
+		intermediate:        ComputeBundleDigest(tb.assets.intermediateBundleData),
+		rootCatalog:         catalogDigest(tb.rootCatalog),
+		intermediateCatalog: catalogDigest(tb.intermediateCatalog),
+	}
+}
+
+// expectedDigests returns the digests the bundle held in memory must match.
+//
+// Once verified, the checksums file is the reference for the raw bundles; otherwise
+// their digests are computed from the bytes retrieved. The caller must hold tb.mu.
+func (tb *trustedBundle) expectedDigests() bundleDigests {
+	expected := tb.digests()
+	if tb.skipVerify || len(tb.assets.checksum) == 0 {
+		return expected
+	}
+
+	checksums, err := ParseChecksums(tb.assets.checksum)
+	if err != nil {
+		// Unreachable since the checksums file was verified: make the integrity check fail
+		return bundleDigests{}
+	}
+	// The checksums file lists hex digests, while ComputeBundleDigest prefixes them with the algorithm
+	expected.root = "sha256:" + checksums[cache.RootBundleFilename]
+	if len(tb.assets.intermediateBundleData) > 0 {
+		expected.intermediate = "sha256:" + checksums[cache.IntermediateBundleFilename]
+	}
+	return expected
+}
+
+// catalogDigest computes the digest of the DER encoding of the certificates of catalog.
+func catalogDigest(catalog map[vendors.ID][]*x509.Certificate) string {
+	h := sha256.New()
+	for _, vendorID := range slices.Sorted(maps.Keys(catalog)) {
+		h.Write([]byte(vendorID))
+		for _, cert := range catalog[vendorID] {
+			h.Write(cert.Raw)
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// checkIntegrity recomputes the digests of the bundle held in memory, both the raw bundles
+// and the parsed certificates, and compares them against the reference digests.
+func (tb *trustedBundle) checkIntegrity() error {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	got, want := tb.digests(), tb.referenceDigests
+	for _, c := range []struct{ name, got, want string }{
+		{"root bundle", got.root, want.root},
+		{"intermediate bundle", got.intermediate, want.intermediate},
+		{"root certificates", got.rootCatalog, want.rootCatalog},
+		{"intermediate certificates", got.intermediateCatalog, want.intermediateCatalog},
+	} {
+		if c.got != c.want {
+			return fmt.Errorf("%w: %s digest is %s, expected %s", ErrIntegrityCheckFailed, c.name, c.got, c.want)
+		}
+	}
+	return nil
+}
+
+// setVendorFilter restricts the bundle to the given vendors.
+//
+// The certificates of the other vendors are dropped from the catalogs rather than
+// filtered on each access, so that they don't hold memory. The raw bundles are kept
+// untouched since they are needed to verify and persist the bundle.
+func (tb *trustedBundle) setVendorFilter(vendorIDs []VendorID) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.vendorFilter = vendorIDs
+	tb.rootCatalog = filterCatalog(tb.rootCatalog, vendorIDs)
+	tb.intermediateCatalog = filterCatalog(tb.intermediateCatalog, vendorIDs)
+}
+
+// filterCatalog returns the part of catalog owned by the given vendors.
+//
+// If vendorIDs is empty, catalog is returned as is.
+func filterCatalog(catalog map[vendors.ID][]*x509.Certificate, vendorIDs []VendorID) map[vendors.ID][]*x509.Certificate {
+	if len(vendorIDs) == 0 || catalog == nil {
+		return catalog
+	}
+	filtered := make(map[vendors.ID][]*x509.Certificate, len(vendorIDs))
+	for _, vendorID := range vendorIDs {
+		if certs, ok := catalog[vendorID]; ok {
+			filtered[vendorID] = certs
+		}
+	}
+	return filtered
 }
 
 // LoadTrustedBundle reads a persisted [TrustedBundle] from disk and verifies its integrity.
 //
+// Returns [ErrInvalidCacheConfig] if the persisted cache configuration is malformed.
+//
 // Example:
 //
 //	tb, err := apiv1beta.LoadTrustedBundle(context.Background(), apiv1beta.LoadConfig{})
@@ -445,31 +854,42 @@ func LoadTrustedBundle(ctx context.Context, cfg LoadConfig) (TrustedBundle, erro
 		return nil, err
 	}
 
-	rootBundleData, err := cache.LoadFile(cfg.CachePath, cache.RootBundleFilename)
-	if err != nil {
-		return nil, err
+	// Load from the first cache holding a bundle: CachePath, then the read-only fallbacks
+	cachePath, ok := findCachePath("", append([]string{cfg.CachePath}, cfg.FallbackCachePaths...)...)
+	if !ok {
+		cachePath = cfg.CachePath
 	}
-	// first releases did not have intermediate bundle
-	// so we ignore [os.ErrNotExist] here
-	intermediateBundleData, err := cache.LoadFile(cfg.CachePath, cache.IntermediateBundleFilename)
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
+
+	rootBundleData, err := cache.LoadFile(cachePath, cache.RootBundleFilename)
+	if err != nil {
 		return nil, err
 	}
 
-	configData, err := cache.LoadFile(cfg.CachePath, cache.ConfigFilename)
+	configData, err := cache.LoadFile(cachePath, cache.ConfigFilename)
 	if err != nil {
 		return nil, err
 	}
 
-	var cacheCfg CacheConfig
-	if err := json.Unmarshal(configData, &cacheCfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	cacheCfg, err := parseCacheConfig(configData)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := cacheCfg.CheckAndSetDefaults(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	var intermediateBundleData []byte
+	// A stale intermediate bundle may remain from a previous persist: ignore it in roots-only mode
+	if !cacheCfg.RootsOnly {
+		// first releases did not have intermediate bundle
+		// so we ignore [os.ErrNotExist] here
+		intermediateBundleData, err = cache.LoadFile(cachePath, cache.IntermediateBundleFilename)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
 	var skipVerify bool
 	switch {
 	// user has highest priority
@@ -479,28 +899,47 @@ func LoadTrustedBundle(ctx context.Context, cfg LoadConfig) (TrustedBundle, erro
 		// then we fallback to cached config
 		skipVerify = cacheCfg.SkipVerify
 	}
+	if skipVerify {
+		warnSkipVerify(ctx, cfg.Logger, cacheCfg.Version)
+	}
+
+	// The bundle is verified, and updated, against the source it was retrieved from,
+	// unless the user sets it explicitly
+	sourceRepo := cfg.sourceRepo
+	if sourceRepo == nil {
+		sourceRepo, err = trustedSourceRepo(cacheCfg.SourceRepo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	tufMirror := cfg.TUFMirror
+	if tufMirror == nil {
+		tufMirror = cacheCfg.TUFMirror
+	}
 
 	var checksumData, checksumSigData, provenanceData, trustedRootData []byte
 	if !skipVerify {
 		var err error
-		checksumData, err = cache.LoadFile(cfg.CachePath, cache.ChecksumsFilename)
+		checksumData, err = cache.LoadFile(cachePath, cache.ChecksumsFilename)
 		if err != nil {
 			return nil, err
 		}
 
-		checksumSigData, err = cache.LoadFile(cfg.CachePath, cache.ChecksumsSigFilename)
+		checksumSigData, err = cache.LoadFile(cachePath, cache.ChecksumsSigFilename)
 		if err != nil {
 			return nil, err
 		}
 
-		provenanceData, err = cache.LoadFile(cfg.CachePath, cache.ProvenanceFilename)
+		provenanceData, err = cache.LoadFile(cachePath, cache.ProvenanceFilename)
 		if err != nil {
 			return nil, err
 		}
 
-		// In offline mode, load trusted-root.json from cache
+		// In offline mode, load trusted-root.json from cache (in place of the TUF mirror)
+		verifyMirror := tufMirror
 		if cfg.OfflineMode {
-			trustedRootData, err = cache.LoadFile(cfg.CachePath, cache.TrustedRootFilename)
+			verifyMirror = nil
+			trustedRootData, err = cache.LoadFile(cachePath, cache.TrustedRootFilename)
 			if err != nil {
 				return nil, err
 			}
@@ -512,7 +951,10 @@ func LoadTrustedBundle(ctx context.Context, cfg LoadConfig) (TrustedBundle, erro
 			ChecksumSignature: checksumSigData,
 			Provenance:        provenanceData,
 			TrustedRoot:       trustedRootData,
+			TUFMirror:         verifyMirror,
+			HTTPClient:        cfg.HTTPClient,
 			DisableLocalCache: cfg.DisableLocalCache,
+			sourceRepo:        sourceRepo,
 		}); err != nil {
 			return nil, fmt.Errorf("root verification failed: %w", err)
 		}
@@ -524,31 +966,45 @@ func LoadTrustedBundle(ctx context.Context, cfg LoadConfig) (TrustedBundle, erro
 				ChecksumSignature: checksumSigData,
 				Provenance:        provenanceData,
 				TrustedRoot:       trustedRootData,
+				TUFMirror:         verifyMirror,
+				HTTPClient:        cfg.HTTPClient,
 				DisableLocalCache: cfg.DisableLocalCache,
+				sourceRepo:        sourceRepo,
 			}); err != nil {
 				return nil, fmt.Errorf("intermediate bundle verification failed: %w", err)
 			}
 		}
 	}
 
-	tb, err := newTrustedBundle(ctx, rootBundleData, intermediateBundleData)
+	tb, err := newTrustedBundle(ctx, cfg.Logger, rootBundleData, intermediateBundleData)
 	if err != nil {
 		return nil, err
 	}
 
 	// Store vendor filter and verification assets
 	tbImpl := tb.(*trustedBundle)
-	tbImpl.vendorFilter = cacheCfg.VendorIDs
+	tbImpl.setVendorFilter(cacheCfg.VendorIDs)
 	tbImpl.autoUpdateCfg = cacheCfg.AutoUpdate
+	tbImpl.rootsOnly = cacheCfg.RootsOnly
+	tbImpl.skipVerify = skipVerify
+	tbImpl.sourceRepo = sourceRepo
+	tbImpl.tufMirror = tufMirror
 	tbImpl.assets.checksum = checksumData
 	tbImpl.assets.checksumSignature = checksumSigData
 	tbImpl.assets.provenance = provenanceData
+	if !cfg.OfflineMode && !cfg.snapshot {
+		tbImpl.updater = cfg
+	}
 
 	// In offline mode, auto-update must be disabled since the cached trusted-root.json
 	// may not work with future bundles due to Sigstore key rotation
 	if cacheCfg.AutoUpdate != nil {
-		if !cfg.OfflineMode && !cacheCfg.AutoUpdate.DisableAutoUpdate {
-			tb.(*trustedBundle).startWatcher(ctx, cfg, cacheCfg.AutoUpdate.Interval)
+		update, integrityCheck := cacheCfg.AutoUpdate.watchIntervals()
+		if cfg.OfflineMode || cfg.snapshot {
+			update = 0
+		}
+		if update > 0 || integrityCheck > 0 {
+			tb.(*trustedBundle).startWatcher(ctx, cfg, update, integrityCheck)
 		}
 	}
 	return tb, nil
@@ -559,18 +1015,38 @@ type updaterConfig interface {
 	GetSkipVerify() bool
 	GetDisableLocalCache() bool
 	GetCachePath() string
+	GetFallbackCachePaths() []string
+	GetUserAgent() string
+	GetLogger() *slog.Logger
 }
 
 // startWatcher starts the auto-update watcher in a background goroutine.
-func (tb *trustedBundle) startWatcher(ctx context.Context, cfg updaterConfig, interval time.Duration) {
+//
+// The watcher checks for updates every updateInterval and the integrity of the bundle
+// every integrityCheckInterval; a zero interval disables the matching check.
+func (tb *trustedBundle) startWatcher(ctx context.Context, cfg updaterConfig, updateInterval, integrityCheckInterval time.Duration) {
 	tb.stopChan = make(chan struct{})
 	tb.stoppedChan = make(chan struct{})
 
+	tb.mu.Lock()
+	tb.referenceDigests = tb.expectedDigests()
+	tb.mu.Unlock()
+
 	go func() {
 		defer close(tb.stoppedChan)
 
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
+		// A nil channel never fires, which disables the matching check
+		var updateC, integrityCheckC <-chan time.Time
+		if updateInterval > 0 {
+			ticker := time.NewTicker(updateInterval)
+			defer ticker.Stop()
+			updateC = ticker.C
+		}
+		if integrityCheckInterval > 0 {
+			ticker := time.NewTicker(integrityCheckInterval)
+			defer ticker.Stop()
+			integrityCheckC = ticker.C
+		}
 
 		for {
 			select {
@@ -578,70 +1054,142 @@ func (tb *trustedBundle) startWatcher(ctx context.Context, cfg updaterConfig, in
 				return
 			case <-tb.stopChan:
 				return
-			case <-ticker.C:
-				tb.checkAndUpdate(ctx, cfg)
+			case <-updateC:
+				_, _ = tb.refresh(ctx, cfg)
+			case <-integrityCheckC:
+				tb.reportIntegrity()
 			}
 		}
 	}()
 }
 
+// reportIntegrity checks the integrity of the bundle and reports a mismatch to [AutoUpdateConfig.OnUpdate].
+func (tb *trustedBundle) reportIntegrity() {
+	// Serialize with the update checks, which replace the bundle and report to OnUpdate as well
+	tb.refreshMu.Lock()
+	defer tb.refreshMu.Unlock()
+
+	if err := tb.checkIntegrity(); err != nil && tb.autoUpdateCfg != nil && tb.autoUpdateCfg.OnUpdate != nil {
+		tb.autoUpdateCfg.OnUpdate(UpdateOutcomeFailed, err)
+	}
+}
+
+// RefreshNow checks for a newer bundle and updates it immediately.
+func (tb *trustedBundle) RefreshNow(ctx context.Context) (UpdateOutcome, error) {
+	if tb.updater == nil {
+		return UpdateOutcomeFailed, ErrRefreshUnsupported
+	}
+	return tb.refresh(ctx, tb.updater)
+}
+
+// refresh runs an update check and reports its outcome to [AutoUpdateConfig.OnUpdate].
+func (tb *trustedBundle) refresh(ctx context.Context, cfg updaterConfig) (UpdateOutcome, error) {
+	// Serialize the watcher and RefreshNow checks
+	tb.refreshMu.Lock()
+	defer tb.refreshMu.Unlock()
+
+	outcome, err := tb.checkAndUpdate(ctx, cfg)
+	if tb.autoUpdateCfg != nil && tb.autoUpdateCfg.OnUpdate != nil {
+		tb.autoUpdateCfg.OnUpdate(outcome, err)
+	}
+	return outcome, err
+}
+
 // checkAndUpdate checks for a new bundle version and updates if necessary.
-func (tb *trustedBundle) checkAndUpdate(ctx context.Context, cfg updaterConfig) {
+//
+// On failure, the current bundle is kept.
+func (tb *trustedBundle) checkAndUpdate(ctx context.Context, cfg updaterConfig) (UpdateOutcome, error) {
 	// Fetch the latest bundle without starting a watcher
 	newBundle, err := GetTrustedBundle(ctx, GetConfig{
 		Date:       "", // Always fetch latest
 		SkipVerify: cfg.GetSkipVerify(),
-		HTTPClient: cfg.GetHTTPClient(),
+		// Skipping the verification was acknowledged when the bundle was first retrieved
+		AcknowledgeSkipVerify: cfg.GetSkipVerify(),
+		HTTPClient:            cfg.GetHTTPClient(),
+		UserAgent:             cfg.GetUserAgent(),
+		Logger:                cfg.GetLogger(),
+		CachePath:             cfg.GetCachePath(),
+		FallbackCachePaths:    cfg.GetFallbackCachePaths(),
+		DisableLocalCache:     cfg.GetDisableLocalCache(),
+		RootsOnly:             tb.rootsOnly,
 		AutoUpdate: AutoUpdateConfig{
 			DisableAutoUpdate: true, // Don't start a watcher for this temporary bundle
 		},
+		TUFMirror:  tb.tufMirror,
+		sourceRepo: tb.sourceRepo,
 	})
 	if err != nil {
-		// Silently fail and keep current bundle
-		return
+		return UpdateOutcomeFailed, fmt.Errorf("failed to get latest bundle: %w", err)
 	}
 
 	// Check if the date is newer
 	currentMetadata := tb.GetRootMetadata()
 	newMetadata := newBundle.GetRootMetadata()
 	if newMetadata.Date <= currentMetadata.Date {
-		// No update needed
-		return
+		return UpdateOutcomeUpToDate, nil
 	}
 
 	newTB := newBundle.(*trustedBundle)
-	tb.update(newTB.assets, newTB.rootMetadata, newTB.intermediateMetadata, newTB.rootCatalog)
+	tb.update(newTB.assets, newTB.rootMetadata, newTB.intermediateMetadata, newTB.rootCatalog, newTB.intermediateCatalog)
 
 	// Persist the updated bundle if local cache is enabled
 	if !cfg.GetDisableLocalCache() {
 		// Ignore error as persistence failure shouldn't stop the update
 		_ = tb.Persist(ctx, cfg.GetCachePath())
 	}
+	return UpdateOutcomeUpdated, nil
 }
 
 // newTrustedBundle creates a TrustedBundle from raw bundle data.
-func newTrustedBundle(ctx context.Context, bundles ...[]byte) (TrustedBundle, error) {
+//
+// Each input may be a single bundle or a combined root + intermediate bundle (see [bundle.SplitDocuments]).
+// Certificates that cannot be parsed are skipped and reported to logger, if not nil.
+func newTrustedBundle(ctx context.Context, logger *slog.Logger, bundles ...[]byte) (TrustedBundle, error) {
 	_, span := observability.StartSpan(ctx, "tpmtb.newTrustedBundle")
 	defer span.End()
 
 	tb := &trustedBundle{
 		assets: &assets{},
 	}
+	var docs [][]byte
 	for _, b := range bundles {
-		if len(b) == 0 {
-			continue
+		// A combined bundle carries both the root and the intermediate documents
+		split, err := bundle.SplitDocuments(b)
+		if err != nil {
+			observability.RecordError(span, err)
+			return nil, fmt.Errorf("failed to split bundle: %w", err)
 		}
+		docs = append(docs, split...)
+	}
+	for _, b := range docs {
 		metadata, err := bundle.ParseMetadata(b)
 		if err != nil {
 			observability.RecordError(span, err)
 			return nil, fmt.Errorf("failed to parse bundle metadata: %w", err)
 		}
 
-		catalog, err := bundle.ParseBundle(b)
+		result, err := bundle.ParseBundleWithDiagnostics(b)
 		if err != nil {
 			observability.RecordError(span, err)
 			return nil, fmt.Errorf("failed to parse bundle: %w", err)
 		}
+		if logger != nil {
+			for _, skipped := range result.Skipped {
+				logger.WarnContext(ctx, "skipped certificate that failed to parse",
+					slog.String("bundle_type", metadata.Type.String()),
+					slog.Int("line", skipped.Line),
+					slog.String("reason", skipped.Reason.Error()),
+				)
+			}
+		}
+		catalog := result.Catalog
+
+		if (metadata.Type == bundle.TypeRoot && tb.rootMetadata != nil) ||
+			(metadata.Type == bundle.TypeIntermediate && tb.intermediateMetadata != nil) {
+			err := fmt.Errorf("more than one %s bundle provided", metadata.Type)
+			observability.RecordError(span, err)
+			return nil, err
+		}
 
 		if metadata.Type == bundle.TypeRoot {
 			tb.assets.rootBundleData = b
@@ -6,18 +6,28 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"math/big"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/loicsikidi/go-tpm-kit/tpmcert/ekca"
 	"github.com/loicsikidi/go-tpm-kit/tpmcert/x509ext"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/cache"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/config/vendors"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/github"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
 	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
 )
@@ -26,8 +36,9 @@ func TestGetTrustedBundle(t *testing.T) {
 
 	t.Run("invalid vendor ID", func(t *testing.T) {
 		cfg := GetConfig{
-			SkipVerify: true,
-			VendorIDs:  []VendorID{"INVALID_VENDOR"},
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			VendorIDs:             []VendorID{"INVALID_VENDOR"},
 			AutoUpdate: AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -49,7 +60,7 @@ func TestPersist(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -111,7 +122,7 @@ func TestPersist(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -133,6 +144,191 @@ func TestPersist(t *testing.T) {
 	})
 }
 
+func TestNewTrustedBundleCombined(t *testing.T) {
+	root, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+	intermediate := []byte(strings.Replace(string(root), CacheRootBundleFilename, CacheIntermediateBundleFilename, 1))
+
+	combined, err := bundle.Merge(root, intermediate)
+	if err != nil {
+		t.Fatalf("Failed to merge bundles: %v", err)
+	}
+
+	t.Run("populates both catalogs", func(t *testing.T) {
+		tb, err := newTrustedBundle(t.Context(), nil, combined)
+		if err != nil {
+			t.Fatalf("Failed to create trusted bundle: %v", err)
+		}
+		tbImpl := tb.(*trustedBundle)
+
+		if len(tbImpl.rootCatalog) == 0 || len(tbImpl.intermediateCatalog) == 0 {
+			t.Fatalf("Expected both catalogs to be populated, got %d root and %d intermediate vendors",
+				len(tbImpl.rootCatalog), len(tbImpl.intermediateCatalog))
+		}
+		if !bytes.Equal(tb.GetRawRoot(), combined[:len(tb.GetRawRoot())]) {
+			t.Error("Expected raw root to be the first document of the combined bundle")
+		}
+		if !bytes.Equal(tb.GetRawIntermediate(), intermediate) {
+			t.Error("Expected raw intermediate to be the second document of the combined bundle")
+		}
+		if tb.GetIntermediateMetadata().Type != bundle.TypeIntermediate {
+			t.Errorf("Expected intermediate metadata, got %s", tb.GetIntermediateMetadata().Type)
+		}
+	})
+
+	t.Run("rejects three blocks", func(t *testing.T) {
+		data := append(append([]byte{}, combined...), root...)
+		if _, err := newTrustedBundle(t.Context(), nil, data); err == nil {
+			t.Fatal("Expected error for a bundle with three global metadata blocks")
+		}
+	})
+
+	t.Run("rejects duplicate bundle types", func(t *testing.T) {
+		if _, err := newTrustedBundle(t.Context(), nil, combined, intermediate); err == nil {
+			t.Fatal("Expected error for two intermediate bundles")
+		}
+	})
+}
+
+func TestNewTrustedBundleSkipsCorruptCertificates(t *testing.T) {
+	data, line := testutil.CorruptRootBundleCertificate(t, 0)
+
+	var logs bytes.Buffer
+	tb, err := newTrustedBundle(t.Context(), slog.New(slog.NewTextHandler(&logs, nil)), data)
+	if err != nil {
+		t.Fatalf("newTrustedBundle() error = %v", err)
+	}
+
+	if slices.Contains(tb.GetVendors(), IFX) {
+		t.Error("expected the corrupt IFX certificate to be skipped")
+	}
+	for _, want := range []string{"level=WARN", "skipped certificate that failed to parse", fmt.Sprintf("line=%d", line)} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log output missing %q:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestLoadTrustedBundleLogsCorruptCertificates(t *testing.T) {
+	data, line := testutil.CorruptRootBundleCertificate(t, 0)
+	cacheDir := testutil.CreateCacheDir(t, []byte(`{"version":"2025-12-05","lastTimestamp":"2025-12-14T00:00:00Z","skipVerify":true}`))
+	if err := cache.SaveFile(cacheDir, cache.RootBundleFilename, data); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+
+	var logs bytes.Buffer
+	tb, err := LoadTrustedBundle(t.Context(), LoadConfig{
+		CachePath:             cacheDir,
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
+		Logger:                slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+	if err != nil {
+		t.Fatalf("LoadTrustedBundle() error = %v", err)
+	}
+	defer tb.Stop()
+
+	for _, want := range []string{"skipped certificate that failed to parse", fmt.Sprintf("line=%d", line)} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log output missing %q:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestLoadTrustedBundleSkipVerify(t *testing.T) {
+	t.Run("SkipVerify without acknowledgement is rejected", func(t *testing.T) {
+		cacheDir := testutil.CreateCacheDir(t, []byte(`{"version":"2025-12-05","lastTimestamp":"2025-12-14T00:00:00Z","skipVerify":true}`))
+
+		_, err := LoadTrustedBundle(t.Context(), LoadConfig{
+			CachePath:  cacheDir,
+			SkipVerify: true,
+		})
+		if !errors.Is(err, ErrSkipVerifyNotAcknowledged) {
+			t.Fatalf("LoadTrustedBundle() error = %v, want %v", err, ErrSkipVerifyNotAcknowledged)
+		}
+	})
+
+	t.Run("cached SkipVerify is logged", func(t *testing.T) {
+		cacheDir := testutil.CreateCacheDir(t, []byte(`{"version":"2025-12-05","lastTimestamp":"2025-12-14T00:00:00Z","skipVerify":true}`))
+
+		var logs bytes.Buffer
+		tb, err := LoadTrustedBundle(t.Context(), LoadConfig{
+			CachePath: cacheDir,
+			Logger:    slog.New(slog.NewTextHandler(&logs, nil)),
+		})
+		if err != nil {
+			t.Fatalf("LoadTrustedBundle() error = %v", err)
+		}
+		defer tb.Stop()
+
+		for _, want := range []string{"level=WARN", "bundle verification is skipped", "version=2025-12-05"} {
+			if !strings.Contains(logs.String(), want) {
+				t.Errorf("log output missing %q:\n%s", want, logs.String())
+			}
+		}
+	})
+}
+
+func TestLoadTrustedBundleSourceRepo(t *testing.T) {
+	const fork = "acme/tpm-ca-certificates"
+
+	t.Run("round-trip keeps the fork", func(t *testing.T) {
+		cachePath := t.TempDir()
+		tb, err := GetTrustedBundle(t.Context(), GetConfig{
+			Date:                  testutil.BundleVersion,
+			CachePath:             cachePath,
+			TrustedSourceRepo:     fork,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+		})
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		tb.Stop()
+
+		cacheCfg, err := getCacheConfig(cachePath)
+		if err != nil {
+			t.Fatalf("failed to read cache config: %v", err)
+		}
+		if cacheCfg.SourceRepo != fork {
+			t.Errorf("CacheConfig.SourceRepo = %q, want %q", cacheCfg.SourceRepo, fork)
+		}
+
+		loaded, err := LoadTrustedBundle(t.Context(), LoadConfig{CachePath: cachePath})
+		if err != nil {
+			t.Fatalf("LoadTrustedBundle() error = %v", err)
+		}
+		defer loaded.Stop()
+		if got := loaded.(*trustedBundle).sourceRepo.String(); got != fork {
+			t.Errorf("loaded bundle source repository = %s, want %s", got, fork)
+		}
+	})
+
+	t.Run("cache is verified against the persisted repository", func(t *testing.T) {
+		// The test assets are signed by upstream: a policy built for the fork rejects them
+		cacheDir := testutil.CreateCacheDir(t, []byte(`{"version":"2025-12-05","lastTimestamp":"2025-12-14T00:00:00Z","sourceRepo":"`+fork+`"}`))
+
+		_, err := LoadTrustedBundle(t.Context(), LoadConfig{CachePath: cacheDir, OfflineMode: true})
+		if !errors.Is(err, ErrBundleVerificationFailed) {
+			t.Fatalf("LoadTrustedBundle() error = %v, want %v", err, ErrBundleVerificationFailed)
+		}
+
+		tb, err := LoadTrustedBundle(t.Context(), LoadConfig{
+			CachePath:         cacheDir,
+			OfflineMode:       true,
+			TrustedSourceRepo: github.SourceRepo.String(),
+		})
+		if err != nil {
+			t.Fatalf("LoadTrustedBundle() with TrustedSourceRepo error = %v", err)
+		}
+		tb.Stop()
+	})
+}
+
 func TestGetVendors(t *testing.T) {
 	t.Run("returns all vendors when no filter", func(t *testing.T) {
 		bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
@@ -140,7 +336,7 @@ func TestGetVendors(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -163,7 +359,7 @@ func TestGetVendors(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -186,7 +382,7 @@ func TestGetVendors(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -207,7 +403,7 @@ func TestGetVendors(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -282,7 +478,7 @@ func TestLoadOfflineMode(t *testing.T) {
 		}
 
 		configPath := filepath.Join(tmpDir, CacheConfigFilename)
-		configData := []byte(`{"version":"2025-12-14","autoUpdate":{"disableAutoUpdate":true}}`)
+		configData := []byte(`{"version":"2025-12-14","autoUpdate":{"disableAutoUpdate":true},"lastTimestamp":"2025-12-14T00:00:00Z"}`)
 		if err := os.WriteFile(configPath, configData, 0644); err != nil {
 			t.Fatalf("Failed to write config: %v", err)
 		}
@@ -353,6 +549,107 @@ func TestLoadOfflineMode(t *testing.T) {
 	})
 }
 
+func TestLoadFallbackCachePaths(t *testing.T) {
+	// A stale read-only cache baked into the image, and an empty writable volume
+	staleConfig := []byte(`{"version":"2025-01-01","lastTimestamp":"2025-01-01T00:00:00Z","skipVerify":true}`)
+	fallbackPath := testutil.CreateCacheDir(t, staleConfig)
+	rootBundleData, err := cache.LoadFile(fallbackPath, cache.RootBundleFilename)
+	if err != nil {
+		t.Fatalf("failed to read fallback bundle: %v", err)
+	}
+	staleBundle := bytes.Replace(rootBundleData, []byte("## Date: "+testutil.BundleVersion), []byte("## Date: 2025-01-01"), 1)
+	if err := cache.SaveFile(fallbackPath, cache.RootBundleFilename, staleBundle); err != nil {
+		t.Fatalf("failed to write stale fallback bundle: %v", err)
+	}
+	writablePath := t.TempDir()
+
+	tb, err := LoadTrustedBundle(t.Context(), LoadConfig{
+		CachePath:             writablePath,
+		FallbackCachePaths:    []string{fallbackPath},
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
+		HTTPClient:            &releaseHTTPClient{},
+	})
+	if err != nil {
+		t.Fatalf("LoadTrustedBundle() error = %v", err)
+	}
+	defer tb.Stop()
+
+	if got := tb.GetRootMetadata().Date; got != "2025-01-01" {
+		t.Fatalf("GetRootMetadata().Date = %s, want the stale fallback bundle", got)
+	}
+
+	outcome, err := tb.RefreshNow(t.Context())
+	if err != nil {
+		t.Fatalf("RefreshNow() error = %v", err)
+	}
+	if outcome != UpdateOutcomeUpdated {
+		t.Fatalf("RefreshNow() outcome = %v, want %v", outcome, UpdateOutcomeUpdated)
+	}
+
+	if !checkCacheExists(writablePath, testutil.BundleVersion) {
+		t.Errorf("expected the update to be persisted to the writable cache")
+	}
+	fallbackCfg, err := getCacheConfig(fallbackPath)
+	if err != nil {
+		t.Fatalf("failed to read fallback cache config: %v", err)
+	}
+	if fallbackCfg.Version != "2025-01-01" {
+		t.Errorf("fallback cache must not be written, got version %s", fallbackCfg.Version)
+	}
+
+	// The writable cache now takes precedence over the fallback
+	cachePath, ok := findCachePath("", writablePath, fallbackPath)
+	if !ok || cachePath != writablePath {
+		t.Errorf("findCachePath() = %q, want %q", cachePath, writablePath)
+	}
+}
+
+func TestLoadInvalidCacheConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		configData string
+	}{
+		{
+			name:       "malformed version",
+			configData: `{"version":"14-12-2025","lastTimestamp":"2025-12-14T00:00:00Z"}`,
+		},
+		{
+			name:       "unknown field",
+			configData: `{"version":"` + testutil.BundleVersion + `","lastTimestamp":"2025-12-14T00:00:00Z","skipVerfy":true}`,
+		},
+		{
+			name:       "invalid vendor ID",
+			configData: `{"version":"` + testutil.BundleVersion + `","vendorIDs":["INVALID"],"lastTimestamp":"2025-12-14T00:00:00Z"}`,
+		},
+		{
+			name:       "missing last timestamp",
+			configData: `{"version":"` + testutil.BundleVersion + `"}`,
+		},
+		{
+			name:       "truncated",
+			configData: `{"version":"` + testutil.BundleVersion + `","lastTimes`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := testutil.CreateCacheDir(t, nil)
+			if err := os.WriteFile(filepath.Join(cacheDir, CacheConfigFilename), []byte(tt.configData), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			_, err := LoadTrustedBundle(t.Context(), LoadConfig{
+				CachePath:   cacheDir,
+				OfflineMode: true,
+			})
+			if !errors.Is(err, ErrInvalidCacheConfig) {
+				t.Fatalf("expected ErrInvalidCacheConfig, got %v", err)
+			}
+		})
+	}
+}
+
 func TestLoadConfigValidation(t *testing.T) {
 	t.Run("rejects offline mode with disabled local cache", func(t *testing.T) {
 		cfg := LoadConfig{
@@ -366,6 +663,17 @@ func TestLoadConfigValidation(t *testing.T) {
 			t.Fatal("Expected error when offline mode is enabled with DisableLocalCache=true")
 		}
 	})
+
+	t.Run("accepts a missing cache path with an existing fallback", func(t *testing.T) {
+		cfg := LoadConfig{
+			CachePath:          filepath.Join(t.TempDir(), "missing"),
+			FallbackCachePaths: []string{t.TempDir()},
+		}
+
+		if err := cfg.CheckAndSetDefaults(); err != nil {
+			t.Fatalf("CheckAndSetDefaults() error = %v", err)
+		}
+	})
 }
 
 func Test_getVerifyOptions(t *testing.T) {
@@ -375,7 +683,7 @@ func Test_getVerifyOptions(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -402,7 +710,7 @@ func Test_getVerifyOptions(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -506,7 +814,7 @@ func TestContains(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -538,7 +846,7 @@ func TestContains(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -582,7 +890,7 @@ func TestContains(t *testing.T) {
 			t.Fatalf("Failed to read test bundle: %v", err)
 		}
 
-		tb, err := newTrustedBundle(t.Context(), bundleData)
+		tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 		if err != nil {
 			t.Fatalf("Failed to create trusted bundle: %v", err)
 		}
@@ -624,7 +932,7 @@ func TestContainsFunc(t *testing.T) {
 		{
 			name: "returns true when predicate matches certificate in root catalog",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, bool) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -652,7 +960,7 @@ func TestContainsFunc(t *testing.T) {
 		{
 			name: "returns false when predicate matches no certificates",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, bool) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -682,7 +990,7 @@ func TestContainsFunc(t *testing.T) {
 		{
 			name: "respects vendor filter - returns true for filtered vendor",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, bool) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -709,7 +1017,7 @@ func TestContainsFunc(t *testing.T) {
 		{
 			name: "respects vendor filter - returns false for non-filtered vendor",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, bool) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -748,7 +1056,7 @@ func TestContainsFunc(t *testing.T) {
 		{
 			name: "checks certificates in intermediate catalog",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, bool) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -781,7 +1089,7 @@ func TestContainsFunc(t *testing.T) {
 		{
 			name: "stops iteration when predicate returns true",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, bool) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -882,7 +1190,7 @@ func TestFindFunc(t *testing.T) {
 		{
 			name: "respects vendor filter - returns certificate for filtered vendor",
 			setupFunc: func(t *testing.T) (TrustedBundle, func(c *x509.Certificate) bool, *x509.Certificate) {
-				tb, err := newTrustedBundle(t.Context(), bundleData)
+				tb, err := newTrustedBundle(t.Context(), nil, bundleData)
 				if err != nil {
 					t.Fatalf("Failed to create trusted bundle: %v", err)
 				}
@@ -949,9 +1257,9 @@ func setupVerifyTest(t *testing.T, vendorID vendors.ID, includeIntermediate bool
 	var tb TrustedBundle
 	var err error
 	if includeIntermediate {
-		tb, err = newTrustedBundle(t.Context(), rootPEM, intermediatePEM)
+		tb, err = newTrustedBundle(t.Context(), nil, rootPEM, intermediatePEM)
 	} else {
-		tb, err = newTrustedBundle(t.Context(), rootPEM)
+		tb, err = newTrustedBundle(t.Context(), nil, rootPEM)
 	}
 	if err != nil {
 		t.Fatalf("Failed to create trusted bundle: %v", err)
@@ -1057,3 +1365,661 @@ func generateEKCertificate(t *testing.T, ca *ekca.CA, vendorID vendors.ID) *x509
 
 	return cert
 }
+
+func TestExpiringCertificates(t *testing.T) {
+	parse := func(der []byte, _ string) *x509.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Failed to parse certificate: %v", err)
+		}
+		return cert
+	}
+
+	ifxSoon := parse(testutil.GenerateTestCertExpiringSoon(t, 10))
+	ifxLater := parse(testutil.GenerateTestCertExpiringSoon(t, 400))
+	stmSoon := parse(testutil.GenerateTestCertExpiringSoon(t, 5))
+	stmExpired := parse(testutil.GenerateTestCertExpired(t))
+	ifxIntermediateSoon := parse(testutil.GenerateTestCertExpiringSoon(t, 20))
+
+	newBundle := func(filter ...VendorID) *trustedBundle {
+		return &trustedBundle{
+			rootCatalog: map[VendorID][]*x509.Certificate{
+				IFX: {ifxSoon, ifxLater},
+				STM: {stmSoon, stmExpired},
+			},
+			intermediateCatalog: map[VendorID][]*x509.Certificate{
+				IFX: {ifxIntermediateSoon},
+			},
+			vendorFilter: filter,
+		}
+	}
+
+	t.Run("returns certificates expiring within the window", func(t *testing.T) {
+		entries := newBundle().ExpiringCertificates(30 * 24 * time.Hour)
+		if len(entries) != 4 {
+			t.Fatalf("Expected 4 expiring certificates, got %d", len(entries))
+		}
+
+		// Sorted soonest first
+		wantOrder := []*x509.Certificate{stmExpired, stmSoon, ifxSoon, ifxIntermediateSoon}
+		for i, want := range wantOrder {
+			if !entries[i].Certificate.Equal(want) {
+				t.Errorf("entries[%d] = %s (%s), want NotAfter %s", i, entries[i].VendorID, entries[i].NotAfter, want.NotAfter)
+			}
+		}
+		if entries[0].VendorID != STM || entries[3].VendorID != IFX || !entries[3].Intermediate {
+			t.Errorf("Unexpected entries: %+v", entries)
+		}
+		if entries[0].Subject != stmExpired.Subject.String() {
+			t.Errorf("Subject = %q, want %q", entries[0].Subject, stmExpired.Subject.String())
+		}
+	})
+
+	t.Run("respects vendor filter", func(t *testing.T) {
+		entries := newBundle(IFX).ExpiringCertificates(30 * 24 * time.Hour)
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 expiring certificates, got %d", len(entries))
+		}
+		for _, entry := range entries {
+			if entry.VendorID != IFX {
+				t.Errorf("Unexpected vendor %s", entry.VendorID)
+			}
+		}
+	})
+
+	t.Run("returns nil when nothing expires", func(t *testing.T) {
+		tb := newBundle(IFX)
+		tb.rootCatalog[IFX] = []*x509.Certificate{ifxLater}
+		tb.intermediateCatalog = nil
+		if entries := tb.ExpiringCertificates(30 * 24 * time.Hour); entries != nil {
+			t.Errorf("Expected no expiring certificates, got %+v", entries)
+		}
+	})
+}
+
+func TestGetRootCertPoolValidAt(t *testing.T) {
+	parse := func(der []byte, _ string) *x509.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Failed to parse certificate: %v", err)
+		}
+		return cert
+	}
+
+	ifxValid := parse(testutil.GenerateTestCertExpiringSoon(t, 400))
+	ifxSoon := parse(testutil.GenerateTestCertExpiringSoon(t, 10))
+	stmExpired := parse(testutil.GenerateTestCertExpired(t))
+
+	tb := &trustedBundle{
+		rootCatalog: map[VendorID][]*x509.Certificate{
+			IFX: {ifxValid, ifxSoon},
+			STM: {stmExpired},
+		},
+	}
+	newPool := func(certs ...*x509.Certificate) *x509.CertPool {
+		pool := x509.NewCertPool()
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+		return pool
+	}
+
+	if !tb.GetRootCertPool().Equal(newPool(ifxValid, ifxSoon, stmExpired)) {
+		t.Error("GetRootCertPool() must keep expired roots")
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want *x509.CertPool
+	}{
+		{
+			name: "now excludes expired roots",
+			at:   time.Now(),
+			want: newPool(ifxValid, ifxSoon),
+		},
+		{
+			name: "future instant excludes roots expiring before",
+			at:   time.Now().Add(30 * 24 * time.Hour),
+			want: newPool(ifxValid),
+		},
+		{
+			name: "instant before every root is valid",
+			at:   time.Now().Add(-365 * 24 * time.Hour),
+			want: x509.NewCertPool(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tb.GetRootCertPoolValidAt(tt.at); !got.Equal(tt.want) {
+				t.Error("GetRootCertPoolValidAt() returned an unexpected pool")
+			}
+		})
+	}
+
+	t.Run("respects vendor filter", func(t *testing.T) {
+		filtered := &trustedBundle{rootCatalog: tb.rootCatalog, vendorFilter: []VendorID{STM}}
+		if got := filtered.GetRootCertPoolValidAt(time.Now()); !got.Equal(x509.NewCertPool()) {
+			t.Error("expected an empty pool for a vendor with expired roots only")
+		}
+	})
+}
+
+func TestPublicKeys(t *testing.T) {
+	parse := func(der []byte, _ string) *x509.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Failed to parse certificate: %v", err)
+		}
+		return cert
+	}
+
+	ifxEC := parse(testutil.GenerateTestCertWithCN(t, "IFX EC Root"))
+	stmRSA := parse(testutil.GenerateTestCertRSA(t, 2048, x509.SHA256WithRSA))
+	stmEC := parse(testutil.GenerateTestCertWithCN(t, "STM EC Root"))
+
+	newBundle := func(filter ...VendorID) *trustedBundle {
+		return &trustedBundle{
+			rootCatalog: map[VendorID][]*x509.Certificate{
+				STM: {stmRSA, stmEC},
+				IFX: {ifxEC},
+			},
+			intermediateCatalog: map[VendorID][]*x509.Certificate{
+				IFX: {parse(testutil.GenerateTestCertDER(t))},
+			},
+			vendorFilter: filter,
+		}
+	}
+
+	t.Run("extracts EC and RSA keys of root certificates", func(t *testing.T) {
+		entries := newBundle().PublicKeys()
+		if len(entries) != 3 {
+			t.Fatalf("Expected 3 public keys, got %d", len(entries))
+		}
+
+		// Sorted by vendor then subject
+		wantOrder := []*x509.Certificate{ifxEC, stmRSA, stmEC}
+		slices.SortStableFunc(wantOrder[1:], func(a, b *x509.Certificate) int {
+			return strings.Compare(a.Subject.String(), b.Subject.String())
+		})
+		for i, want := range wantOrder {
+			if entries[i].Subject != want.Subject.String() {
+				t.Errorf("entries[%d].Subject = %q, want %q", i, entries[i].Subject, want.Subject.String())
+			}
+		}
+
+		for _, entry := range entries {
+			switch entry.Subject {
+			case stmRSA.Subject.String():
+				key, ok := entry.PublicKey.(*rsa.PublicKey)
+				if !ok {
+					t.Fatalf("Expected *rsa.PublicKey, got %T", entry.PublicKey)
+				}
+				if entry.Algorithm != x509.RSA || entry.VendorID != STM {
+					t.Errorf("Unexpected entry: %s %s", entry.VendorID, entry.Algorithm)
+				}
+				if !key.Equal(stmRSA.PublicKey) {
+					t.Error("RSA public key does not match the certificate")
+				}
+			default:
+				key, ok := entry.PublicKey.(*ecdsa.PublicKey)
+				if !ok {
+					t.Fatalf("Expected *ecdsa.PublicKey, got %T", entry.PublicKey)
+				}
+				if entry.Algorithm != x509.ECDSA {
+					t.Errorf("Algorithm = %s, want %s", entry.Algorithm, x509.ECDSA)
+				}
+				if key.Curve != elliptic.P256() {
+					t.Errorf("Unexpected curve %s", key.Curve.Params().Name)
+				}
+			}
+		}
+	})
+
+	t.Run("restricts to the requested vendors", func(t *testing.T) {
+		entries := newBundle().PublicKeys(IFX)
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 public key, got %d", len(entries))
+		}
+		if entries[0].VendorID != IFX || !entries[0].PublicKey.(*ecdsa.PublicKey).Equal(ifxEC.PublicKey) {
+			t.Errorf("Unexpected entry: %+v", entries[0])
+		}
+	})
+
+	t.Run("respects vendor filter", func(t *testing.T) {
+		if entries := newBundle(IFX).PublicKeys(STM); entries != nil {
+			t.Errorf("Expected no public keys outside the bundle filter, got %+v", entries)
+		}
+		if entries := newBundle(STM).PublicKeys(); len(entries) != 2 {
+			t.Errorf("Expected 2 public keys, got %d", len(entries))
+		}
+	})
+}
+
+func TestFindByPublicKey(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	// Same key pair, different serial number, extensions and encoding (eg. vendor re-issuing a root)
+	newCert := func(serial int64, template *x509.Certificate) *x509.Certificate {
+		template.SerialNumber = big.NewInt(serial)
+		template.Subject = pkix.Name{CommonName: "Vendor Root CA"}
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(365 * 24 * time.Hour)
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+		if err != nil {
+			t.Fatalf("Failed to create certificate: %v", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Failed to parse certificate: %v", err)
+		}
+		return cert
+	}
+	original := newCert(1, &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	reencoded := newCert(2, &x509.Certificate{
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            1,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+	})
+	if bytes.Equal(original.Raw, reencoded.Raw) {
+		t.Fatal("Expected certificates to have different encodings")
+	}
+
+	intermediate, _ := testutil.GenerateTestCert(t)
+
+	newBundle := func(filter ...VendorID) *trustedBundle {
+		return &trustedBundle{
+			rootCatalog: map[VendorID][]*x509.Certificate{
+				IFX: {original},
+			},
+			intermediateCatalog: map[VendorID][]*x509.Certificate{
+				STM: {intermediate},
+			},
+			vendorFilter: filter,
+		}
+	}
+
+	t.Run("matches re-encoded certificate by public key", func(t *testing.T) {
+		tb := newBundle()
+		byOriginal, ok := tb.FindByPublicKey(original.PublicKey)
+		if !ok {
+			t.Fatal("Expected certificate to be found by its own public key")
+		}
+		byReencoded, ok := tb.FindByPublicKey(reencoded.PublicKey)
+		if !ok {
+			t.Fatal("Expected re-encoded certificate to be found by public key")
+		}
+		if !byOriginal.Certificate.Equal(byReencoded.Certificate) || !byReencoded.Certificate.Equal(original) {
+			t.Error("Expected both certificates to map to the same key identity")
+		}
+		if byReencoded.VendorID != IFX || byReencoded.Intermediate {
+			t.Errorf("Unexpected entry: %+v", byReencoded)
+		}
+	})
+
+	t.Run("matches intermediate certificates", func(t *testing.T) {
+		entry, ok := newBundle().FindByPublicKey(intermediate.PublicKey)
+		if !ok {
+			t.Fatal("Expected intermediate certificate to be found")
+		}
+		if entry.VendorID != STM || !entry.Intermediate {
+			t.Errorf("Unexpected entry: %+v", entry)
+		}
+	})
+
+	t.Run("respects vendor filter", func(t *testing.T) {
+		if _, ok := newBundle(STM).FindByPublicKey(original.PublicKey); ok {
+			t.Error("Expected certificate outside the vendor filter not to be found")
+		}
+	})
+
+	t.Run("returns false for unknown or invalid keys", func(t *testing.T) {
+		other, _ := testutil.GenerateTestCert(t)
+		if _, ok := newBundle().FindByPublicKey(other.PublicKey); ok {
+			t.Error("Expected unknown key not to be found")
+		}
+		if _, ok := newBundle().FindByPublicKey(nil); ok {
+			t.Error("Expected nil key not to be found")
+		}
+	})
+}
+
+func TestStats(t *testing.T) {
+	root, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("Failed to read test bundle: %v", err)
+	}
+
+	t.Run("counts certificates of the test bundle", func(t *testing.T) {
+		tb, err := newTrustedBundle(t.Context(), nil, root)
+		if err != nil {
+			t.Fatalf("Failed to create trusted bundle: %v", err)
+		}
+
+		stats := tb.Stats()
+		if stats.TotalCertificates != 15 {
+			t.Errorf("TotalCertificates = %d, want 15", stats.TotalCertificates)
+		}
+		wantVendors := map[VendorID]int{IFX: 1, INTC: 1, NTC: 8, STM: 5}
+		if !maps.Equal(stats.Vendors, wantVendors) {
+			t.Errorf("Vendors = %v, want %v", stats.Vendors, wantVendors)
+		}
+		if stats.HasIntermediate || stats.IntermediateCertificates != 0 {
+			t.Errorf("Expected no intermediate bundle, got %+v", stats)
+		}
+
+		// IFX root expires on Sat Jul 25 23:59:59 2043
+		ifxNotAfter := time.Date(2043, time.July, 25, 23, 59, 59, 0, time.UTC)
+		if stats.EarliestNotAfter.IsZero() || stats.EarliestNotAfter.After(ifxNotAfter) {
+			t.Errorf("EarliestNotAfter = %s, want at most %s", stats.EarliestNotAfter, ifxNotAfter)
+		}
+		if stats.LatestNotAfter.Before(ifxNotAfter) {
+			t.Errorf("LatestNotAfter = %s, want at least %s", stats.LatestNotAfter, ifxNotAfter)
+		}
+		for _, certs := range tb.(*trustedBundle).rootCatalog {
+			for _, c := range certs {
+				if c.NotAfter.Before(stats.EarliestNotAfter) || c.NotAfter.After(stats.LatestNotAfter) {
+					t.Errorf("NotAfter %s outside [%s, %s]", c.NotAfter, stats.EarliestNotAfter, stats.LatestNotAfter)
+				}
+			}
+		}
+	})
+
+	t.Run("respects vendor filter", func(t *testing.T) {
+		tb, err := newTrustedBundle(t.Context(), nil, root)
+		if err != nil {
+			t.Fatalf("Failed to create trusted bundle: %v", err)
+		}
+		tb.(*trustedBundle).vendorFilter = []VendorID{IFX, STM}
+
+		stats := tb.Stats()
+		if stats.TotalCertificates != 6 {
+			t.Errorf("TotalCertificates = %d, want 6", stats.TotalCertificates)
+		}
+		if wantVendors := map[VendorID]int{IFX: 1, STM: 5}; !maps.Equal(stats.Vendors, wantVendors) {
+			t.Errorf("Vendors = %v, want %v", stats.Vendors, wantVendors)
+		}
+	})
+
+	t.Run("reports intermediate bundle", func(t *testing.T) {
+		intermediate := []byte(strings.Replace(string(root), CacheRootBundleFilename, CacheIntermediateBundleFilename, 1))
+		tb, err := newTrustedBundle(t.Context(), nil, root, intermediate)
+		if err != nil {
+			t.Fatalf("Failed to create trusted bundle: %v", err)
+		}
+
+		stats := tb.Stats()
+		if !stats.HasIntermediate || stats.IntermediateCertificates != 15 {
+			t.Errorf("Expected 15 intermediate certificates, got %+v", stats)
+		}
+		if stats.TotalCertificates != 15 {
+			t.Errorf("TotalCertificates = %d, want 15", stats.TotalCertificates)
+		}
+	})
+
+	t.Run("empty bundle", func(t *testing.T) {
+		stats := (&trustedBundle{}).Stats()
+		if stats.TotalCertificates != 0 || !stats.EarliestNotAfter.IsZero() || !stats.LatestNotAfter.IsZero() {
+			t.Errorf("Expected empty stats, got %+v", stats)
+		}
+	})
+}
+
+func TestRefreshNow(t *testing.T) {
+	// newBundle returns a bundle served by releaseHTTPClient and the outcomes reported to OnUpdate.
+	newBundle := func(t *testing.T) (*trustedBundle, *[]UpdateOutcome) {
+		t.Helper()
+		var outcomes []UpdateOutcome
+		tb, err := GetTrustedBundle(t.Context(), GetConfig{
+			Date:                  testutil.BundleVersion,
+			CachePath:             t.TempDir(),
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			HTTPClient:            &releaseHTTPClient{},
+			AutoUpdate: AutoUpdateConfig{
+				DisableAutoUpdate: true,
+				OnUpdate: func(outcome UpdateOutcome, err error) {
+					if (outcome == UpdateOutcomeFailed) != (err != nil) {
+						t.Errorf("OnUpdate(%v, %v): error must be set only on failure", outcome, err)
+					}
+					outcomes = append(outcomes, outcome)
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("GetTrustedBundle() error = %v", err)
+		}
+		return tb.(*trustedBundle), &outcomes
+	}
+
+	tests := []struct {
+		name        string
+		setup       func(tb *trustedBundle)
+		wantOutcome UpdateOutcome
+		wantErr     bool
+	}{
+		{
+			name:        "same date",
+			setup:       func(tb *trustedBundle) {},
+			wantOutcome: UpdateOutcomeUpToDate,
+		},
+		{
+			name: "newer date",
+			setup: func(tb *trustedBundle) {
+				outdated := *tb.rootMetadata
+				outdated.Date = "2025-01-01"
+				tb.rootMetadata = &outdated
+			},
+			wantOutcome: UpdateOutcomeUpdated,
+		},
+		{
+			name: "error",
+			setup: func(tb *trustedBundle) {
+				cfg := tb.updater.(GetConfig)
+				cfg.HTTPClient = &http.Client{Transport: failingRoundTripper{}}
+				tb.updater = cfg
+			},
+			wantOutcome: UpdateOutcomeFailed,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb, outcomes := newBundle(t)
+			tt.setup(tb)
+
+			outcome, err := tb.RefreshNow(t.Context())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RefreshNow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if outcome != tt.wantOutcome {
+				t.Errorf("RefreshNow() outcome = %v, want %v", outcome, tt.wantOutcome)
+			}
+			if !slices.Equal(*outcomes, []UpdateOutcome{tt.wantOutcome}) {
+				t.Errorf("OnUpdate outcomes = %v, want [%v]", *outcomes, tt.wantOutcome)
+			}
+			if got := tb.GetRootMetadata().Date; tt.wantOutcome != UpdateOutcomeFailed && got != testutil.BundleVersion {
+				t.Errorf("bundle date = %s, want %s", got, testutil.BundleVersion)
+			}
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		bundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+		if err != nil {
+			t.Fatalf("Failed to read test bundle: %v", err)
+		}
+		tb, err := NewTrustedBundle(bytes.NewReader(bundleData), WithSkipVerify())
+		if err != nil {
+			t.Fatalf("NewTrustedBundle() error = %v", err)
+		}
+
+		outcome, err := tb.RefreshNow(t.Context())
+		if !errors.Is(err, ErrRefreshUnsupported) {
+			t.Errorf("RefreshNow() error = %v, want %v", err, ErrRefreshUnsupported)
+		}
+		if outcome != UpdateOutcomeFailed {
+			t.Errorf("RefreshNow() outcome = %v, want %v", outcome, UpdateOutcomeFailed)
+		}
+	})
+}
+
+func TestRefreshNowPropagatesConfig(t *testing.T) {
+	const userAgent = "my-app/1.0"
+	var logs bytes.Buffer
+	client := &releaseHTTPClient{}
+	tb, err := GetTrustedBundle(t.Context(), GetConfig{
+		Date:                  testutil.BundleVersion,
+		CachePath:             t.TempDir(),
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
+		HTTPClient:            client,
+		UserAgent:             userAgent,
+		Logger:                slog.New(slog.NewTextHandler(&logs, nil)),
+		AutoUpdate:            AutoUpdateConfig{DisableAutoUpdate: true},
+	})
+	if err != nil {
+		t.Fatalf("GetTrustedBundle() error = %v", err)
+	}
+	requests, warnings := client.requests, strings.Count(logs.String(), "level=WARN")
+
+	if _, err := tb.RefreshNow(t.Context()); err != nil {
+		t.Fatalf("RefreshNow() error = %v", err)
+	}
+	if client.requests == requests {
+		t.Fatal("expected the refresh to send requests")
+	}
+	for _, got := range client.userAgents[requests:] {
+		if got != userAgent {
+			t.Errorf("refresh request User-Agent = %q, want %q", got, userAgent)
+		}
+	}
+	if got := strings.Count(logs.String(), "level=WARN"); got <= warnings {
+		t.Error("expected the refresh to log to the configured Logger")
+	}
+}
+
+func TestIntegrityCheck(t *testing.T) {
+	failures := make(chan error, 1)
+	tb, err := GetTrustedBundle(t.Context(), GetConfig{
+		Date:                  testutil.BundleVersion,
+		CachePath:             t.TempDir(),
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
+		HTTPClient:            &releaseHTTPClient{},
+		AutoUpdate: AutoUpdateConfig{
+			DisableAutoUpdate:      true,
+			IntegrityCheckInterval: 10 * time.Millisecond,
+			OnUpdate: func(outcome UpdateOutcome, err error) {
+				if outcome != UpdateOutcomeFailed {
+					t.Errorf("OnUpdate() outcome = %v, want %v", outcome, UpdateOutcomeFailed)
+				}
+				select {
+				case failures <- err:
+				default:
+				}
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetTrustedBundle() error = %v", err)
+	}
+	defer tb.Stop()
+
+	tbImpl := tb.(*trustedBundle)
+	if err := tbImpl.checkIntegrity(); err != nil {
+		t.Fatalf("checkIntegrity() error = %v", err)
+	}
+
+	// Simulate a corruption of the bundle held in memory
+	tbImpl.mu.Lock()
+	tbImpl.assets.rootBundleData[len(tbImpl.assets.rootBundleData)-1] ^= 0xff
+	tbImpl.mu.Unlock()
+
+	select {
+	case err := <-failures:
+		if !errors.Is(err, ErrIntegrityCheckFailed) {
+			t.Errorf("OnUpdate() error = %v, want %v", err, ErrIntegrityCheckFailed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("integrity mismatch was not reported")
+	}
+}
+
+func TestIntegrityCheckCertificates(t *testing.T) {
+	tb, err := LoadTrustedBundle(t.Context(), LoadConfig{
+		CachePath:   testutil.CreateCacheDir(t, nil),
+		OfflineMode: true,
+	})
+	if err != nil {
+		t.Fatalf("LoadTrustedBundle() error = %v", err)
+	}
+	defer tb.Stop()
+
+	tbImpl := tb.(*trustedBundle)
+	tbImpl.mu.Lock()
+	tbImpl.referenceDigests = tbImpl.expectedDigests()
+	tbImpl.mu.Unlock()
+	if err := tbImpl.checkIntegrity(); err != nil {
+		t.Fatalf("checkIntegrity() error = %v", err)
+	}
+
+	// Simulate a corruption of a parsed certificate, the raw bundle being untouched
+	tbImpl.mu.Lock()
+	for _, certs := range tbImpl.rootCatalog {
+		certs[0].Raw[len(certs[0].Raw)-1] ^= 0xff
+		break
+	}
+	tbImpl.mu.Unlock()
+
+	if err := tbImpl.checkIntegrity(); !errors.Is(err, ErrIntegrityCheckFailed) {
+		t.Errorf("checkIntegrity() error = %v, want %v", err, ErrIntegrityCheckFailed)
+	}
+}
+
+func TestIntegrityCheckReferenceDigests(t *testing.T) {
+	rootBundleData, err := testutil.ReadTestFile(testutil.RootBundleFile)
+	if err != nil {
+		t.Fatalf("failed to read test bundle: %v", err)
+	}
+	tb, err := newTrustedBundle(t.Context(), nil, rootBundleData)
+	if err != nil {
+		t.Fatalf("newTrustedBundle() error = %v", err)
+	}
+	tbImpl := tb.(*trustedBundle)
+	// The verified checksums file is the reference, not the bytes held in memory
+	listed := strings.Repeat("a", 64)
+	tbImpl.assets.checksum = []byte(listed + "  " + CacheRootBundleFilename + "\n")
+
+	t.Run("verified bundle", func(t *testing.T) {
+		if got, want := tbImpl.expectedDigests().root, "sha256:"+listed; got != want {
+			t.Errorf("expectedDigests().root = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("unverified bundle", func(t *testing.T) {
+		tbImpl.skipVerify = true
+		t.Cleanup(func() { tbImpl.skipVerify = false })
+		if got, want := tbImpl.expectedDigests().root, ComputeBundleDigest(rootBundleData); got != want {
+			t.Errorf("expectedDigests().root = %s, want %s", got, want)
+		}
+	})
+}
+
+func TestAutoUpdateConfigValidation(t *testing.T) {
+	cfg := AutoUpdateConfig{IntegrityCheckInterval: -time.Second}
+	if err := cfg.CheckAndSetDefaults(); err == nil {
+		t.Error("expected an error for a negative integrity check interval")
+	}
+}
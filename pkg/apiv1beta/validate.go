@@ -0,0 +1,69 @@
+package apiv1beta
+
+import (
+	"fmt"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/bundle"
+)
+
+// BundleValidationSeverity indicates how serious a [BundleValidationError] is.
+type BundleValidationSeverity string
+
+const (
+	// SeverityError indicates that the bundle is malformed and must not be trusted.
+	SeverityError BundleValidationSeverity = "error"
+)
+
+// BundleValidationError represents a structural issue found in a bundle.
+type BundleValidationError struct {
+	// Line is the 1-based line number where the issue was detected.
+	Line int
+	// Message describes the issue.
+	Message string
+	// Severity indicates how serious the issue is.
+	Severity BundleValidationSeverity
+}
+
+func (e BundleValidationError) String() string {
+	return fmt.Sprintf("line %d: [%s] %s", e.Line, e.Severity, e.Message)
+}
+
+// ValidateBundle checks that a TPM trust bundle is structurally correct.
+//
+// It validates the global metadata block (date and commit), each certificate
+// metadata block, the PEM encoding, the consistency between metadata and
+// certificate content and the vendor IDs. At most 10 issues are returned.
+//
+// ValidateBundle does NOT check signatures nor provenance: a structurally valid
+// bundle is not necessarily authentic. Use [VerifyTrustedBundle] to assert
+// authenticity before trusting a bundle received out-of-band.
+//
+// Example:
+//
+//	issues, err := apiv1beta.ValidateBundle(bundleData)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, issue := range issues {
+//	    fmt.Println(issue)
+//	}
+func ValidateBundle(data []byte) ([]BundleValidationError, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("bundle cannot be empty")
+	}
+
+	validationErrors, err := bundle.NewBundleValidator().ValidateBundle(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate bundle: %w", err)
+	}
+
+	result := make([]BundleValidationError, 0, len(validationErrors))
+	for _, e := range validationErrors {
+		result = append(result, BundleValidationError{
+			Line:     e.Line,
+			Message:  e.Message,
+			Severity: SeverityError,
+		})
+	}
+	return result, nil
+}
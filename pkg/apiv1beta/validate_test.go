@@ -0,0 +1,148 @@
+package apiv1beta
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+)
+
+const validGlobalMetadata = `##
+## tpm-ca-certificates.pem
+##
+## Date: 2024-12-08
+## Commit: 1234567890abcdef1234567890abcdef12345678
+##
+## This file has been auto-generated by tpmtb (TPM Trust Bundle)
+## and contains a list of verified TPM Root Endorsement Certificates.
+##
+`
+
+func TestValidateBundle(t *testing.T) {
+	t.Run("valid bundle", func(t *testing.T) {
+		data, err := testutil.ReadTestFile(testutil.RootBundleFile)
+		if err != nil {
+			t.Fatalf("failed to read test bundle: %v", err)
+		}
+
+		issues, err := ValidateBundle(data)
+		if err != nil {
+			t.Fatalf("ValidateBundle() error = %v", err)
+		}
+		if len(issues) > 0 {
+			t.Errorf("expected no validation errors, got %d: %v", len(issues), issues)
+		}
+	})
+
+	t.Run("empty bundle", func(t *testing.T) {
+		if _, err := ValidateBundle(nil); err == nil {
+			t.Fatal("ValidateBundle() error = nil, want error for empty bundle")
+		}
+	})
+
+	tests := []struct {
+		name        string
+		bundle      string
+		wantMessage string
+	}{
+		{
+			name: "missing global metadata",
+			bundle: `# Certificate: Test
+# Owner: STM
+-----BEGIN CERTIFICATE-----
+MIIBkTCB+wIJAKHHCgVZU2T9MA0GCSqGSIb3DQEBCwUAMA0xCzAJBgNVBAYTAlVT
+-----END CERTIFICATE-----`,
+			wantMessage: "global metadata block marker",
+		},
+		{
+			name:        "invalid date format",
+			bundle:      strings.Replace(validGlobalMetadata, "2024-12-08", "2024/12/08", 1),
+			wantMessage: "invalid date format",
+		},
+		{
+			name:        "invalid commit hash",
+			bundle:      strings.Replace(validGlobalMetadata, "1234567890abcdef1234567890abcdef12345678", "abc123", 1),
+			wantMessage: "invalid commit hash",
+		},
+		{
+			name:        "missing commit",
+			bundle:      strings.Replace(validGlobalMetadata, "## Commit: 1234567890abcdef1234567890abcdef12345678\n", "", 1),
+			wantMessage: "missing required 'Commit' field",
+		},
+		{
+			name: "invalid vendor ID",
+			bundle: validGlobalMetadata + `
+#
+# Certificate: Test Certificate
+# Owner: INVALID
+#
+-----BEGIN CERTIFICATE-----
+MIIBkTCB+wIJAKHHCgVZU2T9MA0GCSqGSIb3DQEBCwUAMA0xCzAJBgNVBAYTAlVT
+-----END CERTIFICATE-----`,
+			wantMessage: "invalid vendor ID",
+		},
+		{
+			name: "missing certificate metadata",
+			bundle: validGlobalMetadata + `
+#
+# Certificate: Test Certificate
+# Owner: STM
+#
+-----BEGIN CERTIFICATE-----
+MIIBkTCB+wIJAKHHCgVZU2T9MA0GCSqGSIb3DQEBCwUAMA0xCzAJBgNVBAYTAlVT
+-----END CERTIFICATE-----`,
+			wantMessage: "missing required 'Issuer' field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := ValidateBundle([]byte(tt.bundle))
+			if err != nil {
+				t.Fatalf("ValidateBundle() error = %v", err)
+			}
+			if len(issues) == 0 {
+				t.Fatal("expected validation errors")
+			}
+
+			found := false
+			for _, issue := range issues {
+				if issue.Severity != SeverityError {
+					t.Errorf("issue.Severity = %q, want %q", issue.Severity, SeverityError)
+				}
+				if issue.Line <= 0 {
+					t.Errorf("issue.Line = %d, want a positive line number", issue.Line)
+				}
+				if strings.Contains(issue.Message, tt.wantMessage) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected an issue containing %q, got: %v", tt.wantMessage, issues)
+			}
+		})
+	}
+
+	t.Run("caps the number of errors", func(t *testing.T) {
+		bundle := strings.NewReplacer("2024-12-08", "invalid-date", "1234567890abcdef1234567890abcdef12345678", "invalid").Replace(validGlobalMetadata)
+		for i := range 15 {
+			bundle += `
+#
+# Certificate: Test ` + string(rune('A'+i)) + `
+# Owner: INVALID
+#
+-----BEGIN CERTIFICATE-----
+INVALID
+-----END CERTIFICATE-----
+`
+		}
+
+		issues, err := ValidateBundle([]byte(bundle))
+		if err != nil {
+			t.Fatalf("ValidateBundle() error = %v", err)
+		}
+		if len(issues) != 10 {
+			t.Errorf("expected exactly 10 errors (max), got %d", len(issues))
+		}
+	})
+}
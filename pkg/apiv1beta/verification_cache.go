@@ -0,0 +1,120 @@
+package apiv1beta
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loicsikidi/tpm-ca-certificates/internal/transparency/utils/digest"
+)
+
+const (
+	// defaultVerificationCacheTTL is the default lifetime of a cached verification.
+	//
+	// It is deliberately short: a revocation or a Sigstore key rotation happening
+	// after the verification is not taken into account until the entry expires.
+	defaultVerificationCacheTTL = time.Hour
+
+	// maxVerificationCacheEntries bounds the number of cached verifications.
+	maxVerificationCacheEntries = 256
+)
+
+// verifiedBundles is the in-memory verification cache, keyed by bundle digest and policy.
+//
+// It is never persisted: an entry on disk could be forged by anyone able to write to the
+// cache directory to skip the verification of a tampered bundle.
+var verifiedBundles = &verificationCache{entries: make(map[string]time.Time)}
+
+// verificationCache is a bounded map of verification timestamps.
+type verificationCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// load returns the time key was verified, if it is no older than ttl.
+func (c *verificationCache) load(key string, ttl time.Duration) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	verifiedAt, ok := c.entries[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	if time.Since(verifiedAt) > ttl {
+		delete(c.entries, key)
+		return time.Time{}, false
+	}
+	return verifiedAt, true
+}
+
+// store records that key was verified at verifiedAt, evicting the oldest entry when full.
+func (c *verificationCache) store(key string, verifiedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxVerificationCacheEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, t := range c.entries {
+			if oldestKey == "" || t.Before(oldest) {
+				oldestKey, oldest = k, t
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+	c.entries[key] = verifiedAt
+}
+
+// clear removes every entry.
+func (c *verificationCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// len returns the number of entries.
+func (c *verificationCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// verificationPolicy identifies everything a verification result depends on besides the bundle
+// bytes, so that a result obtained under a policy is never reused under another one.
+func verificationPolicy(cfg *VerifyConfig) string {
+	return digest.ComputeSHA256([]byte(strings.Join([]string{
+		cfg.sourceRepo.String(),
+		cfg.BundleMetadata.Date,
+		cfg.BundleMetadata.Commit,
+		digest.ComputeSHA256(cfg.PinnedKey),
+		digest.ComputeSHA256(cfg.TrustedRoot),
+		tufMirrorKey(cfg.TUFMirror),
+		strconv.FormatBool(cfg.RequireRekorInclusion),
+		strings.Join(cfg.AllowedOIDCIssuers, ","),
+		strings.Join(cfg.WorkflowFilenames, ","),
+	}, "\n")))
+}
+
+// tufMirrorKey identifies the TUF repository the trusted root is fetched from.
+func tufMirrorKey(mirror *TUFMirror) string {
+	if mirror == nil {
+		return ""
+	}
+	return mirror.URL + "@" + digest.ComputeSHA256(mirror.Root)
+}
+
+// verificationKey returns the cache key of the bundle verified under the policy of cfg.
+func verificationKey(cfg *VerifyConfig) string {
+	return digest.ComputeSHA256(cfg.Bundle) + "/" + verificationPolicy(cfg)
+}
+
+// loadVerification returns the time the bundle was previously verified, if still within the TTL.
+func loadVerification(cfg *VerifyConfig) (time.Time, bool) {
+	return verifiedBundles.load(verificationKey(cfg), cfg.VerificationCacheTTL)
+}
+
+// saveVerification records a successful verification of the bundle.
+func saveVerification(cfg *VerifyConfig, verifiedAt time.Time) {
+	verifiedBundles.store(verificationKey(cfg), verifiedAt)
+}
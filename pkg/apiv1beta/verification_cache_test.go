@@ -0,0 +1,40 @@
+package apiv1beta
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestVerificationCache(t *testing.T) {
+	t.Run("expired entries miss", func(t *testing.T) {
+		c := &verificationCache{entries: make(map[string]time.Time)}
+		c.store("key", time.Now().Add(-2*time.Hour))
+
+		if _, ok := c.load("key", time.Hour); ok {
+			t.Error("expected an expired entry to miss")
+		}
+		if c.len() != 0 {
+			t.Errorf("expected the expired entry to be evicted, got %d entries", c.len())
+		}
+	})
+
+	t.Run("oldest entry is evicted when full", func(t *testing.T) {
+		c := &verificationCache{entries: make(map[string]time.Time)}
+		now := time.Now()
+		for i := range maxVerificationCacheEntries {
+			c.store(fmt.Sprintf("key-%d", i), now.Add(time.Duration(i)*time.Second))
+		}
+		c.store("new", now.Add(time.Hour))
+
+		if c.len() != maxVerificationCacheEntries {
+			t.Errorf("expected %d entries, got %d", maxVerificationCacheEntries, c.len())
+		}
+		if _, ok := c.load("key-0", 2*time.Hour); ok {
+			t.Error("expected the oldest entry to be evicted")
+		}
+		if _, ok := c.load("new", 2*time.Hour); !ok {
+			t.Error("expected the new entry to be cached")
+		}
+	})
+}
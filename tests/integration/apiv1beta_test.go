@@ -35,9 +35,10 @@ func TestVerifyTrustedBundle(t *testing.T) {
 
 		// First download a bundle
 		trustedBundle, err := apiv1beta.GetTrustedBundle(t.Context(), apiv1beta.GetConfig{
-			Date:       testutil.BundleVersion,
-			SkipVerify: true,
-			CachePath:  cachePath,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             cachePath,
 		})
 		if err != nil {
 			t.Fatalf("Failed to download bundle: %v", err)
@@ -113,7 +114,8 @@ func TestGetTrustedBundle(t *testing.T) {
 	t.Run("fetch and parse latest bundle", func(t *testing.T) {
 		t.Parallel()
 		cfg := apiv1beta.GetConfig{
-			SkipVerify: true,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -181,7 +183,8 @@ func TestGetTrustedBundle(t *testing.T) {
 	t.Run("stop is idempotent", func(t *testing.T) {
 		t.Parallel()
 		cfg := apiv1beta.GetConfig{
-			SkipVerify: true,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -205,7 +208,8 @@ func TestGetTrustedBundle(t *testing.T) {
 	t.Run("fetch latest bundle without verification", func(t *testing.T) {
 		t.Parallel()
 		cfg := apiv1beta.GetConfig{
-			SkipVerify: true,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -249,7 +253,8 @@ func TestGetTrustedBundle(t *testing.T) {
 	t.Run("fetch bundle with SkipVerify downloads both root and intermediate bundles", func(t *testing.T) {
 		t.Parallel()
 		cfg := apiv1beta.GetConfig{
-			SkipVerify: true,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -302,8 +307,9 @@ func TestGetTrustedBundle(t *testing.T) {
 	t.Run("filter by vendor IDs", func(t *testing.T) {
 		t.Parallel()
 		cfg := apiv1beta.GetConfig{
-			SkipVerify: true,
-			VendorIDs:  []apiv1beta.VendorID{apiv1beta.NTC, apiv1beta.IFX},
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			VendorIDs:             []apiv1beta.VendorID{apiv1beta.NTC, apiv1beta.IFX},
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -325,8 +331,9 @@ func TestGetTrustedBundle(t *testing.T) {
 	t.Run("auto-update refreshes to newer version", func(t *testing.T) {
 		// Start with an older version (2025-12-05)
 		cfg := apiv1beta.GetConfig{
-			Date:       testutil.BundleVersion,
-			SkipVerify: true,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				Interval: 2 * time.Second, // Short interval for testing
 			},
@@ -377,7 +384,8 @@ func TestTrustedBundle_ThreadSafety(t *testing.T) {
 	t.Parallel()
 
 	cfg := apiv1beta.GetConfig{
-		SkipVerify: true,
+		SkipVerify:            true,
+		AcknowledgeSkipVerify: true,
 		AutoUpdate: apiv1beta.AutoUpdateConfig{
 			DisableAutoUpdate: true,
 		},
@@ -447,9 +455,10 @@ func TestSmartCache(t *testing.T) {
 
 		// First call should download from GitHub and cache
 		cfg := apiv1beta.GetConfig{
-			Date:       testutil.BundleVersion,
-			SkipVerify: true,
-			CachePath:  tmpDir,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             tmpDir,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -494,9 +503,10 @@ func TestSmartCache(t *testing.T) {
 
 		// First call to populate cache
 		cfg1 := apiv1beta.GetConfig{
-			Date:       testutil.BundleVersion,
-			SkipVerify: true,
-			CachePath:  tmpDir,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             tmpDir,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -521,9 +531,10 @@ func TestSmartCache(t *testing.T) {
 
 		// Second call with same config should use cache
 		cfg2 := apiv1beta.GetConfig{
-			Date:       testutil.BundleVersion,
-			SkipVerify: true,
-			CachePath:  tmpDir,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             tmpDir,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -558,9 +569,10 @@ func TestSmartCache(t *testing.T) {
 
 		// First call with version 2025-12-05
 		cfg1 := apiv1beta.GetConfig{
-			Date:       testutil.BundleVersion,
-			SkipVerify: true,
-			CachePath:  tmpDir,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             tmpDir,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -574,9 +586,10 @@ func TestSmartCache(t *testing.T) {
 
 		// Second call with different version should download again
 		cfg2 := apiv1beta.GetConfig{
-			Date:       "2025-12-03",
-			SkipVerify: true,
-			CachePath:  tmpDir,
+			Date:                  "2025-12-03",
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             tmpDir,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -617,10 +630,11 @@ func TestSmartCache(t *testing.T) {
 
 		// Call with DisableLocalCache=true should not create cache
 		cfg := apiv1beta.GetConfig{
-			Date:              testutil.BundleVersion,
-			SkipVerify:        true,
-			CachePath:         tmpDir,
-			DisableLocalCache: true,
+			Date:                  testutil.BundleVersion,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
+			CachePath:             tmpDir,
+			DisableLocalCache:     true,
 			AutoUpdate: apiv1beta.AutoUpdateConfig{
 				DisableAutoUpdate: true,
 			},
@@ -740,8 +754,9 @@ func TestLoad(t *testing.T) {
 
 		// Load the bundle from cache (old version)
 		tb, err := apiv1beta.LoadTrustedBundle(t.Context(), apiv1beta.LoadConfig{
-			CachePath:  tmpDir,
-			SkipVerify: true,
+			CachePath:             tmpDir,
+			SkipVerify:            true,
+			AcknowledgeSkipVerify: true,
 		})
 		if err != nil {
 			t.Fatalf("Load failed: %v", err)
@@ -0,0 +1,27 @@
+package bundle_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/verifyall"
+)
+
+func TestVerifyAllCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that verifies every published release")
+	}
+
+	cmd := verifyall.NewCommand()
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"-j", "4"})
+
+	if err := cmd.ExecuteContext(t.Context()); err != nil {
+		// Skip test if we hit rate limits
+		if strings.Contains(err.Error(), "rate limit") {
+			t.Skipf("skipping due to rate limit: %v", err)
+		}
+		t.Fatalf("verify-all failed: %v", err)
+	}
+}
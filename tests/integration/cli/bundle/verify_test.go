@@ -0,0 +1,52 @@
+package bundle_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/save"
+	"github.com/loicsikidi/tpm-ca-certificates/cmd/bundle/verify"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/testutil"
+	"github.com/loicsikidi/tpm-ca-certificates/internal/utils"
+	"github.com/loicsikidi/tpm-ca-certificates/pkg/apiv1beta"
+)
+
+func TestVerifyCommandWithSavedAssets(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test that downloads bundle from remote")
+	}
+
+	outputDir := t.TempDir()
+	if err := save.Run(t.Context(), &save.Opts{
+		Date:      testutil.BundleVersion,
+		OutputDir: outputDir,
+		Force:     true,
+	}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	path := func(filename string) string {
+		return filepath.Join(outputDir, filename)
+	}
+
+	for _, bundleFilename := range []string{apiv1beta.CacheRootBundleFilename, apiv1beta.CacheIntermediateBundleFilename} {
+		t.Run(bundleFilename, func(t *testing.T) {
+			if !utils.FileExists(path(bundleFilename)) {
+				t.Skipf("%s not published for this release", bundleFilename)
+			}
+
+			cmd := verify.NewCommand()
+			cmd.SetArgs([]string{
+				path(bundleFilename),
+				"--checksums-file", path(apiv1beta.CacheChecksumsFilename),
+				"--checksums-signature", path(apiv1beta.CacheChecksumsSigFilename),
+				"--provenance", path(apiv1beta.CacheProvenanceFilename),
+				"--trusted-root", path(apiv1beta.CacheTrustedRootFilename),
+			})
+
+			if err := cmd.ExecuteContext(t.Context()); err != nil {
+				t.Fatalf("offline verify failed: %v", err)
+			}
+		})
+	}
+}